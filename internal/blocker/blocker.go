@@ -42,6 +42,14 @@ func (d *Detector) RecordFailure(taskID, errorMessage string) (bool, error) {
 
 // MarkAsBlocked marks a task as blocked and creates a blocker record
 func (d *Detector) MarkAsBlocked(taskID, phaseID, projectID, reason, context string) (*state.Blocker, error) {
+	exists, err := d.store.TaskExists(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check task: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("task not found: %s", taskID)
+	}
+
 	// Create blocker record
 	blocker := &state.Blocker{
 		ID:          fmt.Sprintf("blocker-%s-%d", taskID, time.Now().UnixNano()),
@@ -138,14 +146,37 @@ type ResolutionStrategy struct {
 	Automatic   bool
 }
 
-// RequestUserIntervention notifies the user about a blocker that requires manual intervention
+// RequestUserIntervention queues a blocker for manual intervention. The request
+// stays pending until it is answered with ResolveIntervention.
 func (d *Detector) RequestUserIntervention(blocker *state.Blocker, context string) error {
-	// In a real implementation, we would:
-	// 1. Format a user-friendly notification
-	// 2. Display it in the UI
-	// 3. Wait for user response
-	// 4. Record the intervention
+	if blocker == nil {
+		return fmt.Errorf("blocker cannot be nil")
+	}
+
+	intervention := &state.Intervention{
+		ID:          fmt.Sprintf("intervention-%s-%d", blocker.ID, time.Now().UnixNano()),
+		BlockerID:   blocker.ID,
+		Context:     context,
+		RequestedAt: time.Now(),
+	}
 
+	if err := d.store.SaveIntervention(intervention); err != nil {
+		return fmt.Errorf("failed to request user intervention: %w", err)
+	}
+
+	return nil
+}
+
+// ListPendingInterventions returns intervention requests still awaiting a response
+func (d *Detector) ListPendingInterventions(projectID string) ([]state.Intervention, error) {
+	return d.store.ListPendingInterventions(projectID)
+}
+
+// ResolveIntervention records the user's response to an intervention request
+func (d *Detector) ResolveIntervention(interventionID, response string) error {
+	if err := d.store.ResolveIntervention(interventionID, response); err != nil {
+		return fmt.Errorf("failed to resolve intervention: %w", err)
+	}
 	return nil
 }
 
@@ -518,6 +518,38 @@ func TestRequestUserIntervention(t *testing.T) {
 	}
 	defer store.Close()
 
+	project := &state.Project{
+		ID:        "project-1",
+		Name:      "Test Project",
+		CreatedAt: time.Now(),
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	phase := &state.Phase{
+		ID:        "phase-1",
+		ProjectID: "project-1",
+		Number:    1,
+		Title:     "Test Phase",
+		Status:    "in_progress",
+		CreatedAt: time.Now(),
+	}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("failed to save phase: %v", err)
+	}
+
+	task := &state.Task{
+		ID:          "task-1",
+		PhaseID:     "phase-1",
+		Number:      "1",
+		Description: "Test Task",
+		Status:      "in_progress",
+	}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("failed to save task: %v", err)
+	}
+
 	detector := NewDetector(store, nil)
 
 	blocker := &state.Blocker{
@@ -526,11 +558,41 @@ func TestRequestUserIntervention(t *testing.T) {
 		Description: "Task failed",
 		CreatedAt:   time.Now(),
 	}
+	if err := store.SaveBlocker(blocker); err != nil {
+		t.Fatalf("failed to save blocker: %v", err)
+	}
 
 	err = detector.RequestUserIntervention(blocker, "Additional context")
 	if err != nil {
 		t.Fatalf("failed to request user intervention: %v", err)
 	}
+
+	pending, err := detector.ListPendingInterventions("project-1")
+	if err != nil {
+		t.Fatalf("failed to list pending interventions: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending intervention, got %d", len(pending))
+	}
+	if pending[0].BlockerID != "blocker-1" || pending[0].Context != "Additional context" {
+		t.Errorf("unexpected intervention: %+v", pending[0])
+	}
+
+	if err := detector.ResolveIntervention(pending[0].ID, "Use the staging credentials"); err != nil {
+		t.Fatalf("failed to resolve intervention: %v", err)
+	}
+
+	pending, err = detector.ListPendingInterventions("project-1")
+	if err != nil {
+		t.Fatalf("failed to list pending interventions: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no pending interventions after resolving, got %d", len(pending))
+	}
+
+	if err := detector.ResolveIntervention("missing", "response"); err == nil {
+		t.Error("expected error resolving unknown intervention")
+	}
 }
 
 func TestResolutionStrategies(t *testing.T) {
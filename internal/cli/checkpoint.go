@@ -47,7 +47,7 @@ func runCheckpoint(cmd *cobra.Command, args []string) error {
 
 	// Use of same database location as init command
 	dbPath := filepath.Join(cwd, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open state store: %w", err)
 	}
@@ -146,6 +146,7 @@ func listCheckpoints(store *state.Store, projectID string) error {
 		fmt.Printf("%d. %s\n", i+1, cp.Name)
 		fmt.Printf("   Git Tag: %s\n", cp.GitTag)
 		fmt.Printf("   Created: %s\n", cp.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("   Snapshot: $%.2f spent, %.0f%% complete\n", cp.TotalCost, cp.CompletionPercentage)
 		if len(cp.Metadata) > 0 {
 			fmt.Printf("   Metadata: %d key(s)\n", len(cp.Metadata))
 		}
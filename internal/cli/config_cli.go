@@ -14,6 +14,7 @@ import (
 var configListProviders bool
 var configSetKey bool
 var configSetModel bool
+var configListProfiles bool
 
 var configCmd = &cobra.Command{
 	Use:   "config",
@@ -27,6 +28,7 @@ func init() {
 	configCmd.Flags().BoolVar(&configListProviders, "list-providers", false, "List available providers and their models")
 	configCmd.Flags().BoolVar(&configSetKey, "set-key", false, "Set API key interactively")
 	configCmd.Flags().BoolVar(&configSetModel, "set-model", false, "Set default model for a stage")
+	configCmd.Flags().BoolVar(&configListProfiles, "list-profiles", false, "List configured profiles")
 }
 
 func runConfig(cmd *cobra.Command, args []string) error {
@@ -50,9 +52,36 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		return setDefaultModelInteractive(cfgMgr)
 	}
 
+	if configListProfiles {
+		cfgMgr := config.NewManager()
+		if err := cfgMgr.Load(nil); err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		return listProfiles(cfgMgr)
+	}
+
 	return showConfigMenu()
 }
 
+func listProfiles(cfgMgr *config.Manager) error {
+	profiles := cfgMgr.ListProfiles()
+	if len(profiles) == 0 {
+		fmt.Println("No profiles configured. Add a 'profiles' section to your config file.")
+		return nil
+	}
+
+	active := cfgMgr.GetActiveProfile()
+	fmt.Println("Profiles:")
+	for _, name := range profiles {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		fmt.Printf("  %s %s\n", marker, name)
+	}
+	return nil
+}
+
 func showConfigMenu() error {
 	cfgMgr := config.NewManager()
 	if err := cfgMgr.Load(nil); err != nil {
@@ -101,9 +130,8 @@ func showConfigMenu() error {
 				fmt.Printf("⚠️  Error: %v\n", err)
 			}
 		case "5":
-			cfg := cfgMgr.GetConfig()
-			cfg.VerboseLogging = !cfg.VerboseLogging
-			if cfg.VerboseLogging {
+			cfgMgr.SetVerboseLogging(!cfgMgr.GetConfig().VerboseLogging)
+			if cfgMgr.GetConfig().VerboseLogging {
 				fmt.Println("✅ Verbose logging enabled")
 			} else {
 				fmt.Println("✅ Verbose logging disabled")
@@ -540,8 +568,9 @@ func setBudgetLimitInteractive(cfgMgr *config.Manager) error {
 		return fmt.Errorf("invalid budget limit: %w", err)
 	}
 
-	cfg := cfgMgr.GetConfig()
-	cfg.BudgetLimit = limit
+	if err := cfgMgr.SetBudgetLimit(limit); err != nil {
+		return err
+	}
 
 	if err := cfgMgr.Save(); err != nil {
 		return fmt.Errorf("failed to save configuration: %w", err)
@@ -58,7 +58,7 @@ func runDesign(cmd *cobra.Command, args []string) error {
 
 	// Use same database location as other commands
 	dbPath := filepath.Join(cwd, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open state store: %w", err)
 	}
@@ -76,7 +76,7 @@ func runDesign(cmd *cobra.Command, args []string) error {
 	}
 
 	// 4. Setup Provider
-	providerName, modelName, err := getProviderAndModel(cfgMgr, "design", designModel)
+	providerName, modelName, err := selectProviderWithinBudget(cfgMgr, store, projectID, "design", designModel)
 	if err != nil {
 		fmt.Println("\n⚠️  Could not automatically select provider and model")
 		fmt.Println("   Available options:")
@@ -99,9 +99,11 @@ func runDesign(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get provider: %w", err)
 	}
+	prov = capProviderSpend(prov, cfgMgr, store, projectID)
 
 	// 5. Initialize Generator
 	generator := design.NewGenerator(prov, modelName)
+	generator.SetStreaming(cmd.Context(), nil)
 
 	if designRefine != "" {
 		return handleRefinement(generator, store, projectID, designRefine)
@@ -14,6 +14,7 @@ import (
 	"github.com/mojomast/geoffrussy/internal/interview"
 	"github.com/mojomast/geoffrussy/internal/provider"
 	"github.com/mojomast/geoffrussy/internal/state"
+	"github.com/mojomast/geoffrussy/internal/token"
 	"github.com/spf13/cobra"
 )
 
@@ -54,7 +55,7 @@ func runDevelop(cmd *cobra.Command, args []string) error {
 
 	// 2. Initialize Store
 	dbPath := filepath.Join(cwd, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open state store: %w", err)
 	}
@@ -66,7 +67,7 @@ func runDevelop(cmd *cobra.Command, args []string) error {
 	}
 
 	// 3. Initialize Provider
-	providerName, modelName, err := getProviderAndModel(cfgMgr, "develop", developModel)
+	providerName, modelName, err := selectProviderWithinBudget(cfgMgr, store, projectID, "develop", developModel)
 	if err != nil {
 		return fmt.Errorf("failed to get provider and model: %w", err)
 	}
@@ -80,10 +81,22 @@ func runDevelop(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get provider: %w", err)
 	}
+	prov = capProviderSpend(prov, cfgMgr, store, projectID)
 
 	fmt.Printf("📦 Using Provider: %s\n", providerName)
 	fmt.Printf("🤖 Using Model: %s\n", modelName)
 
+	// Apply the on_budget_exceeded policy before spending anything
+	costEstimator := token.NewCostEstimator(store)
+	costEstimator.SetBudgetLimit(cfgMgr.GetConfig().BudgetLimit)
+	warning, err := costEstimator.EnforceBudget(projectID, cfgMgr.GetBudgetPolicy())
+	if err != nil {
+		return err
+	}
+	if warning != "" {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+
 	// 4. Initialize Components
 	interviewEngine := interview.NewEngine(store, prov, modelName)
 	devplanGenerator := devplan.NewGenerator(prov, modelName)
@@ -125,6 +138,9 @@ func runDevelop(cmd *cobra.Command, args []string) error {
 
 	// 6. Initialize Executor and Monitor
 	exec := executor.NewExecutor(store, prov, modelName)
+	exec.SetBudgetCheck(func() (string, error) {
+		return costEstimator.EnforceBudget(projectID, cfgMgr.GetBudgetPolicy())
+	})
 	mon := executor.NewMonitor(exec, projectID)
 
 	// 7. Start Execution
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mojomast/geoffrussy/internal/config"
+	"github.com/mojomast/geoffrussy/internal/provider"
+	"github.com/mojomast/geoffrussy/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// providerHistoryKeep is how many rate limit and quota checks doctor keeps
+// per provider
+const providerHistoryKeep = 10
+
+var doctorRepair bool
+
+// doctorDatabase is a state database doctor checks
+type doctorDatabase struct {
+	label string
+	path  string
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check and repair the local installation",
+	Long: `Check that the state database is healthy, look for orphaned rows left
+behind by crashes or manual edits, and ping every configured provider.
+
+Nothing is changed unless --repair is given, which fixes orphaned rows and
+prunes old rate limit and quota history.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorRepair, "repair", false, "Repair orphaned rows and prune old provider history")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfgMgr := config.NewManager()
+	if err := cfgMgr.Load(nil); err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	cfg := cfgMgr.GetConfig()
+
+	fmt.Println("🩺 Geoffrussy Doctor")
+	fmt.Println("============================================================")
+
+	// Provider history lives in the global database, while phases, tasks,
+	// blockers and token usage are written to the project's own database
+	databases := []doctorDatabase{
+		{"Database", filepath.Join(filepath.Dir(cfg.ConfigPath), "geoffrussy.db")},
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		projectDB := filepath.Join(cwd, ".geoffrussy", "state.db")
+		if _, err := os.Stat(projectDB); err == nil {
+			databases = append(databases, doctorDatabase{"Project database", projectDB})
+		}
+	}
+
+	healthy := true
+	for _, db := range databases {
+		if !checkDatabase(db.label, db.path, doctorRepair) {
+			healthy = false
+		}
+	}
+
+	providers := make(map[string]provider.Provider)
+	for _, name := range getConfiguredProviders(cfg) {
+		p, err := provider.CreateProvider(name)
+		if err != nil {
+			healthy = false
+			fmt.Printf("❌ Provider %s: %v\n", name, err)
+			continue
+		}
+		apiKey, _ := cfgMgr.GetAPIKey(name)
+		if err := p.Authenticate(apiKey); err != nil {
+			healthy = false
+			fmt.Printf("❌ Provider %s: %v\n", name, err)
+			continue
+		}
+		providers[name] = p
+	}
+
+	results := provider.PingAll(providers)
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := results[name]; err != nil {
+			healthy = false
+			fmt.Printf("❌ Provider %s: %v\n", name, err)
+		} else {
+			fmt.Printf("✅ Provider %s: reachable\n", name)
+		}
+	}
+
+	fmt.Println()
+	if !healthy {
+		return fmt.Errorf("doctor found problems")
+	}
+	fmt.Println("All checks passed.")
+	return nil
+}
+
+// checkDatabase runs the health and orphaned-row checks on one database,
+// repairing it and pruning provider history when repair is set, and reports
+// whether it is healthy
+func checkDatabase(label, dbPath string, repair bool) bool {
+	store, err := state.OpenStore(dbPath)
+	if err != nil {
+		fmt.Printf("❌ %s: failed to open %s: %v\n", label, dbPath, err)
+		return false
+	}
+	defer store.Close()
+
+	healthy := true
+
+	if err := store.HealthCheck(); err != nil {
+		healthy = false
+		fmt.Printf("❌ %s: %v\n", label, err)
+	} else {
+		fmt.Printf("✅ %s: healthy (%s)\n", label, dbPath)
+	}
+
+	if repair {
+		report, err := store.RepairOrphans()
+		if err != nil {
+			healthy = false
+			fmt.Printf("❌ %s orphaned rows: %v\n", label, err)
+		} else if report.Total() == 0 {
+			fmt.Printf("✅ %s orphaned rows: none found\n", label)
+		} else {
+			fmt.Printf("🔧 %s orphaned rows: repaired %d\n", label, report.Total())
+			printRepairReport(report)
+		}
+
+		if err := store.PruneRateLimitHistory(providerHistoryKeep); err != nil {
+			healthy = false
+			fmt.Printf("❌ %s provider history: %v\n", label, err)
+		} else if err := store.PruneQuotaHistory(providerHistoryKeep); err != nil {
+			healthy = false
+			fmt.Printf("❌ %s provider history: %v\n", label, err)
+		} else {
+			fmt.Printf("✅ %s provider history: kept the latest %d checks per provider\n", label, providerHistoryKeep)
+		}
+		return healthy
+	}
+
+	report, err := store.FindOrphans()
+	if err != nil {
+		healthy = false
+		fmt.Printf("❌ %s orphaned rows: %v\n", label, err)
+	} else if report.Total() == 0 {
+		fmt.Printf("✅ %s orphaned rows: none found\n", label)
+	} else {
+		healthy = false
+		fmt.Printf("⚠️  %s orphaned rows: found %d (run with --repair to fix them)\n", label, report.Total())
+		printRepairReport(report)
+	}
+	return healthy
+}
+
+// printRepairReport lists the orphaned rows in a repair report by kind
+func printRepairReport(report *state.RepairReport) {
+	fmt.Printf("   - Token usage phase references: %d\n", report.TokenUsagePhaseRefs)
+	fmt.Printf("   - Token usage task references:  %d\n", report.TokenUsageTaskRefs)
+	fmt.Printf("   - Dangling blockers:            %d\n", report.DanglingBlockers)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/mojomast/geoffrussy/internal/state"
+)
+
+func TestCheckDatabase_ProjectOrphans(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), ".geoffrussy", "state.db")
+	store, err := state.NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	project := &state.Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: state.StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	store.Close()
+
+	// Foreign keys are off on a plain connection, as in older databases
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO blockers (id, task_id, description, created_at)
+		VALUES ('blocker-gone', 'task-gone', 'Stale blocker', CURRENT_TIMESTAMP)`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to insert orphan: %v", err)
+	}
+
+	var healthy bool
+	output := captureOutput(func() { healthy = checkDatabase("Project database", dbPath, false) })
+	if healthy || !strings.Contains(output, "found 1") {
+		t.Errorf("Expected the orphaned blocker to be reported, got:\n%s", output)
+	}
+
+	output = captureOutput(func() { healthy = checkDatabase("Project database", dbPath, true) })
+	if !healthy || !strings.Contains(output, "repaired 1") {
+		t.Errorf("Expected the orphaned blocker to be repaired, got:\n%s", output)
+	}
+
+	output = captureOutput(func() { healthy = checkDatabase("Project database", dbPath, false) })
+	if !healthy || !strings.Contains(output, "none found") {
+		t.Errorf("Expected no orphans after repair, got:\n%s", output)
+	}
+}
@@ -74,7 +74,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	// Initialize database
 	dbPath := filepath.Join(cwd, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
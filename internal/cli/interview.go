@@ -2,9 +2,12 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/mojomast/geoffrussy/internal/config"
@@ -12,11 +15,18 @@ import (
 	"github.com/mojomast/geoffrussy/internal/provider"
 	"github.com/mojomast/geoffrussy/internal/state"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var (
-	interviewResume bool
-	interviewModel  string
+	interviewResume   bool
+	interviewModel    string
+	interviewMaxLen   int
+	interviewLang     string
+	interviewAnswers  string
+	interviewDefaults bool
+	interviewRestart  bool
+	interviewVars     map[string]string
 )
 
 var interviewCmd = &cobra.Command{
@@ -30,7 +40,13 @@ a structured five-phase process.`,
 
 func init() {
 	interviewCmd.Flags().BoolVar(&interviewResume, "resume", false, "Resume existing interview")
+	interviewCmd.Flags().BoolVar(&interviewRestart, "restart", false, "Start the interview over, keeping previous answers as defaults")
 	interviewCmd.Flags().StringVar(&interviewModel, "model", "", "Model to use for interview")
+	interviewCmd.Flags().IntVar(&interviewMaxLen, "max-answer-length", interview.DefaultMaxAnswerLength, "Maximum characters per answer (0 for no limit)")
+	interviewCmd.Flags().StringVar(&interviewAnswers, "answers", "", "YAML or JSON file of answers keyed by question ID, recorded before asking the rest")
+	interviewCmd.Flags().StringToStringVar(&interviewVars, "var", nil, "Fill {{name}} placeholders in the answers file, as name=value (repeatable)")
+	interviewCmd.Flags().BoolVar(&interviewDefaults, "accept-defaults", false, "Answer optional questions with their proposed defaults instead of asking")
+	interviewCmd.Flags().StringVar(&interviewLang, "language", interview.DefaultLanguage, fmt.Sprintf("Language of interview questions (%s)", strings.Join(interview.SupportedLanguages(), ", ")))
 }
 
 func runInterview(cmd *cobra.Command, args []string) error {
@@ -52,7 +68,7 @@ func runInterview(cmd *cobra.Command, args []string) error {
 
 	// Use of same database location as init command
 	dbPath := filepath.Join(cwd, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open state store: %w", err)
 	}
@@ -63,7 +79,7 @@ func runInterview(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("project not found. Please run 'geoffrussy init' first: %w", err)
 	}
 
-	providerName, modelName, err := getProviderAndModel(cfgMgr, "interview", interviewModel)
+	providerName, modelName, err := selectProviderWithinBudget(cfgMgr, store, projectID, "interview", interviewModel)
 	if err != nil {
 		fmt.Println("\n⚠️  Could not automatically select provider and model")
 		fmt.Println("   Available options:")
@@ -86,8 +102,12 @@ func runInterview(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get provider: %w", err)
 	}
+	prov = capProviderSpend(prov, cfgMgr, store, projectID)
 
 	engine := interview.NewEngine(store, prov, modelName)
+	engine.SetMaxAnswerLength(interviewMaxLen)
+	engine.SetLanguage(interviewLang)
+	engine.SetStreaming(cmd.Context(), nil)
 
 	var session *interview.InterviewSession
 
@@ -97,6 +117,15 @@ func runInterview(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to resume interview: %w", err)
 		}
+		if session.LoadWarning != "" {
+			fmt.Printf("⚠️  %s\n", session.LoadWarning)
+		}
+	} else if interviewRestart {
+		fmt.Println("🔁 Restarting interview, previous answers are kept as defaults...")
+		session, err = engine.RestartInterview(projectID)
+		if err != nil {
+			return fmt.Errorf("failed to restart interview: %w", err)
+		}
 	} else {
 		fmt.Println("🆕 Starting new interview session...")
 		session, err = engine.StartInterview(projectID)
@@ -105,19 +134,63 @@ func runInterview(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if interviewAnswers != "" {
+		if err := recordAnswersFile(engine, session, interviewAnswers); err != nil {
+			return err
+		}
+	}
+
+	if interviewDefaults {
+		filled, err := engine.AutoFillOptional(session)
+		if err != nil {
+			return fmt.Errorf("failed to fill optional questions: %w", err)
+		}
+		if err := engine.SaveSession(session); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+		fmt.Printf("📥 Filled %d optional questions with proposed defaults\n", len(filled))
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
 	for {
+		previousPhase := session.CurrentPhase
 		question, err := engine.GetNextQuestion(session)
 		if err != nil {
 			return fmt.Errorf("failed to get next question: %w", err)
 		}
 
+		// Show a recap whenever a phase boundary is crossed
+		if question == nil || session.CurrentPhase != previousPhase {
+			if recap, ok := session.PhaseSummaries[previousPhase]; ok {
+				fmt.Printf("\n📝 Recap: %s\n", recap)
+			}
+		}
+
 		if question == nil {
 			complete, missing := engine.ValidateCompleteness(session)
 			if complete {
+				if err := offerMissingTopics(engine, session, reader); err != nil {
+					return err
+				}
+				// The glossary and pitch are niceties for later prompts, so a
+				// failed call doesn't stop the interview from completing
+				if _, err := engine.ExtractGlossary(session); err != nil {
+					fmt.Printf("⚠️  Could not build glossary: %v\n", err)
+				}
+				pitch, err := engine.GenerateElevatorPitch(session)
+				if err != nil {
+					fmt.Printf("⚠️  Could not generate elevator pitch: %v\n", err)
+				}
+				if err := engine.CompleteInterview(session); err != nil {
+					return fmt.Errorf("failed to complete interview: %w", err)
+				}
+
 				fmt.Println("════════════════════════════════════════════════════════")
 				fmt.Println("✅ Interview completed successfully!")
+				if pitch != "" {
+					fmt.Printf("\n🎤 %s\n", pitch)
+				}
 
 				summary, err := engine.GenerateSummary(session)
 				if err != nil {
@@ -141,20 +214,83 @@ func runInterview(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
+		// Questions answered from the answers file or with defaults aren't
+		// asked again
+		if _, answered := session.Answers[question.ID]; answered && (interviewAnswers != "" || interviewDefaults) {
+			session.CurrentQuestion++
+			continue
+		}
+
 		fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 		fmt.Printf("Phase %s - Question %d\n", session.CurrentPhase, session.CurrentQuestion)
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 		fmt.Printf("\n%s\n\n", question.Text)
 
-		fmt.Printf("Your answer (or 'help' for suggestions, 'back' to go back): ")
+		previous, hasPrevious := engine.PreviousAnswer(session, question.ID)
+		if hasPrevious {
+			fmt.Printf("Previous answer: %s\n(press Enter to keep it)\n\n", previous)
+		}
+
+		if question.ID == successMetricsQuestionID {
+			fmt.Println("Not sure what to measure? Type 'suggest' to pick from suggested metrics.")
+		}
+		fmt.Printf("Your answer (or '?' to explain the question, 'help' for suggestions, 'default' for a proposed answer, 'back' to go back, 'undo' to remove your last answer): ")
 		answer, _ := reader.ReadString('\n')
 		answer = strings.TrimSpace(answer)
+		if answer == "" && hasPrevious {
+			answer = previous
+		}
+
+		if answer == "suggest" && question.ID == successMetricsQuestionID {
+			answer = pickSuccessMetrics(engine, session, reader)
+			if answer == "" {
+				continue
+			}
+		}
+
+		if answer == "default" {
+			recorded, err := answerWithProposal(engine, session, question, reader)
+			if err != nil {
+				return err
+			}
+			if recorded {
+				fmt.Println("✅ Answer saved!")
+			}
+			continue
+		}
 
 		if answer == "back" {
 			fmt.Println("⏮️  Going to previous question...")
 			continue
 		}
 
+		if answer == "undo" {
+			if err := engine.UndoLastAnswer(session); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				continue
+			}
+			if err := engine.SaveSession(session); err != nil {
+				return fmt.Errorf("failed to save session: %w", err)
+			}
+			fmt.Println("↩️  Last answer removed")
+			continue
+		}
+
+		if answer == "?" {
+			if question.HelpText == "" && question.Example == "" {
+				fmt.Println("\nNo help is available for this question.")
+			} else {
+				if question.HelpText != "" {
+					fmt.Printf("\n❓ %s\n", question.HelpText)
+				}
+				if question.Example != "" {
+					fmt.Printf("   Example: %s\n", question.Example)
+				}
+			}
+			fmt.Println()
+			continue
+		}
+
 		if answer == "help" {
 			fmt.Println("\n💡 Suggestions:")
 			fmt.Println("   - Be specific about your problem")
@@ -166,6 +302,10 @@ func runInterview(cmd *cobra.Command, args []string) error {
 		}
 
 		if err := engine.RecordAnswer(session, question.ID, answer); err != nil {
+			if errors.Is(err, interview.ErrAnswerTooLong) {
+				fmt.Printf("⚠️  %v. Please trim your answer and try again.\n", err)
+				continue
+			}
 			return fmt.Errorf("failed to record answer: %w", err)
 		}
 
@@ -176,3 +316,171 @@ func runInterview(cmd *cobra.Command, args []string) error {
 		fmt.Println("✅ Answer saved!")
 	}
 }
+
+// successMetricsQuestionID is the question that offers suggested metrics
+const successMetricsQuestionID = "pe_3"
+
+// pickSuccessMetrics lists suggested success metrics and returns the ones the
+// user picks by number, one per line, or "" if none are picked or suggestions
+// can't be made
+func pickSuccessMetrics(engine *interview.Engine, session *interview.InterviewSession, reader *bufio.Reader) string {
+	metrics, err := engine.SuggestSuccessMetrics(session)
+	if err != nil {
+		fmt.Printf("⚠️  Could not suggest metrics: %v\n", err)
+		return ""
+	}
+
+	fmt.Println("\n📏 Suggested success metrics:")
+	for i, metric := range metrics {
+		fmt.Printf("   %d. %s\n", i+1, metric)
+	}
+	fmt.Print("Pick metrics by number, separated by commas (Enter to answer yourself): ")
+	reply, _ := reader.ReadString('\n')
+
+	var picked []string
+	seen := make(map[int]bool)
+	for _, field := range strings.Split(reply, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n < 1 || n > len(metrics) || seen[n] {
+			continue
+		}
+		seen[n] = true
+		picked = append(picked, metrics[n-1])
+	}
+	return strings.Join(picked, "\n")
+}
+
+// answerWithProposal proposes a default answer for question and records
+// the user's reply through AcceptProposal: Enter accepts the proposal and
+// anything else rejects it in favor of the reply. Returns false when no
+// answer was recorded.
+func answerWithProposal(engine *interview.Engine, session *interview.InterviewSession, question *interview.Question, reader *bufio.Reader) (bool, error) {
+	proposal, err := engine.ProposeDefault(*question)
+	if err != nil {
+		fmt.Printf("⚠️  Could not propose an answer: %v\n", err)
+		return false, nil
+	}
+	if proposal == "" {
+		fmt.Println("No proposed answer is available for this question.")
+		return false, nil
+	}
+
+	fmt.Printf("\n💡 Proposed: %s\n", proposal)
+	fmt.Print("Press Enter to accept it, or type your own answer: ")
+	reply, _ := reader.ReadString('\n')
+
+	if err := engine.AcceptProposal(session, question.ID, proposal, reply); err != nil {
+		if errors.Is(err, interview.ErrAnswerTooLong) {
+			fmt.Printf("⚠️  %v. Please trim your answer and try again.\n", err)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to record answer: %w", err)
+	}
+	if err := engine.SaveSession(session); err != nil {
+		return false, fmt.Errorf("failed to save session: %w", err)
+	}
+	return true, nil
+}
+
+// missingTopicsSubInterview is the sub-interview topic for answers to
+// suggested missing topics, so they are only offered once per session
+const missingTopicsSubInterview = "missing topics"
+
+// offerMissingTopics shows topics the interview never touched and, if the
+// user agrees, asks about each in a sub-interview. An empty answer leaves a
+// topic out of scope. Failing to get or ask about suggestions only prints
+// a warning.
+func offerMissingTopics(engine *interview.Engine, session *interview.InterviewSession, reader *bufio.Reader) error {
+	for _, sub := range session.SubInterviews {
+		if sub.Topic == missingTopicsSubInterview {
+			return nil
+		}
+	}
+
+	topics, err := engine.SuggestMissingTopics(session)
+	if err != nil {
+		fmt.Printf("⚠️  Could not check for missing topics: %v\n", err)
+		return nil
+	}
+	if len(topics) == 0 {
+		return nil
+	}
+
+	fmt.Println("\n🔎 The interview didn't cover:")
+	for _, topic := range topics {
+		fmt.Printf("   - %s\n", topic)
+	}
+	fmt.Print("Answer questions about these now? [y/N]: ")
+	reply, _ := reader.ReadString('\n')
+	if !strings.EqualFold(strings.TrimSpace(reply), "y") {
+		return nil
+	}
+
+	if err := engine.StartSubInterview(session, missingTopicsSubInterview, interview.MissingTopicQuestions(topics)); err != nil {
+		fmt.Printf("⚠️  Could not ask about missing topics: %v\n", err)
+		return nil
+	}
+	for question := engine.GetNextSubQuestion(session); question != nil; question = engine.GetNextSubQuestion(session) {
+		fmt.Printf("\n%s\n(press Enter if it's out of scope): ", question.Text)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			answer = "Out of scope"
+		}
+		if err := engine.RecordSubAnswer(session, question.ID, answer); err != nil {
+			if errors.Is(err, interview.ErrAnswerTooLong) {
+				fmt.Printf("⚠️  %v. Please trim your answer and try again.\n", err)
+				continue
+			}
+			return fmt.Errorf("failed to record answer: %w", err)
+		}
+	}
+
+	if err := engine.SaveSession(session); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// recordAnswersFile records the answers in a YAML or JSON file of question
+// IDs to answers, reports which were rejected and saves the session
+func recordAnswersFile(engine *interview.Engine, session *interview.InterviewSession, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read answers file: %w", err)
+	}
+	var answers map[string]string
+	if err := yaml.Unmarshal(data, &answers); err != nil {
+		return fmt.Errorf("failed to parse answers file: %w", err)
+	}
+
+	report, err := engine.RecordAnswers(session, answers)
+	if err != nil {
+		return fmt.Errorf("failed to record answers: %w", err)
+	}
+	if len(interviewVars) > 0 && len(report.Accepted) > 0 {
+		if err := engine.ApplyAnswerVariables(session, interviewVars, report.Accepted...); err != nil {
+			return fmt.Errorf("failed to fill answer variables: %w", err)
+		}
+	}
+	if err := engine.SaveSession(session); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	fmt.Printf("📥 Recorded %d answers from %s\n", len(report.Accepted), path)
+	if len(report.Rejected) > 0 {
+		ids := make([]string, 0, len(report.Rejected))
+		for id := range report.Rejected {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		fmt.Println("⚠️  Rejected answers:")
+		for _, id := range ids {
+			fmt.Printf("   - %s: %s\n", id, report.Rejected[id])
+		}
+	}
+	if len(report.RemainingRequired) > 0 {
+		fmt.Printf("📋 %d required questions remain\n", len(report.RemainingRequired))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mojomast/geoffrussy/internal/interview"
+	"github.com/mojomast/geoffrussy/internal/state"
+)
+
+func TestAnswerWithProposal(t *testing.T) {
+	store, err := state.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.CreateProject(&state.Project{ID: "proj-1", Name: "Test", CreatedAt: time.Now(), CurrentStage: state.StageInterview}); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	engine := interview.NewEngine(store, nil, "")
+	session, err := engine.StartInterview("proj-1")
+	if err != nil {
+		t.Fatalf("failed to start interview: %v", err)
+	}
+
+	// Enter accepts the proposal
+	accepted := &interview.Question{ID: "ip_2", Text: "Which database?"}
+	recorded, err := answerWithProposal(engine, session, accepted, bufio.NewReader(strings.NewReader("\n")))
+	if err != nil || !recorded {
+		t.Fatalf("expected the proposal to be recorded, got %v, %v", recorded, err)
+	}
+	if got := session.Answers["ip_2"].Text; got != "PostgreSQL" {
+		t.Errorf("expected the accepted proposal as the answer, got %q", got)
+	}
+
+	// A typed reply rejects it
+	rejected := &interview.Question{ID: "ip_3", Text: "How do users sign in?"}
+	recorded, err = answerWithProposal(engine, session, rejected, bufio.NewReader(strings.NewReader("Magic links sent by email\n")))
+	if err != nil || !recorded {
+		t.Fatalf("expected the reply to be recorded, got %v, %v", recorded, err)
+	}
+	if got := session.Answers["ip_3"].Text; got != "Magic links sent by email" {
+		t.Errorf("expected the typed reply as the answer, got %q", got)
+	}
+	if divergent := engine.DivergentDefaults(session); len(divergent) != 1 || divergent[0] != "ip_3" {
+		t.Errorf("expected only the rejected proposal to diverge, got %v", divergent)
+	}
+
+	// Questions without a proposal record nothing
+	none := &interview.Question{ID: "pe_1", Text: "What problem are you solving?"}
+	if recorded, err := answerWithProposal(engine, session, none, bufio.NewReader(strings.NewReader("\n"))); err != nil || recorded {
+		t.Errorf("expected nothing recorded without a proposal, got %v, %v", recorded, err)
+	}
+}
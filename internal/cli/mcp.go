@@ -61,7 +61,7 @@ func runMCPServer(cmd *cobra.Command, args []string) error {
 
 	// Initialize database (create if doesn't exist)
 	dbPath := filepath.Join(projectPath, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to initialize state store: %w", err)
 	}
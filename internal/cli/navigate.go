@@ -61,7 +61,7 @@ func runNavigate(cmd *cobra.Command, args []string) error {
 	// Initialize state store
 	configDir := filepath.Dir(cfg.ConfigPath)
 	dbPath := filepath.Join(configDir, "geoffrussy.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to initialize state store: %w", err)
 	}
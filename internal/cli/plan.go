@@ -17,10 +17,14 @@ import (
 )
 
 var (
-	planModel   string
-	planMerge   string
-	planSplit   string
-	planReorder bool
+	planModel    string
+	planMerge    string
+	planSplit    string
+	planReorder  bool
+	planRollback bool
+	planMaxTasks int
+	planDedupe   bool
+	planSplitBig bool
 )
 
 var planCmd = &cobra.Command{
@@ -36,6 +40,10 @@ func init() {
 	planCmd.Flags().StringVar(&planMerge, "merge", "", "Merge phases (format: 1,2)")
 	planCmd.Flags().StringVar(&planSplit, "split", "", "Split phase (format: 1:3 - split phase 1 at task 3)")
 	planCmd.Flags().BoolVar(&planReorder, "reorder", false, "Reorder phases interactively")
+	planCmd.Flags().BoolVar(&planRollback, "rollback", false, "Include a rollback plan for each generated phase")
+	planCmd.Flags().BoolVar(&planDedupe, "dedupe", false, "Merge tasks repeated across phases into their earliest occurrence")
+	planCmd.Flags().IntVar(&planMaxTasks, "max-phase-tasks", devplan.DefaultMaxPhaseTasks, "Warn about generated phases with more tasks than this (0 to disable)")
+	planCmd.Flags().BoolVar(&planSplitBig, "split-oversized", false, "Split generated phases with more tasks than --max-phase-tasks")
 }
 
 func runPlan(cmd *cobra.Command, args []string) error {
@@ -54,7 +62,7 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	projectID := filepath.Base(cwd)
 
 	dbPath := filepath.Join(cwd, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open state store: %w", err)
 	}
@@ -100,7 +108,7 @@ func handlePlanGeneration(store *state.Store, cfgMgr *config.Manager, projectID
 	}
 
 	// Setup provider
-	prov, modelName, err := setupPlanProvider(cfgMgr, planModel)
+	prov, modelName, err := setupPlanProvider(cfgMgr, store, projectID, planModel)
 	if err != nil {
 		return err
 	}
@@ -114,6 +122,37 @@ func handlePlanGeneration(store *state.Store, cfgMgr *config.Manager, projectID
 	}
 
 	fmt.Printf("   Generated %d phases.\n", len(phases))
+	for _, warning := range generator.Warnings() {
+		fmt.Printf("   ⚠️  %s\n", warning)
+	}
+	if planDedupe {
+		plan := &devplan.DevPlan{Phases: phases}
+		if removed := generator.DeduplicateTasks(plan, true); removed > 0 {
+			fmt.Printf("   Merged %d repeated tasks.\n", removed)
+		}
+		phases = plan.Phases
+	}
+	if warnings := generator.PhaseSizeWarnings(phases, planMaxTasks); len(warnings) > 0 {
+		fmt.Println("   ⚠️  Some phases are too large:")
+		for _, warning := range warnings {
+			fmt.Printf("      - %s\n", warning)
+		}
+		if planSplitBig {
+			phases, err = generator.SplitOversizedByTasks(phases, planMaxTasks)
+			if err != nil {
+				return fmt.Errorf("failed to split oversized phases: %w", err)
+			}
+			fmt.Printf("   Split into %d phases.\n", len(phases))
+		} else {
+			fmt.Println("   Run with --split-oversized to split them automatically.")
+		}
+	}
+	if planRollback {
+		if err := generator.GenerateRollbackPlans(phases); err != nil {
+			return fmt.Errorf("failed to generate rollback plans: %w", err)
+		}
+	}
+	suggestCheaperPlanModel(generator, prov, modelName, phases)
 
 	// Save phases
 	for i := range phases {
@@ -413,8 +452,29 @@ func saveAllPhases(store *state.Store, generator *devplan.Generator, phases []de
 
 // Helpers
 
-func setupPlanProvider(cfgMgr *config.Manager, model string) (provider.Provider, string, error) {
-	providerName, modelName, err := getProviderAndModel(cfgMgr, "plan", model)
+// suggestCheaperPlanModel prints a hint when another model from the same
+// provider could run the plan for less. Pricing is best effort, so a failed
+// model lookup prints nothing.
+func suggestCheaperPlanModel(generator *devplan.Generator, prov provider.Provider, modelName string, phases []devplan.Phase) {
+	models, err := prov.ListModels()
+	if err != nil || len(models) == 0 {
+		return
+	}
+
+	pricing := devplan.PricingFromModels(models)
+	candidates := make([]string, 0, len(pricing))
+	for name := range pricing {
+		candidates = append(candidates, name)
+	}
+
+	costs := generator.CompareModelCosts(&devplan.DevPlan{Phases: phases}, pricing, candidates)
+	if suggestion := devplan.SuggestCheaperModel(costs, modelName); suggestion != "" {
+		fmt.Printf("   💡 Cost tip: %s\n", suggestion)
+	}
+}
+
+func setupPlanProvider(cfgMgr *config.Manager, store *state.Store, projectID, model string) (provider.Provider, string, error) {
+	providerName, modelName, err := selectProviderWithinBudget(cfgMgr, store, projectID, "plan", model)
 	if err != nil {
 		return nil, "", err
 	}
@@ -429,7 +489,7 @@ func setupPlanProvider(cfgMgr *config.Manager, model string) (provider.Provider,
 		return nil, "", err
 	}
 
-	return prov, modelName, nil
+	return capProviderSpend(prov, cfgMgr, store, projectID), modelName, nil
 }
 
 // convertStatePhasesToDevplan converts state phases to devplan phases
@@ -460,6 +520,21 @@ func convertStatePhasesToDevplan(store *state.Store, statePhases []*state.Phase)
 			phases[i].CreatedAt = sp.CreatedAt
 		}
 
+		// Criteria checks aren't part of the markdown, so they come from the DB
+		criteria, err := store.ListSuccessCriteria(sp.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list success criteria for phase %s: %w", sp.ID, err)
+		}
+		for _, criterion := range criteria {
+			if criterion.Check == "" {
+				continue
+			}
+			if phases[i].CriteriaChecks == nil {
+				phases[i].CriteriaChecks = make(map[string]string)
+			}
+			phases[i].CriteriaChecks[criterion.Text] = criterion.Check
+		}
+
 		// Load tasks from DB to get their IDs and Status (source of truth)
 		dbTasks, err := store.ListTasks(sp.ID)
 		if err != nil {
@@ -479,6 +554,23 @@ func convertStatePhasesToDevplan(store *state.Store, statePhases []*state.Phase)
 			if dbTask, exists := dbTaskMap[t.Number]; exists {
 				phases[i].Tasks[j].ID = dbTask.ID
 				phases[i].Tasks[j].Status = devplan.TaskStatus(dbTask.Status)
+				// Token estimates aren't part of the markdown
+				if phases[i].Tasks[j].Difficulty == "" {
+					phases[i].Tasks[j].Difficulty = dbTask.Difficulty
+				}
+				if phases[i].Tasks[j].EstimatedTokens == 0 {
+					phases[i].Tasks[j].EstimatedTokens = dbTask.EstimatedTokens
+				}
+
+				notes, err := store.GetTaskNotes(dbTask.ID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get notes for task %s: %w", dbTask.ID, err)
+				}
+				phases[i].Tasks[j].ProgressNotes = nil
+				for _, note := range notes {
+					phases[i].Tasks[j].ProgressNotes = append(phases[i].Tasks[j].ProgressNotes,
+						fmt.Sprintf("%s: %s", note.CreatedAt.Format("2006-01-02 15:04"), note.Note))
+				}
 			}
 		}
 	}
@@ -502,6 +594,9 @@ func convertDevPlanToState(g *devplan.Generator, phase *devplan.Phase, projectID
 		Content:   content,
 		Status:    state.PhaseStatus(phase.Status),
 		CreatedAt: phase.CreatedAt,
+
+		SuccessCriteria: append([]string{}, phase.SuccessCriteria...),
+		CriteriaChecks:  phase.CriteriaChecks,
 	}
 	
 	var stateTasks []*state.Task
@@ -522,6 +617,10 @@ func convertDevPlanToState(g *devplan.Generator, phase *devplan.Phase, projectID
 			Number:      t.Number,
 			Description: t.Description,
 			Status:      state.TaskStatus(t.Status),
+			DependsOn:   t.DependsOn,
+
+			Difficulty:      t.Difficulty,
+			EstimatedTokens: t.EstimatedTokens,
 		}
 		stateTasks = append(stateTasks, stateTask)
 	}
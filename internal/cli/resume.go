@@ -10,6 +10,7 @@ import (
 	"github.com/mojomast/geoffrussy/internal/git"
 	"github.com/mojomast/geoffrussy/internal/resume"
 	"github.com/mojomast/geoffrussy/internal/state"
+	"github.com/mojomast/geoffrussy/internal/token"
 	"github.com/spf13/cobra"
 )
 
@@ -65,12 +66,26 @@ func runResume(cmd *cobra.Command, args []string) error {
 	// Initialize state store (use config directory)
 	configDir := filepath.Dir(cfg.ConfigPath)
 	dbPath := filepath.Join(configDir, "geoffrussy.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to initialize state store: %w", err)
 	}
 	defer store.Close()
 
+	// Resuming lifts any budget hold on the project, but not while the
+	// budget is still exceeded
+	costEstimator := token.NewCostEstimator(store)
+	costEstimator.SetBudgetLimit(cfg.BudgetLimit)
+	if _, err := costEstimator.CheckBudget(projectID); err != nil && cfgMgr.GetBudgetPolicy() != config.BudgetPolicyWarn {
+		return fmt.Errorf("cannot resume: %w. Raise the budget limit first", err)
+	}
+	if paused, reason, err := store.IsPaused(projectID); err == nil && paused {
+		if err := store.SetProjectPaused(projectID, false, ""); err != nil {
+			return fmt.Errorf("failed to unpause project: %w", err)
+		}
+		fmt.Printf("▶️  Lifted pause: %s\n", reason)
+	}
+
 	// Initialize git manager
 	gitMgr := git.NewManager(".")
 
@@ -50,7 +50,7 @@ func runReview(cmd *cobra.Command, args []string) error {
 
 	// Use the same database location as init command
 	dbPath := filepath.Join(cwd, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open state store: %w", err)
 	}
@@ -78,7 +78,7 @@ func runReview(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to convert phases: %w", err)
 	}
 
-	providerName, modelName, err := getProviderAndModel(cfgMgr, "review", reviewModel)
+	providerName, modelName, err := selectProviderWithinBudget(cfgMgr, store, projectID, "review", reviewModel)
 	if err != nil {
 		return fmt.Errorf("failed to get provider and model: %w", err)
 	}
@@ -92,6 +92,7 @@ func runReview(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get provider: %w", err)
 	}
+	prov = capProviderSpend(prov, cfgMgr, store, projectID)
 
 	rev := reviewer.NewReviewer(prov, modelName)
 	report, err := rev.ReviewAllPhases(devplanPhases)
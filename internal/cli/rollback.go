@@ -41,7 +41,7 @@ func runRollback(cmd *cobra.Command, args []string) error {
 
 	// Initialize store (local)
 	dbPath := filepath.Join(cwd, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open state store: %w. Make sure you are in a project directory.", err)
 	}
@@ -14,16 +14,20 @@ import (
 )
 
 var (
-	version string
-	cfgFile string
-	verbose bool
-	rootCmd *cobra.Command
+	version  string
+	cfgFile  string
+	verbose  bool
+	profile  string
+	debugLLM bool
+	rootCmd  *cobra.Command
 )
 
 // Execute runs the root command
 func Execute(ver string) error {
 	version = ver
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	printLLMDebug()
+	return err
 }
 
 func init() {
@@ -40,6 +44,11 @@ pipeline: Interview → Architecture Design → DevPlan Generation → Phase Rev
 		Version: version,
 		RunE:    runRootWithResumeCheck,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// Config is loaded per command, so hand the profile over via the environment
+			if profile != "" {
+				os.Setenv("GEOFFRUSSY_PROFILE", profile)
+			}
+
 			// Don't print banner for help commands
 			if !argsContains(args, "--help") && !argsContains(args, "-h") {
 				fmt.Print(Banner())
@@ -51,6 +60,8 @@ pipeline: Interview → Architecture Design → DevPlan Generation → Phase Rev
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.geoffrussy/config.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "config profile to use (e.g. work, personal)")
+	rootCmd.PersistentFlags().BoolVar(&debugLLM, "debug-llm", false, "print the raw body of the last LLM request and response, with API keys redacted")
 
 	// Add subcommands
 	rootCmd.AddCommand(initCmd)
@@ -62,7 +73,9 @@ pipeline: Interview → Architecture Design → DevPlan Generation → Phase Rev
 	rootCmd.AddCommand(reviewCmd)
 	rootCmd.AddCommand(developCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(tasksCmd)
 	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(quotaCmd)
 	rootCmd.AddCommand(checkpointCmd)
 	rootCmd.AddCommand(rollbackCmd)
@@ -109,7 +122,7 @@ func runRootWithResumeCheck(cmd *cobra.Command, args []string) error {
 	// Initialize state store (use config directory)
 	configDir := filepath.Dir(cfg.ConfigPath)
 	dbPath := filepath.Join(configDir, "geoffrussy.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		// If state store doesn't exist, show help
 		return cmd.Help()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"text/tabwriter"
 
 	"github.com/mojomast/geoffrussy/internal/config"
@@ -13,13 +14,22 @@ import (
 )
 
 var statsCmd = &cobra.Command{
-	Use:   "stats",
-	Short: "Display token usage and cost statistics",
+	Use:     "stats",
+	Aliases: []string{"cost"},
+	Short:   "Display token usage and cost statistics",
 	Long: `Display detailed token usage and cost statistics broken down
-by provider and phase.`,
+by provider and phase.
+
+Use --csv to print one row per recorded LLM call, suitable for Excel.`,
 	RunE: runStats,
 }
 
+var statsCSV bool
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsCSV, "csv", false, "Print the cost report as CSV")
+}
+
 func runStats(cmd *cobra.Command, args []string) error {
 	// Try to load configuration
 	cfgMgr := config.NewManager()
@@ -38,12 +48,16 @@ func runStats(cmd *cobra.Command, args []string) error {
 	// Initialize state store (use config directory)
 	configDir := filepath.Dir(cfg.ConfigPath)
 	dbPath := filepath.Join(configDir, "geoffrussy.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to initialize store: %w", err)
 	}
 	defer store.Close()
 
+	if statsCSV {
+		return store.ExportCostCSV(projectID, os.Stdout)
+	}
+
 	// Initialize token counter and cost estimator
 	counter := token.NewCounter(store)
 	costEstimator := token.NewCostEstimator(store)
@@ -60,6 +74,12 @@ func runStats(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get cost statistics: %w", err)
 	}
 
+	// Get cost by stage
+	stageCosts, err := store.GetCostByStage(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get cost by stage: %w", err)
+	}
+
 	// Get most expensive calls
 	expensiveCalls, err := costEstimator.GetMostExpensiveCalls(projectID, 5)
 	if err != nil {
@@ -105,6 +125,27 @@ func runStats(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// Breakdown by Stage
+	if len(stageCosts) > 0 {
+		fmt.Println("🔷 Breakdown by Stage")
+		fmt.Println("--------------------")
+		fmt.Fprintln(w, "Stage\tCost")
+		stages := make([]string, 0, len(stageCosts))
+		for stage := range stageCosts {
+			stages = append(stages, stage)
+		}
+		sort.Strings(stages)
+		for _, stage := range stages {
+			cost := stageCosts[stage]
+			if stage == "" {
+				stage = "(unattributed)"
+			}
+			fmt.Fprintf(w, "%s\t$%.4f\n", stage, cost)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+
 	// Top Expensive Calls
 	if len(expensiveCalls) > 0 {
 		fmt.Println("🔷 Top 5 Most Expensive Calls")
@@ -41,7 +41,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	// Initialize state store
 	dbPath := filepath.Join(projectRoot, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to initialize state store: %w", err)
 	}
@@ -68,6 +68,13 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("🆔 ID: %s\n", projectID)
 	fmt.Printf("📅 Started: %s\n", project.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("🏗️  Current Stage: %s\n", formatStage(project.CurrentStage))
+	if history, err := store.GetStageHistory(projectID); err == nil && len(history) > 0 {
+		fmt.Println("🕒 Stage Timeline:")
+		for _, transition := range history {
+			fmt.Printf("     %s  %s → %s\n", transition.TransitionedAt.Format("2006-01-02 15:04:05"),
+				formatStage(transition.FromStage), formatStage(transition.ToStage))
+		}
+	}
 	fmt.Println()
 
 	// Calculate and display progress
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mojomast/geoffrussy/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks [project]",
+	Short: "List every task in the project",
+	Long: `List all tasks across every phase of a project as a flat to-do list,
+ordered by phase and task number. Defaults to the project in the current directory.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTasks,
+}
+
+func runTasks(cmd *cobra.Command, args []string) error {
+	// Get current directory as project root
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	// Initialize state store
+	dbPath := filepath.Join(projectRoot, ".geoffrussy", "state.db")
+	store, err := state.OpenStore(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to initialize state store: %w", err)
+	}
+	defer store.Close()
+
+	projectID := filepath.Base(projectRoot)
+	if len(args) > 0 {
+		projectID = args[0]
+	}
+
+	if _, err := store.GetProject(projectID); err != nil {
+		fmt.Println("⚠️  No project found:", projectID)
+		fmt.Println("   Run 'geoffrussy init' to initialize a new project")
+		return nil
+	}
+
+	tasks, err := store.ListAllTasks(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("No tasks found. Run 'geoffrussy plan' to generate a development plan.")
+		return nil
+	}
+
+	fmt.Println("📋 Tasks")
+	fmt.Println("============================================================")
+	currentPhase := -1
+	for _, task := range tasks {
+		if task.PhaseNumber != currentPhase {
+			currentPhase = task.PhaseNumber
+			fmt.Printf("\nPhase %d\n", currentPhase)
+		}
+		fmt.Printf("  %s %s: %s\n", getTaskStatusIcon(task.Status), task.Number, task.Description)
+	}
+
+	return nil
+}
+
+func getTaskStatusIcon(status state.TaskStatus) string {
+	switch status {
+	case state.TaskCompleted:
+		return "✅"
+	case state.TaskInProgress:
+		return "🔄"
+	case state.TaskBlocked:
+		return "🚫"
+	case state.TaskSkipped:
+		return "⏭️ "
+	default:
+		return "⬜"
+	}
+}
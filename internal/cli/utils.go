@@ -2,11 +2,14 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/mojomast/geoffrussy/internal/config"
 	"github.com/mojomast/geoffrussy/internal/provider"
+	"github.com/mojomast/geoffrussy/internal/state"
 )
 
 func formatDuration(d time.Duration) string {
@@ -100,6 +103,86 @@ func getProviderAndModel(cfgMgr *config.Manager, stage, overrideModel string) (s
 	return providerName, modelName, nil
 }
 
+// selectProviderWithinBudget picks a provider and model like
+// getProviderAndModel, then checks the provider's spend cap. Once a cap is
+// reached, another configured provider with a model for the stage and
+// budget left is used instead, unless the model was chosen explicitly.
+func selectProviderWithinBudget(cfgMgr *config.Manager, store *state.Store, projectID, stage, overrideModel string) (string, string, error) {
+	providerName, modelName, err := getProviderAndModel(cfgMgr, stage, overrideModel)
+	if err != nil {
+		return "", "", err
+	}
+
+	limit := cfgMgr.GetProviderBudget(providerName)
+	exceeded, err := store.CheckProviderBudget(projectID, providerName, limit)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to check provider budget: %w", err)
+	}
+	if !exceeded {
+		return providerName, modelName, nil
+	}
+	if overrideModel != "" {
+		return "", "", fmt.Errorf("spend cap of $%.2f reached for provider '%s'", limit, providerName)
+	}
+
+	cfg := cfgMgr.GetConfig()
+	alternatives := make([]string, 0, len(cfg.APIKeys))
+	for name := range cfg.APIKeys {
+		if name != providerName && stageModelForProvider(cfg, stage, name) != "" {
+			alternatives = append(alternatives, name)
+		}
+	}
+	sort.Strings(alternatives)
+
+	for _, name := range alternatives {
+		exceeded, err := store.CheckProviderBudget(projectID, name, cfgMgr.GetProviderBudget(name))
+		if err != nil {
+			return "", "", fmt.Errorf("failed to check provider budget: %w", err)
+		}
+		if !exceeded {
+			fmt.Printf("💰 Spend cap of $%.2f reached for %s, using %s instead\n", limit, providerName, name)
+			return name, stageModelForProvider(cfg, stage, name), nil
+		}
+	}
+	return "", "", fmt.Errorf("spend cap of $%.2f reached for provider '%s' and no other provider has budget left", limit, providerName)
+}
+
+// stageModelForProvider returns a model from providerName to use for stage:
+// the stage's default model when it belongs to the provider, otherwise
+// another stage's default or a favorite model that does. Returns "" when
+// none is configured.
+func stageModelForProvider(cfg *config.Config, stage, providerName string) string {
+	stages := make([]string, 0, len(cfg.DefaultModels))
+	for name := range cfg.DefaultModels {
+		if name != stage {
+			stages = append(stages, name)
+		}
+	}
+	sort.Strings(stages)
+
+	candidates := []string{cfg.DefaultModels[stage]}
+	for _, name := range stages {
+		candidates = append(candidates, cfg.DefaultModels[name])
+	}
+	candidates = append(candidates, cfg.FavoriteModels...)
+
+	for _, model := range candidates {
+		if model == "" {
+			continue
+		}
+		if guessProviderFromModel(model) == providerName || (providerName == "requesty" && strings.Contains(model, "/")) {
+			return model
+		}
+	}
+	return ""
+}
+
+// capProviderSpend wraps p so every call first checks the provider's spend
+// cap, stopping work that would go over it mid-command
+func capProviderSpend(p provider.Provider, cfgMgr *config.Manager, store *state.Store, projectID string) provider.Provider {
+	return provider.NewSpendCappedProvider(p, store, projectID, cfgMgr.GetProviderBudget(p.Name()))
+}
+
 func guessProviderFromModel(model string) string {
 	lowerModel := strings.ToLower(model)
 
@@ -126,27 +209,70 @@ func guessProviderFromModel(model string) string {
 	return ""
 }
 
+// keylessProviders can run without an API key: Ollama, and OpenAI when
+// pointed at a local endpoint
+var keylessProviders = map[string]bool{
+	"ollama": true,
+	"openai": true,
+}
+
 func setupProvider(bridge *provider.Bridge, cfgMgr *config.Manager, providerName string) error {
 	p, err := provider.CreateProvider(providerName)
 	if err != nil {
 		return err
 	}
-
-	if providerName == "ollama" {
-		if err := p.Authenticate(""); err != nil {
-			return fmt.Errorf("failed to authenticate/connect to %s: %w", providerName, err)
-		}
-		return bridge.RegisterProvider(p)
+	if debugLLM {
+		debugProvider := provider.NewDebugProvider(p)
+		debugProviders = append(debugProviders, debugProvider)
+		p = debugProvider
 	}
 
 	apiKey, err := cfgMgr.GetAPIKey(providerName)
 	if err != nil {
-		return err
+		if !keylessProviders[providerName] {
+			return err
+		}
+		// Without a key, a local server is only used once it answers
+		if err := p.Authenticate(""); err != nil {
+			return fmt.Errorf("failed to authenticate/connect to %s: %w", providerName, err)
+		}
+		if _, err := p.ListModels(); err != nil {
+			return fmt.Errorf("%s is not reachable: %w", providerName, err)
+		}
+		return bridge.RegisterProvider(governProvider(p, cfgMgr))
 	}
 
 	if err := p.Authenticate(apiKey); err != nil {
 		return fmt.Errorf("failed to authenticate %s: %w", providerName, err)
 	}
 
-	return bridge.RegisterProvider(p)
+	return bridge.RegisterProvider(governProvider(p, cfgMgr))
+}
+
+// governProvider limits p to the provider's configured requests per
+// minute, shared by every caller in the process. Providers without a limit
+// are returned as they are.
+func governProvider(p provider.Provider, cfgMgr *config.Manager) provider.Provider {
+	limit := cfgMgr.GetProviderRateLimit(p.Name())
+	if limit <= 0 {
+		return p
+	}
+	return provider.NewGovernedProvider(p, provider.SharedRateGovernor(p.Name(), limit))
+}
+
+// debugProviders are the providers set up with --debug-llm, whose last
+// calls are printed when the command finishes
+var debugProviders []*provider.DebugProvider
+
+// printLLMDebug writes the last raw request and response of each debugged
+// provider to stderr
+func printLLMDebug() {
+	for _, p := range debugProviders {
+		request, response := p.DebugLastCall()
+		if request == "" {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "\n🐞 Last %s request:\n%s\n", p.Name(), request)
+		fmt.Fprintf(os.Stderr, "\n🐞 Last %s response:\n%s\n", p.Name(), response)
+	}
 }
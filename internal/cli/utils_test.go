@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mojomast/geoffrussy/internal/config"
+	"github.com/mojomast/geoffrussy/internal/provider"
+)
+
+func TestStageModelForProvider(t *testing.T) {
+	cfg := &config.Config{
+		DefaultModels: map[string]string{
+			"design":  "claude-3-5-sonnet-20241022",
+			"develop": "gpt-4o",
+			"review":  "claude-3-haiku-20240307",
+		},
+		FavoriteModels: []string{"glm-4.6"},
+	}
+
+	tests := []struct {
+		stage    string
+		provider string
+		want     string
+	}{
+		{"develop", "openai", "gpt-4o"},
+		{"review", "anthropic", "claude-3-haiku-20240307"},
+		{"develop", "anthropic", "claude-3-5-sonnet-20241022"},
+		{"develop", "zai", "glm-4.6"},
+		{"develop", "kimi", ""},
+	}
+	for _, tt := range tests {
+		if got := stageModelForProvider(cfg, tt.stage, tt.provider); got != tt.want {
+			t.Errorf("stageModelForProvider(%s, %s) = %q, want %q", tt.stage, tt.provider, got, tt.want)
+		}
+	}
+}
+
+func TestSetupProvider_KeylessNeedsAnsweringServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"id": "local-model"}]}`))
+	}))
+	defer server.Close()
+
+	cfgMgr := config.NewManager()
+
+	t.Setenv("GEOFFRUSSY_OPENAI_BASE_URL", server.URL+"/v1")
+	bridge := provider.NewBridge()
+	if err := setupProvider(bridge, cfgMgr, "openai"); err != nil {
+		t.Fatalf("expected a reachable keyless endpoint to be registered, got %v", err)
+	}
+	if _, err := bridge.GetProvider("openai"); err != nil {
+		t.Errorf("expected openai to be registered: %v", err)
+	}
+
+	// An endpoint that refuses to list models isn't used
+	refusing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer refusing.Close()
+
+	t.Setenv("GEOFFRUSSY_OPENAI_BASE_URL", refusing.URL+"/v1")
+	bridge = provider.NewBridge()
+	if err := setupProvider(bridge, cfgMgr, "openai"); err == nil {
+		t.Error("expected a keyless endpoint that doesn't answer not to be registered")
+	}
+	if _, err := bridge.GetProvider("openai"); err == nil {
+		t.Error("expected openai not to be registered")
+	}
+
+	if err := setupProvider(provider.NewBridge(), cfgMgr, "opencode"); err == nil {
+		t.Error("expected a provider without a key to need one")
+	}
+}
+
+func TestGovernProvider(t *testing.T) {
+	cfgMgr := config.NewManager()
+	echo := provider.NewEchoProvider()
+	if governProvider(echo, cfgMgr) != echo {
+		t.Error("expected a provider without a rate limit to be left alone")
+	}
+
+	if err := cfgMgr.SetProviderRateLimit(echo.Name(), 60); err != nil {
+		t.Fatalf("SetProviderRateLimit failed: %v", err)
+	}
+	if _, ok := governProvider(echo, cfgMgr).(*provider.GovernedProvider); !ok {
+		t.Error("expected a rate-limited provider to be governed")
+	}
+}
@@ -5,19 +5,51 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the application configuration
 type Config struct {
-	APIKeys        map[string]string `yaml:"api_keys"`
-	DefaultModels  map[string]string `yaml:"default_models"`
-	FavoriteModels []string          `yaml:"favorite_models"`
-	BudgetLimit    float64           `yaml:"budget_limit"`
-	VerboseLogging bool              `yaml:"verbose_logging"`
-	MCP            *MCPConfig        `yaml:"mcp,omitempty"`
-	ConfigPath     string            `yaml:"-"` // Not serialized
+	Version            int                 `yaml:"version"` // Schema version; files without one are v0
+	APIKeys            map[string]string   `yaml:"api_keys"`
+	DefaultModels      map[string]string   `yaml:"default_models"`
+	FavoriteModels     []string            `yaml:"favorite_models"`
+	BudgetLimit        float64             `yaml:"budget_limit"`
+	OnBudgetExceeded   string              `yaml:"on_budget_exceeded,omitempty"`   // warn, pause or stop
+	ProviderBudgets    map[string]float64  `yaml:"provider_budgets,omitempty"`     // Spend cap in USD per provider name
+	ProviderRateLimits map[string]int      `yaml:"provider_rate_limits,omitempty"` // Requests per minute per provider name
+	VerboseLogging     bool                `yaml:"verbose_logging"`
+	MCP                *MCPConfig          `yaml:"mcp,omitempty"`
+	Profiles           map[string]*Profile `yaml:"profiles,omitempty"`
+	ActiveProfile      string              `yaml:"active_profile,omitempty"`
+	ConfigPath         string              `yaml:"-"` // Not serialized
+}
+
+// CurrentConfigVersion is the config schema version written by Save.
+// Older files are upgraded by migrateConfig when loaded.
+const CurrentConfigVersion = 1
+
+// configMigrations upgrade a config file by one version each; entry i turns
+// a version i file into version i+1
+var configMigrations = []func(*Config){
+	migrateConfigV0,
+}
+
+// Budget policies applied when a project exceeds its budget limit
+const (
+	BudgetPolicyWarn  = "warn"  // Keep going and report the overrun
+	BudgetPolicyPause = "pause" // Pause the project until it is resumed
+	BudgetPolicyStop  = "stop"  // Fail the current operation
+)
+
+// Profile is a named set of API keys and default models, e.g. "work" or
+// "personal", layered over the top-level config when selected
+type Profile struct {
+	APIKeys       map[string]string `yaml:"api_keys,omitempty"`
+	DefaultModels map[string]string `yaml:"default_models,omitempty"`
+	BudgetLimit   float64           `yaml:"budget_limit,omitempty"`
 }
 
 // MCPConfig represents MCP server configuration
@@ -29,8 +61,10 @@ type MCPConfig struct {
 
 // Manager handles configuration loading and management
 type Manager struct {
-	config    *Config
-	validator APIKeyValidator
+	config     *Config
+	validator  APIKeyValidator
+	fileConfig *Config // Config as read from file; the only config written by Save
+	flagConfig *Config // Flags passed to Load, reapplied after profile switching
 }
 
 // APIKeyValidator is an interface for validating API keys against providers
@@ -42,11 +76,23 @@ type APIKeyValidator interface {
 func NewManager() *Manager {
 	return &Manager{
 		config: &Config{
+			Version:        CurrentConfigVersion,
 			APIKeys:        make(map[string]string),
 			DefaultModels:  make(map[string]string),
 			FavoriteModels: []string{},
 		},
-		validator: nil, // Will be set when provider system is implemented
+		fileConfig: newFileConfig(),
+		validator:  nil, // Will be set when provider system is implemented
+	}
+}
+
+// newFileConfig returns an empty file-level config
+func newFileConfig() *Config {
+	return &Config{
+		Version:        CurrentConfigVersion,
+		APIKeys:        make(map[string]string),
+		DefaultModels:  make(map[string]string),
+		FavoriteModels: []string{},
 	}
 }
 
@@ -58,10 +104,15 @@ func (m *Manager) SetValidator(validator APIKeyValidator) {
 // Load loads configuration from multiple sources with precedence:
 // 1. Command-line flags (highest priority)
 // 2. Environment variables
-// 3. Config file (lowest priority)
+// 3. Selected profile
+// 4. Config file (lowest priority)
+//
+// The profile is taken from flagConfig.ActiveProfile, then GEOFFRUSSY_PROFILE,
+// then active_profile in the config file.
 func (m *Manager) Load(flagConfig *Config) error {
 	// Start with default config
 	m.config = &Config{
+		Version:        CurrentConfigVersion,
 		APIKeys:        make(map[string]string),
 		DefaultModels:  make(map[string]string),
 		BudgetLimit:    0,
@@ -74,6 +125,8 @@ func (m *Manager) Load(flagConfig *Config) error {
 		return fmt.Errorf("failed to get config path: %w", err)
 	}
 	m.config.ConfigPath = configPath
+	m.fileConfig = newFileConfig()
+	m.flagConfig = flagConfig
 
 	// Load from config file (lowest priority)
 	if err := m.loadFromFile(configPath); err != nil {
@@ -83,6 +136,23 @@ func (m *Manager) Load(flagConfig *Config) error {
 		}
 	}
 
+	// Apply the selected profile over the file values
+	profileName := m.config.ActiveProfile
+	if envProfile := os.Getenv("GEOFFRUSSY_PROFILE"); envProfile != "" {
+		profileName = envProfile
+	}
+	if flagConfig != nil && flagConfig.ActiveProfile != "" {
+		profileName = flagConfig.ActiveProfile
+	}
+	if profileName != "" {
+		profile, ok := m.config.Profiles[profileName]
+		if !ok {
+			return fmt.Errorf("profile not found: %s", profileName)
+		}
+		m.applyProfile(profile)
+		m.config.ActiveProfile = profileName
+	}
+
 	// Load from environment variables (medium priority)
 	m.loadFromEnv()
 
@@ -105,6 +175,9 @@ func (m *Manager) loadFromFile(path string) error {
 	if err := yaml.Unmarshal(data, &fileConfig); err != nil {
 		return fmt.Errorf("failed to parse config file: %w", err)
 	}
+	if err := m.migrateConfig(path, data, &fileConfig); err != nil {
+		return err
+	}
 
 	// Merge file config into current config
 	if fileConfig.APIKeys != nil {
@@ -127,13 +200,160 @@ func (m *Manager) loadFromFile(path string) error {
 	if fileConfig.BudgetLimit > 0 {
 		m.config.BudgetLimit = fileConfig.BudgetLimit
 	}
+	if fileConfig.OnBudgetExceeded != "" {
+		m.config.OnBudgetExceeded = fileConfig.OnBudgetExceeded
+	}
+	if fileConfig.ProviderBudgets != nil {
+		m.config.ProviderBudgets = fileConfig.ProviderBudgets
+	}
+	if fileConfig.ProviderRateLimits != nil {
+		m.config.ProviderRateLimits = fileConfig.ProviderRateLimits
+	}
 	if fileConfig.VerboseLogging {
 		m.config.VerboseLogging = fileConfig.VerboseLogging
 	}
+	if fileConfig.Profiles != nil {
+		m.config.Profiles = fileConfig.Profiles
+	}
+	if fileConfig.ActiveProfile != "" {
+		m.config.ActiveProfile = fileConfig.ActiveProfile
+	}
+
+	if fileConfig.APIKeys == nil {
+		fileConfig.APIKeys = make(map[string]string)
+	}
+	if fileConfig.DefaultModels == nil {
+		fileConfig.DefaultModels = make(map[string]string)
+	}
+	m.fileConfig = &fileConfig
 
 	return nil
 }
 
+// migrateConfig upgrades a config file read from path to the current
+// version. The original file is kept as <path>.v<N>.bak before the upgraded
+// config is written back in its place.
+func (m *Manager) migrateConfig(path string, original []byte, fileConfig *Config) error {
+	if fileConfig.Version > CurrentConfigVersion {
+		return fmt.Errorf("config file version %d is newer than this version of geoffrussy supports (%d); please upgrade geoffrussy", fileConfig.Version, CurrentConfigVersion)
+	}
+	if fileConfig.Version == CurrentConfigVersion {
+		return nil
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", path, fileConfig.Version)
+	if err := os.WriteFile(backupPath, original, 0600); err != nil {
+		return fmt.Errorf("failed to back up config file: %w", err)
+	}
+
+	for fileConfig.Version < CurrentConfigVersion {
+		configMigrations[fileConfig.Version](fileConfig)
+		fileConfig.Version++
+	}
+
+	data, err := yaml.Marshal(fileConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal migrated config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write migrated config: %w", err)
+	}
+
+	return nil
+}
+
+// migrateConfigV0 renames the legacy "z" API key to the "zai" provider name,
+// in the top-level keys and in every profile
+func migrateConfigV0(config *Config) {
+	renameKey := func(keys map[string]string) {
+		if key, ok := keys["z"]; ok {
+			if _, exists := keys["zai"]; !exists {
+				keys["zai"] = key
+			}
+			delete(keys, "z")
+		}
+	}
+	renameKey(config.APIKeys)
+	for _, profile := range config.Profiles {
+		if profile != nil {
+			renameKey(profile.APIKeys)
+		}
+	}
+}
+
+// applyProfile overlays a profile's values onto the current config
+func (m *Manager) applyProfile(profile *Profile) {
+	if profile == nil {
+		return
+	}
+	for k, v := range profile.APIKeys {
+		if v != "" {
+			m.config.APIKeys[k] = v
+		}
+	}
+	for k, v := range profile.DefaultModels {
+		if v != "" {
+			m.config.DefaultModels[k] = v
+		}
+	}
+	if profile.BudgetLimit > 0 {
+		m.config.BudgetLimit = profile.BudgetLimit
+	}
+}
+
+// UseProfile switches the active API keys and default models to the named
+// profile. Values from the previous profile are dropped, and environment
+// variables and flags are reapplied on top.
+func (m *Manager) UseProfile(name string) error {
+	profile, ok := m.config.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile not found: %s", name)
+	}
+
+	// Rebuild from the file's top-level values
+	m.config.APIKeys = make(map[string]string)
+	m.config.DefaultModels = make(map[string]string)
+	m.config.BudgetLimit = 0
+	for k, v := range m.fileConfig.APIKeys {
+		if v != "" {
+			m.config.APIKeys[k] = v
+		}
+	}
+	for k, v := range m.fileConfig.DefaultModels {
+		if v != "" {
+			m.config.DefaultModels[k] = v
+		}
+	}
+	if m.fileConfig.BudgetLimit > 0 {
+		m.config.BudgetLimit = m.fileConfig.BudgetLimit
+	}
+
+	m.applyProfile(profile)
+	m.config.ActiveProfile = name
+
+	m.loadFromEnv()
+	if m.flagConfig != nil {
+		m.applyFlags(m.flagConfig)
+	}
+
+	return nil
+}
+
+// ListProfiles returns the configured profile names sorted alphabetically
+func (m *Manager) ListProfiles() []string {
+	names := make([]string, 0, len(m.config.Profiles))
+	for name := range m.config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetActiveProfile returns the name of the active profile, or "" if none
+func (m *Manager) GetActiveProfile() string {
+	return m.config.ActiveProfile
+}
+
 // loadFromEnv loads configuration from environment variables
 func (m *Manager) loadFromEnv() {
 	// API Keys - format: GEOFFRUSSY_API_KEY_<PROVIDER>=<key>
@@ -175,6 +395,11 @@ func (m *Manager) loadFromEnv() {
 		}
 	}
 
+	// Budget policy
+	if policy := os.Getenv("GEOFFRUSSY_ON_BUDGET_EXCEEDED"); policy != "" {
+		m.config.OnBudgetExceeded = policy
+	}
+
 	// Verbose Logging
 	if verboseStr := os.Getenv("GEOFFRUSSY_VERBOSE_LOGGING"); verboseStr != "" {
 		m.config.VerboseLogging = verboseStr == "true" || verboseStr == "1" || verboseStr == "yes"
@@ -233,6 +458,9 @@ func (m *Manager) applyFlags(flagConfig *Config) {
 	if flagConfig.BudgetLimit > 0 {
 		m.config.BudgetLimit = flagConfig.BudgetLimit
 	}
+	if flagConfig.OnBudgetExceeded != "" {
+		m.config.OnBudgetExceeded = flagConfig.OnBudgetExceeded
+	}
 	// For boolean flags, we need to check if it was explicitly set
 	// For now, we'll apply it if true
 	if flagConfig.VerboseLogging {
@@ -240,7 +468,10 @@ func (m *Manager) applyFlags(flagConfig *Config) {
 	}
 }
 
-// Save saves the current configuration to the config file
+// Save writes the file-level configuration to the config file. Values from
+// the selected profile, environment variables and flags only apply to the
+// current run and are not written; use the Set methods to change what is
+// saved.
 func (m *Manager) Save() error {
 	if m.config.ConfigPath == "" {
 		path, err := getConfigPath()
@@ -256,8 +487,9 @@ func (m *Manager) Save() error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Marshal config to YAML
-	data, err := yaml.Marshal(m.config)
+	// Marshal the file-level config to YAML
+	m.fileConfig.Version = CurrentConfigVersion
+	data, err := yaml.Marshal(m.fileConfig)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -301,6 +533,7 @@ func (m *Manager) SetAPIKey(provider, key string) error {
 	}
 
 	m.config.APIKeys[provider] = key
+	m.fileConfig.APIKeys[provider] = key
 	return nil
 }
 
@@ -313,6 +546,84 @@ func (m *Manager) ValidateAPIKey(provider, key string) error {
 	return m.validator.ValidateAPIKey(provider, key)
 }
 
+// GetBudgetPolicy returns the on_budget_exceeded policy, defaulting to stop
+// when unset or unrecognized
+func (m *Manager) GetBudgetPolicy() string {
+	switch m.config.OnBudgetExceeded {
+	case BudgetPolicyWarn, BudgetPolicyPause, BudgetPolicyStop:
+		return m.config.OnBudgetExceeded
+	default:
+		return BudgetPolicyStop
+	}
+}
+
+// SetBudgetPolicy sets the on_budget_exceeded policy
+func (m *Manager) SetBudgetPolicy(policy string) error {
+	switch policy {
+	case BudgetPolicyWarn, BudgetPolicyPause, BudgetPolicyStop:
+		m.config.OnBudgetExceeded = policy
+		m.fileConfig.OnBudgetExceeded = policy
+		return nil
+	default:
+		return fmt.Errorf("invalid budget policy: %s (must be warn, pause or stop)", policy)
+	}
+}
+
+// GetProviderBudget returns the spend cap for a provider, or zero when it
+// has none
+func (m *Manager) GetProviderBudget(provider string) float64 {
+	return m.config.ProviderBudgets[provider]
+}
+
+// SetProviderBudget caps spend on a provider. A limit of zero removes the cap.
+func (m *Manager) SetProviderBudget(provider string, limit float64) error {
+	if provider == "" {
+		return fmt.Errorf("provider cannot be empty")
+	}
+	if limit < 0 {
+		return fmt.Errorf("provider budget cannot be negative: %f", limit)
+	}
+	for _, cfg := range []*Config{m.config, m.fileConfig} {
+		if limit == 0 {
+			delete(cfg.ProviderBudgets, provider)
+			continue
+		}
+		if cfg.ProviderBudgets == nil {
+			cfg.ProviderBudgets = make(map[string]float64)
+		}
+		cfg.ProviderBudgets[provider] = limit
+	}
+	return nil
+}
+
+// GetProviderRateLimit returns the requests-per-minute limit for a
+// provider, or zero when it has none
+func (m *Manager) GetProviderRateLimit(provider string) int {
+	return m.config.ProviderRateLimits[provider]
+}
+
+// SetProviderRateLimit limits requests a minute to a provider. A limit of
+// zero removes it.
+func (m *Manager) SetProviderRateLimit(provider string, requestsPerMinute int) error {
+	if provider == "" {
+		return fmt.Errorf("provider cannot be empty")
+	}
+	if requestsPerMinute < 0 {
+		return fmt.Errorf("provider rate limit cannot be negative: %d", requestsPerMinute)
+	}
+	for _, cfg := range []*Config{m.config, m.fileConfig} {
+		if requestsPerMinute == 0 {
+			delete(cfg.ProviderRateLimits, provider)
+			continue
+		}
+		if cfg.ProviderRateLimits == nil {
+			cfg.ProviderRateLimits = make(map[string]int)
+		}
+		cfg.ProviderRateLimits[provider] = requestsPerMinute
+	}
+	return nil
+}
+
 // GetDefaultModel returns the default model for a specific stage
 func (m *Manager) GetDefaultModel(stage string) (string, error) {
 	model, ok := m.config.DefaultModels[stage]
@@ -331,9 +642,27 @@ func (m *Manager) SetDefaultModel(stage, model string) error {
 		return fmt.Errorf("model cannot be empty")
 	}
 	m.config.DefaultModels[stage] = model
+	m.fileConfig.DefaultModels[stage] = model
 	return nil
 }
 
+// SetBudgetLimit sets the project budget limit in USD. A limit of zero means
+// unlimited.
+func (m *Manager) SetBudgetLimit(limit float64) error {
+	if limit < 0 {
+		return fmt.Errorf("budget limit cannot be negative: %f", limit)
+	}
+	m.config.BudgetLimit = limit
+	m.fileConfig.BudgetLimit = limit
+	return nil
+}
+
+// SetVerboseLogging turns verbose logging on or off
+func (m *Manager) SetVerboseLogging(enabled bool) {
+	m.config.VerboseLogging = enabled
+	m.fileConfig.VerboseLogging = enabled
+}
+
 // AddFavoriteModel adds a model to the favorites list
 func (m *Manager) AddFavoriteModel(model string) error {
 	if model == "" {
@@ -348,6 +677,7 @@ func (m *Manager) AddFavoriteModel(model string) error {
 	}
 
 	m.config.FavoriteModels = append(m.config.FavoriteModels, model)
+	m.fileConfig.FavoriteModels = append([]string{}, m.config.FavoriteModels...)
 	return nil
 }
 
@@ -372,6 +702,7 @@ func (m *Manager) RemoveFavoriteModel(model string) error {
 	}
 
 	m.config.FavoriteModels = newFavorites
+	m.fileConfig.FavoriteModels = append([]string{}, newFavorites...)
 	return nil
 }
 
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestNewManager(t *testing.T) {
@@ -277,10 +280,16 @@ func TestSave(t *testing.T) {
 
 	m := NewManager()
 	m.config.ConfigPath = configPath
-	m.config.APIKeys["openai"] = "test-key"
-	m.config.DefaultModels["interview"] = "gpt-4"
-	m.config.BudgetLimit = 150.0
-	m.config.VerboseLogging = true
+	if err := m.SetAPIKey("openai", "test-key"); err != nil {
+		t.Fatalf("SetAPIKey failed: %v", err)
+	}
+	if err := m.SetDefaultModel("interview", "gpt-4"); err != nil {
+		t.Fatalf("SetDefaultModel failed: %v", err)
+	}
+	if err := m.SetBudgetLimit(150.0); err != nil {
+		t.Fatalf("SetBudgetLimit failed: %v", err)
+	}
+	m.SetVerboseLogging(true)
 
 	if err := m.Save(); err != nil {
 		t.Fatalf("Save failed: %v", err)
@@ -311,6 +320,84 @@ func TestSave(t *testing.T) {
 	}
 }
 
+func TestMigrateConfig_V0(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	v0Content := `api_keys:
+  openai: sk-test-key
+  z: zai-test-key
+default_models:
+  develop: glm-4.7
+budget_limit: 100.0
+verbose_logging: true
+profiles:
+  work:
+    api_keys:
+      z: zai-work-key
+`
+	if err := os.WriteFile(configPath, []byte(v0Content), 0600); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+
+	m := NewManager()
+	if err := m.loadFromFile(configPath); err != nil {
+		t.Fatalf("loadFromFile failed: %v", err)
+	}
+
+	if m.config.APIKeys["zai"] != "zai-test-key" || m.config.APIKeys["z"] != "" {
+		t.Errorf("Expected legacy z key to become zai, got %v", m.config.APIKeys)
+	}
+	if m.config.APIKeys["openai"] != "sk-test-key" || m.config.DefaultModels["develop"] != "glm-4.7" {
+		t.Errorf("Expected other values to be unchanged, got %+v", m.config)
+	}
+	if m.config.BudgetLimit != 100.0 || !m.config.VerboseLogging {
+		t.Errorf("Expected budget and logging to be unchanged, got %+v", m.config)
+	}
+	if m.config.Profiles["work"].APIKeys["zai"] != "zai-work-key" {
+		t.Errorf("Expected profile keys to be migrated, got %v", m.config.Profiles["work"].APIKeys)
+	}
+
+	// The file is rewritten at the current version and the original kept
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read migrated config: %v", err)
+	}
+	var migrated Config
+	if err := yaml.Unmarshal(data, &migrated); err != nil {
+		t.Fatalf("Failed to parse migrated config: %v", err)
+	}
+	if migrated.Version != CurrentConfigVersion {
+		t.Errorf("Expected version %d, got %d", CurrentConfigVersion, migrated.Version)
+	}
+	if migrated.APIKeys["zai"] != "zai-test-key" || migrated.BudgetLimit != 100.0 {
+		t.Errorf("Expected migrated values in the rewritten file, got %+v", migrated)
+	}
+	backup, err := os.ReadFile(configPath + ".v0.bak")
+	if err != nil {
+		t.Fatalf("Expected a backup of the v0 file: %v", err)
+	}
+	if string(backup) != v0Content {
+		t.Errorf("Expected backup to hold the original file, got:\n%s", backup)
+	}
+
+	// Loading the migrated file again leaves it alone
+	if err := NewManager().loadFromFile(configPath); err != nil {
+		t.Fatalf("Failed to reload migrated config: %v", err)
+	}
+	if reloaded, _ := os.ReadFile(configPath); string(reloaded) != string(data) {
+		t.Error("Expected a current config file not to be rewritten")
+	}
+
+	newer := fmt.Sprintf("version: %d\n", CurrentConfigVersion+1)
+	if err := os.WriteFile(configPath, []byte(newer), 0600); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := NewManager().loadFromFile(configPath); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Expected error for a newer config version, got %v", err)
+	}
+}
+
 func TestGetAPIKey(t *testing.T) {
 	m := NewManager()
 	m.config.APIKeys["openai"] = "test-key"
@@ -399,6 +486,64 @@ func TestSetDefaultModel(t *testing.T) {
 	}
 }
 
+func TestProviderBudget(t *testing.T) {
+	m := NewManager()
+
+	if got := m.GetProviderBudget("anthropic"); got != 0 {
+		t.Errorf("Expected no cap by default, got %f", got)
+	}
+	if err := m.SetProviderBudget("anthropic", 20); err != nil {
+		t.Fatalf("SetProviderBudget failed: %v", err)
+	}
+	if got := m.GetProviderBudget("anthropic"); got != 20 {
+		t.Errorf("Expected cap 20, got %f", got)
+	}
+
+	// Zero removes the cap
+	if err := m.SetProviderBudget("anthropic", 0); err != nil {
+		t.Fatalf("SetProviderBudget failed: %v", err)
+	}
+	if _, ok := m.config.ProviderBudgets["anthropic"]; ok {
+		t.Error("Expected cap to be removed")
+	}
+
+	if err := m.SetProviderBudget("anthropic", -1); err == nil {
+		t.Error("Expected error for negative cap")
+	}
+	if err := m.SetProviderBudget("", 5); err == nil {
+		t.Error("Expected error for empty provider")
+	}
+}
+
+func TestProviderRateLimit(t *testing.T) {
+	m := NewManager()
+
+	if got := m.GetProviderRateLimit("anthropic"); got != 0 {
+		t.Errorf("Expected no limit by default, got %d", got)
+	}
+	if err := m.SetProviderRateLimit("anthropic", 50); err != nil {
+		t.Fatalf("SetProviderRateLimit failed: %v", err)
+	}
+	if got := m.GetProviderRateLimit("anthropic"); got != 50 {
+		t.Errorf("Expected limit 50, got %d", got)
+	}
+
+	// Zero removes the limit
+	if err := m.SetProviderRateLimit("anthropic", 0); err != nil {
+		t.Fatalf("SetProviderRateLimit failed: %v", err)
+	}
+	if _, ok := m.config.ProviderRateLimits["anthropic"]; ok {
+		t.Error("Expected limit to be removed")
+	}
+
+	if err := m.SetProviderRateLimit("anthropic", -1); err == nil {
+		t.Error("Expected error for negative limit")
+	}
+	if err := m.SetProviderRateLimit("", 5); err == nil {
+		t.Error("Expected error for empty provider")
+	}
+}
+
 func TestGetConfigPath(t *testing.T) {
 	m := NewManager()
 	m.config.ConfigPath = "/test/path/config.yaml"
@@ -672,3 +817,147 @@ func TestFavoriteModels(t *testing.T) {
 		t.Error("Expected persisted favorite model to be loaded")
 	}
 }
+
+func TestProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `api_keys:
+  ollama: http://localhost:11434
+default_models:
+  interview: gpt-4
+profiles:
+  work:
+    api_keys:
+      openai: sk-work-key
+    default_models:
+      design: claude-3
+  personal:
+    api_keys:
+      anthropic: personal-anthropic-key
+      openai: sk-personal-key
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+
+	m := NewManager()
+	if err := m.loadFromFile(configPath); err != nil {
+		t.Fatalf("loadFromFile failed: %v", err)
+	}
+
+	profiles := m.ListProfiles()
+	if len(profiles) != 2 || profiles[0] != "personal" || profiles[1] != "work" {
+		t.Errorf("Expected profiles [personal work], got %v", profiles)
+	}
+
+	if err := m.UseProfile("work"); err != nil {
+		t.Fatalf("UseProfile(work) failed: %v", err)
+	}
+	if key, _ := m.GetAPIKey("openai"); key != "sk-work-key" {
+		t.Errorf("Expected work openai key, got '%s'", key)
+	}
+	if model, _ := m.GetDefaultModel("design"); model != "claude-3" {
+		t.Errorf("Expected work design model 'claude-3', got '%s'", model)
+	}
+	if key, _ := m.GetAPIKey("ollama"); key != "http://localhost:11434" {
+		t.Errorf("Expected top-level ollama key to be kept, got '%s'", key)
+	}
+
+	if err := m.UseProfile("personal"); err != nil {
+		t.Fatalf("UseProfile(personal) failed: %v", err)
+	}
+	if key, _ := m.GetAPIKey("openai"); key != "sk-personal-key" {
+		t.Errorf("Expected personal openai key, got '%s'", key)
+	}
+	if _, err := m.GetDefaultModel("design"); err == nil {
+		t.Error("Expected work profile's design model to be dropped after switching")
+	}
+	if m.GetActiveProfile() != "personal" {
+		t.Errorf("Expected active profile 'personal', got '%s'", m.GetActiveProfile())
+	}
+
+	if err := m.UseProfile("missing"); err == nil {
+		t.Error("Expected error for unknown profile")
+	}
+}
+
+func TestProfiles_FlagsOverrideProfile(t *testing.T) {
+	m := NewManager()
+	m.config.Profiles = map[string]*Profile{
+		"work": {APIKeys: map[string]string{"openai": "sk-work-key"}},
+	}
+	m.flagConfig = &Config{APIKeys: map[string]string{"openai": "sk-flag-key"}}
+
+	if err := m.UseProfile("work"); err != nil {
+		t.Fatalf("UseProfile failed: %v", err)
+	}
+	if key, _ := m.GetAPIKey("openai"); key != "sk-flag-key" {
+		t.Errorf("Expected flag key to take precedence, got '%s'", key)
+	}
+}
+
+func TestSave_ExcludesProfileOverlay(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `api_keys:
+  ollama: http://localhost:11434
+profiles:
+  work:
+    api_keys:
+      openai: sk-work-key
+    budget_limit: 25
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+
+	m := NewManager()
+	if err := m.loadFromFile(configPath); err != nil {
+		t.Fatalf("loadFromFile failed: %v", err)
+	}
+	m.config.ConfigPath = configPath
+	m.flagConfig = &Config{APIKeys: map[string]string{"groq": "gsk-flag-key"}}
+	if err := m.UseProfile("work"); err != nil {
+		t.Fatalf("UseProfile failed: %v", err)
+	}
+	if err := m.SetAPIKey("anthropic", "sk-ant-key"); err != nil {
+		t.Fatalf("SetAPIKey failed: %v", err)
+	}
+
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read saved config: %v", err)
+	}
+	var saved Config
+	if err := yaml.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to parse saved config: %v", err)
+	}
+
+	if saved.ActiveProfile != "" {
+		t.Errorf("Expected no active_profile to be saved, got '%s'", saved.ActiveProfile)
+	}
+	if _, ok := saved.APIKeys["openai"]; ok {
+		t.Error("Expected profile API key not to be copied to the top level")
+	}
+	if _, ok := saved.APIKeys["groq"]; ok {
+		t.Error("Expected flag API key not to be saved")
+	}
+	if saved.BudgetLimit != 0 {
+		t.Errorf("Expected profile budget limit not to be saved, got %f", saved.BudgetLimit)
+	}
+	if saved.APIKeys["anthropic"] != "sk-ant-key" {
+		t.Errorf("Expected set API key to be saved, got '%s'", saved.APIKeys["anthropic"])
+	}
+	if saved.APIKeys["ollama"] != "http://localhost:11434" {
+		t.Errorf("Expected file API key to be kept, got '%s'", saved.APIKeys["ollama"])
+	}
+	if saved.Profiles["work"] == nil || saved.Profiles["work"].APIKeys["openai"] != "sk-work-key" {
+		t.Error("Expected work profile to be kept")
+	}
+}
@@ -1,19 +1,36 @@
 package design
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/mojomast/geoffrussy/internal/interview"
 	"github.com/mojomast/geoffrussy/internal/provider"
 	"github.com/mojomast/geoffrussy/internal/state"
 )
 
 // Generator generates system architecture from interview data
 type Generator struct {
-	provider provider.Provider
-	model    string
+	provider  provider.Provider
+	model     string
+	streamCtx context.Context    // Set by SetStreaming; nil makes plain calls
+	onChunk   func(chunk string) // Sees streamed chunks, may be nil
+}
+
+// ErrNotReadyForDesign is returned when the interview lacks the minimum
+// answers needed to generate an architecture
+var ErrNotReadyForDesign = errors.New("interview is not ready for design")
+
+// architectureCallOptions sends the architect role as the system prompt and
+// asks for a low temperature so the sectioned architecture output parses
+// reliably
+var architectureCallOptions = provider.CallOptions{
+	Temperature: provider.Float64(0.2),
+	System:      "You are an expert software architect.",
 }
 
 // NewGenerator creates a new design generator
@@ -24,6 +41,23 @@ func NewGenerator(provider provider.Provider, model string) *Generator {
 	}
 }
 
+// SetStreaming makes provider calls stream until ctx is done, so a stalled
+// stream fails after provider.DefaultStreamIdleTimeout instead of hanging
+// generation. onChunk, if not nil, sees each chunk as it arrives.
+func (g *Generator) SetStreaming(ctx context.Context, onChunk func(chunk string)) {
+	g.streamCtx = ctx
+	g.onChunk = onChunk
+}
+
+// callProvider makes an architecture call, streaming it if SetStreaming was
+// used
+func (g *Generator) callProvider(prompt string) (*provider.Response, error) {
+	if g.streamCtx != nil {
+		return provider.StreamCall(g.streamCtx, g.provider, g.model, prompt, architectureCallOptions, provider.DefaultStreamIdleTimeout, g.onChunk)
+	}
+	return g.provider.CallWithOptions(g.model, prompt, architectureCallOptions)
+}
+
 // Architecture represents the system architecture
 type Architecture struct {
 	ProjectID         string
@@ -186,11 +220,15 @@ func (g *Generator) GenerateArchitecture(interviewData *state.InterviewData) (*A
 		return nil, fmt.Errorf("provider is required for architecture generation")
 	}
 
+	if ready, blockers := interview.ReadyForDesignData(interviewData); !ready {
+		return nil, fmt.Errorf("%w, missing answers: %s", ErrNotReadyForDesign, strings.Join(blockers, "; "))
+	}
+
 	// Create the architecture prompt
 	prompt := g.buildArchitecturePrompt(interviewData)
 
 	// Call the LLM
-	response, err := g.provider.Call(g.model, prompt)
+	response, err := g.callProvider(prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate architecture: %w", err)
 	}
@@ -209,13 +247,13 @@ func (g *Generator) GenerateArchitecture(interviewData *state.InterviewData) (*A
 
 // buildArchitecturePrompt creates the prompt for architecture generation
 func (g *Generator) buildArchitecturePrompt(interviewData *state.InterviewData) string {
-	prompt := `You are an expert software architect. Based on the following project requirements, generate a comprehensive system architecture.
+	prompt := `Based on the following project requirements, generate a comprehensive system architecture.
 
 PROJECT INFORMATION:
-Problem Statement: ` + interviewData.ProblemStatement + `
+` + formatElevatorPitchForPrompt(interviewData.ElevatorPitch) + `Problem Statement: ` + interviewData.ProblemStatement + `
 Target Users: ` + strings.Join(interviewData.TargetUsers, ", ") + `
 Success Metrics: ` + strings.Join(interviewData.SuccessMetrics, ", ") + `
-
+` + formatUnknownsForPrompt(interviewData.Unknowns) + formatReferencesForPrompt(interviewData.References) + interview.FormatGlossaryForPrompt(interviewData.Glossary) + `
 Please provide a detailed architecture document with the following sections:
 
 1. SYSTEM OVERVIEW
@@ -278,6 +316,45 @@ Format your response as structured text that can be parsed. Use clear section he
 	return prompt
 }
 
+// formatUnknownsForPrompt lists answers the user was unsure about so the
+// architecture can call them out as risks
+func formatUnknownsForPrompt(unknowns []string) string {
+	if len(unknowns) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\nUNKNOWNS (the user was unsure of these answers; include them in the risk assessment):\n")
+	for _, unknown := range unknowns {
+		sb.WriteString("- " + unknown + "\n")
+	}
+	return sb.String()
+}
+
+// formatReferencesForPrompt lists documents and links the user cited so the
+// architecture can take them into account
+func formatReferencesForPrompt(references []string) string {
+	if len(references) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\nREFERENCES (documents and links the user cited; align the design with them):\n")
+	for _, reference := range references {
+		sb.WriteString("- " + reference + "\n")
+	}
+	return sb.String()
+}
+
+// formatElevatorPitchForPrompt leads the project information with the pitch,
+// when one was generated, so the system overview starts from it
+func formatElevatorPitchForPrompt(pitch string) string {
+	if pitch == "" {
+		return ""
+	}
+	return "Elevator Pitch: " + pitch + "\n"
+}
+
 // parseArchitectureResponse parses the LLM response into an Architecture struct
 func (g *Generator) parseArchitectureResponse(response string, interviewData *state.InterviewData) (*Architecture, error) {
 	// This is a simplified parser. In production, you'd want more robust parsing
@@ -307,7 +384,7 @@ func (g *Generator) parseArchitectureResponse(response string, interviewData *st
 		},
 		Risks:       []Risk{},
 		Assumptions: []string{},
-		Unknowns:    []string{},
+		Unknowns:    append([]string{}, interviewData.Unknowns...),
 	}
 
 	// Extract components (simplified)
@@ -432,7 +509,7 @@ REFINEMENT REQUEST:
 Please provide the updated content for this section, maintaining consistency with the rest of the architecture.`, 
 		section, g.getSectionContent(architecture, section), refinementRequest)
 
-	response, err := g.provider.Call(g.model, prompt)
+	response, err := g.callProvider(prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to refine architecture: %w", err)
 	}
@@ -1,6 +1,9 @@
 package design
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +14,7 @@ import (
 // MockProvider for testing
 type MockProvider struct {
 	response string
+	lastOpts provider.CallOptions
 }
 
 func (m *MockProvider) Name() string {
@@ -43,6 +47,11 @@ func (m *MockProvider) Call(model string, prompt string) (*provider.Response, er
 	}, nil
 }
 
+func (m *MockProvider) CallWithOptions(model string, prompt string, opts provider.CallOptions) (*provider.Response, error) {
+	m.lastOpts = opts
+	return m.Call(model, prompt)
+}
+
 func (m *MockProvider) Stream(model string, prompt string) (<-chan string, error) {
 	ch := make(chan string, 1)
 	ch <- m.response
@@ -62,6 +71,10 @@ func (m *MockProvider) SupportsCodingPlan() bool {
 	return false
 }
 
+func (m *MockProvider) Ping(model string) error {
+	return nil
+}
+
 func TestDesignGenerator(t *testing.T) {
 	mockResponse := `
 SYSTEM OVERVIEW
@@ -150,6 +163,36 @@ Unknowns: Exact peak load requirements
 		if architecture.SystemOverview == "" {
 			t.Error("System overview should not be empty")
 		}
+
+		if mockProvider.lastOpts.System != architectureCallOptions.System || mockProvider.lastOpts.System == "" {
+			t.Errorf("Expected the architect system prompt, got %q", mockProvider.lastOpts.System)
+		}
+		if got := mockProvider.lastOpts.Temperature; got == nil || *got != *architectureCallOptions.Temperature {
+			t.Errorf("Expected the architecture temperature %v, got %v", *architectureCallOptions.Temperature, got)
+		}
+	})
+
+	t.Run("GenerateArchitectureStreaming", func(t *testing.T) {
+		streamed := &MockProvider{response: mockResponse}
+		streaming := NewGenerator(streamed, "test-model")
+		var chunks strings.Builder
+		streaming.SetStreaming(context.Background(), func(chunk string) {
+			chunks.WriteString(chunk)
+		})
+
+		architecture, err := streaming.GenerateArchitecture(interviewData)
+		if err != nil {
+			t.Fatalf("Failed to generate architecture: %v", err)
+		}
+		if architecture.SystemOverview == "" {
+			t.Error("System overview should not be empty")
+		}
+		if chunks.String() != mockResponse {
+			t.Errorf("Expected the response to arrive through the stream, got %q", chunks.String())
+		}
+		if streamed.lastOpts.System != "" {
+			t.Error("Expected the streamed call not to go through CallWithOptions")
+		}
 	})
 
 	t.Run("ExportMarkdown", func(t *testing.T) {
@@ -384,3 +427,41 @@ func TestDesignGenerator_Reiteration(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildArchitecturePrompt_Glossary(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	prompt := generator.buildArchitecturePrompt(&state.InterviewData{
+		ProblemStatement: "Manual payout reconciliation",
+		Glossary: map[string]string{
+			"Ledger Service":   "The system of record for payouts",
+			"settlement batch": "",
+		},
+	})
+
+	if !strings.Contains(prompt, "GLOSSARY") {
+		t.Fatal("Expected a glossary section in the prompt")
+	}
+	if !strings.Contains(prompt, "- Ledger Service: The system of record for payouts\n- settlement batch\n") {
+		t.Errorf("Expected sorted glossary entries, got:\n%s", prompt)
+	}
+
+	prompt = generator.buildArchitecturePrompt(&state.InterviewData{ProblemStatement: "Manual payout reconciliation"})
+	if strings.Contains(prompt, "GLOSSARY") {
+		t.Error("Expected no glossary section without terms")
+	}
+}
+
+func TestGenerateArchitecture_NotReadyForDesign(t *testing.T) {
+	generator := NewGenerator(&MockProvider{response: "SYSTEM OVERVIEW\nA system"}, "test-model")
+
+	_, err := generator.GenerateArchitecture(&state.InterviewData{
+		ProjectID:        "test-project",
+		ProblemStatement: "Need a task management system",
+	})
+	if !errors.Is(err, ErrNotReadyForDesign) {
+		t.Fatalf("Expected ErrNotReadyForDesign, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "target users") {
+		t.Errorf("Expected the missing answer in the error, got %v", err)
+	}
+}
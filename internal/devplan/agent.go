@@ -0,0 +1,90 @@
+package devplan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mojomast/geoffrussy/internal/design"
+)
+
+// BuildAgentPrompt assembles a ready-to-paste prompt that hands a phase to
+// an executing LLM agent: the objective, every task with its acceptance
+// criteria, the architecture context relevant to the phase and instructions
+// for reporting completion. arch may be nil.
+func (g *Generator) BuildAgentPrompt(phase *Phase, arch *design.Architecture) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("You are implementing Phase %d: %s of a software project.\n\n", phase.Number, phase.Title))
+	prompt.WriteString(fmt.Sprintf("OBJECTIVE:\n%s\n\n", phase.Objective))
+
+	if len(phase.SuccessCriteria) > 0 {
+		prompt.WriteString("PHASE SUCCESS CRITERIA:\n")
+		for _, criterion := range phase.SuccessCriteria {
+			prompt.WriteString(fmt.Sprintf("- %s\n", criterion))
+		}
+		prompt.WriteString("\n")
+	}
+
+	if arch != nil {
+		prompt.WriteString("ARCHITECTURE CONTEXT:\n")
+		if arch.SystemOverview != "" {
+			prompt.WriteString(fmt.Sprintf("%s\n", strings.TrimSpace(arch.SystemOverview)))
+		}
+		for _, component := range relevantComponents(phase, arch.Components) {
+			prompt.WriteString(fmt.Sprintf("- %s (%s): %s", component.Name, component.Type, component.Purpose))
+			if len(component.Technologies) > 0 {
+				prompt.WriteString(fmt.Sprintf(" [%s]", strings.Join(component.Technologies, ", ")))
+			}
+			prompt.WriteString("\n")
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("TASKS:\n")
+	for _, task := range phase.Tasks {
+		prompt.WriteString(fmt.Sprintf("\n%s: %s\n", task.Number, task.Description))
+		if len(task.AcceptanceCriteria) > 0 {
+			prompt.WriteString("Acceptance criteria:\n")
+			for _, criterion := range task.AcceptanceCriteria {
+				prompt.WriteString(fmt.Sprintf("- %s\n", criterion))
+			}
+		}
+		if len(task.ImplementationNotes) > 0 {
+			prompt.WriteString("Notes:\n")
+			for _, note := range task.ImplementationNotes {
+				prompt.WriteString(fmt.Sprintf("- %s\n", note))
+			}
+		}
+	}
+
+	prompt.WriteString("\nINSTRUCTIONS:\n")
+	prompt.WriteString("- Work through the tasks in order and do not start work outside this phase.\n")
+	prompt.WriteString("- A task is only done when all of its acceptance criteria are met.\n")
+	prompt.WriteString("- After each task, report a line of the form \"TASK <number>: COMPLETE\" with a one-line summary.\n")
+	prompt.WriteString("- If you cannot finish a task, report \"TASK <number>: BLOCKED\" with the reason and stop.\n")
+	prompt.WriteString("- When every task is complete, report \"PHASE COMPLETE\".\n")
+
+	return prompt.String()
+}
+
+// relevantComponents returns the components the phase mentions by name,
+// or all of them when the phase doesn't name any
+func relevantComponents(phase *Phase, components []design.Component) []design.Component {
+	var text strings.Builder
+	text.WriteString(phase.Title + " " + phase.Objective)
+	for _, task := range phase.Tasks {
+		text.WriteString(" " + task.Description + " " + strings.Join(task.AcceptanceCriteria, " "))
+	}
+	phaseText := strings.ToLower(text.String())
+
+	var relevant []design.Component
+	for _, component := range components {
+		if component.Name != "" && strings.Contains(phaseText, strings.ToLower(component.Name)) {
+			relevant = append(relevant, component)
+		}
+	}
+	if len(relevant) == 0 {
+		return components
+	}
+	return relevant
+}
@@ -0,0 +1,122 @@
+package devplan
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mojomast/geoffrussy/internal/provider"
+	"github.com/mojomast/geoffrussy/internal/state"
+)
+
+// Pricing holds per-1K token prices by model name
+type Pricing map[string]provider.Pricing
+
+// PricingFromModels collects the prices of a provider's model list.
+// Models without a price are skipped rather than treated as free.
+func PricingFromModels(models []provider.Model) Pricing {
+	pricing := make(Pricing, len(models))
+	for _, model := range models {
+		if model.PriceInput == 0 && model.PriceOutput == 0 {
+			continue
+		}
+		pricing[model.Name] = provider.Pricing{
+			PriceInput:  model.PriceInput,
+			PriceOutput: model.PriceOutput,
+		}
+	}
+	return pricing
+}
+
+// CompareModelCosts projects the plan's total cost on each candidate model.
+// Plan estimates don't separate prompt from completion tokens, so tokens
+// are priced as an even split of input and output. Models missing from
+// pricing are left out of the result.
+func (g *Generator) CompareModelCosts(devplan *DevPlan, pricing Pricing, models []string) map[string]float64 {
+	costs := make(map[string]float64, len(models))
+	if devplan == nil {
+		return costs
+	}
+
+	tokens := devplan.TotalTokens
+	if tokens == 0 {
+		for _, phase := range devplan.Phases {
+			tokens += phase.EstimatedTokens
+		}
+	}
+
+	for _, model := range models {
+		price, ok := pricing[model]
+		if !ok {
+			continue
+		}
+		costs[model] = float64(tokens) / 1000.0 * (price.PriceInput + price.PriceOutput) / 2
+	}
+	return costs
+}
+
+// SuggestCheaperModel names the cheapest model in costs and the saving over
+// the chosen one, or returns "" when the chosen model is already cheapest
+// or has no projected cost
+func SuggestCheaperModel(costs map[string]float64, chosen string) string {
+	chosenCost, ok := costs[chosen]
+	if !ok || chosenCost <= 0 {
+		return ""
+	}
+
+	cheapest, cheapestCost := "", chosenCost
+	for model, cost := range costs {
+		if cost < cheapestCost || (cost == cheapestCost && cheapest != "" && model < cheapest) {
+			cheapest, cheapestCost = model, cost
+		}
+	}
+	if cheapest == "" {
+		return ""
+	}
+
+	saving := math.Round((chosenCost - cheapestCost) / chosenCost * 100)
+	return fmt.Sprintf("switch to %s to save ~%.0f%% ($%.2f instead of $%.2f)", cheapest, saving, cheapestCost, chosenCost)
+}
+
+// EstimateRemainingCost projects what the project's unfinished tasks will
+// cost on the generator's model. Each task is estimated from the token
+// estimate or difficulty stored with it, falling back to the description
+// heuristic for tasks saved without one, and priced as an even split of
+// input and output tokens at the plan's default rate when the model isn't
+// in pricing. Tasks that are in progress or blocked count only the
+// proportion not yet done, taken as the share of their estimate already
+// spent on them; completed and skipped tasks count nothing.
+func (g *Generator) EstimateRemainingCost(store *state.Store, projectID string, pricing Pricing) (float64, error) {
+	tasks, err := store.ListAllTasks(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	spent, err := store.GetCostByTask(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get task costs: %w", err)
+	}
+
+	var remaining float64
+	for _, task := range tasks {
+		tokens := taskWeight(Task{
+			Description:     task.Description,
+			Difficulty:      task.Difficulty,
+			EstimatedTokens: task.EstimatedTokens,
+		})
+		estimate := g.estimatePhaseCost(tokens)
+		if price, ok := pricing[g.model]; ok {
+			estimate = float64(tokens) / 1000.0 * (price.PriceInput + price.PriceOutput) / 2
+		}
+
+		switch task.Status {
+		case state.TaskNotStarted:
+			remaining += estimate
+		case state.TaskInProgress, state.TaskBlocked:
+			if estimate <= 0 {
+				continue
+			}
+			done := math.Min(spent[task.ID]/estimate, 1)
+			remaining += estimate * (1 - done)
+		}
+	}
+	return remaining, nil
+}
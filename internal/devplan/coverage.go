@@ -0,0 +1,142 @@
+package devplan
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mojomast/geoffrussy/internal/interview"
+)
+
+// successMetricsQuestionID is the interview question that records success metrics
+const successMetricsQuestionID = "pe_3"
+
+// coverageStopWords are common words ignored when matching metrics to tasks
+var coverageStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true,
+	"this": true, "from": true, "into": true, "than": true, "then": true,
+	"have": true, "will": true, "should": true, "must": true, "able": true,
+	"more": true, "less": true, "least": true, "most": true, "each": true,
+	"every": true, "within": true, "under": true, "over": true, "their": true,
+	"users": true, "user": true, "system": true,
+}
+
+// Coverage reports which interview success metrics the plan addresses
+type Coverage struct {
+	Metrics   []MetricCoverage
+	Uncovered []string
+}
+
+// MetricCoverage links a success metric to the tasks that address it
+type MetricCoverage struct {
+	Metric  string
+	TaskIDs []string
+}
+
+// Covered reports whether at least one task addresses the metric
+func (m MetricCoverage) Covered() bool {
+	return len(m.TaskIDs) > 0
+}
+
+// CoverageReport checks each success metric from the interview against the
+// plan's task descriptions and acceptance criteria. A task covers a metric
+// when it mentions at least half of the metric's keywords.
+func CoverageReport(session *interview.InterviewSession, devplan *DevPlan) (*Coverage, error) {
+	if session == nil {
+		return nil, fmt.Errorf("interview session is required")
+	}
+	if devplan == nil {
+		return nil, fmt.Errorf("devplan is required")
+	}
+
+	coverage := &Coverage{}
+	answer, ok := session.Answers[successMetricsQuestionID]
+	if !ok {
+		return coverage, nil
+	}
+
+	for _, metric := range splitMetrics(answer.Text) {
+		keywords := coverageKeywords(metric)
+		entry := MetricCoverage{Metric: metric}
+
+		for _, phase := range devplan.Phases {
+			for _, task := range phase.Tasks {
+				if taskCoversKeywords(task, keywords) {
+					entry.TaskIDs = append(entry.TaskIDs, task.ID)
+				}
+			}
+		}
+
+		coverage.Metrics = append(coverage.Metrics, entry)
+		if !entry.Covered() {
+			coverage.Uncovered = append(coverage.Uncovered, metric)
+		}
+	}
+
+	return coverage, nil
+}
+
+// splitMetrics breaks a free-text success metrics answer into individual
+// metrics, one per line or semicolon, dropping list markers
+func splitMetrics(text string) []string {
+	var metrics []string
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == '\n' || r == ';'
+	}) {
+		metric := trimListMarker(line)
+		if metric != "" {
+			metrics = append(metrics, metric)
+		}
+	}
+	return metrics
+}
+
+// coverageKeywords extracts the significant, stemmed words of a metric
+func coverageKeywords(text string) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	for _, word := range coverageWords(text) {
+		if len(word) < 3 || coverageStopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
+
+// coverageWords lowercases and stems the words in text so that variants such
+// as "latency" and "latencies" compare equal
+func coverageWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, field := range fields {
+		if len(field) > 6 {
+			fields[i] = field[:6]
+		}
+	}
+	return fields
+}
+
+// taskCoversKeywords reports whether a task mentions at least half of the
+// keywords in its description or acceptance criteria
+func taskCoversKeywords(task Task, keywords []string) bool {
+	if len(keywords) == 0 {
+		return false
+	}
+
+	text := task.Description + " " + strings.Join(task.AcceptanceCriteria, " ")
+	words := make(map[string]bool)
+	for _, word := range coverageWords(text) {
+		words[word] = true
+	}
+
+	matched := 0
+	for _, keyword := range keywords {
+		if words[keyword] {
+			matched++
+		}
+	}
+	return matched*2 >= len(keywords)
+}
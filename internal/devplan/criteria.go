@@ -0,0 +1,98 @@
+package devplan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// DefaultCriteriaCheckTimeout bounds each criteria check command
+const DefaultCriteriaCheckTimeout = 5 * time.Minute
+
+// criteriaCheckEnv lists the environment variables passed through to
+// criteria checks. Everything else, provider API keys included, is withheld
+// from commands that come from the plan. This only keeps secrets out of the
+// environment; it is not a sandbox, and checks run with the user's
+// permissions and filesystem access.
+var criteriaCheckEnv = []string{
+	"PATH", "HOME", "USER", "LANG", "TMPDIR",
+	"GOPATH", "GOCACHE", "GOMODCACHE", "GOFLAGS", "GOPROXY",
+}
+
+// SetCriteriaCheckTimeout sets how long each criteria check may run
+func (g *Generator) SetCriteriaCheckTimeout(timeout time.Duration) {
+	g.checkTimeout = timeout
+}
+
+// EvaluatePhaseCriteria runs the shell command in CriteriaChecks for each of
+// the phase's success criteria that has one, such as "go test ./..." for
+// "All tests pass", and reports whether each passed, keyed by criterion.
+// Commands run with sh in workdir, with a restricted environment (see
+// criteriaCheckEnv), and their whole process group is killed after the
+// check timeout; a non-zero exit or a timeout is a failure. Checks come from
+// the plan and are not isolated, so review a plan before running its checks.
+// Criteria without a check are left out, so the result can be fed to
+// MarkCriterionMet for the ones that were evaluated.
+func (g *Generator) EvaluatePhaseCriteria(phase *Phase, workdir string) (map[string]bool, error) {
+	if phase == nil {
+		return nil, fmt.Errorf("phase cannot be nil")
+	}
+
+	info, err := os.Stat(workdir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access workdir: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("workdir is not a directory: %s", workdir)
+	}
+
+	timeout := g.checkTimeout
+	if timeout <= 0 {
+		timeout = DefaultCriteriaCheckTimeout
+	}
+
+	results := make(map[string]bool)
+	for _, criterion := range phase.SuccessCriteria {
+		command, ok := phase.CriteriaChecks[criterion]
+		if !ok || command == "" {
+			continue
+		}
+		results[criterion] = RunCriteriaCheck(command, workdir, timeout)
+	}
+	return results, nil
+}
+
+// RunCriteriaCheck reports whether command exits successfully within
+// timeout when run as a criteria check in workdir. A zero timeout uses
+// DefaultCriteriaCheckTimeout.
+func RunCriteriaCheck(command, workdir string, timeout time.Duration) bool {
+	if timeout <= 0 {
+		timeout = DefaultCriteriaCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = workdir
+	cmd.Env = criteriaCheckEnvironment()
+	// Kill everything the check started, not just the shell
+	killProcessGroupOnCancel(cmd)
+	// Don't wait on output pipes held open by anything that survives
+	cmd.WaitDelay = time.Second
+
+	return cmd.Run() == nil
+}
+
+// criteriaCheckEnvironment returns the allowed subset of the current
+// environment
+func criteriaCheckEnvironment() []string {
+	var env []string
+	for _, name := range criteriaCheckEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package devplan
+
+import "os/exec"
+
+// killProcessGroupOnCancel leaves cmd as is; without process groups only the
+// shell itself is killed on cancel
+func killProcessGroupOnCancel(cmd *exec.Cmd) {}
@@ -0,0 +1,204 @@
+package devplan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// duplicateTaskThreshold is the word-overlap ratio above which two task
+// descriptions are treated as the same work
+const duplicateTaskThreshold = 0.8
+
+// FindDuplicateTasks groups tasks whose descriptions are identical or nearly
+// so, by task number. Each group starts with the earliest task in plan
+// order, followed by the later tasks that repeat it; tasks without a
+// duplicate are left out.
+func (g *Generator) FindDuplicateTasks(devplan *DevPlan) [][]string {
+	var groups [][]string
+	for _, group := range duplicateTaskGroups(devplan) {
+		numbers := make([]string, len(group))
+		for i, ref := range group {
+			numbers[i] = devplan.Phases[ref.phase].Tasks[ref.task].Number
+		}
+		groups = append(groups, numbers)
+	}
+	return groups
+}
+
+// DeduplicateTasks removes the repeats found by FindDuplicateTasks, keeping
+// the earliest task of each group. With merge set, the kept task also takes
+// on the acceptance criteria and implementation notes of the tasks removed.
+// Tasks in changed phases are renumbered and the phase and plan estimates
+// updated. A dependency on a removed task moves to the task kept in its
+// place when that is in the same phase, and is dropped otherwise, since
+// dependencies don't cross phases. Returns how many tasks were removed.
+func (g *Generator) DeduplicateTasks(devplan *DevPlan, merge bool) int {
+	remove := make(map[taskRef]bool)
+	keptFor := make(map[taskRef]taskRef)
+	for _, group := range duplicateTaskGroups(devplan) {
+		kept := &devplan.Phases[group[0].phase].Tasks[group[0].task]
+		for _, ref := range group[1:] {
+			if merge {
+				duplicate := devplan.Phases[ref.phase].Tasks[ref.task]
+				kept.AcceptanceCriteria = appendMissing(kept.AcceptanceCriteria, duplicate.AcceptanceCriteria)
+				kept.ImplementationNotes = appendMissing(kept.ImplementationNotes, duplicate.ImplementationNotes)
+			}
+			remove[ref] = true
+			keptFor[ref] = group[0]
+		}
+	}
+	if len(remove) == 0 {
+		return 0
+	}
+
+	for pi := range devplan.Phases {
+		phase := &devplan.Phases[pi]
+
+		// renumbered maps a dependency reference to its new form and
+		// resolved to the new number of the task it names
+		renumbered := make(map[string]string)
+		resolved := make(map[string]string)
+		var tasks []Task
+		for ti, task := range phase.Tasks {
+			if remove[taskRef{phase: pi, task: ti}] {
+				continue
+			}
+			number := fmt.Sprintf("%d.%d", phase.Number, len(tasks)+1)
+			renumbered[task.Number] = number
+			resolved[task.Number] = number
+			if task.ID != "" {
+				renumbered[task.ID] = task.ID
+				resolved[task.ID] = number
+			}
+			task.Number = number
+			tasks = append(tasks, task)
+		}
+		if len(tasks) == len(phase.Tasks) {
+			continue
+		}
+
+		// The kept task always comes first, so it has already been renumbered
+		for ti, task := range phase.Tasks {
+			kept, ok := keptFor[taskRef{phase: pi, task: ti}]
+			if !ok || kept.phase != pi {
+				continue
+			}
+			keptTask := phase.Tasks[kept.task]
+			renumbered[task.Number] = renumbered[keptTask.Number]
+			resolved[task.Number] = resolved[keptTask.Number]
+			if task.ID != "" {
+				renumbered[task.ID] = renumbered[keptTask.Number]
+				if keptTask.ID != "" {
+					renumbered[task.ID] = keptTask.ID
+				}
+				resolved[task.ID] = resolved[keptTask.Number]
+			}
+		}
+
+		for ti := range tasks {
+			var deps []string
+			seen := map[string]bool{tasks[ti].Number: true}
+			for _, dep := range tasks[ti].DependsOn {
+				dep = strings.TrimSpace(dep)
+				updated, ok := renumbered[dep]
+				if !ok || seen[resolved[dep]] {
+					continue
+				}
+				seen[resolved[dep]] = true
+				deps = append(deps, updated)
+			}
+			tasks[ti].DependsOn = deps
+		}
+		phase.Tasks = tasks
+
+		tokens := g.estimateTasksTokens(tasks)
+		cost := g.estimatePhaseCost(tokens)
+		devplan.TotalTokens += tokens - phase.EstimatedTokens
+		devplan.TotalCost += cost - phase.EstimatedCost
+		phase.EstimatedTokens = tokens
+		phase.EstimatedCost = cost
+	}
+
+	return len(remove)
+}
+
+// taskRef locates a task by phase and task index
+type taskRef struct {
+	phase int
+	task  int
+}
+
+// duplicateTaskGroups compares every task against the first task of each
+// group found so far, in plan order
+func duplicateTaskGroups(devplan *DevPlan) [][]taskRef {
+	var groups [][]taskRef
+	var groupWords []map[string]bool
+
+	for pi, phase := range devplan.Phases {
+		for ti, task := range phase.Tasks {
+			words := taskWords(task.Description)
+			if len(words) == 0 {
+				continue
+			}
+
+			matched := false
+			for gi := range groups {
+				if taskWordSimilarity(words, groupWords[gi]) >= duplicateTaskThreshold {
+					groups[gi] = append(groups[gi], taskRef{phase: pi, task: ti})
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				groups = append(groups, []taskRef{{phase: pi, task: ti}})
+				groupWords = append(groupWords, words)
+			}
+		}
+	}
+
+	var duplicates [][]taskRef
+	for _, group := range groups {
+		if len(group) > 1 {
+			duplicates = append(duplicates, group)
+		}
+	}
+	return duplicates
+}
+
+// taskWords is the set of stemmed words in a task description
+func taskWords(description string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range coverageWords(description) {
+		words[word] = true
+	}
+	return words
+}
+
+// taskWordSimilarity returns the Jaccard similarity of two word sets
+func taskWordSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// appendMissing appends the items of extra not already in list
+func appendMissing(list, extra []string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, item := range list {
+		seen[item] = true
+	}
+	for _, item := range extra {
+		if !seen[item] {
+			seen[item] = true
+			list = append(list, item)
+		}
+	}
+	return list
+}
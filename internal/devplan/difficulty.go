@@ -0,0 +1,131 @@
+package devplan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Task difficulty levels
+const (
+	DifficultyTrivial  = "trivial"
+	DifficultyModerate = "moderate"
+	DifficultyComplex  = "complex"
+)
+
+// difficultyTokenEstimates approximate the tokens a task of each difficulty
+// takes to execute, for weighting tasks without their own estimate
+var difficultyTokenEstimates = map[string]int{
+	DifficultyTrivial:  500,
+	DifficultyModerate: 1000,
+	DifficultyComplex:  2500,
+}
+
+// complexityKeywords are description terms that usually signal extra work,
+// weighted by how much effort they tend to add
+var complexityKeywords = map[string]int{
+	"integrat":    2,
+	"migrat":      2,
+	"distributed": 2,
+	"concurren":   2,
+	"real-time":   2,
+	"realtime":    2,
+	"refactor":    1,
+	"authenticat": 1,
+	"authoriz":    1,
+	"security":    1,
+	"optimiz":     1,
+	"deploy":      1,
+	"sync":        1,
+}
+
+// EstimateDifficulty classifies a task as trivial, moderate or complex.
+// The LLM is asked when a provider is configured; otherwise, or if its
+// answer can't be used, a heuristic based on acceptance criteria count and
+// description keywords is applied.
+func (g *Generator) EstimateDifficulty(task Task) string {
+	if g.provider != nil {
+		response, err := g.provider.CallWithOptions(g.model, buildDifficultyPrompt(task), planCallOptions)
+		if err == nil {
+			if difficulty := parseDifficulty(response.Content); difficulty != "" {
+				return difficulty
+			}
+		}
+	}
+
+	return estimateDifficultyHeuristic(task)
+}
+
+// buildDifficultyPrompt creates the prompt for classifying a task
+func buildDifficultyPrompt(task Task) string {
+	var prompt strings.Builder
+	prompt.WriteString("Classify the difficulty of the following development task for an LLM coding agent.\n\n")
+	prompt.WriteString(fmt.Sprintf("TASK: %s\n", task.Description))
+	if len(task.AcceptanceCriteria) > 0 {
+		prompt.WriteString("ACCEPTANCE CRITERIA:\n")
+		for _, criterion := range task.AcceptanceCriteria {
+			prompt.WriteString(fmt.Sprintf("- %s\n", criterion))
+		}
+	}
+	prompt.WriteString("\nRespond with exactly one word: trivial, moderate, or complex.")
+	return prompt.String()
+}
+
+// parseDifficulty extracts a difficulty level from an LLM response
+func parseDifficulty(response string) string {
+	normalized := strings.ToLower(strings.TrimSpace(response))
+	for _, difficulty := range []string{DifficultyComplex, DifficultyModerate, DifficultyTrivial} {
+		if strings.HasPrefix(normalized, difficulty) {
+			return difficulty
+		}
+	}
+	return ""
+}
+
+// estimateDifficultyHeuristic scores a task from its acceptance criteria and
+// description keywords
+func estimateDifficultyHeuristic(task Task) string {
+	score := 0
+
+	switch criteria := len(task.AcceptanceCriteria); {
+	case criteria >= 5:
+		score += 2
+	case criteria >= 3:
+		score++
+	}
+
+	description := strings.ToLower(task.Description)
+	for keyword, weight := range complexityKeywords {
+		score += strings.Count(description, keyword) * weight
+	}
+
+	switch {
+	case score >= 4:
+		return DifficultyComplex
+	case score >= 1:
+		return DifficultyModerate
+	default:
+		return DifficultyTrivial
+	}
+}
+
+// taskWeight returns a task's estimated tokens, falling back to an estimate
+// from its difficulty, or from the heuristic when no difficulty is recorded
+func taskWeight(task Task) int {
+	if task.EstimatedTokens > 0 {
+		return task.EstimatedTokens
+	}
+	difficulty := task.Difficulty
+	if !isValidDifficulty(difficulty) {
+		difficulty = estimateDifficultyHeuristic(task)
+	}
+	return difficultyTokenEstimates[difficulty]
+}
+
+// isValidDifficulty reports whether difficulty is a known level
+func isValidDifficulty(difficulty string) bool {
+	switch difficulty {
+	case DifficultyTrivial, DifficultyModerate, DifficultyComplex:
+		return true
+	}
+	return false
+}
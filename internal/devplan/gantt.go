@@ -0,0 +1,129 @@
+package devplan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// difficultyDurationEstimates approximate how long an agent takes on a task
+// of each difficulty
+var difficultyDurationEstimates = map[string]time.Duration{
+	DifficultyTrivial:  10 * time.Minute,
+	DifficultyModerate: 20 * time.Minute,
+	DifficultyComplex:  45 * time.Minute,
+}
+
+// minPhaseDuration is the shortest a phase is estimated to take, so phases
+// without tasks still show up on a timeline
+const minPhaseDuration = 15 * time.Minute
+
+// EstimateDurations estimates how long each phase takes, keyed by phase ID,
+// from the difficulty of its tasks. Tasks without a recorded difficulty are
+// classified with the heuristic.
+func (g *Generator) EstimateDurations(devplan *DevPlan) map[string]time.Duration {
+	durations := make(map[string]time.Duration, len(devplan.Phases))
+	for _, phase := range devplan.Phases {
+		var total time.Duration
+		for _, task := range phase.Tasks {
+			difficulty := task.Difficulty
+			if !isValidDifficulty(difficulty) {
+				difficulty = estimateDifficultyHeuristic(task)
+			}
+			total += difficultyDurationEstimates[difficulty]
+		}
+		if total < minPhaseDuration {
+			total = minPhaseDuration
+		}
+		durations[phase.ID] = total
+	}
+	return durations
+}
+
+// ExportGanttMermaid renders the devplan as a Mermaid gantt chart. Each phase
+// is a bar starting after all of its dependencies, or at the plan's creation
+// time if it has none, lasting as long as EstimateDurations predicts.
+// Completed phases are marked done and phases in progress active. If the
+// dependencies are invalid the phases are chained in plan order instead.
+func (g *Generator) ExportGanttMermaid(devplan *DevPlan) string {
+	durations := g.EstimateDurations(devplan)
+	after := g.ganttPrerequisites(devplan)
+
+	var md strings.Builder
+	md.WriteString("gantt\n")
+	md.WriteString("    title Development Plan\n")
+	md.WriteString("    dateFormat YYYY-MM-DD HH:mm\n")
+	md.WriteString("    axisFormat %H:%M\n")
+	md.WriteString("    section Phases\n")
+
+	start := devplan.CreatedAt
+	if start.IsZero() {
+		start = time.Now()
+	}
+
+	for i, phase := range devplan.Phases {
+		var tags []string
+		switch phase.Status {
+		case PhaseCompleted:
+			tags = append(tags, "done")
+		case PhaseInProgress:
+			tags = append(tags, "active")
+		}
+		tags = append(tags, ganttTaskID(phase))
+
+		if len(after[i]) > 0 {
+			var deps []string
+			for _, dep := range after[i] {
+				deps = append(deps, ganttTaskID(devplan.Phases[dep]))
+			}
+			tags = append(tags, "after "+strings.Join(deps, " "))
+		} else {
+			tags = append(tags, start.Format("2006-01-02 15:04"))
+		}
+		tags = append(tags, fmt.Sprintf("%dm", int(durations[phase.ID].Minutes())))
+
+		label := ganttLabel(fmt.Sprintf("Phase %d - %s", phase.Number, phase.Title))
+		md.WriteString(fmt.Sprintf("    %s :%s\n", label, strings.Join(tags, ", ")))
+	}
+
+	return md.String()
+}
+
+// ganttPrerequisites returns, for each phase, the indexes of the phases it
+// must start after. Invalid dependencies fall back to running every phase
+// after the one before it.
+func (g *Generator) ganttPrerequisites(devplan *DevPlan) [][]int {
+	phases := devplan.Phases
+	after := make([][]int, len(phases))
+
+	if _, err := g.ExportExecutionWaves(devplan); err != nil {
+		for i := 1; i < len(phases); i++ {
+			after[i] = []int{i - 1}
+		}
+		return after
+	}
+
+	lookup := phaseIndexLookup(phases)
+	for i, phase := range phases {
+		seen := make(map[int]bool)
+		for _, dep := range phase.Dependencies {
+			idx := lookup[strings.TrimSpace(dep)]
+			if !seen[idx] {
+				seen[idx] = true
+				after[i] = append(after[i], idx)
+			}
+		}
+	}
+	return after
+}
+
+// ganttTaskID returns the Mermaid task ID for a phase
+func ganttTaskID(phase Phase) string {
+	return fmt.Sprintf("phase%d", phase.Number)
+}
+
+// ganttLabel strips characters that end a Mermaid gantt task name early
+func ganttLabel(text string) string {
+	replacer := strings.NewReplacer(":", " -", ";", ",", "#", "", "\n", " ")
+	return strings.TrimSpace(replacer.Replace(text))
+}
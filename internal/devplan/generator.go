@@ -3,20 +3,39 @@ package devplan
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mojomast/geoffrussy/internal/design"
+	"github.com/mojomast/geoffrussy/internal/interview"
 	"github.com/mojomast/geoffrussy/internal/provider"
 	"github.com/mojomast/geoffrussy/internal/state"
 )
 
 // Generator generates development plans from architecture
 type Generator struct {
-	provider provider.Provider
-	model    string
+	provider     provider.Provider
+	model        string
+	checkTimeout time.Duration // Limit on each criteria check; zero uses DefaultCriteriaCheckTimeout
+	warnings     []string      // Problems worked around by the last GeneratePhases call
 }
 
+// planCallOptions sends the planner role as the system prompt and keeps
+// phase and task output close to the requested format. Plan prompts embed
+// the whole interview and architecture, so sections are dropped rather than
+// failing when they outgrow the model's context window.
+var planCallOptions = provider.CallOptions{
+	Temperature:     provider.Float64(0.2),
+	System:          "You are an expert software project planner.",
+	ContextStrategy: provider.ContextStrategyTruncate,
+}
+
+// planRetryMaxTokens is the output limit for retrying a plan response that
+// was cut off at the provider's default limit
+const planRetryMaxTokens = 16384
+
 // NewGenerator creates a new devplan generator
 func NewGenerator(provider provider.Provider, model string) *Generator {
 	return &Generator{
@@ -27,17 +46,19 @@ func NewGenerator(provider provider.Provider, model string) *Generator {
 
 // Phase represents a development phase
 type Phase struct {
-	ID              string      `json:"id"`
-	Number          int         `json:"number"`
-	Title           string      `json:"title"`
-	Objective       string      `json:"objective"`
-	SuccessCriteria []string    `json:"success_criteria"`
-	Dependencies    []string    `json:"dependencies"`
-	Tasks           []Task      `json:"tasks"`
-	EstimatedTokens int         `json:"estimated_tokens"`
-	EstimatedCost   float64     `json:"estimated_cost"`
-	Status          PhaseStatus `json:"status"`
-	CreatedAt       time.Time   `json:"created_at"`
+	ID              string            `json:"id"`
+	Number          int               `json:"number"`
+	Title           string            `json:"title"`
+	Objective       string            `json:"objective"`
+	SuccessCriteria []string          `json:"success_criteria"`
+	Dependencies    []string          `json:"dependencies"`
+	Tasks           []Task            `json:"tasks"`
+	EstimatedTokens int               `json:"estimated_tokens"`
+	EstimatedCost   float64           `json:"estimated_cost"`
+	Status          PhaseStatus       `json:"status"`
+	CreatedAt       time.Time         `json:"created_at"`
+	RollbackSteps   []string          `json:"rollback_steps,omitempty"`  // How to revert the phase's changes
+	CriteriaChecks  map[string]string `json:"criteria_checks,omitempty"` // Shell commands verifying success criteria, keyed by criterion
 }
 
 // PhaseStatus represents the status of a phase
@@ -59,6 +80,10 @@ type Task struct {
 	ImplementationNotes []string   `json:"implementation_notes"`
 	BlockersEncountered []string   `json:"blockers_encountered"`
 	Status              TaskStatus `json:"status"`
+	Difficulty          string     `json:"difficulty,omitempty"`
+	EstimatedTokens     int        `json:"estimated_tokens,omitempty"`
+	DependsOn           []string   `json:"depends_on,omitempty"`     // Numbers or IDs of tasks in the same phase that must finish first
+	ProgressNotes       []string   `json:"progress_notes,omitempty"` // Log of attempts and decisions made while working on the task, oldest first
 }
 
 // TaskStatus represents the status of a task
@@ -87,16 +112,43 @@ func (g *Generator) GeneratePhases(architecture *design.Architecture, interviewD
 		return nil, fmt.Errorf("provider is required for phase generation")
 	}
 
+	g.warnings = nil
 	prompt := g.buildPhasesPrompt(architecture, interviewData)
 
-	response, err := g.provider.Call(g.model, prompt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate phases: %w", err)
+	// Providers with tool calling submit phases as structured arguments,
+	// which avoids scraping JSON out of prose. Anything else, or a tool call
+	// that yields nothing, falls back to parsing the text response.
+	var phases []Phase
+	if caller, ok := g.provider.(provider.ToolCaller); ok {
+		var err error
+		phases, err = g.generatePhasesWithTools(caller, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate phases: %w", err)
+		}
 	}
 
-	phases, err := g.parsePhasesResponse(response.Content)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse phases: %w", err)
+	if len(phases) == 0 {
+		response, err := g.provider.CallWithOptions(g.model, prompt, planCallOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate phases: %w", err)
+		}
+		if response.Truncated() {
+			// Truncated JSON can't be parsed, so ask again with room to finish
+			retryOpts := planCallOptions
+			retryOpts.MaxTokens = planRetryMaxTokens
+			response, err = g.provider.CallWithOptions(g.model, prompt, retryOpts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate phases: %w", err)
+			}
+			if response.Truncated() {
+				return nil, fmt.Errorf("phase generation output was truncated at %d tokens", planRetryMaxTokens)
+			}
+		}
+
+		phases, err = g.parsePhasesResponse(response.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse phases: %w", err)
+		}
 	}
 
 	// Estimate tokens and costs for each phase
@@ -104,18 +156,39 @@ func (g *Generator) GeneratePhases(architecture *design.Architecture, interviewD
 		phases[i].EstimatedTokens = g.estimatePhaseTokens(&phases[i])
 		phases[i].EstimatedCost = g.estimatePhaseCost(phases[i].EstimatedTokens)
 		phases[i].CreatedAt = time.Now()
+
+		for j := range phases[i].Tasks {
+			if !isValidDifficulty(phases[i].Tasks[j].Difficulty) {
+				phases[i].Tasks[j].Difficulty = estimateDifficultyHeuristic(phases[i].Tasks[j])
+			}
+		}
+
+		// Bad task references fall back to plain number order rather than
+		// failing the whole plan
+		if err := g.ValidateTaskDependencies(&phases[i]); err != nil {
+			g.warnings = append(g.warnings, fmt.Sprintf("Phase %d: dropped task dependencies (%v); its tasks run in number order", phases[i].Number, err))
+			for j := range phases[i].Tasks {
+				phases[i].Tasks[j].DependsOn = nil
+			}
+		}
 	}
 
 	return phases, nil
 }
 
+// Warnings returns the problems the last GeneratePhases call worked around,
+// such as invalid task dependencies it dropped
+func (g *Generator) Warnings() []string {
+	return append([]string{}, g.warnings...)
+}
+
 // buildPhasesPrompt creates the prompt for phase generation
 func (g *Generator) buildPhasesPrompt(architecture *design.Architecture, interviewData *state.InterviewData) string {
-	prompt := `You are an expert software project planner. Based on the following architecture and requirements, generate 7-10 executable development phases.
+	prompt := `Based on the following architecture and requirements, generate 7-10 executable development phases.
 
 PROJECT: ` + interviewData.ProjectName + `
 PROBLEM: ` + interviewData.ProblemStatement + `
-
+` + interview.FormatGlossaryForPrompt(interviewData.Glossary) + `
 ARCHITECTURE OVERVIEW:
 ` + architecture.SystemOverview + `
 
@@ -135,6 +208,8 @@ Each phase should:
 3. Be completable in 1-2 hours by an LLM agent
 4. Include 3-5 actionable tasks
 5. Have clear objective and success criteria
+6. List in each task's depends_on the numbers of earlier tasks in the same phase it needs; leave it empty for independent tasks
+7. Map each success criterion that a shell command run in the project directory can verify, such as "go test ./..." for "All tests pass", to that command in criteria_checks; leave out criteria that need a human to judge
 
 Follow this standard order:
 - Phase 0: Setup & Infrastructure
@@ -160,13 +235,16 @@ Your thinking here...
     "title": "Phase Title",
     "objective": "Clear objective",
     "success_criteria": ["Criterion 1", "Criterion 2"],
+    "criteria_checks": {"Criterion 1": "shell command that exits 0 when it is met"},
     "dependencies": ["0", "1"],
     "tasks": [
       {
         "number": "0.1",
         "description": "Task description",
         "acceptance_criteria": ["Acceptance 1", "Acceptance 2"],
-        "implementation_notes": ["Note 1", "Note 2"]
+        "implementation_notes": ["Note 1", "Note 2"],
+        "difficulty": "trivial | moderate | complex",
+        "depends_on": []
       }
     ]
   }
@@ -306,31 +384,7 @@ func (g *Generator) parsePhasesResponse(response string) ([]Phase, error) {
 		if err != nil {
 			phases = defaultPhases
 		} else {
-			// Post-process parsed phases
-			for i := range phases {
-				// Generate ID if missing
-				if phases[i].ID == "" {
-					phases[i].ID = fmt.Sprintf("phase-%d", phases[i].Number)
-				}
-
-				// Ensure Status is set
-				if phases[i].Status == "" {
-					phases[i].Status = PhaseNotStarted
-				}
-
-				// Process tasks
-				for j := range phases[i].Tasks {
-					// Generate Task ID if missing
-					if phases[i].Tasks[j].ID == "" {
-						phases[i].Tasks[j].ID = fmt.Sprintf("task-%d-%d", phases[i].Number, j+1)
-					}
-
-					// Ensure Task Status is set
-					if phases[i].Tasks[j].Status == "" {
-						phases[i].Tasks[j].Status = TaskNotStarted
-					}
-				}
-			}
+			fillPhaseDefaults(phases)
 		}
 	} else {
 		phases = defaultPhases
@@ -339,6 +393,28 @@ func (g *Generator) parsePhasesResponse(response string) ([]Phase, error) {
 	return phases, nil
 }
 
+// fillPhaseDefaults generates missing phase and task IDs and sets unset
+// statuses to not started
+func fillPhaseDefaults(phases []Phase) {
+	for i := range phases {
+		if phases[i].ID == "" {
+			phases[i].ID = fmt.Sprintf("phase-%d", phases[i].Number)
+		}
+		if phases[i].Status == "" {
+			phases[i].Status = PhaseNotStarted
+		}
+
+		for j := range phases[i].Tasks {
+			if phases[i].Tasks[j].ID == "" {
+				phases[i].Tasks[j].ID = fmt.Sprintf("task-%d-%d", phases[i].Number, j+1)
+			}
+			if phases[i].Tasks[j].Status == "" {
+				phases[i].Tasks[j].Status = TaskNotStarted
+			}
+		}
+	}
+}
+
 // estimatePhaseTokens estimates the token usage for a phase
 func (g *Generator) estimatePhaseTokens(phase *Phase) int {
 	// Rough estimation: 1000 tokens per task
@@ -376,6 +452,12 @@ func (g *Generator) ExportPhaseMarkdown(phase *Phase) (string, error) {
 	for _, task := range phase.Tasks {
 		md.WriteString(fmt.Sprintf("### %s: %s\n\n", task.Number, task.Description))
 		md.WriteString(fmt.Sprintf("**Status:** %s\n\n", task.Status))
+		if task.Difficulty != "" {
+			md.WriteString(fmt.Sprintf("**Difficulty:** %s\n\n", task.Difficulty))
+		}
+		if len(task.DependsOn) > 0 {
+			md.WriteString(fmt.Sprintf("**Depends On:** %s\n\n", strings.Join(task.DependsOn, ", ")))
+		}
 
 		if len(task.AcceptanceCriteria) > 0 {
 			md.WriteString("**Acceptance Criteria:**\n")
@@ -392,6 +474,22 @@ func (g *Generator) ExportPhaseMarkdown(phase *Phase) (string, error) {
 			}
 			md.WriteString("\n")
 		}
+
+		if len(task.ProgressNotes) > 0 {
+			md.WriteString("**Progress Log:**\n")
+			for _, note := range task.ProgressNotes {
+				md.WriteString(fmt.Sprintf("- %s\n", note))
+			}
+			md.WriteString("\n")
+		}
+	}
+
+	if len(phase.RollbackSteps) > 0 {
+		md.WriteString("## Rollback Plan\n\n")
+		for i, step := range phase.RollbackSteps {
+			md.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
+		}
+		md.WriteString("\n")
 	}
 
 	md.WriteString(fmt.Sprintf("## Estimates\n\n"))
@@ -540,6 +638,163 @@ func (g *Generator) SplitPhase(phase *Phase, splitPoint int) ([]*Phase, error) {
 	return []*Phase{phase1, phase2}, nil
 }
 
+// AutoSplitOversized splits every phase whose estimated tokens exceed
+// maxTokens into parts using SplitPhase. The resulting phases are renumbered
+// sequentially from the first phase's number, each part depends on the part
+// before it, and dependencies on a split phase point at its last part.
+// Phases with a single task cannot be split and are kept as they are.
+func (g *Generator) AutoSplitOversized(phases []Phase, maxTokens int) ([]Phase, error) {
+	if maxTokens <= 0 {
+		return nil, fmt.Errorf("max tokens must be positive")
+	}
+	return g.splitEach(phases, func(phase Phase) ([]Phase, error) {
+		return g.splitToBudget(phase, maxTokens)
+	})
+}
+
+// splitEach replaces every phase with the parts split returns, then
+// renumbers the phases and their tasks and points dependencies on a split
+// phase at its last part
+func (g *Generator) splitEach(phases []Phase, split func(phase Phase) ([]Phase, error)) ([]Phase, error) {
+	if len(phases) == 0 {
+		return []Phase{}, nil
+	}
+
+	// Split each phase, remembering which original phase each part came from
+	type splitGroup struct {
+		original Phase
+		parts    []Phase
+	}
+	groups := make([]splitGroup, 0, len(phases))
+	for _, phase := range phases {
+		// Copy tasks so renumbering in SplitPhase doesn't touch the caller's slice
+		phase.Tasks = append([]Task{}, phase.Tasks...)
+		parts, err := split(phase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split phase %s: %w", phase.ID, err)
+		}
+		groups = append(groups, splitGroup{original: phase, parts: parts})
+	}
+
+	// Assign new numbers and map old references to each group's last part
+	nextNumber := phases[0].Number
+	lastPart := make(map[string]string)
+	for gi := range groups {
+		group := &groups[gi]
+		for pi := range group.parts {
+			group.parts[pi].Number = nextNumber
+			nextNumber++
+		}
+		last := strconv.Itoa(group.parts[len(group.parts)-1].Number)
+		lastPart[strconv.Itoa(group.original.Number)] = last
+		lastPart[group.original.ID] = last
+	}
+
+	result := []Phase{}
+	for _, group := range groups {
+		split := len(group.parts) > 1
+		for pi, part := range group.parts {
+			if split {
+				part.ID = fmt.Sprintf("%s-part%d", group.original.ID, pi+1)
+				part.Title = fmt.Sprintf("%s (Part %d)", group.original.Title, pi+1)
+			}
+
+			if pi == 0 {
+				part.Dependencies = remapDependencies(group.original.Dependencies, lastPart)
+			} else {
+				part.Dependencies = []string{strconv.Itoa(group.parts[pi-1].Number)}
+			}
+
+			for ti := range part.Tasks {
+				part.Tasks[ti].Number = fmt.Sprintf("%d.%d", part.Number, ti+1)
+			}
+
+			result = append(result, part)
+		}
+	}
+
+	return result, nil
+}
+
+// splitToBudget recursively splits a phase until every part fits maxTokens
+// or cannot be split further
+func (g *Generator) splitToBudget(phase Phase, maxTokens int) ([]Phase, error) {
+	tokens := phase.EstimatedTokens
+	if tokens == 0 {
+		tokens = g.estimatePhaseTokens(&phase)
+	}
+	if tokens <= maxTokens || len(phase.Tasks) < 2 {
+		return []Phase{phase}, nil
+	}
+
+	// Size the first part so the phase divides into roughly equal pieces
+	parts := (tokens + maxTokens - 1) / maxTokens
+	if parts > len(phase.Tasks) {
+		parts = len(phase.Tasks)
+	}
+	splitPoint := (len(phase.Tasks) + parts - 1) / parts
+	if splitPoint >= len(phase.Tasks) {
+		splitPoint = len(phase.Tasks) - 1
+	}
+
+	halves, err := g.SplitPhase(&phase, splitPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := g.splitToBudget(*halves[0], maxTokens)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := g.splitToBudget(*halves[1], maxTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(first, rest...), nil
+}
+
+// splitToTaskCount recursively splits a phase into roughly equal parts of
+// at most maxTasks tasks
+func (g *Generator) splitToTaskCount(phase Phase, maxTasks int) ([]Phase, error) {
+	if len(phase.Tasks) <= maxTasks {
+		return []Phase{phase}, nil
+	}
+
+	parts := (len(phase.Tasks) + maxTasks - 1) / maxTasks
+	splitPoint := (len(phase.Tasks) + parts - 1) / parts
+
+	halves, err := g.SplitPhase(&phase, splitPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := g.splitToTaskCount(*halves[0], maxTasks)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := g.splitToTaskCount(*halves[1], maxTasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(first, rest...), nil
+}
+
+// remapDependencies rewrites phase references through mapping, keeping any
+// reference it doesn't know about
+func remapDependencies(deps []string, mapping map[string]string) []string {
+	remapped := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		if mapped, ok := mapping[strings.TrimSpace(dep)]; ok {
+			remapped = append(remapped, mapped)
+		} else {
+			remapped = append(remapped, dep)
+		}
+	}
+	return remapped
+}
+
 // estimateTasksTokens estimates tokens for a list of tasks
 func (g *Generator) estimateTasksTokens(tasks []Task) int {
 	return 1000 + (len(tasks) * 1000)
@@ -618,6 +873,92 @@ func (g *Generator) ValidatePhaseOrder(phases []Phase) (bool, []string) {
 	return len(issues) == 0, issues
 }
 
+// ExportExecutionWaves groups phases into waves that can run in parallel.
+// Each wave holds the IDs of phases whose dependencies all appear in earlier
+// waves. Dependencies may reference a phase by ID or by number. Returns an
+// error if the dependencies contain a cycle or reference an unknown phase.
+func (g *Generator) ExportExecutionWaves(devplan *DevPlan) ([][]string, error) {
+	if devplan == nil {
+		return nil, fmt.Errorf("devplan cannot be nil")
+	}
+
+	phases := devplan.Phases
+	lookup := phaseIndexLookup(phases)
+
+	// Build the dependency graph as in-degrees and dependents
+	inDegree := make([]int, len(phases))
+	dependents := make([][]int, len(phases))
+	for i, phase := range phases {
+		seen := make(map[int]bool)
+		for _, dep := range phase.Dependencies {
+			depIdx, ok := lookup[strings.TrimSpace(dep)]
+			if !ok {
+				return nil, fmt.Errorf("phase %s depends on unknown phase %s", phase.ID, dep)
+			}
+			if depIdx == i {
+				return nil, fmt.Errorf("phase %s depends on itself", phase.ID)
+			}
+			if seen[depIdx] {
+				continue
+			}
+			seen[depIdx] = true
+			inDegree[i]++
+			dependents[depIdx] = append(dependents[depIdx], i)
+		}
+	}
+
+	// Kahn's algorithm, peeling off one layer at a time
+	var current []int
+	for i := range phases {
+		if inDegree[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	waves := [][]string{}
+	placed := 0
+	for len(current) > 0 {
+		sort.Slice(current, func(a, b int) bool {
+			return phases[current[a]].Number < phases[current[b]].Number
+		})
+
+		wave := make([]string, 0, len(current))
+		var next []int
+		for _, idx := range current {
+			wave = append(wave, phases[idx].ID)
+			for _, dependent := range dependents[idx] {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+
+		waves = append(waves, wave)
+		placed += len(current)
+		current = next
+	}
+
+	if placed != len(phases) {
+		return nil, fmt.Errorf("phase dependencies contain a cycle")
+	}
+
+	return waves, nil
+}
+
+// phaseIndexLookup maps each phase's number and ID to its index, with IDs
+// taking precedence when a number and an ID collide
+func phaseIndexLookup(phases []Phase) map[string]int {
+	lookup := make(map[string]int, len(phases)*2)
+	for i, phase := range phases {
+		lookup[strconv.Itoa(phase.Number)] = i
+	}
+	for i, phase := range phases {
+		lookup[phase.ID] = i
+	}
+	return lookup
+}
+
 // ChangelogEntry represents a single changelog entry
 type ChangelogEntry struct {
 	Timestamp   time.Time
@@ -712,6 +1053,29 @@ func (g *Generator) UpdateMasterPlanWithChangelog(devplan *DevPlan, changelog *C
 	return md.String(), nil
 }
 
+// WeightedProgress returns the percentage of estimated effort completed, so a
+// complex task counts for more than a trivial one. Each task is weighted by
+// its EstimatedTokens, or by a token estimate for its difficulty when that
+// isn't set.
+func (g *Generator) WeightedProgress(devplan *DevPlan) float64 {
+	total := 0
+	completed := 0
+	for _, phase := range devplan.Phases {
+		for _, task := range phase.Tasks {
+			weight := taskWeight(task)
+			total += weight
+			if task.Status == TaskCompleted {
+				completed += weight
+			}
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return float64(completed) / float64(total) * 100
+}
+
 // VisualizeProgress generates a visual representation of DevPlan progress
 func (g *Generator) VisualizeProgress(devplan *DevPlan) string {
 	var vis strings.Builder
@@ -743,8 +1107,10 @@ func (g *Generator) VisualizeProgress(devplan *DevPlan) string {
 		completionPercentage = float64(completedTasks) / float64(totalTasks) * 100
 	}
 
-	vis.WriteString(fmt.Sprintf("**Overall Progress:** %.1f%% (%d/%d tasks completed)\n\n",
+	vis.WriteString(fmt.Sprintf("**Overall Progress:** %.1f%% (%d/%d tasks completed)\n",
 		completionPercentage, completedTasks, totalTasks))
+	vis.WriteString(fmt.Sprintf("**Weighted Progress:** %.1f%% (by estimated effort)\n\n",
+		g.WeightedProgress(devplan)))
 	vis.WriteString(fmt.Sprintf("**In Progress:** %d tasks\n", inProgressTasks))
 	vis.WriteString(fmt.Sprintf("**Blocked:** %d tasks\n\n", blockedTasks))
 
@@ -819,17 +1185,22 @@ func getStatusIcon(status interface{}) string {
 	return "⬜"
 }
 
-// RecordTaskCompletion records a task completion in the changelog
-func (changelog *Changelog) RecordTaskCompletion(taskID, taskDescription, phaseTitle string) {
+// RecordTaskCompletion records a task completion in the changelog, listing
+// the SHAs of any commits that implemented it
+func (changelog *Changelog) RecordTaskCompletion(taskID, taskDescription, phaseTitle string, commits ...string) {
+	details := map[string]string{
+		"task_id":     taskID,
+		"phase":       phaseTitle,
+		"completed_at": time.Now().Format(time.RFC3339),
+	}
+	if len(commits) > 0 {
+		details["commits"] = strings.Join(commits, ", ")
+	}
 	changelog.AddEntry(
 		"task_completed",
 		fmt.Sprintf("Completed task: %s", taskDescription),
 		"geoffrussy-agent",
-		map[string]string{
-			"task_id":     taskID,
-			"phase":       phaseTitle,
-			"completed_at": time.Now().Format(time.RFC3339),
-		},
+		details,
 	)
 }
 
@@ -1,10 +1,19 @@
 package devplan
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/mojomast/geoffrussy/internal/design"
+	"github.com/mojomast/geoffrussy/internal/interview"
 	"github.com/mojomast/geoffrussy/internal/provider"
 	"github.com/mojomast/geoffrussy/internal/state"
 )
@@ -12,6 +21,46 @@ import (
 // MockProvider for testing
 type MockProvider struct {
 	response string
+	lastOpts provider.CallOptions
+
+	// truncatedCalls makes that many calls report a length finish reason
+	truncatedCalls int
+}
+
+// ToolMockProvider is a MockProvider that answers CallWithTools with fixed
+// tool calls
+type ToolMockProvider struct {
+	MockProvider
+	toolCalls     []provider.ToolCall
+	lastTools     []provider.Tool
+	lastToolOpts  provider.CallOptions
+	toolCallsMade int
+
+	// truncatedToolCalls makes that many tool calls report a length finish reason
+	truncatedToolCalls int
+
+	// toolErr, when set, is returned by every tool call
+	toolErr error
+}
+
+func (m *ToolMockProvider) CallWithTools(model string, messages []provider.Message, tools []provider.Tool, opts provider.CallOptions) (*provider.Response, error) {
+	m.lastTools = tools
+	m.lastToolOpts = opts
+	m.toolCallsMade++
+	if m.toolErr != nil {
+		return nil, m.toolErr
+	}
+	finishReason := provider.FinishReasonToolCalls
+	if m.truncatedToolCalls > 0 {
+		m.truncatedToolCalls--
+		finishReason = provider.FinishReasonLength
+	}
+	return &provider.Response{
+		Model:        model,
+		Provider:     "mock",
+		ToolCalls:    m.toolCalls,
+		FinishReason: finishReason,
+	}, nil
 }
 
 func (m *MockProvider) Name() string {
@@ -44,6 +93,20 @@ func (m *MockProvider) Call(model string, prompt string) (*provider.Response, er
 	}, nil
 }
 
+func (m *MockProvider) CallWithOptions(model string, prompt string, opts provider.CallOptions) (*provider.Response, error) {
+	m.lastOpts = opts
+	if m.truncatedCalls > 0 {
+		m.truncatedCalls--
+		return &provider.Response{
+			Content:      m.response[:len(m.response)/2],
+			Model:        model,
+			Provider:     "mock",
+			FinishReason: provider.FinishReasonLength,
+		}, nil
+	}
+	return m.Call(model, prompt)
+}
+
 func (m *MockProvider) Stream(model string, prompt string) (<-chan string, error) {
 	ch := make(chan string, 1)
 	ch <- m.response
@@ -63,6 +126,10 @@ func (m *MockProvider) SupportsCodingPlan() bool {
 	return false
 }
 
+func (m *MockProvider) Ping(model string) error {
+	return nil
+}
+
 func TestDevPlanGenerator(t *testing.T) {
 	mockResponse := `
 [
@@ -169,6 +236,27 @@ Thinking about the architecture...
 		}
 	})
 
+	t.Run("GeneratePhases_RetriesTruncatedOutput", func(t *testing.T) {
+		truncatingProvider := &MockProvider{response: mockResponse, truncatedCalls: 1}
+		truncatingGenerator := NewGenerator(truncatingProvider, "test-model")
+
+		phases, err := truncatingGenerator.GeneratePhases(architecture, interviewData)
+		if err != nil {
+			t.Fatalf("Failed to generate phases: %v", err)
+		}
+		if len(phases) != 1 || phases[0].Title != "Setup & Infrastructure" {
+			t.Errorf("Expected the retried response to be parsed, got %+v", phases)
+		}
+		if truncatingProvider.lastOpts.MaxTokens != planRetryMaxTokens {
+			t.Errorf("Expected retry with %d max tokens, got %d", planRetryMaxTokens, truncatingProvider.lastOpts.MaxTokens)
+		}
+
+		alwaysTruncated := NewGenerator(&MockProvider{response: mockResponse, truncatedCalls: 2}, "test-model")
+		if _, err := alwaysTruncated.GeneratePhases(architecture, interviewData); err == nil {
+			t.Error("Expected error when the retry is also truncated")
+		}
+	})
+
 	t.Run("GeneratePhases_InvalidJSON", func(t *testing.T) {
 		// Create a generator with a provider that returns invalid JSON
 		invalidJSONProvider := &MockProvider{response: "This is not JSON"}
@@ -190,6 +278,132 @@ Thinking about the architecture...
 		}
 	})
 
+	t.Run("GeneratePhases_SubmitPhaseTool", func(t *testing.T) {
+		toolProvider := &ToolMockProvider{
+			MockProvider: MockProvider{response: "This is not JSON"},
+			toolCalls: []provider.ToolCall{
+				{ID: "call_1", Name: "submit_phase", Arguments: `{"number": 0, "title": "Scaffold", "objective": "Set up", "tasks": [{"number": "0.1", "description": "Init repo"}]}`},
+				{ID: "call_2", Name: "submit_phase", Arguments: `not json`},
+				{ID: "call_3", Name: "submit_phase", Arguments: `{"number": 1, "title": "Models", "objective": "Add models", "dependencies": ["0"], "tasks": []}`},
+			},
+		}
+		toolGenerator := NewGenerator(toolProvider, "test-model")
+
+		phases, err := toolGenerator.GeneratePhases(architecture, interviewData)
+		if err != nil {
+			t.Fatalf("Failed to generate phases: %v", err)
+		}
+		if len(toolProvider.lastTools) != 1 || toolProvider.lastTools[0].Name != "submit_phase" {
+			t.Fatalf("Expected the submit_phase tool to be offered, got %+v", toolProvider.lastTools)
+		}
+		if len(phases) != 2 || phases[0].Title != "Scaffold" || phases[1].Title != "Models" {
+			t.Fatalf("Expected phases from tool calls, got %+v", phases)
+		}
+		if phases[0].ID != "phase-0" || phases[0].Status != PhaseNotStarted {
+			t.Errorf("Expected defaults filled in, got ID %q status %q", phases[0].ID, phases[0].Status)
+		}
+		if phases[0].Tasks[0].ID != "task-0-1" || phases[0].Tasks[0].Status != TaskNotStarted {
+			t.Errorf("Expected task defaults filled in, got %+v", phases[0].Tasks[0])
+		}
+
+		// No tool calls falls back to parsing the text response
+		toolProvider.toolCalls = nil
+		phases, err = toolGenerator.GeneratePhases(architecture, interviewData)
+		if err != nil {
+			t.Fatalf("Failed to generate phases: %v", err)
+		}
+		if len(phases) == 0 || phases[0].Title != "Setup & Infrastructure" {
+			t.Errorf("Expected fallback to the text response, got %+v", phases)
+		}
+	})
+
+	t.Run("GeneratePhases_SubmitPhaseToolTruncated", func(t *testing.T) {
+		toolCalls := []provider.ToolCall{
+			{ID: "call_1", Name: "submit_phase", Arguments: `{"number": 0, "title": "Scaffold", "objective": "Set up", "tasks": []}`},
+		}
+		toolProvider := &ToolMockProvider{
+			MockProvider:       MockProvider{response: "This is not JSON"},
+			toolCalls:          toolCalls,
+			truncatedToolCalls: 1,
+		}
+		phases, err := NewGenerator(toolProvider, "test-model").GeneratePhases(architecture, interviewData)
+		if err != nil {
+			t.Fatalf("Failed to generate phases: %v", err)
+		}
+		if toolProvider.toolCallsMade != 2 {
+			t.Errorf("Expected a retry after truncation, got %d tool calls", toolProvider.toolCallsMade)
+		}
+		if toolProvider.lastToolOpts.MaxTokens != planRetryMaxTokens {
+			t.Errorf("Expected retry with %d max tokens, got %d", planRetryMaxTokens, toolProvider.lastToolOpts.MaxTokens)
+		}
+		if got := toolProvider.lastToolOpts.Temperature; got == nil || *got != *planCallOptions.Temperature {
+			t.Errorf("Expected plan temperature %v, got %v", *planCallOptions.Temperature, got)
+		}
+		if len(phases) != 1 || phases[0].Title != "Scaffold" {
+			t.Errorf("Expected phases from the retried tool call, got %+v", phases)
+		}
+
+		// Still truncated after the retry falls back to the text response
+		alwaysTruncated := &ToolMockProvider{
+			MockProvider:       MockProvider{response: "This is not JSON"},
+			toolCalls:          toolCalls,
+			truncatedToolCalls: 2,
+		}
+		phases, err = NewGenerator(alwaysTruncated, "test-model").GeneratePhases(architecture, interviewData)
+		if err != nil {
+			t.Fatalf("Failed to generate phases: %v", err)
+		}
+		if len(phases) == 0 || phases[0].Title == "Scaffold" {
+			t.Errorf("Expected fallback to the text response, got %+v", phases)
+		}
+	})
+
+	t.Run("GeneratePhases_SubmitPhaseToolErrors", func(t *testing.T) {
+		capped := &ToolMockProvider{
+			MockProvider: MockProvider{response: "This is not JSON"},
+			toolErr:      fmt.Errorf("%w: $1.00 for provider 'mock'", provider.ErrSpendCapReached),
+		}
+		if _, err := NewGenerator(capped, "test-model").GeneratePhases(architecture, interviewData); !errors.Is(err, provider.ErrSpendCapReached) {
+			t.Errorf("Expected the tool call error to be returned, got %v", err)
+		}
+		if capped.lastOpts.System != "" {
+			t.Error("Expected no text fallback after a failed tool call")
+		}
+
+		unsupported := &ToolMockProvider{
+			MockProvider: MockProvider{response: "This is not JSON"},
+			toolErr:      fmt.Errorf("%w by provider mock", provider.ErrToolsUnsupported),
+		}
+		phases, err := NewGenerator(unsupported, "test-model").GeneratePhases(architecture, interviewData)
+		if err != nil {
+			t.Fatalf("Expected fallback when tools are unsupported, got %v", err)
+		}
+		if len(phases) == 0 || unsupported.lastOpts.System == "" {
+			t.Errorf("Expected phases from the text response, got %+v", phases)
+		}
+	})
+
+	t.Run("GeneratePhases_WarnsOnInvalidTaskDependencies", func(t *testing.T) {
+		badDeps := `[{"number": 0, "title": "Setup", "objective": "Set up", "tasks": [
+			{"number": "0.1", "description": "Init repo", "depends_on": ["0.9"]},
+			{"number": "0.2", "description": "Add CI", "depends_on": ["0.1"]}
+		]}]`
+		badGenerator := NewGenerator(&MockProvider{response: badDeps}, "test-model")
+		phases, err := badGenerator.GeneratePhases(architecture, interviewData)
+		if err != nil {
+			t.Fatalf("Failed to generate phases: %v", err)
+		}
+		for _, task := range phases[0].Tasks {
+			if task.DependsOn != nil {
+				t.Errorf("Expected dependencies dropped from task %s, got %v", task.Number, task.DependsOn)
+			}
+		}
+		warnings := badGenerator.Warnings()
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "0.9") {
+			t.Errorf("Expected a warning naming the unknown task, got %v", warnings)
+		}
+	})
+
 	t.Run("ExportPhaseMarkdown", func(t *testing.T) {
 		phase := Phase{
 			ID:              "phase-0",
@@ -545,3 +759,1158 @@ func TestDevPlanGenerator_PhaseManipulation(t *testing.T) {
 		}
 	})
 }
+
+func TestEstimateDifficulty_Heuristic(t *testing.T) {
+	generator := NewGenerator(nil, "")
+
+	t.Run("MultiIntegrationIsComplex", func(t *testing.T) {
+		task := Task{
+			Description: "Integrate Stripe payments, integrate SendGrid email and migrate existing customer records",
+			AcceptanceCriteria: []string{
+				"Payments processed through Stripe",
+				"Receipts sent through SendGrid",
+				"Customer records migrated",
+			},
+		}
+		if got := generator.EstimateDifficulty(task); got != DifficultyComplex {
+			t.Errorf("Expected %s, got %s", DifficultyComplex, got)
+		}
+	})
+
+	t.Run("SimpleTaskIsTrivial", func(t *testing.T) {
+		task := Task{
+			Description:        "Add a README",
+			AcceptanceCriteria: []string{"README exists"},
+		}
+		if got := generator.EstimateDifficulty(task); got != DifficultyTrivial {
+			t.Errorf("Expected %s, got %s", DifficultyTrivial, got)
+		}
+	})
+
+	t.Run("UsesProviderWhenAvailable", func(t *testing.T) {
+		mock := &MockProvider{response: "Moderate"}
+		llmGenerator := NewGenerator(mock, "test-model")
+		task := Task{Description: "Add a README"}
+		if got := llmGenerator.EstimateDifficulty(task); got != DifficultyModerate {
+			t.Errorf("Expected %s, got %s", DifficultyModerate, got)
+		}
+		if got := mock.lastOpts.Temperature; got == nil || *got != *planCallOptions.Temperature {
+			t.Errorf("Expected low temperature %v, got %v", *planCallOptions.Temperature, got)
+		}
+		if mock.lastOpts.ContextStrategy != provider.ContextStrategyTruncate {
+			t.Errorf("Expected truncation strategy, got %v", mock.lastOpts.ContextStrategy)
+		}
+		if mock.lastOpts.System != planCallOptions.System {
+			t.Errorf("Expected the planner system prompt, got %q", mock.lastOpts.System)
+		}
+	})
+}
+
+func TestExportExecutionWaves(t *testing.T) {
+	generator := NewGenerator(nil, "")
+
+	t.Run("Diamond", func(t *testing.T) {
+		// a -> b, a -> c, b + c -> d
+		devplan := &DevPlan{
+			Phases: []Phase{
+				{ID: "phase-0", Number: 0},
+				{ID: "phase-1", Number: 1, Dependencies: []string{"0"}},
+				{ID: "phase-2", Number: 2, Dependencies: []string{"phase-0"}},
+				{ID: "phase-3", Number: 3, Dependencies: []string{"1", "2"}},
+			},
+		}
+
+		waves, err := generator.ExportExecutionWaves(devplan)
+		if err != nil {
+			t.Fatalf("Failed to export waves: %v", err)
+		}
+
+		expected := [][]string{{"phase-0"}, {"phase-1", "phase-2"}, {"phase-3"}}
+		if len(waves) != len(expected) {
+			t.Fatalf("Expected %d waves, got %d: %v", len(expected), len(waves), waves)
+		}
+		for i := range expected {
+			if len(waves[i]) != len(expected[i]) {
+				t.Fatalf("Wave %d: expected %v, got %v", i, expected[i], waves[i])
+			}
+			for j := range expected[i] {
+				if waves[i][j] != expected[i][j] {
+					t.Errorf("Wave %d: expected %v, got %v", i, expected[i], waves[i])
+				}
+			}
+		}
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		devplan := &DevPlan{
+			Phases: []Phase{
+				{ID: "phase-0", Number: 0, Dependencies: []string{"1"}},
+				{ID: "phase-1", Number: 1, Dependencies: []string{"0"}},
+			},
+		}
+
+		if _, err := generator.ExportExecutionWaves(devplan); err == nil {
+			t.Error("Expected error for cyclic dependencies")
+		}
+	})
+
+	t.Run("UnknownDependency", func(t *testing.T) {
+		devplan := &DevPlan{
+			Phases: []Phase{{ID: "phase-0", Number: 0, Dependencies: []string{"7"}}},
+		}
+
+		if _, err := generator.ExportExecutionWaves(devplan); err == nil {
+			t.Error("Expected error for unknown dependency")
+		}
+	})
+}
+
+func TestAutoSplitOversized(t *testing.T) {
+	generator := NewGenerator(nil, "")
+
+	tasks := []Task{
+		{ID: "task-1-1", Number: "1.1", Description: "Task 1"},
+		{ID: "task-1-2", Number: "1.2", Description: "Task 2"},
+		{ID: "task-1-3", Number: "1.3", Description: "Task 3"},
+		{ID: "task-1-4", Number: "1.4", Description: "Task 4"},
+	}
+	phases := []Phase{
+		{ID: "phase-0", Number: 0, Title: "Setup", EstimatedTokens: 2000, Tasks: []Task{{ID: "task-0-1", Number: "0.1"}}},
+		{ID: "phase-1", Number: 1, Title: "Core", Dependencies: []string{"0"}, EstimatedTokens: 10000, Tasks: tasks},
+		{ID: "phase-2", Number: 2, Title: "API", Dependencies: []string{"1"}, EstimatedTokens: 2000, Tasks: []Task{{ID: "task-2-1", Number: "2.1"}}},
+	}
+	maxTokens := 5000
+
+	result, err := generator.AutoSplitOversized(phases, maxTokens)
+	if err != nil {
+		t.Fatalf("Failed to auto-split: %v", err)
+	}
+
+	if len(result) != 4 {
+		t.Fatalf("Expected 4 phases (oversized phase split in two), got %d", len(result))
+	}
+
+	for i, phase := range result {
+		if phase.Number != i {
+			t.Errorf("Phase %d: expected number %d, got %d", i, i, phase.Number)
+		}
+		if phase.EstimatedTokens > maxTokens {
+			t.Errorf("Phase %s: %d tokens exceeds budget %d", phase.ID, phase.EstimatedTokens, maxTokens)
+		}
+	}
+
+	if result[1].ID != "phase-1-part1" || result[2].ID != "phase-1-part2" {
+		t.Errorf("Unexpected part IDs: %s, %s", result[1].ID, result[2].ID)
+	}
+	if len(result[1].Tasks) != 2 || len(result[2].Tasks) != 2 {
+		t.Errorf("Expected tasks split 2/2, got %d/%d", len(result[1].Tasks), len(result[2].Tasks))
+	}
+	if result[2].Tasks[0].Number != "2.1" {
+		t.Errorf("Expected renumbered task 2.1, got %s", result[2].Tasks[0].Number)
+	}
+
+	// Part 2 follows part 1, and the old dependent now waits for part 2
+	if len(result[2].Dependencies) != 1 || result[2].Dependencies[0] != "1" {
+		t.Errorf("Expected part 2 to depend on phase 1, got %v", result[2].Dependencies)
+	}
+	if len(result[3].Dependencies) != 1 || result[3].Dependencies[0] != "2" {
+		t.Errorf("Expected API phase to depend on phase 2, got %v", result[3].Dependencies)
+	}
+
+	// The caller's phases are left untouched
+	if phases[1].Tasks[2].Number != "1.3" {
+		t.Errorf("Expected input tasks to be unchanged, got %s", phases[1].Tasks[2].Number)
+	}
+
+	if _, err := generator.AutoSplitOversized(phases, 0); err == nil {
+		t.Error("Expected error for non-positive budget")
+	}
+}
+
+func TestBuildRiskRegister(t *testing.T) {
+	store, err := state.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.CreateProject(&state.Project{ID: "proj-1", Name: "Test", CreatedAt: time.Now(), CurrentStage: state.StageDevelop}); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if err := store.SavePhase(&state.Phase{ID: "phase-1", ProjectID: "proj-1", Number: 1, Title: "Setup", Status: state.PhaseInProgress, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	if err := store.SaveTask(&state.Task{ID: "task-1", PhaseID: "phase-1", Number: "1.1", Description: "Configure database", Status: state.TaskBlocked}); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	if err := store.SaveTask(&state.Task{ID: "task-2", PhaseID: "phase-1", Number: "1.2", Description: "Deploy the API", Status: state.TaskBlocked}); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	for i, b := range []struct{ taskID, description string }{
+		{"task-1", "Database connection refused"},
+		{"task-1", "Database connection refused"},
+		{"task-2", "Missing deploy credentials"},
+		{"task-2", "Load balancer health check failing"},
+	} {
+		blocker := &state.Blocker{
+			ID:          fmt.Sprintf("blocker-%d", i),
+			TaskID:      b.taskID,
+			Description: b.description,
+			CreatedAt:   time.Now(),
+		}
+		if err := store.SaveBlocker(blocker); err != nil {
+			t.Fatalf("Failed to save blocker: %v", err)
+		}
+	}
+
+	arch := &design.Architecture{
+		Risks: []design.Risk{
+			{Name: "Vendor lock-in", Probability: design.RiskHigh, Impact: design.RiskHigh, Mitigation: "Abstract the storage layer"},
+		},
+	}
+
+	register, err := BuildRiskRegister(arch, store, "proj-1")
+	if err != nil {
+		t.Fatalf("BuildRiskRegister failed: %v", err)
+	}
+
+	var sawDesign, sawBlocker bool
+	var blockerEntries []string
+	for _, entry := range register.Entries {
+		if entry.Source == RiskSourceDesign && entry.Name == "Vendor lock-in" {
+			sawDesign = true
+		}
+		if entry.Source == RiskSourceBlocker {
+			blockerEntries = append(blockerEntries, entry.Name)
+		}
+		if entry.Source == RiskSourceBlocker && strings.Contains(entry.Name, "Database connection refused") {
+			sawBlocker = true
+			if entry.Occurrences != 2 {
+				t.Errorf("Expected 2 occurrences, got %d", entry.Occurrences)
+			}
+		}
+	}
+	if !sawDesign || !sawBlocker {
+		t.Fatalf("Expected both design and blocker risks, got %+v", register.Entries)
+	}
+
+	// The recurring blocker isn't listed again under its task, while a task
+	// blocked for different reasons still is
+	sort.Strings(blockerEntries)
+	expected := []string{"Recurring blocker: Database connection refused", "Repeatedly blocked task: 1.2 Deploy the API"}
+	if strings.Join(blockerEntries, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected blocker risks %v, got %v", expected, blockerEntries)
+	}
+	if register.Entries[0].Name != "Vendor lock-in" {
+		t.Errorf("Expected high/high design risk first, got %s", register.Entries[0].Name)
+	}
+
+	md := register.ExportMarkdown()
+	if !strings.Contains(md, "# Risk Register") || !strings.Contains(md, "Vendor lock-in") || !strings.Contains(md, "Database connection refused") {
+		t.Errorf("Markdown missing expected risks:\n%s", md)
+	}
+}
+
+func TestCoverageReport(t *testing.T) {
+	session := &interview.InterviewSession{
+		ProjectID: "test-project",
+		Answers: map[string]interview.Answer{
+			"pe_3": {QuestionID: "pe_3", Text: "- Page load latency under 200ms\n- Monthly active users reach 10k; Zero data loss during backups\n1. 99.9% uptime"},
+		},
+	}
+
+	plan := &DevPlan{
+		ProjectID: "test-project",
+		Phases: []Phase{
+			{
+				ID: "phase-1",
+				Tasks: []Task{
+					{ID: "1.1", Description: "Add caching to reduce page load latency", AcceptanceCriteria: []string{"Pages load in under 200ms"}},
+					{ID: "1.2", Description: "Schedule nightly database backups", AcceptanceCriteria: []string{"Backups complete with no data loss"}},
+				},
+			},
+		},
+	}
+
+	coverage, err := CoverageReport(session, plan)
+	if err != nil {
+		t.Fatalf("CoverageReport failed: %v", err)
+	}
+
+	if len(coverage.Metrics) != 4 {
+		t.Fatalf("Expected 4 metrics, got %d: %+v", len(coverage.Metrics), coverage.Metrics)
+	}
+	if !coverage.Metrics[0].Covered() || coverage.Metrics[0].TaskIDs[0] != "1.1" {
+		t.Errorf("Expected latency metric to be covered by task 1.1, got %+v", coverage.Metrics[0])
+	}
+	if !coverage.Metrics[2].Covered() || coverage.Metrics[2].TaskIDs[0] != "1.2" {
+		t.Errorf("Expected data loss metric to be covered by task 1.2, got %+v", coverage.Metrics[2])
+	}
+	if len(coverage.Uncovered) != 2 || coverage.Uncovered[0] != "Monthly active users reach 10k" || coverage.Uncovered[1] != "99.9% uptime" {
+		t.Errorf("Expected the active users and uptime metrics to be uncovered, got %v", coverage.Uncovered)
+	}
+
+	if _, err := CoverageReport(nil, plan); err == nil {
+		t.Error("Expected error for nil session")
+	}
+}
+
+func TestBuildAgentPrompt(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	phase := &Phase{
+		Number:    2,
+		Title:     "Task API",
+		Objective: "Expose task CRUD through the Backend API",
+		Tasks: []Task{
+			{Number: "2.1", Description: "Create task endpoints", AcceptanceCriteria: []string{"POST /tasks returns 201", "GET /tasks lists tasks"}},
+			{Number: "2.2", Description: "Add request validation", AcceptanceCriteria: []string{"Invalid payloads return 400"}},
+		},
+	}
+	arch := &design.Architecture{
+		SystemOverview: "A task management system.",
+		Components: []design.Component{
+			{Name: "Backend API", Type: design.ComponentBackend, Purpose: "Serves the REST API", Technologies: []string{"Go"}},
+			{Name: "Frontend", Type: design.ComponentFrontend, Purpose: "Web UI"},
+		},
+	}
+
+	prompt := generator.BuildAgentPrompt(phase, arch)
+
+	for _, task := range phase.Tasks {
+		if !strings.Contains(prompt, task.Number+": "+task.Description) {
+			t.Errorf("Prompt is missing task %s", task.Number)
+		}
+		for _, criterion := range task.AcceptanceCriteria {
+			if !strings.Contains(prompt, criterion) {
+				t.Errorf("Prompt is missing acceptance criterion %q", criterion)
+			}
+		}
+	}
+	if !strings.Contains(prompt, phase.Objective) {
+		t.Error("Prompt is missing the phase objective")
+	}
+	if !strings.Contains(prompt, "Backend API") || strings.Contains(prompt, "Web UI") {
+		t.Error("Prompt should only include the components the phase mentions")
+	}
+	if !strings.Contains(prompt, "PHASE COMPLETE") {
+		t.Error("Prompt should tell the agent how to report completion")
+	}
+}
+
+func TestWeightedProgress(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	newPlan := func() *DevPlan {
+		return &DevPlan{
+			Phases: []Phase{{
+				ID: "phase-0",
+				Tasks: []Task{
+					{ID: "small-1", EstimatedTokens: 500, Status: TaskNotStarted},
+					{ID: "small-2", EstimatedTokens: 500, Status: TaskNotStarted},
+					{ID: "large", EstimatedTokens: 4000, Status: TaskNotStarted},
+					{ID: "complex", Difficulty: DifficultyComplex, Status: TaskNotStarted},
+				},
+			}},
+		}
+	}
+
+	if got := generator.WeightedProgress(newPlan()); got != 0 {
+		t.Errorf("Expected 0%% for an untouched plan, got %.1f", got)
+	}
+
+	smallDone := newPlan()
+	smallDone.Phases[0].Tasks[0].Status = TaskCompleted
+	largeDone := newPlan()
+	largeDone.Phases[0].Tasks[2].Status = TaskCompleted
+
+	small := generator.WeightedProgress(smallDone)
+	large := generator.WeightedProgress(largeDone)
+	if large <= small {
+		t.Errorf("Expected the high-token task to move progress more: small=%.1f large=%.1f", small, large)
+	}
+	if large != 4000.0/7500.0*100 {
+		t.Errorf("Expected %.1f%%, got %.1f%%", 4000.0/7500.0*100, large)
+	}
+
+	vis := generator.VisualizeProgress(largeDone)
+	if !strings.Contains(vis, "**Overall Progress:** 25.0%") || !strings.Contains(vis, "**Weighted Progress:** 53.3%") {
+		t.Errorf("Expected simple and weighted progress in visualization, got:\n%s", vis)
+	}
+}
+
+func TestInjectTestingTasks(t *testing.T) {
+	generator := NewGenerator(nil, "")
+
+	phase := &Phase{
+		Number:          2,
+		Title:           "API Layer",
+		SuccessCriteria: []string{"Endpoints return JSON", "Errors use problem details"},
+		Tasks: []Task{
+			{ID: "task-2-1", Number: "2.1", Description: "Implement the latest endpoints"},
+		},
+	}
+
+	generator.InjectTestingTasks(phase)
+	generator.InjectTestingTasks(phase)
+
+	if len(phase.Tasks) != 2 {
+		t.Fatalf("Expected exactly one testing task to be added, got %d tasks", len(phase.Tasks))
+	}
+	added := phase.Tasks[1]
+	if added.ID != "task-2-2" || added.Number != "2.2" || added.Status != TaskNotStarted {
+		t.Errorf("Unexpected testing task: %+v", added)
+	}
+	if len(added.AcceptanceCriteria) != 3 || !strings.Contains(added.AcceptanceCriteria[0], "Endpoints return JSON") {
+		t.Errorf("Expected criteria derived from success criteria, got %v", added.AcceptanceCriteria)
+	}
+
+	tested := &Phase{Number: 1, Tasks: []Task{{Description: "Add unit tests for the parser"}}}
+	if generator.InjectTestingTasks(tested); len(tested.Tasks) != 1 {
+		t.Errorf("Expected a phase with a testing task to be left alone, got %d tasks", len(tested.Tasks))
+	}
+}
+
+func TestPreviewRegeneration(t *testing.T) {
+	generator := NewGenerator(nil, "")
+
+	oldPhase := &Phase{
+		Number:          1,
+		Title:           "Database Layer",
+		Objective:       "Store tasks",
+		SuccessCriteria: []string{"Schema created", "Migrations run"},
+		Tasks: []Task{
+			{Number: "1.1", Description: "Design schema"},
+			{Number: "1.2", Description: "Write raw SQL queries"},
+			{Number: "1.3", Description: "Add migrations"},
+		},
+	}
+	newPhase := &Phase{
+		Number:          1,
+		Title:           "Database Layer",
+		Objective:       "Store tasks and users",
+		SuccessCriteria: []string{"Schema created", "Migrations run"},
+		Tasks: []Task{
+			{Number: "1.1", Description: "Design schema"},
+			{Number: "1.2", Description: "Add migrations"},
+			{Number: "1.3", Description: "Generate query layer with sqlc"},
+		},
+	}
+
+	preview := generator.PreviewRegeneration(oldPhase, newPhase)
+
+	for _, want := range []string{
+		"-Write raw SQL queries\n",
+		"+Generate query layer with sqlc\n",
+		" Design schema\n",
+		" Add migrations\n",
+		"-Store tasks\n",
+		"+Store tasks and users\n",
+		" Migrations run\n",
+	} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", want, preview)
+		}
+	}
+	if strings.Contains(preview, "-Add migrations") || strings.Contains(preview, "+Add migrations") {
+		t.Errorf("Expected renumbered task to be unchanged, got:\n%s", preview)
+	}
+}
+
+func TestCompareModelCosts(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	devplan := &DevPlan{
+		Phases: []Phase{
+			{Number: 1, EstimatedTokens: 40000},
+			{Number: 2, EstimatedTokens: 60000},
+		},
+	}
+	pricing := PricingFromModels([]provider.Model{
+		{Name: "gpt-4o", PriceInput: 0.005, PriceOutput: 0.015},
+		{Name: "gpt-4o-mini", PriceInput: 0.002, PriceOutput: 0.006},
+		{Name: "unpriced"},
+	})
+
+	costs := generator.CompareModelCosts(devplan, pricing, []string{"gpt-4o", "gpt-4o-mini", "unpriced", "unknown"})
+	if len(costs) != 2 {
+		t.Fatalf("Expected costs for the 2 priced models, got %v", costs)
+	}
+	// 100K tokens split evenly: 50 * 0.005 + 50 * 0.015
+	if got := costs["gpt-4o"]; got < 0.9999 || got > 1.0001 {
+		t.Errorf("Expected gpt-4o cost 1.00, got %f", got)
+	}
+	if costs["gpt-4o-mini"] >= costs["gpt-4o"] {
+		t.Errorf("Expected cheaper model to cost less: %v", costs)
+	}
+
+	suggestion := SuggestCheaperModel(costs, "gpt-4o")
+	if !strings.Contains(suggestion, "gpt-4o-mini") || !strings.Contains(suggestion, "~60%") {
+		t.Errorf("Expected suggestion to switch to gpt-4o-mini saving ~60%%, got %q", suggestion)
+	}
+	if suggestion := SuggestCheaperModel(costs, "gpt-4o-mini"); suggestion != "" {
+		t.Errorf("Expected no suggestion for the cheapest model, got %q", suggestion)
+	}
+	if suggestion := SuggestCheaperModel(costs, "unknown"); suggestion != "" {
+		t.Errorf("Expected no suggestion for an unpriced model, got %q", suggestion)
+	}
+}
+
+func TestEstimateRemainingCost(t *testing.T) {
+	store, err := state.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{ID: "proj-1", Name: "Test", CreatedAt: time.Now(), CurrentStage: state.StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	phase := &state.Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Setup", Content: "Setup", Status: state.PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	for _, number := range []string{"1.1", "1.2", "1.3"} {
+		task := &state.Task{ID: "task-" + number, PhaseID: phase.ID, Number: number, Description: "Write the config loader", Status: state.TaskNotStarted}
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+
+	generator := NewGenerator(nil, "gpt-4o")
+	pricing := Pricing{"gpt-4o": {PriceInput: 0.005, PriceOutput: 0.015}}
+	estimate := func() float64 {
+		t.Helper()
+		remaining, err := generator.EstimateRemainingCost(store, project.ID, pricing)
+		if err != nil {
+			t.Fatalf("Failed to estimate remaining cost: %v", err)
+		}
+		return remaining
+	}
+
+	full := estimate()
+	if full <= 0 {
+		t.Fatalf("Expected a positive estimate for unstarted tasks, got %f", full)
+	}
+	perTask := full / 3
+
+	if err := store.UpdateTaskStatus("task-1.1", state.TaskCompleted); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	afterCompleting := estimate()
+	if afterCompleting >= full {
+		t.Errorf("Expected completing a task to reduce the estimate: %f -> %f", full, afterCompleting)
+	}
+
+	// An in-progress task counts only what hasn't been spent on it yet
+	if err := store.UpdateTaskStatus("task-1.2", state.TaskInProgress); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+	usage := &state.TokenUsage{ProjectID: project.ID, TaskID: "task-1.2", Provider: "openai", Model: "gpt-4o", Cost: perTask / 2, Timestamp: time.Now()}
+	if err := store.RecordTokenUsage(usage); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+	if got, want := estimate(), perTask*1.5; got < want-1e-9 || got > want+1e-9 {
+		t.Errorf("Expected half of the in-progress task to remain, got %f want %f", got, want)
+	}
+
+	// A difficulty saved with the task overrides the description heuristic
+	complexTask := &state.Task{ID: "task-1.4", PhaseID: phase.ID, Number: "1.4", Description: "Write the config loader", Status: state.TaskNotStarted, Difficulty: DifficultyComplex}
+	if err := store.SaveTask(complexTask); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	complexCost := float64(difficultyTokenEstimates[DifficultyComplex]) / 1000.0 * (0.005 + 0.015) / 2
+	if got, want := estimate(), perTask*1.5+complexCost; got < want-1e-9 || got > want+1e-9 {
+		t.Errorf("Expected the stored difficulty to price the task, got %f want %f", got, want)
+	}
+}
+
+func TestTaskDependencies(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	newPhase := func() *Phase {
+		return &Phase{
+			Number: 1,
+			Title:  "API",
+			Tasks: []Task{
+				{ID: "task-1-1", Number: "1.1", Description: "Define schema", Status: TaskNotStarted},
+				{ID: "task-1-2", Number: "1.2", Description: "Write handlers", Status: TaskNotStarted, DependsOn: []string{"1.3"}},
+				{ID: "task-1-3", Number: "1.3", Description: "Write models", Status: TaskNotStarted, DependsOn: []string{"task-1-1"}},
+				{ID: "task-1-4", Number: "1.4", Description: "Write docs", Status: TaskNotStarted},
+			},
+		}
+	}
+
+	t.Run("NextTaskWaitsForPrerequisite", func(t *testing.T) {
+		phase := newPhase()
+		phase.Tasks[0].Status = TaskCompleted
+		phase.Tasks[2].Status = TaskInProgress
+
+		next, err := generator.NextTask(phase)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		// 1.2 comes first by number but 1.3 isn't done yet
+		if next == nil || next.Number != "1.4" {
+			t.Fatalf("Expected task 1.4, got %+v", next)
+		}
+
+		phase.Tasks[2].Status = TaskCompleted
+		next, err = generator.NextTask(phase)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if next == nil || next.Number != "1.2" {
+			t.Fatalf("Expected task 1.2 once its prerequisite completed, got %+v", next)
+		}
+
+		for i := range phase.Tasks {
+			phase.Tasks[i].Status = TaskCompleted
+		}
+		if next, _ := generator.NextTask(phase); next != nil {
+			t.Errorf("Expected no task when all are completed, got %s", next.Number)
+		}
+	})
+
+	t.Run("ExportTaskWaves", func(t *testing.T) {
+		waves, err := generator.ExportTaskWaves(newPhase())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := [][]string{{"1.1", "1.4"}, {"1.3"}, {"1.2"}}
+		if fmt.Sprint(waves) != fmt.Sprint(expected) {
+			t.Errorf("Expected waves %v, got %v", expected, waves)
+		}
+	})
+
+	t.Run("RejectsCycles", func(t *testing.T) {
+		phase := newPhase()
+		phase.Tasks[0].DependsOn = []string{"1.2"}
+		if err := generator.ValidateTaskDependencies(phase); err == nil || !strings.Contains(err.Error(), "cycle") {
+			t.Errorf("Expected cycle error, got %v", err)
+		}
+		if _, err := generator.NextTask(phase); err != nil {
+			t.Errorf("Expected NextTask to still work on a cyclic phase, got %v", err)
+		}
+	})
+
+	t.Run("RejectsUnknownAndSelfReferences", func(t *testing.T) {
+		phase := newPhase()
+		phase.Tasks[3].DependsOn = []string{"9.9"}
+		if err := generator.ValidateTaskDependencies(phase); err == nil {
+			t.Error("Expected error for unknown task")
+		}
+		phase = newPhase()
+		phase.Tasks[3].DependsOn = []string{"1.4"}
+		if err := generator.ValidateTaskDependencies(phase); err == nil {
+			t.Error("Expected error for self reference")
+		}
+	})
+
+	t.Run("MarkdownRoundTrip", func(t *testing.T) {
+		md, err := generator.ExportPhaseMarkdown(newPhase())
+		if err != nil {
+			t.Fatalf("Failed to export phase: %v", err)
+		}
+		parsed, err := ParsePhaseMarkdown(md)
+		if err != nil {
+			t.Fatalf("Failed to parse phase: %v", err)
+		}
+		if len(parsed.Tasks) != 4 || fmt.Sprint(parsed.Tasks[1].DependsOn) != "[1.3]" {
+			t.Errorf("Expected task 1.2 to depend on 1.3 after round trip, got %+v", parsed.Tasks)
+		}
+	})
+}
+
+func TestChangelog_RecordTaskCompletionCommits(t *testing.T) {
+	changelog := &Changelog{}
+	changelog.RecordTaskCompletion("task-1", "Add login", "Auth", "a1b2c3d", "e4f5a6b")
+	changelog.RecordTaskCompletion("task-2", "Add logout", "Auth")
+
+	if got := changelog.Entries[0].Details["commits"]; got != "a1b2c3d, e4f5a6b" {
+		t.Errorf("Expected commit SHAs in details, got %q", got)
+	}
+	if _, ok := changelog.Entries[1].Details["commits"]; ok {
+		t.Error("Expected no commits detail without commits")
+	}
+	if !strings.Contains(changelog.ExportMarkdown(), "commits: a1b2c3d, e4f5a6b") {
+		t.Error("Expected commits in the exported changelog")
+	}
+}
+
+func TestGenerateRollbackPlans(t *testing.T) {
+	phases := []Phase{
+		{Number: 0, Title: "Setup", Objective: "Initialize project"},
+		{Number: 1, Title: "Database", Objective: "Add schema", Tasks: []Task{{Number: "1.1", Description: "Create tables"}}},
+	}
+
+	// Without a provider the phases are left alone
+	if err := NewGenerator(nil, "").GenerateRollbackPlans(phases); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, phase := range phases {
+		if len(phase.RollbackSteps) != 0 {
+			t.Errorf("Expected no rollback steps without a provider, got %v", phase.RollbackSteps)
+		}
+	}
+
+	generator := NewGenerator(&MockProvider{response: "1. Revert the deploy\n- Run the down migration\n3rd-party webhooks: re-point them at the old endpoint\n\n"}, "test-model")
+	if err := generator.GenerateRollbackPlans(phases); err != nil {
+		t.Fatalf("Failed to generate rollback plans: %v", err)
+	}
+	expected := []string{"Revert the deploy", "Run the down migration", "3rd-party webhooks: re-point them at the old endpoint"}
+	for _, phase := range phases {
+		if strings.Join(phase.RollbackSteps, "|") != strings.Join(expected, "|") {
+			t.Errorf("Expected rollback steps %v for phase %d, got %v", expected, phase.Number, phase.RollbackSteps)
+		}
+	}
+
+	markdown, err := generator.ExportPhaseMarkdown(&phases[1])
+	if err != nil {
+		t.Fatalf("Failed to export phase markdown: %v", err)
+	}
+	if !strings.Contains(markdown, "## Rollback Plan\n\n1. Revert the deploy\n2. Run the down migration\n3. 3rd-party webhooks: re-point them at the old endpoint\n") {
+		t.Errorf("Expected rollback plan in markdown, got:\n%s", markdown)
+	}
+
+	parsed, err := ParsePhaseMarkdown(markdown)
+	if err != nil {
+		t.Fatalf("Failed to parse phase markdown: %v", err)
+	}
+	if strings.Join(parsed.RollbackSteps, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected rollback steps to round-trip, got %v", parsed.RollbackSteps)
+	}
+}
+
+func TestExportMasterPlanWithTOC(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	devplan := &DevPlan{
+		ProjectID: "test-project",
+		Phases: []Phase{
+			{Number: 0, Title: "Setup & Infrastructure", Status: PhaseCompleted, EstimatedCost: 0.5, Tasks: []Task{{Number: "0.1"}}},
+			{Number: 1, Title: "Core API", Status: PhaseInProgress, EstimatedCost: 1.25},
+			{Number: 2, Title: "Testing | QA", Status: PhaseNotStarted},
+		},
+		CreatedAt: time.Now(),
+	}
+
+	markdown, err := generator.ExportMasterPlanWithTOC(devplan)
+	if err != nil {
+		t.Fatalf("Failed to export master plan: %v", err)
+	}
+
+	// Collect the anchors of every heading in document order
+	headingAnchors := newAnchorSet()
+	anchors := make(map[string]bool)
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(line, "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			anchors[headingAnchors.add(heading)] = true
+		}
+	}
+
+	contents := markdown[strings.Index(markdown, "## Contents"):strings.Index(markdown, "## Overview")]
+	for _, phase := range devplan.Phases {
+		entry := fmt.Sprintf("[Phase %d: %s](#", phase.Number, phase.Title)
+		if !strings.Contains(contents, entry) {
+			t.Errorf("Expected the contents to list phase %d, got:\n%s", phase.Number, contents)
+		}
+	}
+	if !strings.Contains(contents, "(#phase-0-setup--infrastructure)") {
+		t.Errorf("Expected a GitHub-style anchor for phase 0, got:\n%s", contents)
+	}
+
+	for _, link := range regexp.MustCompile(`\]\(#([^)]+)\)`).FindAllStringSubmatch(markdown, -1) {
+		if !anchors[link[1]] {
+			t.Errorf("Link to #%s doesn't match any heading", link[1])
+		}
+	}
+
+	if !strings.Contains(markdown, "| [1](#phase-1-core-api) | Core API | 0 | $1.25 | in_progress |") {
+		t.Errorf("Expected a summary row for phase 1, got:\n%s", markdown)
+	}
+	if !strings.Contains(markdown, "Testing \\| QA") {
+		t.Error("Expected pipes in titles to be escaped in the summary table")
+	}
+
+	original, err := generator.ExportMasterPlan(devplan)
+	if err != nil {
+		t.Fatalf("Failed to export master plan: %v", err)
+	}
+	if strings.Contains(original, "## Contents") {
+		t.Error("Expected ExportMasterPlan to stay without a table of contents")
+	}
+}
+
+func TestPhaseSizeWarnings(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	makeTasks := func(n int) []Task {
+		tasks := make([]Task, n)
+		for i := range tasks {
+			tasks[i] = Task{ID: fmt.Sprintf("task-%d", i+1), Number: fmt.Sprintf("1.%d", i+1), Description: "Task"}
+		}
+		return tasks
+	}
+
+	const maxTasks = 4
+	phases := []Phase{
+		{ID: "phase-0", Number: 0, Title: "Setup", Tasks: makeTasks(maxTasks)},
+		{ID: "phase-1", Number: 1, Title: "Core API", Tasks: makeTasks(maxTasks + 1)},
+	}
+	for i := range phases {
+		phases[i].EstimatedTokens = generator.estimatePhaseTokens(&phases[i])
+	}
+
+	warnings := generator.PhaseSizeWarnings(phases, maxTasks)
+	if len(warnings) != 1 {
+		t.Fatalf("Expected one warning, got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], "Phase 1 (Core API) has 5 tasks") || !strings.Contains(warnings[0], "--split 1:3") {
+		t.Errorf("Unexpected warning: %s", warnings[0])
+	}
+	if len(generator.PhaseSizeWarnings(phases, 0)) != 0 {
+		t.Error("Expected no warnings when the check is disabled")
+	}
+
+	split, err := generator.SplitOversizedByTasks(phases, maxTasks)
+	if err != nil {
+		t.Fatalf("Failed to split phases: %v", err)
+	}
+	if len(split) != 3 {
+		t.Fatalf("Expected the oversized phase to be split in two, got %d phases", len(split))
+	}
+	if warnings := generator.PhaseSizeWarnings(split, maxTasks); len(warnings) != 0 {
+		t.Errorf("Expected no warnings after splitting, got %v", warnings)
+	}
+
+	// Only the task count matters, not the phase's token estimate
+	byCount := []Phase{
+		{ID: "phase-0", Number: 0, Title: "Heavy", Tasks: makeTasks(2), EstimatedTokens: 1000000},
+		{ID: "phase-1", Number: 1, Title: "Long", Tasks: makeTasks(9), Dependencies: []string{"0"}},
+	}
+	split, err = generator.SplitOversizedByTasks(byCount, maxTasks)
+	if err != nil {
+		t.Fatalf("Failed to split phases: %v", err)
+	}
+	sizes := []int{}
+	for _, phase := range split {
+		sizes = append(sizes, len(phase.Tasks))
+	}
+	if fmt.Sprint(sizes) != "[2 3 3 3]" {
+		t.Errorf("Expected the long phase split into three equal parts, got task counts %v", sizes)
+	}
+}
+
+func TestExportGanttMermaid(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	devplan := &DevPlan{
+		CreatedAt: created,
+		Phases: []Phase{
+			{ID: "phase-0", Number: 0, Title: "Setup: tooling", Status: PhaseCompleted, Tasks: []Task{
+				{Number: "0.1", Description: "Init repo", Difficulty: DifficultyTrivial},
+				{Number: "0.2", Description: "Add CI", Difficulty: DifficultyModerate},
+			}},
+			{ID: "phase-1", Number: 1, Title: "Database", Dependencies: []string{"0"}, Tasks: []Task{
+				{Number: "1.1", Description: "Schema", Difficulty: DifficultyComplex},
+			}},
+		},
+	}
+
+	durations := generator.EstimateDurations(devplan)
+	if durations["phase-0"] != 30*time.Minute || durations["phase-1"] != 45*time.Minute {
+		t.Errorf("Unexpected durations: %v", durations)
+	}
+
+	chart := generator.ExportGanttMermaid(devplan)
+	if !strings.HasPrefix(chart, "gantt\n") {
+		t.Errorf("Expected a Mermaid gantt chart, got:\n%s", chart)
+	}
+	if !strings.Contains(chart, "Phase 0 - Setup - tooling :done, phase0, 2024-03-01 09:00, 30m") {
+		t.Errorf("Expected the completed first phase at the plan start, got:\n%s", chart)
+	}
+	if !strings.Contains(chart, "Phase 1 - Database :phase1, after phase0, 45m") {
+		t.Errorf("Expected the dependent phase to start after its prerequisite, got:\n%s", chart)
+	}
+
+	// A dependency cycle falls back to plan order
+	devplan.Phases[0].Dependencies = []string{"1"}
+	chart = generator.ExportGanttMermaid(devplan)
+	if !strings.Contains(chart, ":done, phase0, 2024-03-01 09:00, 30m") || !strings.Contains(chart, ":phase1, after phase0, 45m") {
+		t.Errorf("Expected phases chained in plan order for invalid dependencies, got:\n%s", chart)
+	}
+}
+
+func TestExportPhaseMarkdown_ProgressLog(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	phase := &Phase{
+		Number: 1,
+		Title:  "Database",
+		Status: PhaseInProgress,
+		Tasks: []Task{
+			{
+				Number:              "1.1",
+				Description:         "Design schema",
+				Status:              TaskCompleted,
+				ImplementationNotes: []string{"Consider normalization"},
+				ProgressNotes:       []string{"2024-03-01 09:00: Tried the ORM", "2024-03-01 10:30: Switched to raw SQL"},
+			},
+			{Number: "1.2", Description: "Write models", Status: TaskNotStarted},
+		},
+	}
+
+	markdown, err := generator.ExportPhaseMarkdown(phase)
+	if err != nil {
+		t.Fatalf("Failed to export phase: %v", err)
+	}
+	if !strings.Contains(markdown, "**Progress Log:**\n- 2024-03-01 09:00: Tried the ORM\n- 2024-03-01 10:30: Switched to raw SQL\n") {
+		t.Errorf("Expected the progress log in order, got:\n%s", markdown)
+	}
+	if strings.Count(markdown, "**Progress Log:**") != 1 {
+		t.Errorf("Expected no progress log for a task without notes, got:\n%s", markdown)
+	}
+
+	parsed, err := ParsePhaseMarkdown(markdown)
+	if err != nil {
+		t.Fatalf("Failed to parse exported phase: %v", err)
+	}
+	if got := parsed.Tasks[0].ProgressNotes; len(got) != 2 || got[1] != "2024-03-01 10:30: Switched to raw SQL" {
+		t.Errorf("Expected the progress log to round-trip, got %v", got)
+	}
+	if got := parsed.Tasks[0].ImplementationNotes; len(got) != 1 {
+		t.Errorf("Expected implementation notes kept separate, got %v", got)
+	}
+}
+
+func TestDeduplicateTasks(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	newPlan := func() *DevPlan {
+		return &DevPlan{
+			Phases: []Phase{
+				{ID: "phase-1", Number: 1, EstimatedTokens: 3000, EstimatedCost: 0.03, Tasks: []Task{
+					{Number: "1.1", Description: "Create the user model"},
+					{Number: "1.2", Description: "Write unit tests", AcceptanceCriteria: []string{"Models covered"}},
+				}},
+				{ID: "phase-2", Number: 2, EstimatedTokens: 4000, EstimatedCost: 0.04, Tasks: []Task{
+					{Number: "2.1", Description: "Build the REST API"},
+					{Number: "2.2", Description: "Write unit tests.", AcceptanceCriteria: []string{"Handlers covered"}},
+					{Number: "2.3", Description: "Document endpoints", DependsOn: []string{"2.1", "2.2"}},
+				}},
+			},
+			TotalTokens: 7000,
+			TotalCost:   0.07,
+		}
+	}
+
+	plan := newPlan()
+	groups := generator.FindDuplicateTasks(plan)
+	if len(groups) != 1 || strings.Join(groups[0], ",") != "1.2,2.2" {
+		t.Fatalf("Expected the repeated testing task to be grouped, got %v", groups)
+	}
+
+	if removed := generator.DeduplicateTasks(plan, false); removed != 1 {
+		t.Fatalf("Expected 1 task removed, got %d", removed)
+	}
+	phase2 := plan.Phases[1]
+	if len(phase2.Tasks) != 2 || phase2.Tasks[1].Number != "2.2" || phase2.Tasks[1].Description != "Document endpoints" {
+		t.Errorf("Expected the later duplicate removed and tasks renumbered, got %+v", phase2.Tasks)
+	}
+	if deps := phase2.Tasks[1].DependsOn; len(deps) != 1 || deps[0] != "2.1" {
+		t.Errorf("Expected dependencies on the removed task dropped, got %v", deps)
+	}
+	if len(plan.Phases[0].Tasks[1].AcceptanceCriteria) != 1 {
+		t.Errorf("Expected the kept task unchanged without merging, got %v", plan.Phases[0].Tasks[1].AcceptanceCriteria)
+	}
+	if phase2.EstimatedTokens != 3000 || plan.TotalTokens != 6000 {
+		t.Errorf("Expected estimates updated, got phase %d and total %d", phase2.EstimatedTokens, plan.TotalTokens)
+	}
+
+	plan = newPlan()
+	generator.DeduplicateTasks(plan, true)
+	if criteria := plan.Phases[0].Tasks[1].AcceptanceCriteria; strings.Join(criteria, ",") != "Models covered,Handlers covered" {
+		t.Errorf("Expected the kept task to absorb the duplicate's criteria, got %v", criteria)
+	}
+
+	if removed := generator.DeduplicateTasks(plan, true); removed != 0 {
+		t.Errorf("Expected nothing left to remove, got %d", removed)
+	}
+
+	// Dependents of a duplicate removed from the kept task's phase follow it
+	plan = &DevPlan{
+		Phases: []Phase{
+			{ID: "phase-1", Number: 1, Tasks: []Task{
+				{Number: "1.1", Description: "Define the schema"},
+				{ID: "task-seed", Number: "1.2", Description: "Seed the database"},
+				{ID: "task-seed-2", Number: "1.3", Description: "Seed the database."},
+				{Number: "1.4", Description: "Write fixtures", DependsOn: []string{"1.3"}},
+				{Number: "1.5", Description: "Load fixtures", DependsOn: []string{"task-seed-2", "1.2"}},
+			}},
+		},
+	}
+	if removed := generator.DeduplicateTasks(plan, false); removed != 1 {
+		t.Fatalf("Expected 1 task removed, got %d", removed)
+	}
+	tasks := plan.Phases[0].Tasks
+	if len(tasks) != 4 || tasks[2].Number != "1.3" || tasks[3].Number != "1.4" {
+		t.Fatalf("Expected the duplicate removed and tasks renumbered, got %+v", tasks)
+	}
+	if deps := tasks[2].DependsOn; len(deps) != 1 || deps[0] != "1.2" {
+		t.Errorf("Expected the dependency by number moved to the kept task, got %v", deps)
+	}
+	if deps := tasks[3].DependsOn; strings.Join(deps, ",") != "task-seed" {
+		t.Errorf("Expected the dependency by ID moved to the kept task without repeats, got %v", deps)
+	}
+}
+
+func TestExportTaskJSONL(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	plan := &DevPlan{
+		Phases: []Phase{
+			{ID: "phase-api", Number: 1, Title: "API", Objective: "Serve data", Dependencies: []string{"phase-db"}, Tasks: []Task{
+				{ID: "task-1-1", Number: "1.1", Description: "Document endpoints", DependsOn: []string{"task-1-2"}},
+				{ID: "task-1-2", Number: "1.2", Description: "Build handlers", AcceptanceCriteria: []string{"Handlers return JSON"}},
+			}},
+			{ID: "phase-db", Number: 2, Title: "Database", Tasks: []Task{
+				{Number: "2.1", Description: "Add migrations", DependsOn: []string{"2.2"}},
+				{Number: "2.2", Description: "Design schema"},
+				{Number: "2.3", Description: "Seed data", DependsOn: []string{"2.1"}},
+			}},
+		},
+	}
+
+	output, err := generator.ExportTaskJSONL(plan)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected one line per task, got %d:\n%s", len(lines), output)
+	}
+
+	position := make(map[string]int)
+	var entries []TaskStreamEntry
+	for i, line := range lines {
+		var entry TaskStreamEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Line %d is not valid JSON: %v", i+1, err)
+		}
+		if entry.Prompt == "" || !strings.Contains(entry.Prompt, entry.Description) {
+			t.Errorf("Expected a prompt describing task %s, got %q", entry.Number, entry.Prompt)
+		}
+		position[entry.Number] = i
+		entries = append(entries, entry)
+	}
+
+	// Every task comes after its dependencies and after the tasks of the
+	// phases its phase depends on
+	for _, entry := range entries {
+		for _, dep := range entry.DependsOn {
+			if position[dep] >= position[entry.Number] {
+				t.Errorf("Task %s streamed before its dependency %s", entry.Number, dep)
+			}
+		}
+	}
+	if got := entries[0].Number + "," + entries[1].Number + "," + entries[2].Number; got != "2.2,2.1,2.3" {
+		t.Errorf("Expected the database phase first, got %s", got)
+	}
+	if entries[3].Number != "1.2" || entries[4].PhaseTitle != "API" {
+		t.Errorf("Unexpected API task order: %+v", entries[3:])
+	}
+	if len(entries[4].DependsOn) != 1 || entries[4].DependsOn[0] != "1.2" {
+		t.Errorf("Expected ID dependencies resolved to numbers, got %v", entries[4].DependsOn)
+	}
+
+	plan.Phases[1].Tasks[1].DependsOn = []string{"2.3"}
+	if _, err := generator.ExportTaskJSONL(plan); err == nil {
+		t.Error("Expected an error for cyclic task dependencies")
+	}
+}
+
+func TestEvaluatePhaseCriteria(t *testing.T) {
+	generator := NewGenerator(nil, "")
+	generator.SetCriteriaCheckTimeout(500 * time.Millisecond)
+	workdir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workdir, "marker.txt"), []byte("ok"), 0644); err != nil {
+		t.Fatalf("Failed to write marker: %v", err)
+	}
+	t.Setenv("GEOFFRUSSY_TEST_SECRET", "hunter2")
+
+	phase := &Phase{
+		Number: 1,
+		SuccessCriteria: []string{
+			"Marker file exists",
+			"Build succeeds",
+			"Secrets stay hidden",
+			"Finishes quickly",
+			"Documentation reviewed",
+		},
+		CriteriaChecks: map[string]string{
+			"Marker file exists":  "test -f marker.txt",
+			"Build succeeds":      "exit 1",
+			"Secrets stay hidden": `test -z "$GEOFFRUSSY_TEST_SECRET"`,
+			"Finishes quickly":    "sleep 10",
+		},
+	}
+
+	start := time.Now()
+	results, err := generator.EvaluatePhaseCriteria(phase, workdir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the slow check to be cut off, took %v", elapsed)
+	}
+
+	expected := map[string]bool{
+		"Marker file exists":  true,
+		"Build succeeds":      false,
+		"Secrets stay hidden": true,
+		"Finishes quickly":    false,
+	}
+	if len(results) != len(expected) {
+		t.Errorf("Expected only criteria with checks evaluated, got %v", results)
+	}
+	for criterion, want := range expected {
+		if got, ok := results[criterion]; !ok || got != want {
+			t.Errorf("%s: expected %v, got %v (present %v)", criterion, want, got, ok)
+		}
+	}
+
+	if _, err := generator.EvaluatePhaseCriteria(phase, filepath.Join(workdir, "missing")); err == nil {
+		t.Error("Expected an error for a missing workdir")
+	}
+}
+
+func TestRunCriteriaCheck_KillsProcessGroup(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("needs /proc to inspect the child process")
+	}
+	workdir := t.TempDir()
+
+	// The shell backgrounds a child that would outlive it if only the shell
+	// were killed
+	start := time.Now()
+	if RunCriteriaCheck("sleep 30 & echo $! > child.pid; wait", workdir, 300*time.Millisecond) {
+		t.Fatal("Expected a timed-out check to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the check to be cut off, took %v", elapsed)
+	}
+
+	data, err := os.ReadFile(filepath.Join(workdir, "child.pid"))
+	if err != nil {
+		t.Fatalf("Failed to read child pid: %v", err)
+	}
+	statPath := filepath.Join("/proc", strings.TrimSpace(string(data)), "stat")
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stat, err := os.ReadFile(statPath)
+		// Gone, or a zombie waiting to be reaped, means it was killed
+		if err != nil || strings.Contains(string(stat), ") Z ") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the background child to be killed, still running: %s", stat)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,131 @@
+package devplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TaskStreamEntry is one line of the JSONL task stream: a task with the
+// context an agent needs to work on it in isolation
+type TaskStreamEntry struct {
+	PhaseID             string     `json:"phase_id"`
+	PhaseNumber         int        `json:"phase_number"`
+	PhaseTitle          string     `json:"phase_title"`
+	PhaseObjective      string     `json:"phase_objective"`
+	PhaseDependencies   []string   `json:"phase_dependencies,omitempty"`
+	TaskID              string     `json:"task_id,omitempty"`
+	Number              string     `json:"number"`
+	Description         string     `json:"description"`
+	AcceptanceCriteria  []string   `json:"acceptance_criteria,omitempty"`
+	ImplementationNotes []string   `json:"implementation_notes,omitempty"`
+	DependsOn           []string   `json:"depends_on,omitempty"` // Numbers of tasks that must finish first
+	Status              TaskStatus `json:"status"`
+	Difficulty          string     `json:"difficulty,omitempty"`
+	Prompt              string     `json:"prompt"`
+}
+
+// ExportTaskJSONL exports every task as one JSON object per line, in an
+// order that respects dependencies: phases follow ExportExecutionWaves and
+// tasks within a phase follow ExportTaskWaves. Each line carries its
+// phase's context and a prompt for an agent to carry out the task. Returns
+// an error if phase or task dependencies contain a cycle or reference an
+// unknown phase or task.
+func (g *Generator) ExportTaskJSONL(devplan *DevPlan) (string, error) {
+	phaseWaves, err := g.ExportExecutionWaves(devplan)
+	if err != nil {
+		return "", err
+	}
+	lookup := phaseIndexLookup(devplan.Phases)
+
+	var out strings.Builder
+	for _, wave := range phaseWaves {
+		for _, phaseID := range wave {
+			phase := &devplan.Phases[lookup[phaseID]]
+
+			taskWaves, err := g.ExportTaskWaves(phase)
+			if err != nil {
+				return "", err
+			}
+			prerequisites, err := taskPrerequisites(phase)
+			if err != nil {
+				return "", err
+			}
+			taskIndex := make(map[string]int, len(phase.Tasks))
+			for i, task := range phase.Tasks {
+				taskIndex[task.Number] = i
+			}
+
+			for _, taskWave := range taskWaves {
+				for _, number := range taskWave {
+					i := taskIndex[number]
+					task := phase.Tasks[i]
+
+					var deps []string
+					for _, dep := range prerequisites[i] {
+						deps = append(deps, phase.Tasks[dep].Number)
+					}
+
+					line, err := json.Marshal(TaskStreamEntry{
+						PhaseID:             phase.ID,
+						PhaseNumber:         phase.Number,
+						PhaseTitle:          phase.Title,
+						PhaseObjective:      phase.Objective,
+						PhaseDependencies:   phase.Dependencies,
+						TaskID:              task.ID,
+						Number:              task.Number,
+						Description:         task.Description,
+						AcceptanceCriteria:  task.AcceptanceCriteria,
+						ImplementationNotes: task.ImplementationNotes,
+						DependsOn:           deps,
+						Status:              task.Status,
+						Difficulty:          task.Difficulty,
+						Prompt:              buildTaskAgentPrompt(phase, task, deps),
+					})
+					if err != nil {
+						return "", fmt.Errorf("failed to marshal task %s: %w", task.Number, err)
+					}
+					out.Write(line)
+					out.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	return out.String(), nil
+}
+
+// buildTaskAgentPrompt is the single-task counterpart of BuildAgentPrompt
+func buildTaskAgentPrompt(phase *Phase, task Task, deps []string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("You are implementing task %s of Phase %d: %s.\n\n", task.Number, phase.Number, phase.Title))
+	if phase.Objective != "" {
+		prompt.WriteString(fmt.Sprintf("PHASE OBJECTIVE:\n%s\n\n", phase.Objective))
+	}
+	prompt.WriteString(fmt.Sprintf("TASK:\n%s\n", task.Description))
+
+	if len(task.AcceptanceCriteria) > 0 {
+		prompt.WriteString("\nACCEPTANCE CRITERIA:\n")
+		for _, criterion := range task.AcceptanceCriteria {
+			prompt.WriteString(fmt.Sprintf("- %s\n", criterion))
+		}
+	}
+	if len(task.ImplementationNotes) > 0 {
+		prompt.WriteString("\nNOTES:\n")
+		for _, note := range task.ImplementationNotes {
+			prompt.WriteString(fmt.Sprintf("- %s\n", note))
+		}
+	}
+	if len(deps) > 0 {
+		prompt.WriteString(fmt.Sprintf("\nTasks %s are already complete; build on their work.\n", strings.Join(deps, ", ")))
+	}
+
+	prompt.WriteString("\nINSTRUCTIONS:\n")
+	prompt.WriteString("- Only do the work of this task.\n")
+	prompt.WriteString("- The task is only done when all of its acceptance criteria are met.\n")
+	prompt.WriteString(fmt.Sprintf("- When finished, report \"TASK %s: COMPLETE\" with a one-line summary.\n", task.Number))
+	prompt.WriteString(fmt.Sprintf("- If you cannot finish, report \"TASK %s: BLOCKED\" with the reason and stop.\n", task.Number))
+
+	return prompt.String()
+}
@@ -3,6 +3,7 @@ package devplan
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -112,6 +113,21 @@ func ParsePhaseMarkdown(content string) (*Phase, error) {
 					continue
 				}
 
+				if strings.HasPrefix(line, "**Difficulty:**") {
+					difficulty := strings.TrimPrefix(line, "**Difficulty:**")
+					currentTask.Difficulty = strings.TrimSpace(difficulty)
+					continue
+				}
+
+				if strings.HasPrefix(line, "**Depends On:**") {
+					for _, dep := range strings.Split(strings.TrimPrefix(line, "**Depends On:**"), ",") {
+						if dep = strings.TrimSpace(dep); dep != "" {
+							currentTask.DependsOn = append(currentTask.DependsOn, dep)
+						}
+					}
+					continue
+				}
+
 				if strings.HasPrefix(line, "**Acceptance Criteria:**") {
 					currentTaskSection = "acceptance"
 					continue
@@ -122,6 +138,11 @@ func ParsePhaseMarkdown(content string) (*Phase, error) {
 					continue
 				}
 
+				if strings.HasPrefix(line, "**Progress Log:**") {
+					currentTaskSection = "progress"
+					continue
+				}
+
 				if strings.HasPrefix(line, "- ") {
 					item := strings.TrimPrefix(line, "- ")
 					switch currentTaskSection {
@@ -129,10 +150,20 @@ func ParsePhaseMarkdown(content string) (*Phase, error) {
 						currentTask.AcceptanceCriteria = append(currentTask.AcceptanceCriteria, item)
 					case "notes":
 						currentTask.ImplementationNotes = append(currentTask.ImplementationNotes, item)
+					case "progress":
+						currentTask.ProgressNotes = append(currentTask.ProgressNotes, item)
 					}
 				}
 			}
 
+		case "Rollback Plan":
+			// Numbered steps: "1. Drop the new tables"
+			if number, step, ok := strings.Cut(line, ". "); ok {
+				if _, err := strconv.Atoi(number); err == nil {
+					phase.RollbackSteps = append(phase.RollbackSteps, step)
+				}
+			}
+
 		case "Estimates":
 			if strings.HasPrefix(line, "- **Tokens:**") {
 				var tokens int
@@ -0,0 +1,39 @@
+package devplan
+
+import "fmt"
+
+// DefaultMaxPhaseTasks is the most tasks a phase should have to stay
+// completable by an agent in 1-2 hours
+const DefaultMaxPhaseTasks = 8
+
+// PhaseSizeWarnings returns a warning for each phase with more than
+// maxTasks tasks, suggesting where to split it. A maxTasks of zero or less
+// disables the check.
+func (g *Generator) PhaseSizeWarnings(phases []Phase, maxTasks int) []string {
+	warnings := []string{}
+	if maxTasks <= 0 {
+		return warnings
+	}
+
+	for _, phase := range phases {
+		if len(phase.Tasks) <= maxTasks {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"Phase %d (%s) has %d tasks, more than the %d that fit in 1-2 hours; consider splitting it, e.g. --split %d:%d",
+			phase.Number, phase.Title, len(phase.Tasks), maxTasks, phase.Number, (len(phase.Tasks)+1)/2))
+	}
+	return warnings
+}
+
+// SplitOversizedByTasks splits phases with more than maxTasks tasks into
+// roughly equal parts of at most maxTasks tasks, renumbering and rewiring
+// dependencies like AutoSplitOversized
+func (g *Generator) SplitOversizedByTasks(phases []Phase, maxTasks int) ([]Phase, error) {
+	if maxTasks <= 0 {
+		return nil, fmt.Errorf("max tasks must be positive")
+	}
+	return g.splitEach(phases, func(phase Phase) ([]Phase, error) {
+		return g.splitToTaskCount(phase, maxTasks)
+	})
+}
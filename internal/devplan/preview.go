@@ -0,0 +1,94 @@
+package devplan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PreviewRegeneration compares a phase with its regenerated version as a
+// unified-diff-style listing of the title, objective, success criteria and
+// tasks. Unchanged lines start with a space, removed lines with "-" and
+// added lines with "+", so the result can be shown before asking the user
+// to approve the new content.
+func (g *Generator) PreviewRegeneration(oldPhase, newPhase *Phase) string {
+	if oldPhase == nil {
+		oldPhase = &Phase{}
+	}
+	if newPhase == nil {
+		newPhase = &Phase{}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- Phase %d: %s (current)\n", oldPhase.Number, oldPhase.Title))
+	sb.WriteString(fmt.Sprintf("+++ Phase %d: %s (regenerated)\n", newPhase.Number, newPhase.Title))
+
+	writeDiffSection(&sb, "Objective", []string{oldPhase.Objective}, []string{newPhase.Objective})
+	writeDiffSection(&sb, "Success Criteria", oldPhase.SuccessCriteria, newPhase.SuccessCriteria)
+	writeDiffSection(&sb, "Tasks", taskLines(oldPhase.Tasks), taskLines(newPhase.Tasks))
+
+	return sb.String()
+}
+
+// taskLines lists task descriptions for diffing. Numbers are left out since
+// they shift whenever a task is added or removed.
+func taskLines(tasks []Task) []string {
+	lines := make([]string, len(tasks))
+	for i, task := range tasks {
+		lines[i] = task.Description
+	}
+	return lines
+}
+
+// writeDiffSection writes a section header followed by the line diff
+func writeDiffSection(sb *strings.Builder, title string, oldLines, newLines []string) {
+	sb.WriteString(fmt.Sprintf("@@ %s @@\n", title))
+	for _, line := range diffLines(oldLines, newLines) {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+}
+
+// diffLines returns a line diff of old and new based on their longest
+// common subsequence, with each line prefixed by " ", "-" or "+"
+func diffLines(oldLines, newLines []string) []string {
+	// lcs[i][j] is the LCS length of oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(oldLines) && j < len(newLines) {
+		switch {
+		case oldLines[i] == newLines[j]:
+			diff = append(diff, " "+oldLines[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "-"+oldLines[i])
+			i++
+		default:
+			diff = append(diff, "+"+newLines[j])
+			j++
+		}
+	}
+	for ; i < len(oldLines); i++ {
+		diff = append(diff, "-"+oldLines[i])
+	}
+	for ; j < len(newLines); j++ {
+		diff = append(diff, "+"+newLines[j])
+	}
+	return diff
+}
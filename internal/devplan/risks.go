@@ -0,0 +1,162 @@
+package devplan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mojomast/geoffrussy/internal/blocker"
+	"github.com/mojomast/geoffrussy/internal/design"
+	"github.com/mojomast/geoffrussy/internal/state"
+)
+
+// Risk sources in a RiskRegister
+const (
+	RiskSourceDesign  = "design"
+	RiskSourceBlocker = "blocker"
+)
+
+// RiskRegister is a single prioritized view of project risks
+type RiskRegister struct {
+	ProjectID string
+	Entries   []RiskEntry
+}
+
+// RiskEntry is one risk in the register
+type RiskEntry struct {
+	Source      string
+	Name        string
+	Probability design.RiskLevel
+	Impact      design.RiskLevel
+	Mitigation  string
+	Occurrences int // Number of blockers behind a recurring blocker risk
+	Score       int
+}
+
+// riskLevelWeights maps risk levels to scores for prioritization
+var riskLevelWeights = map[design.RiskLevel]int{
+	design.RiskLow:      1,
+	design.RiskMedium:   2,
+	design.RiskHigh:     3,
+	design.RiskCritical: 4,
+}
+
+// BuildRiskRegister merges the architecture's risks with recurring blocker
+// patterns into a register ordered from highest to lowest priority. Either
+// arch or store may be nil to build from a single source.
+func BuildRiskRegister(arch *design.Architecture, store *state.Store, projectID string) (*RiskRegister, error) {
+	register := &RiskRegister{ProjectID: projectID}
+
+	if arch != nil {
+		for _, risk := range arch.Risks {
+			register.Entries = append(register.Entries, RiskEntry{
+				Source:      RiskSourceDesign,
+				Name:        risk.Name,
+				Probability: risk.Probability,
+				Impact:      risk.Impact,
+				Mitigation:  risk.Mitigation,
+			})
+		}
+	}
+
+	if store != nil {
+		blockers, err := store.ListActiveBlockers(projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze blockers: %w", err)
+		}
+
+		descriptionCounts := make(map[string]int)
+		for _, b := range blockers {
+			descriptionCounts[b.Description]++
+		}
+		for description, count := range descriptionCounts {
+			if count < 2 {
+				continue
+			}
+			register.Entries = append(register.Entries, blockerRiskEntry(
+				fmt.Sprintf("Recurring blocker: %s", description), count))
+		}
+
+		// Blockers already listed as recurring aren't counted against their
+		// task again, so each blocker backs at most one entry
+		taskCounts := make(map[string]int)
+		for _, b := range blockers {
+			if descriptionCounts[b.Description] < 2 {
+				taskCounts[b.TaskID]++
+			}
+		}
+		for taskID, count := range taskCounts {
+			if count < 2 {
+				continue
+			}
+			name := taskID
+			if task, err := store.GetTask(taskID); err == nil {
+				name = fmt.Sprintf("%s %s", task.Number, task.Description)
+			}
+			register.Entries = append(register.Entries, blockerRiskEntry(
+				fmt.Sprintf("Repeatedly blocked task: %s", name), count))
+		}
+	}
+
+	for i := range register.Entries {
+		entry := &register.Entries[i]
+		entry.Score = riskLevelWeights[entry.Probability] * riskLevelWeights[entry.Impact]
+	}
+
+	sort.SliceStable(register.Entries, func(i, j int) bool {
+		if register.Entries[i].Score != register.Entries[j].Score {
+			return register.Entries[i].Score > register.Entries[j].Score
+		}
+		return register.Entries[i].Name < register.Entries[j].Name
+	})
+
+	return register, nil
+}
+
+// blockerRiskEntry rates a recurring blocker, treating anything that has hit
+// the failure threshold as likely to happen again
+func blockerRiskEntry(name string, count int) RiskEntry {
+	probability := design.RiskMedium
+	if count >= blocker.FailureThreshold {
+		probability = design.RiskHigh
+	}
+	return RiskEntry{
+		Source:      RiskSourceBlocker,
+		Name:        name,
+		Probability: probability,
+		Impact:      design.RiskMedium,
+		Mitigation:  "Investigate the root cause before retrying the affected work",
+		Occurrences: count,
+	}
+}
+
+// ExportMarkdown exports the risk register as a markdown table
+func (r *RiskRegister) ExportMarkdown() string {
+	var md strings.Builder
+
+	md.WriteString("# Risk Register\n\n")
+	if len(r.Entries) == 0 {
+		md.WriteString("No risks identified.\n")
+		return md.String()
+	}
+
+	md.WriteString("| # | Risk | Source | Probability | Impact | Score | Mitigation |\n")
+	md.WriteString("|---|------|--------|-------------|--------|-------|------------|\n")
+	for i, entry := range r.Entries {
+		name := entry.Name
+		if entry.Occurrences > 0 {
+			name = fmt.Sprintf("%s (%d occurrences)", name, entry.Occurrences)
+		}
+		md.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s | %d | %s |\n",
+			i+1, escapeTableCell(name), entry.Source, entry.Probability, entry.Impact,
+			entry.Score, escapeTableCell(entry.Mitigation)))
+	}
+
+	return md.String()
+}
+
+// escapeTableCell keeps free text from breaking a markdown table row
+func escapeTableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
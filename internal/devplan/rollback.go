@@ -0,0 +1,70 @@
+package devplan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// listMarker matches a bullet or "1." / "1)" numbering at the start of a
+// line, but not digits that belong to the item itself
+var listMarker = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s+`)
+
+// trimListMarker strips surrounding space and any list marker from a line
+func trimListMarker(line string) string {
+	return strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+}
+
+// maxRollbackSteps caps how many rollback steps are kept per phase
+const maxRollbackSteps = 8
+
+// GenerateRollbackPlans asks the LLM how to revert each phase's changes and
+// stores the answer in the phase's RollbackSteps, replacing any earlier
+// plan. Without a provider the phases are left unchanged.
+func (g *Generator) GenerateRollbackPlans(phases []Phase) error {
+	if g.provider == nil {
+		return nil
+	}
+
+	for i := range phases {
+		response, err := g.provider.CallWithOptions(g.model, buildRollbackPrompt(&phases[i]), planCallOptions)
+		if err != nil {
+			return fmt.Errorf("failed to generate rollback plan for phase %d: %w", phases[i].Number, err)
+		}
+		phases[i].RollbackSteps = parseRollbackSteps(response.Content)
+	}
+	return nil
+}
+
+// buildRollbackPrompt creates the prompt for a phase's rollback plan
+func buildRollbackPrompt(phase *Phase) string {
+	var prompt strings.Builder
+	prompt.WriteString("Describe how to safely revert the changes made by the following development phase in production, for example undoing migrations, removing deployed services or disabling feature flags.\n\n")
+	prompt.WriteString(fmt.Sprintf("PHASE %d: %s\n", phase.Number, phase.Title))
+	prompt.WriteString(fmt.Sprintf("OBJECTIVE: %s\n", phase.Objective))
+	if len(phase.Tasks) > 0 {
+		prompt.WriteString("TASKS:\n")
+		for _, task := range phase.Tasks {
+			prompt.WriteString(fmt.Sprintf("- %s: %s\n", task.Number, task.Description))
+		}
+	}
+	prompt.WriteString(fmt.Sprintf("\nList up to %d rollback steps in the order to perform them, one per line with no numbering or extra text.", maxRollbackSteps))
+	return prompt.String()
+}
+
+// parseRollbackSteps extracts rollback steps from an LLM response, one per
+// non-empty line, stripping list markers
+func parseRollbackSteps(response string) []string {
+	var steps []string
+	for _, line := range strings.Split(response, "\n") {
+		step := trimListMarker(line)
+		if step == "" {
+			continue
+		}
+		steps = append(steps, step)
+		if len(steps) == maxRollbackSteps {
+			break
+		}
+	}
+	return steps
+}
@@ -0,0 +1,132 @@
+package devplan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// taskPrerequisites resolves each task's DependsOn to indexes of tasks in
+// the same phase. Dependencies may reference a task by ID or by number.
+// Returns an error for unknown or self references.
+func taskPrerequisites(phase *Phase) ([][]int, error) {
+	lookup := make(map[string]int, len(phase.Tasks)*2)
+	for i, task := range phase.Tasks {
+		lookup[task.Number] = i
+	}
+	for i, task := range phase.Tasks {
+		if task.ID != "" {
+			lookup[task.ID] = i
+		}
+	}
+
+	prerequisites := make([][]int, len(phase.Tasks))
+	for i, task := range phase.Tasks {
+		seen := make(map[int]bool)
+		for _, dep := range task.DependsOn {
+			depIdx, ok := lookup[strings.TrimSpace(dep)]
+			if !ok {
+				return nil, fmt.Errorf("task %s depends on unknown task %s", task.Number, dep)
+			}
+			if depIdx == i {
+				return nil, fmt.Errorf("task %s depends on itself", task.Number)
+			}
+			if !seen[depIdx] {
+				seen[depIdx] = true
+				prerequisites[i] = append(prerequisites[i], depIdx)
+			}
+		}
+	}
+	return prerequisites, nil
+}
+
+// ExportTaskWaves groups a phase's tasks into waves that can run in
+// parallel. Each wave holds the numbers of tasks whose dependencies all
+// appear in earlier waves, in phase order. Returns an error if the
+// dependencies contain a cycle or reference an unknown task.
+func (g *Generator) ExportTaskWaves(phase *Phase) ([][]string, error) {
+	if phase == nil {
+		return nil, fmt.Errorf("phase cannot be nil")
+	}
+
+	prerequisites, err := taskPrerequisites(phase)
+	if err != nil {
+		return nil, err
+	}
+
+	inDegree := make([]int, len(phase.Tasks))
+	dependents := make([][]int, len(phase.Tasks))
+	for i, deps := range prerequisites {
+		inDegree[i] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], i)
+		}
+	}
+
+	// Kahn's algorithm, scanning in phase order so each wave stays sorted
+	waves := [][]string{}
+	placed := make([]bool, len(phase.Tasks))
+	remaining := len(phase.Tasks)
+	for remaining > 0 {
+		var ready []int
+		for i := range phase.Tasks {
+			if !placed[i] && inDegree[i] == 0 {
+				ready = append(ready, i)
+			}
+		}
+		if len(ready) == 0 {
+			return nil, fmt.Errorf("task dependencies in phase %d contain a cycle", phase.Number)
+		}
+
+		wave := make([]string, 0, len(ready))
+		for _, idx := range ready {
+			placed[idx] = true
+			wave = append(wave, phase.Tasks[idx].Number)
+			for _, dependent := range dependents[idx] {
+				inDegree[dependent]--
+			}
+		}
+		waves = append(waves, wave)
+		remaining -= len(ready)
+	}
+
+	return waves, nil
+}
+
+// ValidateTaskDependencies checks that every task dependency in a phase
+// references another task of the phase and that they contain no cycle
+func (g *Generator) ValidateTaskDependencies(phase *Phase) error {
+	_, err := g.ExportTaskWaves(phase)
+	return err
+}
+
+// NextTask returns the first task in the phase that hasn't started and whose
+// dependencies are all completed or skipped, or nil when no task is ready.
+// Tasks without dependencies are offered in number order as before.
+func (g *Generator) NextTask(phase *Phase) (*Task, error) {
+	if phase == nil {
+		return nil, fmt.Errorf("phase cannot be nil")
+	}
+
+	prerequisites, err := taskPrerequisites(phase)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range phase.Tasks {
+		if phase.Tasks[i].Status != TaskNotStarted {
+			continue
+		}
+		ready := true
+		for _, dep := range prerequisites[i] {
+			status := phase.Tasks[dep].Status
+			if status != TaskCompleted && status != TaskSkipped {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			return &phase.Tasks[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package devplan
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// InjectTestingTasks appends a task to write tests for the phase, with one
+// acceptance criterion per success criterion, unless a task already covers
+// testing. The phase is updated in place and returned, so calling it again
+// adds nothing.
+func (g *Generator) InjectTestingTasks(phase *Phase) *Phase {
+	if phase == nil {
+		return nil
+	}
+	for _, task := range phase.Tasks {
+		if isTestingTask(task) {
+			return phase
+		}
+	}
+
+	var criteria []string
+	for _, criterion := range phase.SuccessCriteria {
+		criteria = append(criteria, fmt.Sprintf("Tests verify: %s", criterion))
+	}
+	if len(criteria) == 0 {
+		criteria = append(criteria, fmt.Sprintf("Tests cover the phase objective: %s", phase.Objective))
+	}
+	criteria = append(criteria, "All tests pass")
+
+	number := len(phase.Tasks) + 1
+	task := Task{
+		ID:                  fmt.Sprintf("task-%d-%d", phase.Number, number),
+		Number:              fmt.Sprintf("%d.%d", phase.Number, number),
+		Description:         fmt.Sprintf("Write tests for phase %d: %s", phase.Number, phase.Title),
+		AcceptanceCriteria:  criteria,
+		ImplementationNotes: []string{"Cover each success criterion with at least one automated test"},
+		Status:              TaskNotStarted,
+	}
+	task.Difficulty = estimateDifficultyHeuristic(task)
+	phase.Tasks = append(phase.Tasks, task)
+
+	return phase
+}
+
+// isTestingTask reports whether a task's description mentions testing. Words
+// are matched by prefix so "latest" and "contest" don't count.
+func isTestingTask(task Task) bool {
+	words := strings.FieldsFunc(strings.ToLower(task.Description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if strings.HasPrefix(word, "test") || word == "qa" || word == "e2e" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package devplan
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ExportMasterPlanWithTOC exports the master devplan overview like
+// ExportMasterPlan, with a linked table of contents and a summary table of
+// phases at the top. Links use the anchors GitHub generates for headings.
+func (g *Generator) ExportMasterPlanWithTOC(devplan *DevPlan) (string, error) {
+	anchors := newAnchorSet()
+	phaseHeadings := make([]string, len(devplan.Phases))
+	phaseAnchors := make([]string, len(devplan.Phases))
+
+	// Anchors are assigned in document order so repeated headings get the
+	// same -1, -2 suffixes GitHub gives them
+	for _, heading := range []string{"Development Plan", "Contents", "Overview", "Phase Summary", "Phases"} {
+		anchors.add(heading)
+	}
+	for i, phase := range devplan.Phases {
+		phaseHeadings[i] = fmt.Sprintf("Phase %d: %s", phase.Number, phase.Title)
+		phaseAnchors[i] = anchors.add(phaseHeadings[i])
+	}
+	totalsAnchor := anchors.add("Total Estimates")
+
+	var md strings.Builder
+
+	md.WriteString("# Development Plan\n\n")
+	md.WriteString(fmt.Sprintf("**Project ID:** %s\n", devplan.ProjectID))
+	md.WriteString(fmt.Sprintf("**Generated:** %s\n\n", devplan.CreatedAt.Format("2006-01-02 15:04:05")))
+
+	md.WriteString("## Contents\n\n")
+	md.WriteString("- [Overview](#overview)\n")
+	md.WriteString("- [Phase Summary](#phase-summary)\n")
+	md.WriteString("- [Phases](#phases)\n")
+	for i := range devplan.Phases {
+		md.WriteString(fmt.Sprintf("  - [%s](#%s)\n", phaseHeadings[i], phaseAnchors[i]))
+	}
+	md.WriteString(fmt.Sprintf("- [Total Estimates](#%s)\n\n", totalsAnchor))
+
+	md.WriteString("## Overview\n\n")
+	md.WriteString(fmt.Sprintf("This development plan consists of %d phases.\n\n", len(devplan.Phases)))
+
+	md.WriteString("## Phase Summary\n\n")
+	md.WriteString("| Phase | Title | Tasks | Cost | Status |\n")
+	md.WriteString("|-------|-------|-------|------|--------|\n")
+	for i, phase := range devplan.Phases {
+		md.WriteString(fmt.Sprintf("| [%d](#%s) | %s | %d | $%.2f | %s |\n",
+			phase.Number, phaseAnchors[i], escapeTableCell(phase.Title), len(phase.Tasks),
+			phase.EstimatedCost, phase.Status))
+	}
+	md.WriteString("\n")
+
+	md.WriteString("## Phases\n\n")
+	for i, phase := range devplan.Phases {
+		md.WriteString(fmt.Sprintf("### %s\n\n", phaseHeadings[i]))
+		md.WriteString(fmt.Sprintf("**Objective:** %s\n\n", phase.Objective))
+		md.WriteString(fmt.Sprintf("**Tasks:** %d\n", len(phase.Tasks)))
+		md.WriteString(fmt.Sprintf("**Estimated Tokens:** %d\n", phase.EstimatedTokens))
+		md.WriteString(fmt.Sprintf("**Estimated Cost:** $%.2f\n", phase.EstimatedCost))
+		md.WriteString(fmt.Sprintf("**Status:** %s\n\n", phase.Status))
+	}
+
+	md.WriteString("## Total Estimates\n\n")
+	md.WriteString(fmt.Sprintf("- **Total Tokens:** %d\n", devplan.TotalTokens))
+	md.WriteString(fmt.Sprintf("- **Total Cost:** $%.2f\n", devplan.TotalCost))
+
+	return md.String(), nil
+}
+
+// anchorSet hands out heading anchors, numbering repeats the way GitHub does
+type anchorSet map[string]int
+
+func newAnchorSet() anchorSet {
+	return make(anchorSet)
+}
+
+// add returns the anchor for the next heading with this text
+func (a anchorSet) add(heading string) string {
+	anchor := headingAnchor(heading)
+	count := a[anchor]
+	a[anchor] = count + 1
+	if count > 0 {
+		return fmt.Sprintf("%s-%d", anchor, count)
+	}
+	return anchor
+}
+
+// headingAnchor converts a heading to a GitHub-style anchor: lowercased,
+// punctuation dropped and spaces turned into hyphens
+func headingAnchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
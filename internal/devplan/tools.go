@@ -0,0 +1,110 @@
+package devplan
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/mojomast/geoffrussy/internal/provider"
+)
+
+// submitPhaseToolName is the tool the model calls once per generated phase
+const submitPhaseToolName = "submit_phase"
+
+// stringArraySchema is the JSON Schema for a list of strings
+var stringArraySchema = map[string]interface{}{
+	"type":  "array",
+	"items": map[string]interface{}{"type": "string"},
+}
+
+// submitPhaseTool lets the model hand back each phase as arguments that
+// unmarshal straight into a Phase, matching its JSON tags
+var submitPhaseTool = provider.Tool{
+	Name:        submitPhaseToolName,
+	Description: "Submit one development phase of the plan. Call once per phase, in order.",
+	Parameters: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"number":           map[string]interface{}{"type": "integer"},
+			"title":            map[string]interface{}{"type": "string"},
+			"objective":        map[string]interface{}{"type": "string"},
+			"success_criteria": stringArraySchema,
+			"criteria_checks": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Shell commands that verify success criteria, keyed by criterion",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"dependencies": stringArraySchema,
+			"tasks": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"number":               map[string]interface{}{"type": "string"},
+						"description":          map[string]interface{}{"type": "string"},
+						"acceptance_criteria":  stringArraySchema,
+						"implementation_notes": stringArraySchema,
+						"difficulty": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"trivial", "moderate", "complex"},
+						},
+						"depends_on": stringArraySchema,
+					},
+					"required": []string{"number", "description"},
+				},
+			},
+		},
+		"required": []string{"number", "title", "objective", "tasks"},
+	},
+}
+
+// generatePhasesWithTools asks the model to submit each phase through the
+// submit_phase tool. It returns no phases and no error when the provider
+// turns out not to support tools or no usable phase is submitted, so the
+// caller can fall back to parsing a text response; any other failure is
+// returned.
+func (g *Generator) generatePhasesWithTools(caller provider.ToolCaller, prompt string) ([]Phase, error) {
+	messages := []provider.Message{
+		{Role: "system", Content: "Submit every phase by calling the " + submitPhaseToolName + " tool once per phase, in order, instead of writing JSON in your reply."},
+		{Role: "user", Content: prompt},
+	}
+
+	tools := []provider.Tool{submitPhaseTool}
+	response, err := caller.CallWithTools(g.model, messages, tools, planCallOptions)
+	if errors.Is(err, provider.ErrToolsUnsupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.Truncated() {
+		// A cut-off reply may be missing phases, so ask again with room to finish
+		retryOpts := planCallOptions
+		retryOpts.MaxTokens = planRetryMaxTokens
+		response, err = caller.CallWithTools(g.model, messages, tools, retryOpts)
+		if err != nil {
+			return nil, err
+		}
+		if response.Truncated() {
+			return nil, nil
+		}
+	}
+	return phasesFromToolCalls(response.ToolCalls), nil
+}
+
+// phasesFromToolCalls decodes submit_phase calls into phases, skipping other
+// tools and calls whose arguments don't parse
+func phasesFromToolCalls(calls []provider.ToolCall) []Phase {
+	var phases []Phase
+	for _, call := range calls {
+		if call.Name != submitPhaseToolName {
+			continue
+		}
+		var phase Phase
+		if err := json.Unmarshal([]byte(call.Arguments), &phase); err != nil {
+			continue
+		}
+		phases = append(phases, phase)
+	}
+	fillPhaseDefaults(phases)
+	return phases
+}
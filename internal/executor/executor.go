@@ -3,9 +3,11 @@ package executor
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/mojomast/geoffrussy/internal/devplan"
 	"github.com/mojomast/geoffrussy/internal/provider"
 	"github.com/mojomast/geoffrussy/internal/state"
 )
@@ -45,6 +47,8 @@ type Executor struct {
 	paused     bool
 	pauseMu    sync.RWMutex
 	pauseCond  *sync.Cond
+
+	budgetCheck func() (warning string, err error)
 }
 
 // NewExecutor creates a new task executor
@@ -64,6 +68,12 @@ func NewExecutor(store *state.Store, provider provider.Provider, modelName strin
 	}
 }
 
+// SetBudgetCheck sets a check run after each task. A warning is reported
+// as progress; an error stops the phase before the next task starts.
+func (e *Executor) SetBudgetCheck(check func() (warning string, err error)) {
+	e.budgetCheck = check
+}
+
 // ExecuteProject executes all phases in a project
 func (e *Executor) ExecuteProject(projectID string, startPhaseID string, stopAfterPhase bool) error {
 	phaseID := startPhaseID
@@ -129,11 +139,19 @@ func (e *Executor) ExecutePhase(phaseID string) error {
 		return fmt.Errorf("failed to list tasks: %w", err)
 	}
 
-	for _, task := range tasks {
-		if task.Status == state.TaskCompleted {
-			continue
+	// Run tasks in dependency order: each pass takes the first task whose
+	// dependencies have finished
+	plan := taskPlan(tasks)
+	generator := devplan.NewGenerator(nil, "")
+	for {
+		next, err := generator.NextTask(plan)
+		if err != nil {
+			return fmt.Errorf("failed to order tasks: %w", err)
 		}
-		if err := e.ExecuteTask(task.ID); err != nil {
+		if next == nil {
+			break
+		}
+		if err := e.ExecuteTask(next.ID); err != nil {
 			// If task failed, stop phase execution
 			e.sendUpdate(TaskUpdate{
 				PhaseID:   phaseID,
@@ -144,6 +162,20 @@ func (e *Executor) ExecutePhase(phaseID string) error {
 			})
 			return err
 		}
+		next.Status = devplan.TaskCompleted
+
+		if err := e.checkBudget(phaseID); err != nil {
+			return err
+		}
+	}
+	for _, task := range plan.Tasks {
+		if task.Status == devplan.TaskNotStarted {
+			return fmt.Errorf("task %s can't run: its dependencies never finish", task.Number)
+		}
+	}
+
+	if err := e.acceptOutstandingCriteria(phaseID); err != nil {
+		return err
 	}
 
 	// Update phase status to completed
@@ -162,6 +194,95 @@ func (e *Executor) ExecutePhase(phaseID string) error {
 	return nil
 }
 
+// checkBudget runs the budget check, if one is set, reporting a warning as
+// progress and an overrun as a phase error
+func (e *Executor) checkBudget(phaseID string) error {
+	if e.budgetCheck == nil {
+		return nil
+	}
+
+	warning, err := e.budgetCheck()
+	if err != nil {
+		e.sendUpdate(TaskUpdate{
+			PhaseID:   phaseID,
+			Type:      TaskError,
+			Content:   fmt.Sprintf("Phase stopped by the budget: %v", err),
+			Timestamp: time.Now(),
+			Error:     err,
+		})
+		return err
+	}
+	if warning != "" {
+		e.sendUpdate(TaskUpdate{
+			PhaseID:   phaseID,
+			Type:      TaskProgress,
+			Content:   warning,
+			Timestamp: time.Now(),
+		})
+	}
+	return nil
+}
+
+// taskPlan mirrors a phase's stored tasks as a devplan phase for ordering.
+// Completed and skipped tasks count as finished; any other task, including
+// one left in progress by an interrupted run, is run again.
+func taskPlan(tasks []state.Task) *devplan.Phase {
+	plan := &devplan.Phase{Tasks: make([]devplan.Task, len(tasks))}
+	for i, task := range tasks {
+		status := devplan.TaskNotStarted
+		if task.Status == state.TaskCompleted || task.Status == state.TaskSkipped {
+			status = devplan.TaskStatus(task.Status)
+		}
+		plan.Tasks[i] = devplan.Task{
+			ID:        task.ID,
+			Number:    task.Number,
+			Status:    status,
+			DependsOn: task.DependsOn,
+		}
+	}
+	return plan
+}
+
+// acceptOutstandingCriteria settles the phase's unmet success criteria once
+// all of its tasks have completed. A criterion with a shell check is met only
+// if the check passes in the working directory the tasks wrote to; one
+// without a check is accepted. Failed checks stay unmet, so completing the
+// phase then fails with state.ErrUnmetCriteria.
+func (e *Executor) acceptOutstandingCriteria(phaseID string) error {
+	outstanding, err := e.store.ListOutstandingCriteria(phaseID)
+	if err != nil {
+		return fmt.Errorf("failed to list success criteria: %w", err)
+	}
+
+	workdir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	for _, criterion := range outstanding {
+		if criterion.Check != "" && !devplan.RunCriteriaCheck(criterion.Check, workdir, 0) {
+			e.sendUpdate(TaskUpdate{
+				PhaseID:   phaseID,
+				Type:      TaskProgress,
+				Content:   fmt.Sprintf("Success criterion not met: %s (check failed: %s)", criterion.Text, criterion.Check),
+				Timestamp: time.Now(),
+			})
+			continue
+		}
+		if err := e.store.MarkCriterionMet(phaseID, criterion.Index, true); err != nil {
+			return fmt.Errorf("failed to mark success criterion: %w", err)
+		}
+		e.sendUpdate(TaskUpdate{
+			PhaseID:   phaseID,
+			Type:      TaskProgress,
+			Content:   fmt.Sprintf("Success criterion met: %s", criterion.Text),
+			Timestamp: time.Now(),
+		})
+	}
+
+	return nil
+}
+
 // ExecuteTask executes a single task
 func (e *Executor) ExecuteTask(taskID string) error {
 	// Check if paused
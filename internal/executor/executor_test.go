@@ -1,6 +1,8 @@
 package executor
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,15 +17,29 @@ func setupTestExecutor(t *testing.T) (*Executor, *state.Store) {
 		t.Fatalf("failed to create store: %v", err)
 	}
 
-	// Create mock provider (nil is acceptable for testing)
-	var mockProvider provider.Provider = nil
+	// The echo provider answers offline; tasks write its reply to output.md
+	mockProvider := provider.NewEchoProvider()
 
 	// Create executor
-	executor := NewExecutor(store, mockProvider)
+	executor := NewExecutor(store, mockProvider, "")
 
 	return executor, store
 }
 
+// seedProjectContext stores the interview data and architecture a task needs
+// and runs the test in a temporary directory so generated files land there
+func seedProjectContext(t *testing.T, store *state.Store, projectID string) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	if err := store.SaveInterviewData(projectID, &state.InterviewData{ProjectID: projectID, ProjectName: "Test Project"}); err != nil {
+		t.Fatalf("failed to save interview data: %v", err)
+	}
+	if err := store.SaveArchitecture(projectID, &state.Architecture{ProjectID: projectID, Content: "# Architecture", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("failed to save architecture: %v", err)
+	}
+}
+
 func TestNewExecutor(t *testing.T) {
 	executor, store := setupTestExecutor(t)
 	defer store.Close()
@@ -133,6 +149,7 @@ func TestExecutor_SkipTask(t *testing.T) {
 	if err := store.CreateProject(project); err != nil {
 		t.Fatalf("failed to create project: %v", err)
 	}
+	seedProjectContext(t, store, project.ID)
 
 	// Create a test phase
 	phase := &state.Phase{
@@ -189,6 +206,7 @@ func TestExecutor_MarkBlocked(t *testing.T) {
 	if err := store.CreateProject(project); err != nil {
 		t.Fatalf("failed to create project: %v", err)
 	}
+	seedProjectContext(t, store, project.ID)
 
 	// Create a test phase
 	phase := &state.Phase{
@@ -264,6 +282,7 @@ func TestExecutor_ResolveBlocker(t *testing.T) {
 	if err := store.CreateProject(project); err != nil {
 		t.Fatalf("failed to create project: %v", err)
 	}
+	seedProjectContext(t, store, project.ID)
 
 	// Create a test phase
 	phase := &state.Phase{
@@ -342,6 +361,7 @@ func TestExecutor_ExecuteTask(t *testing.T) {
 	if err := store.CreateProject(project); err != nil {
 		t.Fatalf("failed to create project: %v", err)
 	}
+	seedProjectContext(t, store, project.ID)
 
 	// Create a test phase
 	phase := &state.Phase{
@@ -422,6 +442,7 @@ func TestExecutor_ExecutePhase(t *testing.T) {
 	if err := store.CreateProject(project); err != nil {
 		t.Fatalf("failed to create project: %v", err)
 	}
+	seedProjectContext(t, store, project.ID)
 
 	// Create a test phase
 	phase := &state.Phase{
@@ -463,7 +484,7 @@ func TestExecutor_ExecutePhase(t *testing.T) {
 		select {
 		case update := <-executor.StreamOutput():
 			updates = append(updates, update)
-			if update.Type == TaskCompleted && update.PhaseID == phase.ID {
+			if update.Type == TaskCompleted && update.PhaseID == phase.ID && update.TaskID == "" {
 				goto done
 			}
 		case <-timeout:
@@ -487,3 +508,239 @@ done:
 		t.Errorf("expected phase status to be '%s', got %s", state.PhaseCompleted, updatedPhase.Status)
 	}
 }
+
+func TestExecutor_ExecutePhaseWithSuccessCriteria(t *testing.T) {
+	executor, store := setupTestExecutor(t)
+	defer store.Close()
+	defer executor.Close()
+
+	project := &state.Project{ID: "test-project", Name: "Test Project", CreatedAt: time.Now()}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	seedProjectContext(t, store, project.ID)
+
+	phase := &state.Phase{
+		ID:              "phase-1",
+		ProjectID:       project.ID,
+		Number:          1,
+		Title:           "Test Phase",
+		Status:          state.PhaseNotStarted,
+		CreatedAt:       time.Now(),
+		SuccessCriteria: []string{"Service starts", "Tests pass"},
+		CriteriaChecks:  map[string]string{"Tests pass": "true"},
+	}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("failed to save phase: %v", err)
+	}
+	for _, task := range []*state.Task{
+		{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Write the service", Status: state.TaskNotStarted},
+		{ID: "task-2", PhaseID: phase.ID, Number: "1.2", Description: "Write the tests", Status: state.TaskNotStarted},
+	} {
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("failed to save task: %v", err)
+		}
+	}
+
+	if err := executor.ExecuteProject(project.ID, phase.ID, false); err != nil {
+		t.Fatalf("failed to execute project: %v", err)
+	}
+
+	updated, err := store.GetPhase(phase.ID)
+	if err != nil {
+		t.Fatalf("failed to get phase: %v", err)
+	}
+	if updated.Status != state.PhaseCompleted || updated.CompletedAt == nil {
+		t.Errorf("expected phase completed, got %s", updated.Status)
+	}
+
+	tasks, err := store.ListTasks(phase.ID)
+	if err != nil {
+		t.Fatalf("failed to list tasks: %v", err)
+	}
+	for _, task := range tasks {
+		if task.Status != state.TaskCompleted {
+			t.Errorf("expected task %s completed, got %s", task.Number, task.Status)
+		}
+	}
+
+	outstanding, err := store.ListOutstandingCriteria(phase.ID)
+	if err != nil {
+		t.Fatalf("failed to list criteria: %v", err)
+	}
+	if len(outstanding) != 0 {
+		t.Errorf("expected every criterion met, got %d outstanding", len(outstanding))
+	}
+
+	criteria, err := store.ListSuccessCriteria(phase.ID)
+	if err != nil {
+		t.Fatalf("failed to list criteria: %v", err)
+	}
+	if !criteria[1].Met {
+		t.Errorf("expected the passing check to mark %q met", criteria[1].Text)
+	}
+
+	stageCosts, err := store.GetCostByStage(project.ID)
+	if err != nil {
+		t.Fatalf("failed to get cost by stage: %v", err)
+	}
+	if _, ok := stageCosts[string(state.StageDevelop)]; !ok {
+		t.Errorf("expected task calls to be recorded under the develop stage, got %v", stageCosts)
+	}
+}
+
+func TestExecutor_ExecutePhaseFailedCriteriaCheck(t *testing.T) {
+	executor, store := setupTestExecutor(t)
+	defer store.Close()
+	defer executor.Close()
+
+	project := &state.Project{ID: "test-project", Name: "Test Project", CreatedAt: time.Now()}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	seedProjectContext(t, store, project.ID)
+
+	phase := &state.Phase{
+		ID:              "phase-1",
+		ProjectID:       project.ID,
+		Number:          1,
+		Title:           "Test Phase",
+		Status:          state.PhaseNotStarted,
+		CreatedAt:       time.Now(),
+		SuccessCriteria: []string{"Service starts", "Binary built"},
+		CriteriaChecks:  map[string]string{"Binary built": "test -f service.bin"},
+	}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("failed to save phase: %v", err)
+	}
+	task := &state.Task{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Write the service", Status: state.TaskNotStarted}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("failed to save task: %v", err)
+	}
+
+	err := executor.ExecuteProject(project.ID, phase.ID, false)
+	if !errors.Is(err, state.ErrUnmetCriteria) {
+		t.Fatalf("expected ErrUnmetCriteria, got %v", err)
+	}
+
+	outstanding, err := store.ListOutstandingCriteria(phase.ID)
+	if err != nil {
+		t.Fatalf("failed to list criteria: %v", err)
+	}
+	if len(outstanding) != 1 || outstanding[0].Text != "Binary built" {
+		t.Errorf("expected only the failed check outstanding, got %+v", outstanding)
+	}
+	updated, err := store.GetPhase(phase.ID)
+	if err != nil {
+		t.Fatalf("failed to get phase: %v", err)
+	}
+	if updated.Status == state.PhaseCompleted {
+		t.Error("expected the phase not to be completed")
+	}
+}
+
+func TestExecutor_ExecutePhaseDependencyOrder(t *testing.T) {
+	executor, store := setupTestExecutor(t)
+	defer store.Close()
+	defer executor.Close()
+
+	project := &state.Project{ID: "test-project", Name: "Test Project", CreatedAt: time.Now()}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	seedProjectContext(t, store, project.ID)
+
+	phase := &state.Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Test Phase", Status: state.PhaseNotStarted, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("failed to save phase: %v", err)
+	}
+	for _, task := range []*state.Task{
+		{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Wire the handlers", Status: state.TaskNotStarted, DependsOn: []string{"1.3"}},
+		{ID: "task-2", PhaseID: phase.ID, Number: "1.2", Description: "Write the config", Status: state.TaskNotStarted},
+		{ID: "task-3", PhaseID: phase.ID, Number: "1.3", Description: "Write the handlers", Status: state.TaskNotStarted},
+	} {
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("failed to save task: %v", err)
+		}
+	}
+
+	if err := executor.ExecuteProject(project.ID, phase.ID, false); err != nil {
+		t.Fatalf("failed to execute project: %v", err)
+	}
+
+	var started []string
+	for len(executor.updateChan) > 0 {
+		update := <-executor.updateChan
+		if update.Type == TaskStarted && update.TaskID != "" {
+			started = append(started, update.TaskID)
+		}
+	}
+	expected := []string{"task-2", "task-3", "task-1"}
+	if strings.Join(started, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected tasks started in order %v, got %v", expected, started)
+	}
+
+	// A dependency cycle can't be ordered
+	cyclic := &state.Phase{ID: "phase-2", ProjectID: project.ID, Number: 2, Title: "Cyclic Phase", Status: state.PhaseNotStarted, CreatedAt: time.Now()}
+	if err := store.SavePhase(cyclic); err != nil {
+		t.Fatalf("failed to save phase: %v", err)
+	}
+	for _, task := range []*state.Task{
+		{ID: "task-4", PhaseID: cyclic.ID, Number: "2.1", Description: "First", Status: state.TaskNotStarted, DependsOn: []string{"2.2"}},
+		{ID: "task-5", PhaseID: cyclic.ID, Number: "2.2", Description: "Second", Status: state.TaskNotStarted, DependsOn: []string{"2.1"}},
+	} {
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("failed to save task: %v", err)
+		}
+	}
+	if err := executor.ExecutePhase(cyclic.ID); err == nil {
+		t.Error("expected an error for tasks whose dependencies never finish")
+	}
+}
+
+func TestExecutor_ExecutePhaseBudgetCheck(t *testing.T) {
+	executor, store := setupTestExecutor(t)
+	defer store.Close()
+	defer executor.Close()
+
+	project := &state.Project{ID: "test-project", Name: "Test Project", CreatedAt: time.Now()}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	seedProjectContext(t, store, project.ID)
+
+	phase := &state.Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Test Phase", Status: state.PhaseNotStarted, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("failed to save phase: %v", err)
+	}
+	for _, task := range []*state.Task{
+		{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Write the config", Status: state.TaskNotStarted},
+		{ID: "task-2", PhaseID: phase.ID, Number: "1.2", Description: "Write the handlers", Status: state.TaskNotStarted},
+	} {
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("failed to save task: %v", err)
+		}
+	}
+
+	// The budget runs out once the first task has been paid for
+	checks := 0
+	executor.SetBudgetCheck(func() (string, error) {
+		checks++
+		return "", errors.New("budget limit exceeded: $12.00 / $10.00")
+	})
+
+	if err := executor.ExecutePhase(phase.ID); err == nil {
+		t.Fatal("expected the phase to stop once the budget is exceeded")
+	}
+	if checks != 1 {
+		t.Errorf("expected the budget to be checked once, got %d", checks)
+	}
+
+	second, err := store.GetTask("task-2")
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if second.Status != state.TaskNotStarted {
+		t.Errorf("expected the second task not to start, got status %s", second.Status)
+	}
+}
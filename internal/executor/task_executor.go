@@ -82,20 +82,24 @@ func (te *TaskExecutor) ExecuteTask(taskID string) error {
 	// Store phase ID for updates
 	te.phaseID = phase.ID
 
-	// Get project
-	project, err := te.store.GetProject(phase.ProjectID)
+	// Only the project's ID is needed, so just check it exists
+	projectID := phase.ProjectID
+	exists, err := te.store.ProjectExists(projectID)
 	if err != nil {
 		return fmt.Errorf("failed to get project: %w", err)
 	}
+	if !exists {
+		return fmt.Errorf("failed to get project: project not found: %s", projectID)
+	}
 
 	// Get interview data for context
-	interviewData, err := te.store.GetInterviewData(project.ID)
+	interviewData, err := te.store.GetInterviewData(projectID)
 	if err != nil {
 		return fmt.Errorf("failed to get interview data: %w", err)
 	}
 
 	// Get architecture for context
-	architecture, err := te.store.GetArchitecture(project.ID)
+	architecture, err := te.store.GetArchitecture(projectID)
 	if err != nil {
 		return fmt.Errorf("failed to get architecture: %w", err)
 	}
@@ -115,6 +119,15 @@ func (te *TaskExecutor) ExecuteTask(taskID string) error {
 		Timestamp: time.Now(),
 	})
 
+	// A budget hold pauses the project; don't spend more until it is resumed
+	paused, reason, err := te.store.IsPaused(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to check project pause state: %w", err)
+	}
+	if paused {
+		return fmt.Errorf("project is paused: %s", reason)
+	}
+
 	// Call LLM to generate code
 	response, err := te.provider.Call(modelName, prompt)
 	if err != nil {
@@ -137,6 +150,23 @@ func (te *TaskExecutor) ExecuteTask(taskID string) error {
 		Timestamp: time.Now(),
 	})
 
+	usage := &state.TokenUsage{
+		ProjectID:    projectID,
+		PhaseID:      phase.ID,
+		TaskID:       taskID,
+		Provider:     response.Provider,
+		Model:        response.Model,
+		TokensInput:  response.TokensInput,
+		TokensOutput: response.TokensOutput,
+		Cost:         provider.CallCost(te.provider, response),
+		Timestamp:    time.Now(),
+		Stage:        string(state.StageDevelop),
+	}
+	if err := te.store.RecordTokenUsage(usage); err != nil {
+		// Usage tracking is best effort and must not fail the task
+		_ = err
+	}
+
 	// Parse response
 	var codeResp CodeGenerationResponse
 	if err := json.Unmarshal([]byte(response.Content), &codeResp); err != nil {
@@ -0,0 +1,124 @@
+package interview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RecordReport describes the outcome of RecordAnswers
+type RecordReport struct {
+	Accepted          []string          // Question IDs recorded, in interview order
+	Rejected          map[string]string // Question ID to the reason its answer was rejected
+	RemainingRequired []string          // Required questions still unanswered, as from ValidateCompleteness
+}
+
+// RecordAnswers records many answers at once, keyed by question ID. Each
+// answer is validated on its own, so bad answers are reported instead of
+// stopping the batch. Unlike RecordAnswer the interview position doesn't
+// move; answered questions are skipped by the caller.
+func (e *Engine) RecordAnswers(session *InterviewSession, answers map[string]string) (*RecordReport, error) {
+	if session == nil {
+		return nil, fmt.Errorf("session cannot be nil")
+	}
+
+	report := &RecordReport{Rejected: make(map[string]string)}
+	known := make(map[string]bool)
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			known[q.ID] = true
+			text, ok := answers[q.ID]
+			if !ok {
+				continue
+			}
+			if reason := e.validateBatchAnswer(text); reason != "" {
+				report.Rejected[q.ID] = reason
+				continue
+			}
+
+			session.Answers[q.ID] = Answer{
+				QuestionID: q.ID,
+				Text:       strings.TrimSpace(text),
+				Timestamp:  time.Now(),
+			}
+			session.AnswerOrder = append(removeQuestionID(session.AnswerOrder, q.ID), q.ID)
+			report.Accepted = append(report.Accepted, q.ID)
+		}
+	}
+
+	unknown := make([]string, 0)
+	for id := range answers {
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+	for _, id := range unknown {
+		report.Rejected[id] = "unknown question"
+	}
+
+	if len(report.Accepted) > 0 {
+		session.LastUpdatedAt = time.Now()
+	}
+	_, report.RemainingRequired = e.ValidateCompleteness(session)
+	return report, nil
+}
+
+// validateBatchAnswer returns why an answer can't be recorded, or "" if it
+// can
+func (e *Engine) validateBatchAnswer(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return "answer is empty"
+	}
+	if err := e.checkAnswerLength(text); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// AutoFillOptional answers every unanswered optional question with its
+// ProposeDefault value and returns the IDs filled, in interview order.
+// Required questions are left for the user. Questions without a usable
+// default are skipped. Like RecordAnswers, the interview position doesn't
+// move.
+func (e *Engine) AutoFillOptional(session *InterviewSession) ([]string, error) {
+	if session == nil {
+		return nil, fmt.Errorf("session cannot be nil")
+	}
+
+	filled := []string{}
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			if q.Required {
+				continue
+			}
+			if _, answered := session.Answers[q.ID]; answered {
+				continue
+			}
+
+			proposed, err := e.ProposeDefault(q)
+			if err != nil {
+				return filled, fmt.Errorf("failed to auto-fill %s: %w", q.ID, err)
+			}
+			proposed = strings.TrimSpace(proposed)
+			if e.validateBatchAnswer(proposed) != "" {
+				continue
+			}
+
+			session.Answers[q.ID] = Answer{
+				QuestionID: q.ID,
+				Text:       proposed,
+				Timestamp:  time.Now(),
+			}
+			session.AnswerOrder = append(removeQuestionID(session.AnswerOrder, q.ID), q.ID)
+			recordProposal(session, q.ID, proposed)
+			filled = append(filled, q.ID)
+		}
+	}
+
+	if len(filled) > 0 {
+		session.LastUpdatedAt = time.Now()
+	}
+	return filled, nil
+}
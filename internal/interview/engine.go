@@ -1,13 +1,19 @@
 package interview
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/mojomast/geoffrussy/internal/provider"
 	"github.com/mojomast/geoffrussy/internal/state"
+	"gopkg.in/yaml.v3"
 )
 
 // Phase represents an interview phase
@@ -21,20 +27,93 @@ const (
 	PhaseRefinementValidation Phase = "refinement_validation"
 )
 
+// DefaultMaxAnswerLength is the default limit, in characters, on a single answer
+const DefaultMaxAnswerLength = 8000
+
+// ErrAnswerTooLong is returned when an answer exceeds the engine's limit
+var ErrAnswerTooLong = errors.New("answer is too long")
+
 // Engine conducts the interactive interview
 type Engine struct {
-	store    *state.Store
-	provider provider.Provider
-	model    string
+	store           *state.Store
+	provider        provider.Provider
+	model           string
+	maxAnswerLength int
+	projectID       string // Project of the current session, for usage attribution
+	language        string // Locale of question text, see SetLanguage
+	phaseHooks      []func(session *InterviewSession, phase Phase)
+	streamCtx       context.Context    // Set by SetStreaming; nil makes plain calls
+	onChunk         func(chunk string) // Sees streamed chunks, may be nil
 }
 
 // NewEngine creates a new interview engine
 func NewEngine(store *state.Store, provider provider.Provider, model string) *Engine {
 	return &Engine{
-		store:    store,
-		provider: provider,
-		model:    model,
+		store:           store,
+		provider:        provider,
+		model:           model,
+		maxAnswerLength: DefaultMaxAnswerLength,
+		language:        DefaultLanguage,
+	}
+}
+
+// SetMaxAnswerLength changes the answer length limit, in characters. A
+// value of zero or less removes the limit.
+func (e *Engine) SetMaxAnswerLength(max int) {
+	e.maxAnswerLength = max
+}
+
+// SetStreaming makes provider calls stream until ctx is done, so a stalled
+// stream fails after provider.DefaultStreamIdleTimeout instead of hanging the
+// interview. onChunk, if not nil, sees each chunk as it arrives.
+func (e *Engine) SetStreaming(ctx context.Context, onChunk func(chunk string)) {
+	e.streamCtx = ctx
+	e.onChunk = onChunk
+}
+
+// callProvider makes an LLM call and records its token usage against the
+// interview stage of the current project
+func (e *Engine) callProvider(prompt string) (*provider.Response, error) {
+	var response *provider.Response
+	var err error
+	if e.streamCtx != nil {
+		response, err = provider.StreamCall(e.streamCtx, e.provider, e.model, prompt, provider.CallOptions{}, provider.DefaultStreamIdleTimeout, e.onChunk)
+	} else {
+		response, err = e.provider.Call(e.model, prompt)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if e.store != nil && e.projectID != "" {
+		usage := &state.TokenUsage{
+			ProjectID:    e.projectID,
+			Provider:     response.Provider,
+			Model:        response.Model,
+			TokensInput:  response.TokensInput,
+			TokensOutput: response.TokensOutput,
+			Cost:         provider.CallCost(e.provider, response),
+			Timestamp:    time.Now(),
+			Stage:        string(state.StageInterview),
+		}
+		if err := e.store.RecordTokenUsage(usage); err != nil {
+			// Usage tracking is best effort and must not interrupt the interview
+			_ = err
+		}
 	}
+
+	return response, nil
+}
+
+// checkAnswerLength rejects answers over the configured limit
+func (e *Engine) checkAnswerLength(answerText string) error {
+	if e.maxAnswerLength <= 0 {
+		return nil
+	}
+	if length := utf8.RuneCountInString(answerText); length > e.maxAnswerLength {
+		return fmt.Errorf("%w: %d characters, limit is %d", ErrAnswerTooLong, length, e.maxAnswerLength)
+	}
+	return nil
 }
 
 // Question represents an interview question
@@ -44,27 +123,71 @@ type Question struct {
 	Text     string
 	Category string
 	Required bool
+	HelpText string  // What the question is after, shown by the CLI's "?" command
+	Example  string  // A sample answer
+	Answer   *Answer // Current answer, set by ListAnsweredQuestions
 }
 
 // Answer represents a user's answer
 type Answer struct {
-	QuestionID string
-	Text       string
-	Timestamp  time.Time
+	QuestionID  string
+	Text        string
+	Timestamp   time.Time
+	Confidence  string // low, medium or high; empty when not asked
+	Attachments []Attachment
 }
 
+// Attachment is a reference document or link cited alongside an answer
+type Attachment struct {
+	Type        string // file or url
+	URI         string
+	Description string
+}
+
+// Attachment types
+const (
+	AttachmentFile = "file"
+	AttachmentURL  = "url"
+)
+
+// Answer confidence levels
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
 // InterviewSession represents an active interview session
 type InterviewSession struct {
-	ProjectID       string
-	CurrentPhase    Phase
-	CurrentQuestion int
-	Answers         map[string]Answer
-	FollowUpAnswers map[string][]Answer // Stores follow-up Q&A pairs
-	StartedAt       time.Time
-	LastUpdatedAt   time.Time
-	Completed       bool
-	Paused          bool
-	Iterations      []Iteration // Track reiteration history
+	ProjectID        string
+	CurrentPhase     Phase
+	CurrentQuestion  int
+	Answers          map[string]Answer
+	FollowUpAnswers  map[string][]Answer // Stores follow-up Q&A pairs
+	StartedAt        time.Time
+	LastUpdatedAt    time.Time
+	Completed        bool
+	Paused           bool
+	Iterations       []Iteration       // Track reiteration history
+	PhaseSummaries   map[Phase]string  // Recaps generated at phase boundaries
+	AnswerOrder      []string          // Question IDs in the order they were answered, for undo
+	SubInterviews    []*SubInterview   // Focused follow-up interviews, in the order they were started
+	Glossary         map[string]string // Project-specific terms from ExtractGlossary, mapped to definitions
+	ElevatorPitch    string            // Short shareable description from GenerateElevatorPitch
+	PreviousSessions []ArchivedSession // Answers of earlier runs archived by RestartInterview, oldest first
+	ProposedDefaults map[string]string // Defaults proposed for questions, keyed by question ID, for DivergentDefaults
+	LoadWarning      string            // Set by LoadSession when the saved session was unreadable and only basic data was restored
+}
+
+// SubInterview is a temporary set of questions on one topic, launched when
+// an answer reveals an area that needs more detail. Its answers are kept
+// apart from the main interview and exported under the parent question.
+type SubInterview struct {
+	ParentQuestionID string
+	Topic            string
+	Questions        []Question
+	Answers          map[string]Answer
+	Completed        bool
 }
 
 // Iteration represents a reiteration of answers
@@ -76,8 +199,14 @@ type Iteration struct {
 	Reason      string
 }
 
-// GetPhaseQuestions returns the questions for a specific phase
+// GetPhaseQuestions returns the questions for a specific phase in the
+// engine's language
 func (e *Engine) GetPhaseQuestions(phase Phase) []Question {
+	return e.localizeQuestions(withQuestionHelp(phaseQuestions(phase)))
+}
+
+// phaseQuestions returns the English questions for a specific phase
+func phaseQuestions(phase Phase) []Question {
 	switch phase {
 	case PhaseProjectEssence:
 		return []Question{
@@ -129,6 +258,7 @@ func (e *Engine) GetAllPhases() []Phase {
 
 // StartInterview starts a new interview session
 func (e *Engine) StartInterview(projectID string) (*InterviewSession, error) {
+	e.projectID = projectID
 	session := &InterviewSession{
 		ProjectID:       projectID,
 		CurrentPhase:    PhaseProjectEssence,
@@ -140,6 +270,7 @@ func (e *Engine) StartInterview(projectID string) (*InterviewSession, error) {
 		Completed:       false,
 		Paused:          false,
 		Iterations:      []Iteration{},
+		PhaseSummaries:  make(map[Phase]string),
 	}
 	
 	return session, nil
@@ -185,11 +316,18 @@ func (e *Engine) GetNextQuestion(session *InterviewSession) (*Question, error) {
 			return nil, fmt.Errorf("invalid current phase")
 		}
 		
+		e.recordPhaseSummary(session, session.CurrentPhase)
+
 		if currentPhaseIndex >= len(phases)-1 {
-			// Interview complete
+			// Interview complete. Asking again afterwards doesn't complete
+			// the last phase a second time.
+			if !session.Completed {
+				e.runPhaseHooks(session, session.CurrentPhase)
+			}
 			session.Completed = true
 			return nil, nil
 		}
+		e.runPhaseHooks(session, session.CurrentPhase)
 		
 		// Move to next phase
 		session.CurrentPhase = phases[currentPhaseIndex+1]
@@ -205,8 +343,101 @@ func (e *Engine) GetNextQuestion(session *InterviewSession) (*Question, error) {
 	return &question, nil
 }
 
+// OnPhaseComplete registers fn to run whenever GetNextQuestion moves past
+// the last question of a phase, including the final one. Hooks run in the
+// order they were registered, after the phase's recap has been recorded.
+func (e *Engine) OnPhaseComplete(fn func(session *InterviewSession, phase Phase)) {
+	e.phaseHooks = append(e.phaseHooks, fn)
+}
+
+// runPhaseHooks calls the phase completion hooks for a finished phase
+func (e *Engine) runPhaseHooks(session *InterviewSession, phase Phase) {
+	for _, hook := range e.phaseHooks {
+		hook(session, phase)
+	}
+}
+
+// recordPhaseSummary stores a recap of a finished phase on the session so
+// it can be shown at the phase boundary. A phase that already has a recap
+// keeps it, so asking for the next question again doesn't summarize anew.
+func (e *Engine) recordPhaseSummary(session *InterviewSession, phase Phase) {
+	if _, ok := session.PhaseSummaries[phase]; ok {
+		return
+	}
+
+	summary, err := e.SummarizePhase(session, phase)
+	if err != nil || summary == "" {
+		return
+	}
+
+	if session.PhaseSummaries == nil {
+		session.PhaseSummaries = make(map[Phase]string)
+	}
+	session.PhaseSummaries[phase] = summary
+}
+
+// SummarizePhase produces a short recap of what was learned in a phase.
+// The LLM is used when available; otherwise the recap is built from the
+// recorded answers. An empty string is returned if the phase has no answers.
+func (e *Engine) SummarizePhase(session *InterviewSession, phase Phase) (string, error) {
+	var answered []string
+	for _, q := range e.GetPhaseQuestions(phase) {
+		if answer, ok := session.Answers[q.ID]; ok && strings.TrimSpace(answer.Text) != "" {
+			answered = append(answered, fmt.Sprintf("%s: %s", q.Text, answer.Text))
+		}
+	}
+
+	if len(answered) == 0 {
+		return "", nil
+	}
+
+	if e.provider != nil {
+		prompt := fmt.Sprintf(`Summarize what was learned in the "%s" phase of a project requirements interview in 2-3 sentences. Be concise and factual; do not add information that is not in the answers.
+
+%s
+
+Summary:`, formatPhaseName(phase), strings.Join(answered, "\n"))
+
+		response, err := e.callProvider(prompt)
+		if err == nil && strings.TrimSpace(response.Content) != "" {
+			return strings.TrimSpace(response.Content), nil
+		}
+	}
+
+	return e.templatePhaseSummary(session, phase), nil
+}
+
+// templatePhaseSummary builds a recap of a phase from its answers without an LLM
+func (e *Engine) templatePhaseSummary(session *InterviewSession, phase Phase) string {
+	questions := e.GetPhaseQuestions(phase)
+
+	var points []string
+	for _, q := range questions {
+		if answer, ok := session.Answers[q.ID]; ok && strings.TrimSpace(answer.Text) != "" {
+			label := strings.ReplaceAll(q.Category, "_", " ")
+			points = append(points, fmt.Sprintf("%s: %s", label, truncateText(strings.TrimSpace(answer.Text), 80)))
+		}
+	}
+
+	return fmt.Sprintf("%s covered %d of %d questions. Key points - %s.",
+		formatPhaseName(phase), len(points), len(questions), strings.Join(points, "; "))
+}
+
+// truncateText shortens text to at most max characters, adding an ellipsis
+func truncateText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "..."
+}
+
 // RecordAnswer records a user's answer
 func (e *Engine) RecordAnswer(session *InterviewSession, questionID string, answerText string) error {
+	if err := e.checkAnswerLength(answerText); err != nil {
+		return err
+	}
+
 	answer := Answer{
 		QuestionID: questionID,
 		Text:       answerText,
@@ -214,12 +445,151 @@ func (e *Engine) RecordAnswer(session *InterviewSession, questionID string, answ
 	}
 	
 	session.Answers[questionID] = answer
+	session.AnswerOrder = append(removeQuestionID(session.AnswerOrder, questionID), questionID)
 	session.CurrentQuestion++
 	session.LastUpdatedAt = time.Now()
 	
 	return nil
 }
 
+// UndoLastAnswer removes the most recently recorded answer and rewinds the
+// session so that its question is asked next, moving back across a phase
+// boundary if needed
+func (e *Engine) UndoLastAnswer(session *InterviewSession) error {
+	if len(session.AnswerOrder) == 0 {
+		return fmt.Errorf("no answer to undo")
+	}
+
+	last := len(session.AnswerOrder) - 1
+	questionID := session.AnswerOrder[last]
+	session.AnswerOrder = session.AnswerOrder[:last]
+	delete(session.Answers, questionID)
+
+	if phase, index, ok := e.findQuestionPosition(questionID); ok {
+		session.CurrentPhase = phase
+		session.CurrentQuestion = index
+	} else if session.CurrentQuestion > 0 {
+		session.CurrentQuestion--
+	} else if previous, ok := e.previousPhase(session.CurrentPhase); ok {
+		session.CurrentPhase = previous
+		session.CurrentQuestion = len(e.GetPhaseQuestions(previous)) - 1
+	}
+
+	session.Completed = false
+	session.LastUpdatedAt = time.Now()
+
+	return nil
+}
+
+// findQuestionPosition locates a question's phase and index within it
+func (e *Engine) findQuestionPosition(questionID string) (Phase, int, bool) {
+	for _, phase := range e.GetAllPhases() {
+		for i, question := range e.GetPhaseQuestions(phase) {
+			if question.ID == questionID {
+				return phase, i, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// previousPhase returns the phase before the given one
+func (e *Engine) previousPhase(phase Phase) (Phase, bool) {
+	phases := e.GetAllPhases()
+	for i, p := range phases {
+		if p == phase && i > 0 {
+			return phases[i-1], true
+		}
+	}
+	return "", false
+}
+
+// removeQuestionID returns ids without any occurrence of questionID
+func removeQuestionID(ids []string, questionID string) []string {
+	filtered := ids[:0]
+	for _, id := range ids {
+		if id != questionID {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered
+}
+
+// RecordAnswerWithConfidence records a user's answer along with how sure
+// they are of it. Low-confidence answers are reported as unknowns.
+func (e *Engine) RecordAnswerWithConfidence(session *InterviewSession, questionID string, answerText string, confidence string) error {
+	switch confidence {
+	case ConfidenceLow, ConfidenceMedium, ConfidenceHigh:
+	default:
+		return fmt.Errorf("invalid confidence %q: must be low, medium, or high", confidence)
+	}
+
+	if err := e.RecordAnswer(session, questionID, answerText); err != nil {
+		return err
+	}
+
+	answer := session.Answers[questionID]
+	answer.Confidence = confidence
+	session.Answers[questionID] = answer
+
+	return nil
+}
+
+// GetUnknowns lists low-confidence answers in interview order, formatted as
+// "question: answer" so later stages can treat them as risks
+func (e *Engine) GetUnknowns(session *InterviewSession) []string {
+	unknowns := []string{}
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			if answer, ok := session.Answers[q.ID]; ok && answer.Confidence == ConfidenceLow {
+				unknowns = append(unknowns, fmt.Sprintf("%s: %s", q.Text, answer.Text))
+			}
+		}
+	}
+	return unknowns
+}
+
+// AttachToAnswer cites a reference file or link alongside an existing answer
+func (e *Engine) AttachToAnswer(session *InterviewSession, questionID string, att Attachment) error {
+	answer, ok := session.Answers[questionID]
+	if !ok {
+		return fmt.Errorf("answer not found for question: %s", questionID)
+	}
+	if att.URI == "" {
+		return fmt.Errorf("attachment URI cannot be empty")
+	}
+	if att.Type == "" {
+		att.Type = AttachmentFile
+		if strings.HasPrefix(att.URI, "http://") || strings.HasPrefix(att.URI, "https://") {
+			att.Type = AttachmentURL
+		}
+	}
+
+	answer.Attachments = append(answer.Attachments, att)
+	session.Answers[questionID] = answer
+	session.LastUpdatedAt = time.Now()
+
+	return nil
+}
+
+// GetReferences lists every attachment in interview order, formatted as
+// "description (uri)" for use in later stages
+func (e *Engine) GetReferences(session *InterviewSession) []string {
+	references := []string{}
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			for _, att := range session.Answers[q.ID].Attachments {
+				if att.Description != "" {
+					references = append(references, fmt.Sprintf("%s (%s)", att.Description, att.URI))
+				} else {
+					references = append(references, att.URI)
+				}
+			}
+		}
+	}
+	return references
+}
+
 // RecordFollowUpAnswer records an answer to a follow-up question
 func (e *Engine) RecordFollowUpAnswer(session *InterviewSession, questionID string, followUpQuestion string, answerText string) error {
 	answer := Answer{
@@ -238,12 +608,121 @@ func (e *Engine) RecordFollowUpAnswer(session *InterviewSession, questionID stri
 	return nil
 }
 
+// StartSubInterview launches a focused interview on topic, tied to the most
+// recently answered question. Questions without an ID are numbered under the
+// parent. Only one sub-interview can be active at a time; it completes once
+// every question has been answered through RecordSubAnswer.
+func (e *Engine) StartSubInterview(session *InterviewSession, topic string, questions []Question) error {
+	if strings.TrimSpace(topic) == "" {
+		return fmt.Errorf("sub-interview topic is required")
+	}
+	if len(questions) == 0 {
+		return fmt.Errorf("sub-interview needs at least one question")
+	}
+	if e.ActiveSubInterview(session) != nil {
+		return fmt.Errorf("a sub-interview is already in progress")
+	}
+	if len(session.AnswerOrder) == 0 {
+		return fmt.Errorf("answer a question before starting a sub-interview")
+	}
+	parentID := session.AnswerOrder[len(session.AnswerOrder)-1]
+	parentPhase, _, _ := e.findQuestionPosition(parentID)
+
+	sub := &SubInterview{
+		ParentQuestionID: parentID,
+		Topic:            topic,
+		Answers:          make(map[string]Answer),
+	}
+	seen := make(map[string]bool)
+	for i, q := range questions {
+		if q.ID == "" {
+			q.ID = fmt.Sprintf("%s_sub%d_%d", parentID, len(session.SubInterviews)+1, i+1)
+		}
+		if seen[q.ID] {
+			return fmt.Errorf("duplicate sub-interview question %s", q.ID)
+		}
+		seen[q.ID] = true
+		if q.Phase == "" {
+			q.Phase = parentPhase
+		}
+		if q.Category == "" {
+			q.Category = topic
+		}
+		q.Answer = nil
+		sub.Questions = append(sub.Questions, q)
+	}
+
+	session.SubInterviews = append(session.SubInterviews, sub)
+	session.LastUpdatedAt = time.Now()
+
+	return nil
+}
+
+// ActiveSubInterview returns the sub-interview still awaiting answers, or nil
+func (e *Engine) ActiveSubInterview(session *InterviewSession) *SubInterview {
+	if n := len(session.SubInterviews); n > 0 && !session.SubInterviews[n-1].Completed {
+		return session.SubInterviews[n-1]
+	}
+	return nil
+}
+
+// GetNextSubQuestion returns the next unanswered question of the active
+// sub-interview, or nil when there is none
+func (e *Engine) GetNextSubQuestion(session *InterviewSession) *Question {
+	sub := e.ActiveSubInterview(session)
+	if sub == nil {
+		return nil
+	}
+	for i := range sub.Questions {
+		if _, ok := sub.Answers[sub.Questions[i].ID]; !ok {
+			return &sub.Questions[i]
+		}
+	}
+	return nil
+}
+
+// RecordSubAnswer records an answer in the active sub-interview, completing
+// it once all of its questions are answered
+func (e *Engine) RecordSubAnswer(session *InterviewSession, questionID string, answerText string) error {
+	sub := e.ActiveSubInterview(session)
+	if sub == nil {
+		return fmt.Errorf("no sub-interview in progress")
+	}
+	known := false
+	for _, q := range sub.Questions {
+		if q.ID == questionID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("question %s is not part of the %s sub-interview", questionID, sub.Topic)
+	}
+	if err := e.checkAnswerLength(answerText); err != nil {
+		return err
+	}
+
+	sub.Answers[questionID] = Answer{
+		QuestionID: questionID,
+		Text:       answerText,
+		Timestamp:  time.Now(),
+	}
+	sub.Completed = len(sub.Answers) == len(sub.Questions)
+	session.LastUpdatedAt = time.Now()
+
+	return nil
+}
+
 // ReiterateAnswer allows changing a previous answer
 func (e *Engine) ReiterateAnswer(session *InterviewSession, questionID string, newAnswer string, reason string) error {
 	oldAnswer, exists := session.Answers[questionID]
 	if !exists {
 		return fmt.Errorf("no previous answer found for question %s", questionID)
 	}
+
+	if err := e.checkAnswerLength(newAnswer); err != nil {
+		return err
+	}
 	
 	// Record the iteration
 	iteration := Iteration{
@@ -256,11 +735,13 @@ func (e *Engine) ReiterateAnswer(session *InterviewSession, questionID string, n
 	
 	session.Iterations = append(session.Iterations, iteration)
 	
-	// Update the answer
+	// Update the answer, keeping the confidence and attachments given with it
 	session.Answers[questionID] = Answer{
-		QuestionID: questionID,
-		Text:       newAnswer,
-		Timestamp:  time.Now(),
+		QuestionID:  questionID,
+		Text:        newAnswer,
+		Timestamp:   time.Now(),
+		Confidence:  oldAnswer.Confidence,
+		Attachments: oldAnswer.Attachments,
 	}
 	
 	session.LastUpdatedAt = time.Now()
@@ -268,6 +749,24 @@ func (e *Engine) ReiterateAnswer(session *InterviewSession, questionID string, n
 	return nil
 }
 
+// ListAnsweredQuestions returns the questions that have an answer, in
+// interview order, each with its current answer attached. It is meant to
+// back an edit menu whose selection is passed to ReiterateAnswer.
+func (e *Engine) ListAnsweredQuestions(session *InterviewSession) []Question {
+	var answered []Question
+	for _, phase := range e.GetAllPhases() {
+		for _, question := range e.GetPhaseQuestions(phase) {
+			answer, ok := session.Answers[question.ID]
+			if !ok {
+				continue
+			}
+			question.Answer = &answer
+			answered = append(answered, question)
+		}
+	}
+	return answered
+}
+
 // GetAnswer retrieves an answer for a specific question
 func (e *Engine) GetAnswer(session *InterviewSession, questionID string) (*Answer, error) {
 	answer, exists := session.Answers[questionID]
@@ -289,6 +788,73 @@ func (e *Engine) GetIterationHistory(session *InterviewSession, questionID strin
 	return history
 }
 
+// duplicateAnswerThreshold is the word-overlap ratio above which two answers
+// are treated as the same information
+const duplicateAnswerThreshold = 0.8
+
+// DedupeAnswers groups questions whose answers are identical or nearly so.
+// Keys are the first question (in interview order) to give the answer and
+// values are the later questions that repeat it. Answers are not modified.
+func (e *Engine) DedupeAnswers(session *InterviewSession) map[string][]string {
+	groups := make(map[string][]string)
+	var canonicalIDs []string
+	canonicalWords := make(map[string]map[string]bool)
+
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			answer, ok := session.Answers[q.ID]
+			if !ok {
+				continue
+			}
+			words := answerWords(answer.Text)
+			if len(words) == 0 {
+				continue
+			}
+
+			matched := false
+			for _, canonicalID := range canonicalIDs {
+				if wordSimilarity(words, canonicalWords[canonicalID]) >= duplicateAnswerThreshold {
+					groups[canonicalID] = append(groups[canonicalID], q.ID)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				canonicalIDs = append(canonicalIDs, q.ID)
+				canonicalWords[q.ID] = words
+			}
+		}
+	}
+
+	return groups
+}
+
+// answerWords normalizes an answer into a set of lowercase words, ignoring
+// punctuation
+func answerWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+// wordSimilarity returns the Jaccard similarity of two word sets
+func wordSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
 // GenerateFollowUp generates a follow-up question based on the answer
 func (e *Engine) GenerateFollowUp(question Question, answer Answer) (string, error) {
 	if e.provider == nil {
@@ -310,7 +876,7 @@ Answer: %s
 
 Follow-up question:`, question.Text, answer.Text)
 	
-	response, err := e.provider.Call(e.model, prompt)
+	response, err := e.callProvider(prompt)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate follow-up: %w", err)
 	}
@@ -346,7 +912,7 @@ SUGGESTIONS: If incomplete, suggest what additional information would be helpful
 
 Analysis:`, question.Text, answer.Text)
 	
-	response, err := e.provider.Call(e.model, prompt)
+	response, err := e.callProvider(prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze answer: %w", err)
 	}
@@ -358,13 +924,42 @@ Analysis:`, question.Text, answer.Text)
 		Suggestions:  []string{},
 	}
 	
-	// In a production system, you'd parse the structured response more carefully
-	// For now, we'll just store the raw analysis
 	analysis.RawAnalysis = response.Content
+	for _, line := range strings.Split(response.Content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "KEY_POINTS:"):
+			if points := splitAnalysisList(strings.TrimPrefix(line, "KEY_POINTS:")); len(points) > 0 {
+				analysis.KeyPoints = points
+			}
+		case strings.HasPrefix(line, "COMPLETENESS:"):
+			rating := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "COMPLETENESS:")))
+			rating = strings.Trim(rating, `"'.`)
+			switch rating {
+			case "complete", "partial", "incomplete":
+				analysis.Completeness = rating
+			}
+		case strings.HasPrefix(line, "SUGGESTIONS:"):
+			analysis.Suggestions = splitAnalysisList(strings.TrimPrefix(line, "SUGGESTIONS:"))
+		}
+	}
 	
 	return analysis, nil
 }
 
+// splitAnalysisList splits a comma-separated analysis field, dropping "none"
+func splitAnalysisList(field string) []string {
+	items := []string{}
+	for _, item := range strings.Split(field, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || strings.EqualFold(item, "none") {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 // AnswerAnalysis contains analysis of a user's answer
 type AnswerAnalysis struct {
 	KeyPoints    []string
@@ -373,6 +968,184 @@ type AnswerAnalysis struct {
 	RawAnalysis  string
 }
 
+// maxClarifications caps the number of clarifying questions asked per answer
+const maxClarifications = 3
+
+// cannedClarifications are category-based clarifying questions used when no
+// provider is available
+var cannedClarifications = map[string][]string{
+	"problem_statement": {
+		"Who experiences this problem today, and how often?",
+		"How is the problem currently being solved or worked around?",
+		"What happens if the problem is not solved?",
+	},
+	"target_users": {
+		"What is the primary user's role or technical skill level?",
+		"Roughly how many users do you expect at launch?",
+		"Are there secondary user groups with different needs?",
+	},
+	"success_metrics": {
+		"Which metric matters most in the first three months?",
+		"What target value would count as success for that metric?",
+		"How will the metric be measured or collected?",
+	},
+	"value_proposition": {
+		"What makes this better than existing alternatives?",
+		"Which single feature delivers most of the value?",
+	},
+	"language": {
+		"Is the language choice fixed, or open to recommendation?",
+		"Are there frameworks or libraries the team already knows?",
+	},
+	"performance": {
+		"What response time is acceptable for the most common operation?",
+		"Are there throughput or latency requirements under peak load?",
+	},
+	"scale": {
+		"How many concurrent users do you expect at peak?",
+		"How much data will be stored in the first year?",
+		"How quickly do you expect usage to grow?",
+	},
+	"compliance": {
+		"Which regulations apply, and in which regions?",
+		"Is there sensitive data such as health or payment information?",
+	},
+	"external_apis": {
+		"Which specific services or APIs are required for launch?",
+		"Are there rate limits or costs associated with those APIs?",
+		"What should happen when an external API is unavailable?",
+	},
+	"database": {
+		"Is the data mostly relational, document-shaped, or time-series?",
+		"Are there existing databases this must work with?",
+	},
+	"authentication": {
+		"Do users sign in with passwords, SSO, or a third-party provider?",
+		"Are different roles or permission levels needed?",
+	},
+	"existing_code": {
+		"What language and framework is the existing codebase written in?",
+		"Which parts of the existing code must remain unchanged?",
+	},
+	"mvp_features": {
+		"Which features are absolutely required for the first release?",
+		"Which features can wait until after launch?",
+		"What is the single most important user workflow?",
+	},
+	"timeline": {
+		"Is there a hard deadline, and what drives it?",
+		"Are there intermediate milestones or demos planned?",
+	},
+	"resources": {
+		"How many people will work on this, and in which roles?",
+		"Is there a budget limit for infrastructure or API usage?",
+	},
+	"prioritization": {
+		"When two features conflict, which criteria decide between them?",
+		"Who makes the final call on priorities?",
+	},
+	"validation": {
+		"Which parts of the summary need correction?",
+		"Is anything important missing from the summary?",
+	},
+}
+
+// defaultClarifications are used for categories without canned questions
+var defaultClarifications = []string{
+	"Could you give a concrete example?",
+	"Are there any constraints or edge cases we should know about?",
+	"What would an ideal outcome look like?",
+}
+
+// vagueAnswerMarkers are phrases that suggest an answer lacks detail
+var vagueAnswerMarkers = []string{
+	"not sure", "don't know", "dont know", "idk", "tbd", "maybe", "whatever", "n/a", "no idea",
+}
+
+// GenerateClarifications returns up to three targeted clarifying questions
+// when AnalyzeAnswer rates the answer incomplete or partial. Without a
+// provider the answer is judged heuristically and the question's
+// category-based canned clarifications are returned.
+func (e *Engine) GenerateClarifications(question Question, answer Answer) ([]string, error) {
+	analysis, err := e.AnalyzeAnswer(question, answer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate clarifications: %w", err)
+	}
+
+	completeness := analysis.Completeness
+	if completeness == "unknown" {
+		completeness = heuristicCompleteness(answer.Text)
+	}
+	if completeness == "complete" {
+		return []string{}, nil
+	}
+
+	if e.provider == nil {
+		return cannedClarificationsFor(question.Category), nil
+	}
+
+	prompt := fmt.Sprintf(`You are conducting a technical interview to gather project requirements. The answer below is missing important detail. Generate up to %d brief, specific clarifying questions that would fill the gaps.
+
+Question: %s
+Answer: %s
+Missing information: %s
+
+List one question per line with no numbering or extra text.`, maxClarifications, question.Text, answer.Text, strings.Join(analysis.Suggestions, ", "))
+
+	response, err := e.callProvider(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate clarifications: %w", err)
+	}
+
+	clarifications := []string{}
+	for _, line := range strings.Split(response.Content, "\n") {
+		line = trimListMarker(line)
+		if !strings.HasSuffix(line, "?") {
+			continue
+		}
+		clarifications = append(clarifications, line)
+		if len(clarifications) == maxClarifications {
+			break
+		}
+	}
+
+	if len(clarifications) == 0 {
+		return cannedClarificationsFor(question.Category), nil
+	}
+	return clarifications, nil
+}
+
+// heuristicCompleteness rates an answer without an LLM based on its length
+// and whether it contains vague phrasing
+func heuristicCompleteness(text string) string {
+	normalized := strings.ToLower(strings.TrimSpace(text))
+	if normalized == "" {
+		return "incomplete"
+	}
+	for _, marker := range vagueAnswerMarkers {
+		if strings.Contains(normalized, marker) {
+			return "incomplete"
+		}
+	}
+	if len(strings.Fields(normalized)) < 5 {
+		return "incomplete"
+	}
+	return "complete"
+}
+
+// cannedClarificationsFor returns a copy of the canned clarifications for a
+// category, capped at maxClarifications
+func cannedClarificationsFor(category string) []string {
+	canned, ok := cannedClarifications[category]
+	if !ok {
+		canned = defaultClarifications
+	}
+	if len(canned) > maxClarifications {
+		canned = canned[:maxClarifications]
+	}
+	return append([]string{}, canned...)
+}
+
 // GenerateSummary generates a summary of all answers
 func (e *Engine) GenerateSummary(session *InterviewSession) (string, error) {
 	var sb strings.Builder
@@ -389,6 +1162,14 @@ func (e *Engine) GenerateSummary(session *InterviewSession) (string, error) {
 		return "In Progress"
 	}())
 
+	// Repeated answers point back to the first question that gave them
+	duplicateOf := make(map[string]string)
+	for canonicalID, duplicates := range e.DedupeAnswers(session) {
+		for _, id := range duplicates {
+			duplicateOf[id] = canonicalID
+		}
+	}
+
 	phases := e.GetAllPhases()
 	for _, phase := range phases {
 		questions := e.GetPhaseQuestions(phase)
@@ -399,11 +1180,30 @@ func (e *Engine) GenerateSummary(session *InterviewSession) (string, error) {
 			if answer, ok := session.Answers[q.ID]; ok {
 				hasAnswers = true
 				fmt.Fprintf(&sb, "**Q: %s**\n", q.Text)
-				fmt.Fprintf(&sb, "A: %s\n\n", answer.Text)
+				if canonicalID, ok := duplicateOf[q.ID]; ok {
+					fmt.Fprintf(&sb, "A: *(see %s)*\n\n", canonicalID)
+				} else if answer.Confidence == ConfidenceLow {
+					fmt.Fprintf(&sb, "A: %s *(low confidence)*\n\n", answer.Text)
+				} else {
+					fmt.Fprintf(&sb, "A: %s\n\n", answer.Text)
+				}
 
-				// Include follow-up answers if any
-				if followUps, ok := session.FollowUpAnswers[q.ID]; ok && len(followUps) > 0 {
-					sb.WriteString("  *Follow-up responses:*\n")
+				// Include cited references if any
+				if len(answer.Attachments) > 0 {
+					sb.WriteString("  *References:*\n")
+					for _, att := range answer.Attachments {
+						if att.Description != "" {
+							fmt.Fprintf(&sb, "  - %s: %s\n", att.Description, att.URI)
+						} else {
+							fmt.Fprintf(&sb, "  - %s\n", att.URI)
+						}
+					}
+					sb.WriteString("\n")
+				}
+
+				// Include follow-up answers if any
+				if followUps, ok := session.FollowUpAnswers[q.ID]; ok && len(followUps) > 0 {
+					sb.WriteString("  *Follow-up responses:*\n")
 					for i, fu := range followUps {
 						fmt.Fprintf(&sb, "  %d. %s\n", i+1, fu.Text)
 					}
@@ -428,6 +1228,26 @@ func (e *Engine) GenerateSummary(session *InterviewSession) (string, error) {
 		}
 	}
 
+	// Low-confidence answers need confirming before they become decisions
+	if unknowns := e.GetUnknowns(session); len(unknowns) > 0 {
+		sb.WriteString("## Unknowns\n\n")
+		for _, unknown := range unknowns {
+			fmt.Fprintf(&sb, "- %s\n", unknown)
+		}
+		sb.WriteString("\n")
+	}
+
+	// Answers that rejected a proposed default mark where the project is
+	// unconventional
+	if divergent := e.DivergentDefaults(session); len(divergent) > 0 {
+		sb.WriteString("## Unconventional Choices\n\n")
+		for _, qid := range divergent {
+			fmt.Fprintf(&sb, "- %s: chose \"%s\" over the usual \"%s\"\n",
+				qid, session.Answers[qid].Text, session.ProposedDefaults[qid])
+		}
+		sb.WriteString("\n")
+	}
+
 	// Add statistics
 	sb.WriteString("## Statistics\n\n")
 	fmt.Fprintf(&sb, "- Total questions answered: %d\n", len(session.Answers))
@@ -442,6 +1262,123 @@ func (e *Engine) GenerateSummary(session *InterviewSession) (string, error) {
 	return sb.String(), nil
 }
 
+// ThemeAnswers clusters answered questions into themes such as "Security"
+// or "Scale", returning theme name to question IDs in interview order. The
+// LLM picks the themes; without a provider the interview phases are used.
+func (e *Engine) ThemeAnswers(session *InterviewSession) (map[string][]string, error) {
+	var answered []Question
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			if _, ok := session.Answers[q.ID]; ok {
+				answered = append(answered, q)
+			}
+		}
+	}
+
+	if e.provider == nil || len(answered) == 0 {
+		return e.themeAnswersByPhase(session), nil
+	}
+
+	var sb strings.Builder
+	for _, q := range answered {
+		fmt.Fprintf(&sb, "%s: %s -> %s\n", q.ID, q.Text, session.Answers[q.ID].Text)
+	}
+
+	prompt := fmt.Sprintf(`Group the following interview answers into a few themes (for example Security, Scale, UX, Data). Every answer ID must appear in exactly one theme.
+
+%s
+Respond with one theme per line in the format:
+Theme Name: id1, id2, id3`, sb.String())
+
+	response, err := e.callProvider(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to theme answers: %w", err)
+	}
+
+	themes := make(map[string][]string)
+	assigned := make(map[string]bool)
+	for _, line := range strings.Split(response.Content, "\n") {
+		name, ids, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(name), "-*#"))
+		if name == "" {
+			continue
+		}
+		for _, id := range strings.Split(ids, ",") {
+			id = strings.TrimSpace(id)
+			if _, answeredID := session.Answers[id]; !answeredID || assigned[id] {
+				continue
+			}
+			themes[name] = append(themes[name], id)
+			assigned[id] = true
+		}
+	}
+
+	if len(themes) == 0 {
+		return e.themeAnswersByPhase(session), nil
+	}
+
+	// Keep anything the LLM missed so no answer drops out of the summary
+	for _, q := range answered {
+		if !assigned[q.ID] {
+			themes["Other"] = append(themes["Other"], q.ID)
+		}
+	}
+
+	return themes, nil
+}
+
+// themeAnswersByPhase groups answered questions under their phase names
+func (e *Engine) themeAnswersByPhase(session *InterviewSession) map[string][]string {
+	themes := make(map[string][]string)
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			if _, ok := session.Answers[q.ID]; ok {
+				name := formatPhaseName(phase)
+				themes[name] = append(themes[name], q.ID)
+			}
+		}
+	}
+	return themes
+}
+
+// GenerateThematicSummary summarizes answers grouped by theme rather than
+// by interview phase
+func (e *Engine) GenerateThematicSummary(session *InterviewSession) (string, error) {
+	themes, err := e.ThemeAnswers(session)
+	if err != nil {
+		return "", err
+	}
+
+	questions := make(map[string]Question)
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			questions[q.ID] = q
+		}
+	}
+
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("# Interview Summary by Theme\n\n")
+	fmt.Fprintf(&sb, "**Project ID:** %s\n\n", session.ProjectID)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "## %s\n\n", name)
+		for _, id := range themes[name] {
+			fmt.Fprintf(&sb, "**Q: %s**\n", questions[id].Text)
+			fmt.Fprintf(&sb, "A: %s\n\n", session.Answers[id].Text)
+		}
+	}
+
+	return sb.String(), nil
+}
+
 // formatPhaseName converts a phase constant to a readable name
 func formatPhaseName(phase Phase) string {
 	switch phase {
@@ -480,8 +1417,68 @@ func (e *Engine) ValidateCompleteness(session *InterviewSession) (bool, []string
 	return len(missingQuestions) == 0, missingQuestions
 }
 
+// CompleteInterview marks a fully answered session as completed, saves the
+// final interview data and advances the project from the interview stage to
+// design. It refuses to complete while required questions are unanswered.
+func (e *Engine) CompleteInterview(session *InterviewSession) error {
+	if e.store == nil {
+		return fmt.Errorf("store is required to complete an interview")
+	}
+
+	if complete, missing := e.ValidateCompleteness(session); !complete {
+		return fmt.Errorf("interview is incomplete, missing required answers: %s", strings.Join(missing, "; "))
+	}
+
+	session.Completed = true
+	session.LastUpdatedAt = time.Now()
+	if err := e.SaveSession(session); err != nil {
+		session.Completed = false
+		return fmt.Errorf("failed to save interview: %w", err)
+	}
+
+	project, err := e.store.GetProject(session.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	// Don't pull a project that has already moved past design back to it
+	if project.CurrentStage == state.StageInit || project.CurrentStage == state.StageInterview {
+		if err := e.store.UpdateProjectStage(session.ProjectID, state.StageDesign); err != nil {
+			return fmt.Errorf("failed to advance project stage: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // ExportToJSON exports the interview data to JSON format
 func (e *Engine) ExportToJSON(session *InterviewSession) (string, error) {
+	data := e.buildExportData(session)
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	
+	return string(jsonData), nil
+}
+
+// ExportToYAML exports the interview data to YAML format, using the same
+// structure as ExportToJSON
+func (e *Engine) ExportToYAML(session *InterviewSession) (string, error) {
+	data := e.buildExportData(session)
+
+	yamlData, err := yaml.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	return string(yamlData), nil
+}
+
+// buildExportData assembles the export structure shared by the JSON and
+// YAML exporters
+func (e *Engine) buildExportData(session *InterviewSession) map[string]interface{} {
 	// Validate completeness first
 	isComplete, missingQuestions := e.ValidateCompleteness(session)
 	
@@ -522,10 +1519,25 @@ func (e *Engine) ExportToJSON(session *InterviewSession) (string, error) {
 		for _, q := range questions {
 			if answer, ok := session.Answers[q.ID]; ok {
 				answerData := map[string]interface{}{
+					"question_id": q.ID,
 					"question": q.Text,
 					"answer":   answer.Text,
 					"timestamp": answer.Timestamp,
 				}
+				if answer.Confidence != "" {
+					answerData["confidence"] = answer.Confidence
+				}
+				if len(answer.Attachments) > 0 {
+					attachments := make([]map[string]interface{}, len(answer.Attachments))
+					for i, att := range answer.Attachments {
+						attachments[i] = map[string]interface{}{
+							"type":        att.Type,
+							"uri":         att.URI,
+							"description": att.Description,
+						}
+					}
+					answerData["attachments"] = attachments
+				}
 				
 				// Include follow-ups if any
 				if followUps, ok := session.FollowUpAnswers[q.ID]; ok && len(followUps) > 0 {
@@ -551,6 +1563,10 @@ func (e *Engine) ExportToJSON(session *InterviewSession) (string, error) {
 					answerData["revisions"] = iterData
 				}
 				
+				if subs := e.exportSubInterviews(session, q.ID); len(subs) > 0 {
+					answerData["sub_interviews"] = subs
+				}
+				
 				phaseData[q.Category] = answerData
 			}
 		}
@@ -561,21 +1577,332 @@ func (e *Engine) ExportToJSON(session *InterviewSession) (string, error) {
 	}
 	
 	data["phases"] = phaseAnswers
+
+	if unknowns := e.GetUnknowns(session); len(unknowns) > 0 {
+		data["unknowns"] = unknowns
+	}
 	
 	// Add metadata
 	data["metadata"] = map[string]interface{}{
 		"total_questions_answered": len(session.Answers),
 		"total_revisions":          len(session.Iterations),
 		"current_phase":            string(session.CurrentPhase),
+		"current_question":         session.CurrentQuestion,
+		"completed":                session.Completed,
 		"paused":                   session.Paused,
 	}
 	
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+	return data
+}
+
+// exportSubInterviews lists the sub-interviews launched from a question, with
+// their answers in question order
+func (e *Engine) exportSubInterviews(session *InterviewSession, questionID string) []map[string]interface{} {
+	var subs []map[string]interface{}
+	for _, sub := range session.SubInterviews {
+		if sub.ParentQuestionID != questionID {
+			continue
+		}
+		var answers []map[string]interface{}
+		for _, q := range sub.Questions {
+			if answer, ok := sub.Answers[q.ID]; ok {
+				answers = append(answers, map[string]interface{}{
+					"question_id": q.ID,
+					"question":    q.Text,
+					"answer":      answer.Text,
+				})
+			}
+		}
+		subs = append(subs, map[string]interface{}{
+			"topic":     sub.Topic,
+			"completed": sub.Completed,
+			"answers":   answers,
+		})
+	}
+	return subs
+}
+
+// exportedAnswer mirrors an answer entry written by buildExportData
+type exportedAnswer struct {
+	QuestionID  string    `yaml:"question_id"`
+	Question    string    `yaml:"question"`
+	Answer      string    `yaml:"answer"`
+	Timestamp   time.Time `yaml:"timestamp"`
+	Confidence  string    `yaml:"confidence"`
+	FollowUps   []string  `yaml:"follow_ups"`
+	Attachments []struct {
+		Type        string `yaml:"type"`
+		URI         string `yaml:"uri"`
+		Description string `yaml:"description"`
+	} `yaml:"attachments"`
+	Revisions []struct {
+		OldAnswer string    `yaml:"old_answer"`
+		NewAnswer string    `yaml:"new_answer"`
+		Reason    string    `yaml:"reason"`
+		Timestamp time.Time `yaml:"timestamp"`
+	} `yaml:"revisions"`
+	SubInterviews []struct {
+		Topic   string `yaml:"topic"`
+		Answers []struct {
+			QuestionID string `yaml:"question_id"`
+			Question   string `yaml:"question"`
+			Answer     string `yaml:"answer"`
+		} `yaml:"answers"`
+	} `yaml:"sub_interviews"`
+}
+
+// exportedInterview mirrors the parts of the export needed to rebuild a session
+type exportedInterview struct {
+	ProjectID string                               `yaml:"project_id"`
+	StartedAt time.Time                            `yaml:"started_at"`
+	Phases    map[string]map[string]exportedAnswer `yaml:"phases"`
+	Metadata  struct {
+		CurrentPhase    string `yaml:"current_phase"`
+		CurrentQuestion int    `yaml:"current_question"`
+		Completed       bool   `yaml:"completed"`
+		Paused          bool   `yaml:"paused"`
+	} `yaml:"metadata"`
+}
+
+// ImportFromYAML rebuilds an interview session from ExportToYAML output,
+// typically after the user has hand-edited the answers
+func (e *Engine) ImportFromYAML(yamlData string) (*InterviewSession, error) {
+	var exported exportedInterview
+	if err := yaml.Unmarshal([]byte(yamlData), &exported); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if exported.ProjectID == "" {
+		return nil, fmt.Errorf("project_id is required")
+	}
+
+	session, err := e.StartInterview(exported.ProjectID)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+		return nil, err
 	}
-	
-	return string(jsonData), nil
+	if !exported.StartedAt.IsZero() {
+		session.StartedAt = exported.StartedAt
+	}
+	if exported.Metadata.CurrentPhase != "" {
+		session.CurrentPhase = Phase(exported.Metadata.CurrentPhase)
+	}
+	session.CurrentQuestion = exported.Metadata.CurrentQuestion
+	session.Completed = exported.Metadata.Completed
+	session.Paused = exported.Metadata.Paused
+
+	// Walk phases in interview order and categories by name so sub-interviews
+	// and answer order come out the same on every import
+	phaseRank := make(map[string]int)
+	for i, phase := range e.GetAllPhases() {
+		phaseRank[string(phase)] = i + 1
+	}
+	phaseNames := make([]string, 0, len(exported.Phases))
+	for phaseName := range exported.Phases {
+		phaseNames = append(phaseNames, phaseName)
+	}
+	sort.Slice(phaseNames, func(i, j int) bool {
+		ri, rj := phaseRank[phaseNames[i]], phaseRank[phaseNames[j]]
+		if ri == 0 {
+			ri = len(phaseRank) + 1
+		}
+		if rj == 0 {
+			rj = len(phaseRank) + 1
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return phaseNames[i] < phaseNames[j]
+	})
+
+	for _, phaseName := range phaseNames {
+		answers := exported.Phases[phaseName]
+		categories := make([]string, 0, len(answers))
+		for category := range answers {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+
+		for _, category := range categories {
+			exportedAns := answers[category]
+			questionID := exportedAns.QuestionID
+			if questionID == "" {
+				// Hand-written entries may omit the ID, so fall back to the category
+				for _, q := range e.GetPhaseQuestions(Phase(phaseName)) {
+					if q.Category == category {
+						questionID = q.ID
+						break
+					}
+				}
+			}
+			if questionID == "" {
+				return nil, fmt.Errorf("unknown question %s in phase %s", category, phaseName)
+			}
+			if strings.TrimSpace(exportedAns.Answer) == "" {
+				// Blank entries, e.g. from ExportQuestionnaire, are unanswered
+				continue
+			}
+			if err := e.checkAnswerLength(exportedAns.Answer); err != nil {
+				return nil, fmt.Errorf("answer to %s: %w", questionID, err)
+			}
+
+			answer := Answer{
+				QuestionID: questionID,
+				Text:       exportedAns.Answer,
+				Timestamp:  exportedAns.Timestamp,
+				Confidence: exportedAns.Confidence,
+			}
+			for _, att := range exportedAns.Attachments {
+				answer.Attachments = append(answer.Attachments, Attachment{
+					Type:        att.Type,
+					URI:         att.URI,
+					Description: att.Description,
+				})
+			}
+			session.Answers[questionID] = answer
+			session.AnswerOrder = append(session.AnswerOrder, questionID)
+
+			for _, followUp := range exportedAns.FollowUps {
+				session.FollowUpAnswers[questionID] = append(session.FollowUpAnswers[questionID], Answer{
+					QuestionID: questionID + "_followup",
+					Text:       followUp,
+					Timestamp:  exportedAns.Timestamp,
+				})
+			}
+
+			for _, revision := range exportedAns.Revisions {
+				session.Iterations = append(session.Iterations, Iteration{
+					Timestamp:  revision.Timestamp,
+					QuestionID: questionID,
+					OldAnswer:  revision.OldAnswer,
+					NewAnswer:  revision.NewAnswer,
+					Reason:     revision.Reason,
+				})
+			}
+
+			// Only answered sub-interview questions are exported, so imported
+			// sub-interviews are closed
+			for _, exportedSub := range exportedAns.SubInterviews {
+				sub := &SubInterview{
+					ParentQuestionID: questionID,
+					Topic:            exportedSub.Topic,
+					Answers:          make(map[string]Answer),
+					Completed:        true,
+				}
+				for _, subAns := range exportedSub.Answers {
+					sub.Questions = append(sub.Questions, Question{
+						ID:       subAns.QuestionID,
+						Phase:    Phase(phaseName),
+						Text:     subAns.Question,
+						Category: exportedSub.Topic,
+					})
+					sub.Answers[subAns.QuestionID] = Answer{
+						QuestionID: subAns.QuestionID,
+						Text:       subAns.Answer,
+						Timestamp:  exportedAns.Timestamp,
+					}
+				}
+				session.SubInterviews = append(session.SubInterviews, sub)
+			}
+		}
+	}
+
+	// Map iteration is unordered, so restore revision history chronologically
+	sort.SliceStable(session.Iterations, func(i, j int) bool {
+		return session.Iterations[i].Timestamp.Before(session.Iterations[j].Timestamp)
+	})
+
+	// Rebuild the undo order from when each answer was given
+	sort.SliceStable(session.AnswerOrder, func(i, j int) bool {
+		return session.Answers[session.AnswerOrder[i]].Timestamp.Before(session.Answers[session.AnswerOrder[j]].Timestamp)
+	})
+
+	return session, nil
+}
+
+// questionnaireQuestion is a question as written by ExportQuestionnaire
+type questionnaireQuestion struct {
+	ID       string `json:"id" yaml:"question_id"`
+	Text     string `json:"text" yaml:"question"`
+	Category string `json:"category" yaml:"-"`
+	Required bool   `json:"required" yaml:"required"`
+	Answer   string `json:"-" yaml:"answer"`
+}
+
+// questionnairePhase groups the questions of one phase for ExportQuestionnaire
+type questionnairePhase struct {
+	Phase     string                  `json:"phase"`
+	Name      string                  `json:"name"`
+	Questions []questionnaireQuestion `json:"questions"`
+}
+
+// ExportQuestionnaire writes every phase and question, without answers, so
+// the interview can be filled in offline or shared with stakeholders.
+// Supported formats are "markdown", "json" and "yaml"; the YAML form uses the
+// ExportToYAML layout and can be re-imported with ImportFromYAML once a
+// project_id and answers are filled in.
+func (e *Engine) ExportQuestionnaire(format string) ([]byte, error) {
+	var phases []questionnairePhase
+	for _, phase := range e.GetAllPhases() {
+		qp := questionnairePhase{Phase: string(phase), Name: formatPhaseName(phase)}
+		for _, q := range e.GetPhaseQuestions(phase) {
+			qp.Questions = append(qp.Questions, questionnaireQuestion{
+				ID:       q.ID,
+				Text:     q.Text,
+				Category: q.Category,
+				Required: q.Required,
+			})
+		}
+		phases = append(phases, qp)
+	}
+
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return []byte(formatQuestionnaireMarkdown(phases)), nil
+	case "json":
+		data, err := json.MarshalIndent(map[string]interface{}{"phases": phases}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return data, nil
+	case "yaml", "yml":
+		byPhase := make(map[string]map[string]questionnaireQuestion)
+		for _, qp := range phases {
+			byPhase[qp.Phase] = make(map[string]questionnaireQuestion)
+			for _, q := range qp.Questions {
+				byPhase[qp.Phase][q.Category] = q
+			}
+		}
+		data, err := yaml.Marshal(map[string]interface{}{
+			"project_id": "",
+			"phases":     byPhase,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported questionnaire format: %s", format)
+	}
+}
+
+// formatQuestionnaireMarkdown renders the questionnaire with a blank answer
+// line under each question
+func formatQuestionnaireMarkdown(phases []questionnairePhase) string {
+	var sb strings.Builder
+	sb.WriteString("# Project Interview Questionnaire\n\n")
+	for _, qp := range phases {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", qp.Name))
+		for _, q := range qp.Questions {
+			requirement := "optional"
+			if q.Required {
+				requirement = "required"
+			}
+			sb.WriteString(fmt.Sprintf("### %s (%s)\n\n", q.Text, requirement))
+			sb.WriteString(fmt.Sprintf("- **ID:** %s\n", q.ID))
+			sb.WriteString(fmt.Sprintf("- **Category:** %s\n\n", q.Category))
+			sb.WriteString("**Answer:**\n\n")
+		}
+	}
+	return sb.String()
 }
 
 // extractStructuredData extracts key structured data from answers
@@ -673,6 +2000,13 @@ func (e *Engine) SaveSession(session *InterviewSession) error {
 		"completed":         session.Completed,
 		"paused":            session.Paused,
 		"iterations":        session.Iterations,
+		"phase_summaries":   session.PhaseSummaries,
+		"answer_order":      session.AnswerOrder,
+		"sub_interviews":    session.SubInterviews,
+		"glossary":          session.Glossary,
+		"elevator_pitch":    session.ElevatorPitch,
+		"previous_sessions": session.PreviousSessions,
+		"proposed_defaults": session.ProposedDefaults,
 	}
 	
 	sessionJSON, err := json.Marshal(sessionData)
@@ -688,10 +2022,14 @@ func (e *Engine) SaveSession(session *InterviewSession) error {
 
 	// Convert session to InterviewData format for storage
 	interviewData := &state.InterviewData{
-		ProjectID:   session.ProjectID,
-		ProjectName: projectName,
-		CreatedAt:   session.StartedAt,
-		RawSession:  string(sessionJSON), // Store the full session as JSON
+		ProjectID:     session.ProjectID,
+		ProjectName:   projectName,
+		CreatedAt:     session.StartedAt,
+		Unknowns:      e.GetUnknowns(session),
+		References:    e.GetReferences(session),
+		Glossary:      session.Glossary,
+		ElevatorPitch: session.ElevatorPitch,
+		RawSession:    string(sessionJSON), // Store the full session as JSON
 	}
 	
 	// Extract key data from answers for easy access
@@ -712,62 +2050,24 @@ func (e *Engine) SaveSession(session *InterviewSession) error {
 	return e.store.SaveInterviewData(session.ProjectID, interviewData)
 }
 
-// LoadSession loads an interview session from the state store
+// LoadSession loads an interview session from the state store. A raw
+// session that can't be decoded, whether truncated or from an older format,
+// doesn't fail the load: the session is rebuilt from the basic interview
+// data instead and LoadWarning says why.
 func (e *Engine) LoadSession(projectID string) (*InterviewSession, error) {
+	e.projectID = projectID
 	data, err := e.store.GetInterviewData(projectID)
 	if err != nil {
 		return nil, err
 	}
 	
-	// If we have raw session data, deserialize it
+	var warning string
 	if data.RawSession != "" {
-		var sessionData map[string]interface{}
-		if err := json.Unmarshal([]byte(data.RawSession), &sessionData); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal session: %w", err)
-		}
-		
-		session := &InterviewSession{
-			ProjectID:       projectID,
-			CurrentPhase:    Phase(sessionData["current_phase"].(string)),
-			CurrentQuestion: int(sessionData["current_question"].(float64)),
-			Answers:         make(map[string]Answer),
-			FollowUpAnswers: make(map[string][]Answer),
-			StartedAt:       data.CreatedAt,
-			LastUpdatedAt:   time.Now(),
-			Completed:       sessionData["completed"].(bool),
-			Paused:          sessionData["paused"].(bool),
-			Iterations:      []Iteration{},
-		}
-		
-		// Reconstruct answers
-		if answersData, ok := sessionData["answers"].(map[string]interface{}); ok {
-			for qid, answerData := range answersData {
-				if answerMap, ok := answerData.(map[string]interface{}); ok {
-					session.Answers[qid] = Answer{
-						QuestionID: answerMap["QuestionID"].(string),
-						Text:       answerMap["Text"].(string),
-						Timestamp:  time.Now(), // Simplified
-					}
-				}
-			}
+		session, err := decodeRawSession(projectID, data)
+		if err == nil {
+			return session, nil
 		}
-		
-		// Reconstruct iterations
-		if iterationsData, ok := sessionData["iterations"].([]interface{}); ok {
-			for _, iterData := range iterationsData {
-				if iterMap, ok := iterData.(map[string]interface{}); ok {
-					session.Iterations = append(session.Iterations, Iteration{
-						Timestamp:  time.Now(), // Simplified
-						QuestionID: iterMap["QuestionID"].(string),
-						OldAnswer:  iterMap["OldAnswer"].(string),
-						NewAnswer:  iterMap["NewAnswer"].(string),
-						Reason:     iterMap["Reason"].(string),
-					})
-				}
-			}
-		}
-		
-		return session, nil
+		warning = fmt.Sprintf("saved session could not be restored, rebuilt from basic data: %v", err)
 	}
 	
 	// Fallback: Create session from basic data
@@ -782,6 +2082,8 @@ func (e *Engine) LoadSession(projectID string) (*InterviewSession, error) {
 		Completed:       false,
 		Paused:          false,
 		Iterations:      []Iteration{},
+		PhaseSummaries:  make(map[Phase]string),
+		LoadWarning:     warning,
 	}
 	
 	// Reconstruct basic answers from data
@@ -792,6 +2094,200 @@ func (e *Engine) LoadSession(projectID string) (*InterviewSession, error) {
 			Timestamp:  data.CreatedAt,
 		}
 	}
+	if len(data.TargetUsers) > 0 {
+		session.Answers["pe_2"] = Answer{
+			QuestionID: "pe_2",
+			Text:       strings.Join(data.TargetUsers, ", "),
+			Timestamp:  data.CreatedAt,
+		}
+	}
+	if len(data.SuccessMetrics) > 0 {
+		session.Answers["pe_3"] = Answer{
+			QuestionID: "pe_3",
+			Text:       strings.Join(data.SuccessMetrics, ", "),
+			Timestamp:  data.CreatedAt,
+		}
+	}
+	
+	return session, nil
+}
+
+// sessionValue reads a field of a decoded raw session. A missing or null
+// field gives the zero value, so sessions saved before the field existed
+// still load; a field of the wrong type is an error.
+func sessionValue[T any](fields map[string]interface{}, key string) (T, error) {
+	var zero T
+	raw, ok := fields[key]
+	if !ok || raw == nil {
+		return zero, nil
+	}
+	value, ok := raw.(T)
+	if !ok {
+		return zero, fmt.Errorf("session field %q has unexpected type %T", key, raw)
+	}
+	return value, nil
+}
+
+// decodeRawSession rebuilds a session from the JSON written by SaveSession
+func decodeRawSession(projectID string, data *state.InterviewData) (*InterviewSession, error) {
+	var sessionData map[string]interface{}
+	if err := json.Unmarshal([]byte(data.RawSession), &sessionData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	
+	currentPhase, err := sessionValue[string](sessionData, "current_phase")
+	if err != nil {
+		return nil, err
+	}
+	if currentPhase == "" {
+		return nil, fmt.Errorf("session has no current phase")
+	}
+	currentQuestion, err := sessionValue[float64](sessionData, "current_question")
+	if err != nil {
+		return nil, err
+	}
+	if currentQuestion < 0 {
+		return nil, fmt.Errorf("session has invalid current question %v", currentQuestion)
+	}
+	completed, err := sessionValue[bool](sessionData, "completed")
+	if err != nil {
+		return nil, err
+	}
+	paused, err := sessionValue[bool](sessionData, "paused")
+	if err != nil {
+		return nil, err
+	}
+	
+	session := &InterviewSession{
+		ProjectID:       projectID,
+		CurrentPhase:    Phase(currentPhase),
+		CurrentQuestion: int(currentQuestion),
+		Answers:         make(map[string]Answer),
+		FollowUpAnswers: make(map[string][]Answer),
+		StartedAt:       data.CreatedAt,
+		LastUpdatedAt:   time.Now(),
+		Completed:       completed,
+		Paused:          paused,
+		Iterations:      []Iteration{},
+		PhaseSummaries:  make(map[Phase]string),
+	}
+	
+	// Reconstruct answer order
+	if orderData, ok := sessionData["answer_order"].([]interface{}); ok {
+		for _, id := range orderData {
+			if questionID, ok := id.(string); ok {
+				session.AnswerOrder = append(session.AnswerOrder, questionID)
+			}
+		}
+	}
+	
+	// Reconstruct sub-interviews, which round-trip through their struct form
+	if subData, ok := sessionData["sub_interviews"]; ok && subData != nil {
+		subJSON, err := json.Marshal(subData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal sub-interviews: %w", err)
+		}
+		if err := json.Unmarshal(subJSON, &session.SubInterviews); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal sub-interviews: %w", err)
+		}
+	}
+	
+	// Reconstruct archived sessions, which round-trip like sub-interviews
+	if previousData, ok := sessionData["previous_sessions"]; ok && previousData != nil {
+		previousJSON, err := json.Marshal(previousData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal previous sessions: %w", err)
+		}
+		if err := json.Unmarshal(previousJSON, &session.PreviousSessions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal previous sessions: %w", err)
+		}
+	}
+	
+	// Reconstruct phase summaries
+	if summariesData, ok := sessionData["phase_summaries"].(map[string]interface{}); ok {
+		for phase, summary := range summariesData {
+			if text, ok := summary.(string); ok {
+				session.PhaseSummaries[Phase(phase)] = text
+			}
+		}
+	}
+	
+	// Reconstruct the glossary
+	if glossaryData, ok := sessionData["glossary"].(map[string]interface{}); ok {
+		session.Glossary = make(map[string]string, len(glossaryData))
+		for term, definition := range glossaryData {
+			text, _ := definition.(string)
+			session.Glossary[term] = text
+		}
+	}
+	
+	// Reconstruct proposed defaults
+	if proposedData, ok := sessionData["proposed_defaults"].(map[string]interface{}); ok {
+		session.ProposedDefaults = make(map[string]string, len(proposedData))
+		for qid, proposal := range proposedData {
+			text, _ := proposal.(string)
+			session.ProposedDefaults[qid] = text
+		}
+	}
+	
+	if pitch, ok := sessionData["elevator_pitch"].(string); ok {
+		session.ElevatorPitch = pitch
+	}
+	
+	// Reconstruct answers
+	if answersData, ok := sessionData["answers"].(map[string]interface{}); ok {
+		for qid, answerData := range answersData {
+			answerMap, ok := answerData.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("answer %q has unexpected type %T", qid, answerData)
+			}
+			text, err := sessionValue[string](answerMap, "Text")
+			if err != nil {
+				return nil, fmt.Errorf("answer %q: %w", qid, err)
+			}
+			confidence, _ := answerMap["Confidence"].(string)
+			answer := Answer{
+				QuestionID: qid,
+				Text:       text,
+				Timestamp:  time.Now(), // Simplified
+				Confidence: confidence,
+			}
+			if attachments, ok := answerMap["Attachments"].([]interface{}); ok {
+				for _, attData := range attachments {
+					if attMap, ok := attData.(map[string]interface{}); ok {
+						attType, _ := attMap["Type"].(string)
+						uri, _ := attMap["URI"].(string)
+						description, _ := attMap["Description"].(string)
+						answer.Attachments = append(answer.Attachments, Attachment{
+							Type:        attType,
+							URI:         uri,
+							Description: description,
+						})
+					}
+				}
+			}
+			session.Answers[qid] = answer
+		}
+	}
+	
+	// Reconstruct iterations
+	if iterationsData, ok := sessionData["iterations"].([]interface{}); ok {
+		for _, iterData := range iterationsData {
+			if iterMap, ok := iterData.(map[string]interface{}); ok {
+				questionID, _ := iterMap["QuestionID"].(string)
+				oldAnswer, _ := iterMap["OldAnswer"].(string)
+				newAnswer, _ := iterMap["NewAnswer"].(string)
+				reason, _ := iterMap["Reason"].(string)
+				session.Iterations = append(session.Iterations, Iteration{
+					Timestamp:  time.Now(), // Simplified
+					QuestionID: questionID,
+					OldAnswer:  oldAnswer,
+					NewAnswer:  newAnswer,
+					Reason:     reason,
+				})
+			}
+		}
+	}
 	
 	return session, nil
 }
@@ -829,7 +2325,7 @@ Category: %s
 
 Proposed default answer:`, question.Text, question.Category)
 	
-	response, err := e.provider.Call(e.model, prompt)
+	response, err := e.callProvider(prompt)
 	if err != nil {
 		return "", fmt.Errorf("failed to propose default: %w", err)
 	}
@@ -1,9 +1,13 @@
 package interview
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -339,6 +343,129 @@ func TestInterviewEngine_SaveLoad(t *testing.T) {
 			t.Errorf("Expected project ID %s, got %s", project.ID, session.ProjectID)
 		}
 	})
+	t.Run("LoadCorruptedSession", func(t *testing.T) {
+		corrupted := map[string]string{
+			"Truncated":   `{"project_id": "test-project", "current_phase": "technical_constr`,
+			"WrongTypes":  `{"current_phase": 3, "current_question": "two", "completed": "yes"}`,
+			"NoPhase":     `{"current_question": 1}`,
+			"BadAnswer":   `{"current_phase": "scope_definition", "current_question": 0, "answers": {"pe_1": "plain text"}}`,
+			"AnswerTypes": `{"current_phase": "scope_definition", "current_question": 0, "answers": {"pe_1": {"Text": 42}}}`,
+		}
+		for name, raw := range corrupted {
+			t.Run(name, func(t *testing.T) {
+				err := store.SaveInterviewData(project.ID, &state.InterviewData{
+					ProjectID:        project.ID,
+					ProjectName:      project.Name,
+					ProblemStatement: "Recovered problem",
+					TargetUsers:      []string{"developers"},
+					CreatedAt:        time.Now(),
+					RawSession:       raw,
+				})
+				if err != nil {
+					t.Fatalf("Failed to save interview data: %v", err)
+				}
+
+				session, err := engine.LoadSession(project.ID)
+				if err != nil {
+					t.Fatalf("Expected degraded session, got error: %v", err)
+				}
+				if session.LoadWarning == "" {
+					t.Error("Expected a load warning")
+				}
+				if session.CurrentPhase != PhaseProjectEssence || session.CurrentQuestion != 0 {
+					t.Errorf("Expected session to restart at the first question, got %s/%d", session.CurrentPhase, session.CurrentQuestion)
+				}
+				if session.Answers["pe_1"].Text != "Recovered problem" {
+					t.Errorf("Expected problem statement to be recovered, got %q", session.Answers["pe_1"].Text)
+				}
+				if session.Answers["pe_2"].Text != "developers" {
+					t.Errorf("Expected target users to be recovered, got %q", session.Answers["pe_2"].Text)
+				}
+				if _, err := engine.GetNextQuestion(session); err != nil {
+					t.Errorf("Expected degraded session to be usable: %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("LoadOlderSessionFormat", func(t *testing.T) {
+		// Sessions saved before completed and paused were stored
+		err := store.SaveInterviewData(project.ID, &state.InterviewData{
+			ProjectID:   project.ID,
+			ProjectName: project.Name,
+			CreatedAt:   time.Now(),
+			RawSession:  `{"current_phase": "integration_points", "current_question": 2, "answers": {"pe_1": {"QuestionID": "pe_1", "Text": "Old answer"}}}`,
+		})
+		if err != nil {
+			t.Fatalf("Failed to save interview data: %v", err)
+		}
+
+		session, err := engine.LoadSession(project.ID)
+		if err != nil {
+			t.Fatalf("Failed to load session: %v", err)
+		}
+		if session.LoadWarning != "" {
+			t.Errorf("Expected no load warning, got %q", session.LoadWarning)
+		}
+		if session.CurrentPhase != PhaseIntegrationPoints || session.CurrentQuestion != 2 {
+			t.Errorf("Expected integration_points/2, got %s/%d", session.CurrentPhase, session.CurrentQuestion)
+		}
+		if session.Answers["pe_1"].Text != "Old answer" {
+			t.Errorf("Expected old answer, got %q", session.Answers["pe_1"].Text)
+		}
+	})
+}
+
+func TestInterviewEngine_SaveLoad_AnswerDetails(t *testing.T) {
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{
+		ID:           "details-project",
+		Name:         "Details Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: state.StageInterview,
+	}
+	store.CreateProject(project)
+
+	engine := NewEngine(store, nil, "")
+	session, _ := engine.StartInterview(project.ID)
+	if err := engine.RecordAnswerWithConfidence(session, "tc_3", "Maybe 1000 users", ConfidenceLow); err != nil {
+		t.Fatalf("Failed to record answer: %v", err)
+	}
+	if err := engine.AttachToAnswer(session, "tc_3", Attachment{URI: "https://example.com/traffic"}); err != nil {
+		t.Fatalf("Failed to attach: %v", err)
+	}
+	if err := engine.ReiterateAnswer(session, "tc_3", "Maybe 2000 users", "Checked the numbers"); err != nil {
+		t.Fatalf("Failed to reiterate answer: %v", err)
+	}
+
+	revised := session.Answers["tc_3"]
+	if revised.Confidence != ConfidenceLow || len(revised.Attachments) != 1 {
+		t.Fatalf("Expected the revision to keep confidence and attachments, got %+v", revised)
+	}
+
+	if err := engine.SaveSession(session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	loaded, err := engine.LoadSession(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+
+	answer := loaded.Answers["tc_3"]
+	if answer.Text != "Maybe 2000 users" {
+		t.Errorf("Expected the revised text, got %q", answer.Text)
+	}
+	if answer.Confidence != ConfidenceLow {
+		t.Errorf("Expected confidence %q after loading, got %q", ConfidenceLow, answer.Confidence)
+	}
+	if len(answer.Attachments) != 1 || answer.Attachments[0] != revised.Attachments[0] {
+		t.Errorf("Expected attachments %+v after loading, got %+v", revised.Attachments, answer.Attachments)
+	}
 }
 
 // MockProvider implements the provider.Provider interface for testing
@@ -400,6 +527,10 @@ func (m *MockProvider) Call(model string, prompt string) (*provider.Response, er
 	}, nil
 }
 
+func (m *MockProvider) CallWithOptions(model string, prompt string, opts provider.CallOptions) (*provider.Response, error) {
+	return m.Call(model, prompt)
+}
+
 func (m *MockProvider) Stream(model string, prompt string) (<-chan string, error) {
 	ch := make(chan string, 1)
 	ch <- "Mock stream response"
@@ -419,6 +550,10 @@ func (m *MockProvider) SupportsCodingPlan() bool {
 	return false
 }
 
+func (m *MockProvider) Ping(model string) error {
+	return nil
+}
+
 func (m *MockProvider) SetResponse(prompt string, response string) {
 	m.responses[prompt] = response
 }
@@ -505,6 +640,31 @@ func TestInterviewEngine_LLMPoweredFollowUp(t *testing.T) {
 		}
 	})
 
+	t.Run("GenerateFollowUp_Streaming", func(t *testing.T) {
+		question := Question{
+			ID:       "pe_1",
+			Phase:    PhaseProjectEssence,
+			Text:     "What problem does your project solve?",
+			Category: "problem_statement",
+			Required: true,
+		}
+		answer := Answer{QuestionID: "pe_1", Text: "We need a better way to manage tasks", Timestamp: time.Now()}
+
+		streaming := NewEngine(store, NewMockProvider(), "test-model")
+		var chunks []string
+		streaming.SetStreaming(context.Background(), func(chunk string) {
+			chunks = append(chunks, chunk)
+		})
+
+		followUp, err := streaming.GenerateFollowUp(question, answer)
+		if err != nil {
+			t.Fatalf("Failed to generate follow-up: %v", err)
+		}
+		if followUp != "Mock stream response" || len(chunks) != 1 {
+			t.Errorf("Expected the follow-up to arrive through the stream, got %q from chunks %v", followUp, chunks)
+		}
+	})
+
 	t.Run("ProposeDefaultWithLLM", func(t *testing.T) {
 		question := Question{
 			ID:       "custom_1",
@@ -886,6 +1046,8 @@ func TestInterviewEngine_StateManagement(t *testing.T) {
 
 		engine.ReiterateAnswer(session, "pe_1", "Better problem statement", "More clarity needed")
 		engine.RecordFollowUpAnswer(session, "pe_1", "Why?", "Because reasons")
+		engine.StartSubInterview(session, "language", []Question{{Text: "Which Go version?"}, {Text: "Any generics?"}})
+		engine.RecordSubAnswer(session, "tc_1_sub1_1", "1.22")
 
 		// Save the session
 		err := engine.SaveSession(session)
@@ -925,6 +1087,14 @@ func TestInterviewEngine_StateManagement(t *testing.T) {
 		if answer.Text != "Better problem statement" {
 			t.Errorf("Expected 'Better problem statement', got '%s'", answer.Text)
 		}
+
+		sub := engine.ActiveSubInterview(loadedSession)
+		if sub == nil || sub.ParentQuestionID != "tc_1" || sub.Answers["tc_1_sub1_1"].Text != "1.22" {
+			t.Fatalf("Expected the in-progress sub-interview to be restored, got %+v", sub)
+		}
+		if next := engine.GetNextSubQuestion(loadedSession); next == nil || next.Text != "Any generics?" {
+			t.Errorf("Expected the second sub-question next, got %+v", next)
+		}
 	})
 
 	t.Run("GetAnswer", func(t *testing.T) {
@@ -1304,6 +1474,64 @@ func containsHelper(s, substr string) bool {
 	return false
 }
 
+func TestCompleteInterview_AdvancesStage(t *testing.T) {
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{
+		ID:           "complete-project",
+		Name:         "Complete Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: state.StageInterview,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	engine := NewEngine(store, nil, "")
+	session, _ := engine.StartInterview(project.ID)
+	engine.RecordAnswer(session, "pe_1", "Task tracking")
+
+	if err := engine.CompleteInterview(session); err == nil {
+		t.Fatal("Expected incomplete interview to be refused")
+	}
+	if session.Completed {
+		t.Error("Incomplete session should not be marked completed")
+	}
+	if got, _ := store.GetProject(project.ID); got.CurrentStage != state.StageInterview {
+		t.Errorf("Expected stage to stay %s, got %s", state.StageInterview, got.CurrentStage)
+	}
+
+	for _, phase := range engine.GetAllPhases() {
+		for _, q := range engine.GetPhaseQuestions(phase) {
+			if q.Required {
+				engine.RecordAnswer(session, q.ID, "Answer for "+q.ID)
+			}
+		}
+	}
+
+	if err := engine.CompleteInterview(session); err != nil {
+		t.Fatalf("Failed to complete interview: %v", err)
+	}
+	if !session.Completed {
+		t.Error("Expected session to be marked completed")
+	}
+	if got, _ := store.GetProject(project.ID); got.CurrentStage != state.StageDesign {
+		t.Errorf("Expected stage %s, got %s", state.StageDesign, got.CurrentStage)
+	}
+
+	data, err := store.GetInterviewData(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get interview data: %v", err)
+	}
+	if data.ProblemStatement != "Answer for pe_1" {
+		t.Errorf("Expected final answers to be persisted, got %q", data.ProblemStatement)
+	}
+}
+
 func TestInterviewEngine_ProjectNameExport(t *testing.T) {
 	// Create temporary database
 	tmpDir := t.TempDir()
@@ -1408,3 +1636,1544 @@ func TestValidateCompleteness_AllPhasesChecked(t *testing.T) {
 		t.Error("Expected missing question from Technical Constraints")
 	}
 }
+
+func TestSummarizePhase_Template(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, _ := engine.StartInterview("test-project")
+
+	// No answers yet means no recap
+	summary, err := engine.SummarizePhase(session, PhaseProjectEssence)
+	if err != nil {
+		t.Fatalf("SummarizePhase failed: %v", err)
+	}
+	if summary != "" {
+		t.Errorf("Expected empty summary for unanswered phase, got %q", summary)
+	}
+
+	engine.RecordAnswer(session, "pe_1", "Teams lose track of deployments")
+	engine.RecordAnswer(session, "pe_2", "Platform engineers")
+
+	summary, err = engine.SummarizePhase(session, PhaseProjectEssence)
+	if err != nil {
+		t.Fatalf("SummarizePhase failed: %v", err)
+	}
+	if !contains(summary, "Project Essence") {
+		t.Errorf("Summary should name the phase: %s", summary)
+	}
+	if !contains(summary, "Teams lose track of deployments") || !contains(summary, "Platform engineers") {
+		t.Errorf("Summary should include the phase's answers: %s", summary)
+	}
+}
+
+func TestGetNextQuestion_RecordsPhaseSummary(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, _ := engine.StartInterview("test-project")
+
+	for _, q := range engine.GetPhaseQuestions(PhaseProjectEssence) {
+		engine.RecordAnswer(session, q.ID, "Answer for "+q.Category)
+	}
+
+	question, err := engine.GetNextQuestion(session)
+	if err != nil {
+		t.Fatalf("GetNextQuestion failed: %v", err)
+	}
+	if question.Phase != PhaseTechnicalConstraints {
+		t.Fatalf("Expected to move to %s, got %s", PhaseTechnicalConstraints, question.Phase)
+	}
+
+	recap, ok := session.PhaseSummaries[PhaseProjectEssence]
+	if !ok || !contains(recap, "Answer for problem_statement") {
+		t.Errorf("Expected a recap for the finished phase, got %q", recap)
+	}
+}
+
+func TestGetNextQuestion_SummarizesPhaseOnce(t *testing.T) {
+	mock := NewMockProvider()
+	engine := NewEngine(nil, mock, "test-model")
+	session, _ := engine.StartInterview("test-project")
+
+	for _, phase := range engine.GetAllPhases() {
+		for _, q := range engine.GetPhaseQuestions(phase) {
+			engine.RecordAnswer(session, q.ID, "Answer for "+q.Category)
+		}
+	}
+	session.CurrentPhase = engine.GetAllPhases()[len(engine.GetAllPhases())-1]
+	session.CurrentQuestion = len(engine.GetPhaseQuestions(session.CurrentPhase))
+
+	if question, err := engine.GetNextQuestion(session); err != nil || question != nil {
+		t.Fatalf("Expected the interview to be complete, got %v, %v", question, err)
+	}
+	calls := mock.callCount
+	if calls == 0 {
+		t.Fatal("Expected the finished phase to be summarized")
+	}
+
+	// Asking again once the interview is over reuses the recap
+	for i := 0; i < 3; i++ {
+		if _, err := engine.GetNextQuestion(session); err != nil {
+			t.Fatalf("GetNextQuestion failed: %v", err)
+		}
+	}
+	if mock.callCount != calls {
+		t.Errorf("Expected no more summary calls, got %d more", mock.callCount-calls)
+	}
+}
+
+func TestGenerateClarifications(t *testing.T) {
+	question := Question{
+		ID:       "tc_3",
+		Phase:    PhaseTechnicalConstraints,
+		Text:     "What scale do you expect (users, requests, data)?",
+		Category: "scale",
+		Required: true,
+	}
+
+	t.Run("NoProvider_IncompleteAnswer", func(t *testing.T) {
+		engine := NewEngine(nil, nil, "")
+		answer := Answer{QuestionID: "tc_3", Text: "Not sure yet", Timestamp: time.Now()}
+
+		clarifications, err := engine.GenerateClarifications(question, answer)
+		if err != nil {
+			t.Fatalf("Failed to generate clarifications: %v", err)
+		}
+		if len(clarifications) == 0 {
+			t.Fatal("Expected clarifications for an incomplete answer")
+		}
+		if len(clarifications) > 3 {
+			t.Errorf("Expected at most 3 clarifications, got %d", len(clarifications))
+		}
+		if clarifications[0] != cannedClarifications["scale"][0] {
+			t.Errorf("Expected canned scale clarification, got %q", clarifications[0])
+		}
+	})
+
+	t.Run("NoProvider_CompleteAnswer", func(t *testing.T) {
+		engine := NewEngine(nil, nil, "")
+		answer := Answer{
+			QuestionID: "tc_3",
+			Text:       "About 10,000 daily users, 50 requests per second at peak and 200GB of data in year one",
+			Timestamp:  time.Now(),
+		}
+
+		clarifications, err := engine.GenerateClarifications(question, answer)
+		if err != nil {
+			t.Fatalf("Failed to generate clarifications: %v", err)
+		}
+		if len(clarifications) != 0 {
+			t.Errorf("Expected no clarifications for a complete answer, got %v", clarifications)
+		}
+	})
+
+	t.Run("Provider_RatedComplete", func(t *testing.T) {
+		mockProvider := NewMockProvider()
+		mockProvider.SetResponse("", "KEY_POINTS: 10k users\nCOMPLETENESS: complete\nSUGGESTIONS: none")
+		engine := NewEngine(nil, mockProvider, "test-model")
+		answer := Answer{QuestionID: "tc_3", Text: "10k users", Timestamp: time.Now()}
+
+		clarifications, err := engine.GenerateClarifications(question, answer)
+		if err != nil {
+			t.Fatalf("Failed to generate clarifications: %v", err)
+		}
+		if len(clarifications) != 0 {
+			t.Errorf("Expected no clarifications when rated complete, got %v", clarifications)
+		}
+	})
+
+	t.Run("Provider_NumberedQuestions", func(t *testing.T) {
+		mockProvider := NewMockProvider()
+		mockProvider.SetResponse("", "COMPLETENESS: partial\n1. 10k users per day or in total?\n2) 24/7 availability required?")
+		engine := NewEngine(nil, mockProvider, "test-model")
+		answer := Answer{QuestionID: "tc_3", Text: "10k users", Timestamp: time.Now()}
+
+		clarifications, err := engine.GenerateClarifications(question, answer)
+		if err != nil {
+			t.Fatalf("Failed to generate clarifications: %v", err)
+		}
+		expected := []string{"10k users per day or in total?", "24/7 availability required?"}
+		if strings.Join(clarifications, "|") != strings.Join(expected, "|") {
+			t.Errorf("Expected only list markers stripped, got %v", clarifications)
+		}
+	})
+}
+
+func TestRecordAnswerWithConfidence_Unknowns(t *testing.T) {
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{
+		ID:           "test-project",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: state.StageInterview,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	engine := NewEngine(store, nil, "")
+	session, _ := engine.StartInterview(project.ID)
+
+	if err := engine.RecordAnswerWithConfidence(session, "pe_1", "Build a task management system", ConfidenceHigh); err != nil {
+		t.Fatalf("Failed to record answer: %v", err)
+	}
+	if err := engine.RecordAnswerWithConfidence(session, "tc_3", "Maybe 1000 users", ConfidenceLow); err != nil {
+		t.Fatalf("Failed to record answer: %v", err)
+	}
+	if err := engine.RecordAnswerWithConfidence(session, "tc_1", "Go", "certain"); err == nil {
+		t.Error("Expected error for invalid confidence")
+	}
+
+	if err := engine.SaveSession(session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+
+	data, err := store.GetInterviewData(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get interview data: %v", err)
+	}
+	if len(data.Unknowns) != 1 {
+		t.Fatalf("Expected 1 unknown, got %d: %v", len(data.Unknowns), data.Unknowns)
+	}
+	if !contains(data.Unknowns[0], "Maybe 1000 users") {
+		t.Errorf("Expected low-confidence answer in unknowns, got %q", data.Unknowns[0])
+	}
+
+	summary, err := engine.GenerateSummary(session)
+	if err != nil {
+		t.Fatalf("Failed to generate summary: %v", err)
+	}
+	if !contains(summary, "## Unknowns") || !contains(summary, "(low confidence)") {
+		t.Error("Summary should call out low-confidence answers")
+	}
+}
+
+func TestExportToYAML_RoundTrip(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, _ := engine.StartInterview("yaml-project")
+
+	engine.RecordAnswerWithConfidence(session, "pe_1", "Build a task management system", ConfidenceHigh)
+	engine.RecordAnswer(session, "pe_2", "Small teams")
+	engine.RecordFollowUpAnswer(session, "pe_2", "How small?", "Under ten people")
+	engine.ReiterateAnswer(session, "pe_2", "Small remote teams", "More specific")
+	engine.RecordAnswerWithConfidence(session, "tc_3", "Maybe 1000 users", ConfidenceLow)
+	session.CurrentPhase = PhaseTechnicalConstraints
+	session.CurrentQuestion = 3
+
+	yamlData, err := engine.ExportToYAML(session)
+	if err != nil {
+		t.Fatalf("Failed to export YAML: %v", err)
+	}
+	if !contains(yamlData, "project_id: yaml-project") {
+		t.Errorf("Expected project_id in YAML export, got:\n%s", yamlData)
+	}
+
+	imported, err := engine.ImportFromYAML(yamlData)
+	if err != nil {
+		t.Fatalf("Failed to import YAML: %v", err)
+	}
+
+	if imported.ProjectID != session.ProjectID {
+		t.Errorf("Expected project %s, got %s", session.ProjectID, imported.ProjectID)
+	}
+	if imported.CurrentPhase != session.CurrentPhase || imported.CurrentQuestion != session.CurrentQuestion {
+		t.Errorf("Expected position %s/%d, got %s/%d", session.CurrentPhase, session.CurrentQuestion, imported.CurrentPhase, imported.CurrentQuestion)
+	}
+	if len(imported.Answers) != len(session.Answers) {
+		t.Fatalf("Expected %d answers, got %d", len(session.Answers), len(imported.Answers))
+	}
+	for id, want := range session.Answers {
+		got := imported.Answers[id]
+		if got.Text != want.Text || got.Confidence != want.Confidence {
+			t.Errorf("Answer %s: expected %+v, got %+v", id, want, got)
+		}
+	}
+	if followUps := imported.FollowUpAnswers["pe_2"]; len(followUps) != 1 || followUps[0].Text != "Under ten people" {
+		t.Errorf("Expected follow-up to survive round trip, got %+v", followUps)
+	}
+	if iterations := engine.GetIterationHistory(imported, "pe_2"); len(iterations) != 1 || iterations[0].Reason != "More specific" {
+		t.Errorf("Expected revision to survive round trip, got %+v", iterations)
+	}
+	if unknowns := engine.GetUnknowns(imported); len(unknowns) != 1 {
+		t.Errorf("Expected 1 unknown after import, got %v", unknowns)
+	}
+	if strings.Join(imported.AnswerOrder, ",") != "pe_1,pe_2,tc_3" {
+		t.Errorf("Expected answer order rebuilt from timestamps, got %v", imported.AnswerOrder)
+	}
+	if err := engine.UndoLastAnswer(imported); err != nil {
+		t.Errorf("Expected undo to work on an imported session, got %v", err)
+	}
+	if _, ok := imported.Answers["tc_3"]; ok {
+		t.Error("Expected undo to remove the latest answer")
+	}
+
+	if _, err := engine.ImportFromYAML("phases: {}"); err == nil {
+		t.Error("Expected error when project_id is missing")
+	}
+
+	limited := NewEngine(nil, nil, "")
+	limited.SetMaxAnswerLength(10)
+	if _, err := limited.ImportFromYAML(yamlData); !errors.Is(err, ErrAnswerTooLong) {
+		t.Errorf("Expected ErrAnswerTooLong for an over-long imported answer, got %v", err)
+	}
+}
+
+func TestImportFromYAML_SubInterviewOrder(t *testing.T) {
+	yamlData := `project_id: order-project
+phases:
+  technical_constraints:
+    performance:
+      question_id: tc_2
+      answer: Fast
+      sub_interviews:
+        - topic: caching
+          answers:
+            - question_id: sub_perf
+              question: Which cache?
+              answer: Redis
+    language:
+      question_id: tc_1
+      answer: Go
+      sub_interviews:
+        - topic: tooling
+          answers:
+            - question_id: sub_lang
+              question: Which linter?
+              answer: golangci-lint
+  project_essence:
+    problem:
+      question_id: pe_1
+      answer: Slow checkouts
+      sub_interviews:
+        - topic: payments
+          answers:
+            - question_id: sub_pe
+              question: Which processor?
+              answer: Stripe
+`
+	engine := NewEngine(nil, nil, "")
+	for i := 0; i < 10; i++ {
+		imported, err := engine.ImportFromYAML(yamlData)
+		if err != nil {
+			t.Fatalf("Failed to import YAML: %v", err)
+		}
+		topics := []string{}
+		for _, sub := range imported.SubInterviews {
+			topics = append(topics, sub.Topic)
+		}
+		if strings.Join(topics, ",") != "payments,tooling,caching" {
+			t.Fatalf("Expected sub-interviews in phase then category order, got %v", topics)
+		}
+	}
+}
+
+func TestExportQuestionnaire(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+
+	jsonData, err := engine.ExportQuestionnaire("json")
+	if err != nil {
+		t.Fatalf("Failed to export JSON questionnaire: %v", err)
+	}
+	var exported struct {
+		Phases []struct {
+			Phase     string `json:"phase"`
+			Questions []struct {
+				ID       string `json:"id"`
+				Category string `json:"category"`
+				Required bool   `json:"required"`
+			} `json:"questions"`
+		} `json:"phases"`
+	}
+	if err := json.Unmarshal(jsonData, &exported); err != nil {
+		t.Fatalf("Failed to parse JSON questionnaire: %v", err)
+	}
+	required := make(map[string]bool)
+	for _, phase := range exported.Phases {
+		for _, q := range phase.Questions {
+			required[q.ID] = q.Required
+		}
+	}
+
+	markdown, err := engine.ExportQuestionnaire("markdown")
+	if err != nil {
+		t.Fatalf("Failed to export markdown questionnaire: %v", err)
+	}
+	for _, phase := range engine.GetAllPhases() {
+		for _, q := range engine.GetPhaseQuestions(phase) {
+			got, ok := required[q.ID]
+			if !ok {
+				t.Errorf("Question %s missing from JSON questionnaire", q.ID)
+				continue
+			}
+			if got != q.Required {
+				t.Errorf("Question %s: expected required=%v, got %v", q.ID, q.Required, got)
+			}
+			if !strings.Contains(string(markdown), "- **ID:** "+q.ID+"\n") {
+				t.Errorf("Question %s missing from markdown questionnaire", q.ID)
+			}
+		}
+	}
+	if !strings.Contains(string(markdown), "(required)") || !strings.Contains(string(markdown), "(optional)") {
+		t.Error("Expected markdown questionnaire to mark required and optional questions")
+	}
+
+	// The YAML questionnaire re-imports once a project and answers are filled in
+	yamlData, err := engine.ExportQuestionnaire("yaml")
+	if err != nil {
+		t.Fatalf("Failed to export YAML questionnaire: %v", err)
+	}
+	filled := strings.Replace(string(yamlData), `project_id: ""`, "project_id: offline-project", 1)
+	imported, err := engine.ImportFromYAML(filled)
+	if err != nil {
+		t.Fatalf("Failed to import blank questionnaire: %v", err)
+	}
+	if len(imported.Answers) != 0 {
+		t.Errorf("Expected no answers from a blank questionnaire, got %d", len(imported.Answers))
+	}
+
+	if _, err := engine.ExportQuestionnaire("pdf"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestDedupeAnswers(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, _ := engine.StartInterview("dedupe-project")
+
+	engine.RecordAnswer(session, "pe_1", "A task tracker for small remote teams.")
+	engine.RecordAnswer(session, "pe_2", "Small teams")
+	engine.RecordAnswer(session, "pe_3", "a task tracker for small remote teams")
+	engine.RecordAnswer(session, "tc_1", "Go")
+
+	groups := engine.DedupeAnswers(session)
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 duplicate group, got %v", groups)
+	}
+	if dupes := groups["pe_1"]; len(dupes) != 1 || dupes[0] != "pe_3" {
+		t.Errorf("Expected pe_3 grouped under pe_1, got %v", groups)
+	}
+
+	// Reporting must not touch the recorded answers
+	if session.Answers["pe_3"].Text != "a task tracker for small remote teams" {
+		t.Errorf("DedupeAnswers mutated answers: %q", session.Answers["pe_3"].Text)
+	}
+
+	summary, err := engine.GenerateSummary(session)
+	if err != nil {
+		t.Fatalf("Failed to generate summary: %v", err)
+	}
+	if !contains(summary, "(see pe_1)") {
+		t.Error("Summary should point the duplicate answer at pe_1")
+	}
+}
+
+func TestListAnsweredQuestions(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, _ := engine.StartInterview("edit-project")
+
+	// Record out of interview order; the listing must follow phase order
+	engine.RecordAnswer(session, "sd_1", "Login and task lists")
+	engine.RecordAnswer(session, "tc_2", "Under 200ms")
+	engine.RecordAnswer(session, "pe_1", "Task tracking")
+
+	answered := engine.ListAnsweredQuestions(session)
+	expected := []string{"pe_1", "tc_2", "sd_1"}
+	if len(answered) != len(expected) {
+		t.Fatalf("Expected %d answered questions, got %d", len(expected), len(answered))
+	}
+	for i, id := range expected {
+		if answered[i].ID != id {
+			t.Errorf("Position %d: expected %s, got %s", i, id, answered[i].ID)
+		}
+		if answered[i].Answer == nil || answered[i].Answer.Text != session.Answers[id].Text {
+			t.Errorf("Question %s should carry its current answer", id)
+		}
+	}
+
+	if err := engine.ReiterateAnswer(session, answered[1].ID, "Under 100ms", "tighter target"); err != nil {
+		t.Fatalf("Failed to reiterate answer: %v", err)
+	}
+	if got := engine.ListAnsweredQuestions(session)[1].Answer.Text; got != "Under 100ms" {
+		t.Errorf("Expected edited answer to be listed, got %q", got)
+	}
+}
+
+func TestSubInterview(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, _ := engine.StartInterview("sub-project")
+
+	questions := []Question{
+		{Text: "How many users edit the same document at once?"},
+		{Text: "How should conflicting edits be resolved?"},
+	}
+	if err := engine.StartSubInterview(session, "real-time collaboration", questions); err == nil {
+		t.Error("Expected error when no question has been answered")
+	}
+
+	engine.RecordAnswer(session, "tc_1", "Go")
+	engine.RecordAnswer(session, "tc_2", "We need real-time collaboration")
+	if err := engine.StartSubInterview(session, "real-time collaboration", questions); err != nil {
+		t.Fatalf("Failed to start sub-interview: %v", err)
+	}
+	if err := engine.StartSubInterview(session, "offline mode", questions); err == nil {
+		t.Error("Expected error while another sub-interview is active")
+	}
+
+	for i, answer := range []string{"Up to 20", "Last write wins per field"} {
+		q := engine.GetNextSubQuestion(session)
+		if q == nil {
+			t.Fatalf("Expected sub-question %d", i+1)
+		}
+		if q.Text != questions[i].Text {
+			t.Errorf("Expected %q, got %q", questions[i].Text, q.Text)
+		}
+		if err := engine.RecordSubAnswer(session, q.ID, answer); err != nil {
+			t.Fatalf("Failed to record sub-answer: %v", err)
+		}
+	}
+	if engine.GetNextSubQuestion(session) != nil || engine.ActiveSubInterview(session) != nil {
+		t.Error("Expected sub-interview to complete once every question is answered")
+	}
+	if err := engine.RecordSubAnswer(session, "tc_2_sub1_1", "More"); err == nil {
+		t.Error("Expected error when no sub-interview is active")
+	}
+
+	// Sub-interview answers stay out of the main interview
+	if len(session.Answers) != 2 {
+		t.Errorf("Expected 2 main answers, got %d", len(session.Answers))
+	}
+
+	jsonStr, err := engine.ExportToJSON(session)
+	if err != nil {
+		t.Fatalf("Failed to export to JSON: %v", err)
+	}
+	var data struct {
+		Phases map[string]map[string]struct {
+			QuestionID    string `json:"question_id"`
+			SubInterviews []struct {
+				Topic   string `json:"topic"`
+				Answers []struct {
+					Question string `json:"question"`
+					Answer   string `json:"answer"`
+				} `json:"answers"`
+			} `json:"sub_interviews"`
+		} `json:"phases"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	parent := data.Phases[string(PhaseTechnicalConstraints)]["performance"]
+	if parent.QuestionID != "tc_2" || len(parent.SubInterviews) != 1 {
+		t.Fatalf("Expected one sub-interview under tc_2, got %+v", parent)
+	}
+	sub := parent.SubInterviews[0]
+	if sub.Topic != "real-time collaboration" || len(sub.Answers) != 2 || sub.Answers[1].Answer != "Last write wins per field" {
+		t.Errorf("Unexpected sub-interview export: %+v", sub)
+	}
+	if other := data.Phases[string(PhaseTechnicalConstraints)]["language"]; len(other.SubInterviews) != 0 {
+		t.Errorf("Expected no sub-interviews under tc_1, got %+v", other.SubInterviews)
+	}
+
+	yamlData, err := engine.ExportToYAML(session)
+	if err != nil {
+		t.Fatalf("Failed to export YAML: %v", err)
+	}
+	imported, err := engine.ImportFromYAML(yamlData)
+	if err != nil {
+		t.Fatalf("Failed to import YAML: %v", err)
+	}
+	if len(imported.SubInterviews) != 1 || imported.SubInterviews[0].ParentQuestionID != "tc_2" || len(imported.SubInterviews[0].Answers) != 2 {
+		t.Errorf("Expected sub-interview to survive YAML round trip, got %+v", imported.SubInterviews)
+	}
+}
+
+func TestEngine_RecordsInterviewUsage(t *testing.T) {
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{
+		ID:           "usage-project",
+		Name:         "Usage Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: state.StageInterview,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	mock := NewMockProvider()
+	mock.responses[""] = "What does success look like?"
+	engine := NewEngine(store, mock, "test-model")
+	engine.StartInterview(project.ID)
+
+	question := engine.GetPhaseQuestions(PhaseProjectEssence)[0]
+	if _, err := engine.GenerateFollowUp(question, Answer{QuestionID: question.ID, Text: "A tracker"}); err != nil {
+		t.Fatalf("Failed to generate follow-up: %v", err)
+	}
+
+	calls, err := store.GetMostExpensiveCalls(project.ID, 10)
+	if err != nil {
+		t.Fatalf("Failed to get token usage: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 recorded call, got %d", len(calls))
+	}
+	if calls[0].Stage != string(state.StageInterview) || calls[0].TokensInput != 100 {
+		t.Errorf("Expected interview-stage usage, got %+v", calls[0])
+	}
+}
+
+func TestUndoLastAnswer(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, _ := engine.StartInterview("undo-project")
+
+	if err := engine.UndoLastAnswer(session); err == nil {
+		t.Error("Expected error when there is nothing to undo")
+	}
+
+	engine.RecordAnswer(session, "pe_1", "Task tracking")
+	engine.RecordAnswer(session, "pe_2", "Wrong answer")
+
+	if err := engine.UndoLastAnswer(session); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if len(session.Answers) != 1 {
+		t.Errorf("Expected 1 answer after undo, got %d", len(session.Answers))
+	}
+	if _, ok := session.Answers["pe_1"]; !ok {
+		t.Error("Expected pe_1 to survive the undo")
+	}
+	question, _ := engine.GetNextQuestion(session)
+	if question == nil || question.ID != "pe_2" {
+		t.Errorf("Expected pe_2 to be asked again, got %+v", question)
+	}
+
+	t.Run("AcrossPhaseBoundary", func(t *testing.T) {
+		engine.RecordAnswer(session, "pe_2", "Small teams")
+		engine.RecordAnswer(session, "pe_3", "Daily active use")
+		engine.RecordAnswer(session, "pe_4", "Simplicity")
+		if question, _ := engine.GetNextQuestion(session); question == nil || question.ID != "tc_1" {
+			t.Fatalf("Expected to move on to tc_1, got %+v", question)
+		}
+
+		if err := engine.UndoLastAnswer(session); err != nil {
+			t.Fatalf("Failed to undo: %v", err)
+		}
+		if session.CurrentPhase != PhaseProjectEssence || session.CurrentQuestion != 3 {
+			t.Errorf("Expected to rewind to %s question 3, got %s question %d",
+				PhaseProjectEssence, session.CurrentPhase, session.CurrentQuestion)
+		}
+		if question, _ := engine.GetNextQuestion(session); question == nil || question.ID != "pe_4" {
+			t.Errorf("Expected pe_4 to be asked again, got %+v", question)
+		}
+	})
+}
+
+func TestRecordAnswer_LengthLimit(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, _ := engine.StartInterview("limit-project")
+
+	atLimit := strings.Repeat("a", DefaultMaxAnswerLength)
+	if err := engine.RecordAnswer(session, "pe_1", atLimit); err != nil {
+		t.Fatalf("Expected at-limit answer to be accepted, got %v", err)
+	}
+
+	overLimit := atLimit + "a"
+	if err := engine.RecordAnswer(session, "pe_2", overLimit); !errors.Is(err, ErrAnswerTooLong) {
+		t.Errorf("Expected ErrAnswerTooLong, got %v", err)
+	}
+	if _, ok := session.Answers["pe_2"]; ok {
+		t.Error("Over-limit answer should not be recorded")
+	}
+	if err := engine.ReiterateAnswer(session, "pe_1", overLimit, "pasted a file"); !errors.Is(err, ErrAnswerTooLong) {
+		t.Errorf("Expected ErrAnswerTooLong from ReiterateAnswer, got %v", err)
+	}
+
+	engine.SetMaxAnswerLength(2 * DefaultMaxAnswerLength)
+	if err := engine.RecordAnswer(session, "pe_2", overLimit); err != nil {
+		t.Errorf("Expected raised limit to accept the answer, got %v", err)
+	}
+}
+
+func TestAttachToAnswer(t *testing.T) {
+	store, err := state.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{
+		ID:           "attach-project",
+		Name:         "Attach Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: state.StageInterview,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	engine := NewEngine(store, nil, "")
+	session, _ := engine.StartInterview(project.ID)
+
+	if err := engine.AttachToAnswer(session, "pe_1", Attachment{URI: "https://example.com/spec"}); err == nil {
+		t.Error("Expected error when attaching to an unanswered question")
+	}
+
+	engine.RecordAnswer(session, "pe_1", "Build a task management system")
+	if err := engine.AttachToAnswer(session, "pe_1", Attachment{URI: "https://example.com/spec", Description: "Product spec"}); err != nil {
+		t.Fatalf("Failed to attach: %v", err)
+	}
+	if got := session.Answers["pe_1"].Attachments[0].Type; got != AttachmentURL {
+		t.Errorf("Expected inferred type %q, got %q", AttachmentURL, got)
+	}
+
+	if err := engine.SaveSession(session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	loaded, err := engine.LoadSession(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+
+	attachments := loaded.Answers["pe_1"].Attachments
+	if len(attachments) != 1 {
+		t.Fatalf("Expected 1 attachment after load, got %d", len(attachments))
+	}
+	if attachments[0] != (Attachment{Type: AttachmentURL, URI: "https://example.com/spec", Description: "Product spec"}) {
+		t.Errorf("Attachment did not round-trip: %+v", attachments[0])
+	}
+
+	jsonData, err := engine.ExportToJSON(loaded)
+	if err != nil {
+		t.Fatalf("Failed to export JSON: %v", err)
+	}
+	if !contains(jsonData, "https://example.com/spec") || !contains(jsonData, "\"attachments\"") {
+		t.Errorf("Expected attachment in JSON export, got:\n%s", jsonData)
+	}
+
+	data, err := store.GetInterviewData(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get interview data: %v", err)
+	}
+	if len(data.References) != 1 || data.References[0] != "Product spec (https://example.com/spec)" {
+		t.Errorf("Expected reference on interview data, got %v", data.References)
+	}
+}
+
+func TestThemeAnswers_FallbackToPhases(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, _ := engine.StartInterview("theme-project")
+
+	engine.RecordAnswer(session, "pe_1", "Build a task management system")
+	engine.RecordAnswer(session, "pe_2", "Small teams")
+	engine.RecordAnswer(session, "tc_1", "Go")
+
+	themes, err := engine.ThemeAnswers(session)
+	if err != nil {
+		t.Fatalf("ThemeAnswers failed: %v", err)
+	}
+
+	essence := themes["Project Essence"]
+	if len(essence) != 2 || essence[0] != "pe_1" || essence[1] != "pe_2" {
+		t.Errorf("Expected pe_1 and pe_2 under Project Essence, got %v", themes)
+	}
+	if technical := themes["Technical Constraints"]; len(technical) != 1 || technical[0] != "tc_1" {
+		t.Errorf("Expected tc_1 under Technical Constraints, got %v", themes)
+	}
+	if len(themes) != 2 {
+		t.Errorf("Expected only phases with answers, got %v", themes)
+	}
+
+	summary, err := engine.GenerateThematicSummary(session)
+	if err != nil {
+		t.Fatalf("GenerateThematicSummary failed: %v", err)
+	}
+	if !contains(summary, "## Project Essence") || !contains(summary, "A: Go") {
+		t.Errorf("Unexpected thematic summary:\n%s", summary)
+	}
+}
+
+func TestEngine_SetLanguage(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	english := engine.GetPhaseQuestions(PhaseProjectEssence)
+
+	engine.SetLanguage("es")
+	if engine.Language() != "es" {
+		t.Errorf("Expected language es, got %s", engine.Language())
+	}
+	spanish := engine.GetPhaseQuestions(PhaseProjectEssence)
+
+	if len(spanish) != len(english) {
+		t.Fatalf("Expected %d questions, got %d", len(english), len(spanish))
+	}
+	for i := range english {
+		if spanish[i].ID != english[i].ID {
+			t.Errorf("Expected stable ID %s, got %s", english[i].ID, spanish[i].ID)
+		}
+		if spanish[i].Text == english[i].Text {
+			t.Errorf("Expected translated text for %s, got %q", english[i].ID, spanish[i].Text)
+		}
+	}
+	if spanish[0].Text != questionTranslations["es"]["pe_1"] {
+		t.Errorf("Expected %q, got %q", questionTranslations["es"]["pe_1"], spanish[0].Text)
+	}
+
+	// Regional variants use the base language
+	engine.SetLanguage("es_MX")
+	if engine.Language() != "es" {
+		t.Errorf("Expected es_MX to fall back to es, got %s", engine.Language())
+	}
+
+	// Unknown locales keep the English text
+	engine.SetLanguage("xx")
+	for i, q := range engine.GetPhaseQuestions(PhaseProjectEssence) {
+		if q.Text != english[i].Text {
+			t.Errorf("Expected English fallback %q, got %q", english[i].Text, q.Text)
+		}
+	}
+
+	engine.SetLanguage("")
+	if engine.Language() != DefaultLanguage {
+		t.Errorf("Expected default language, got %s", engine.Language())
+	}
+}
+
+func TestExtractGlossaryTerms(t *testing.T) {
+	texts := []string{
+		"Merchants reconcile payouts through the Ledger Service every night.",
+		`Each "settlement batch" is exported as CSV to the Acme Bank SFTP drop.`,
+		"PostgreSQL stores the ledger. We must follow PCI DSS rules.",
+		"Ledger Service also emits webhooks (via Stripe Connect), and the ledger service is the source of truth.",
+		"I'm not sure yet. The team can't decide.",
+	}
+
+	got := extractGlossaryTerms(texts)
+	expected := []string{"Ledger Service", "settlement batch", "CSV", "Acme Bank SFTP", "PostgreSQL", "PCI DSS", "Stripe Connect"}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected terms %v, got %v", expected, got)
+	}
+}
+
+func TestEngine_ExtractGlossary(t *testing.T) {
+	session := &InterviewSession{
+		Answers: map[string]Answer{
+			"pe_1": {QuestionID: "pe_1", Text: "Reconciling payouts in the Ledger Service is manual."},
+			"tc_1": {QuestionID: "tc_1", Text: "Go, with a \"settlement batch\" job."},
+		},
+		FollowUpAnswers: map[string][]Answer{},
+	}
+
+	t.Run("WithoutProvider", func(t *testing.T) {
+		engine := NewEngine(nil, nil, "")
+		glossary, err := engine.ExtractGlossary(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(glossary) != 2 {
+			t.Fatalf("Expected 2 terms, got %v", glossary)
+		}
+		if definition, ok := glossary["Ledger Service"]; !ok || definition != "" {
+			t.Errorf("Expected undefined Ledger Service term, got %q (present %v)", definition, ok)
+		}
+		if len(session.Glossary) != 2 {
+			t.Errorf("Expected glossary to be kept on the session, got %v", session.Glossary)
+		}
+	})
+
+	t.Run("WithProvider", func(t *testing.T) {
+		mock := NewMockProvider()
+		mock.responses[""] = "- Ledger Service: The internal system of record for payouts\nsettlement batch: A day's payouts grouped for transfer\nUnrelated: ignored"
+		engine := NewEngine(nil, mock, "test-model")
+
+		glossary, err := engine.ExtractGlossary(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if glossary["Ledger Service"] != "The internal system of record for payouts" {
+			t.Errorf("Unexpected definition: %q", glossary["Ledger Service"])
+		}
+		if glossary["settlement batch"] != "A day's payouts grouped for transfer" {
+			t.Errorf("Unexpected definition: %q", glossary["settlement batch"])
+		}
+		if _, ok := glossary["Unrelated"]; ok {
+			t.Error("Expected terms not found in the answers to be ignored")
+		}
+	})
+}
+
+func TestEngine_GenerateElevatorPitch(t *testing.T) {
+	session := &InterviewSession{
+		Answers: map[string]Answer{
+			"pe_1": {QuestionID: "pe_1", Text: "Reconciling payouts by hand takes days"},
+			"pe_2": {QuestionID: "pe_2", Text: "Finance teams at small marketplaces"},
+			"pe_4": {QuestionID: "pe_4", Text: "matches every payout to its bank transfer automatically."},
+		},
+	}
+
+	t.Run("Template", func(t *testing.T) {
+		engine := NewEngine(nil, nil, "")
+		pitch, err := engine.GenerateElevatorPitch(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "Reconciling payouts by hand takes days. Built for finance teams at small marketplaces. Matches every payout to its bank transfer automatically."
+		if pitch != expected {
+			t.Errorf("Unexpected pitch:\n got %q\nwant %q", pitch, expected)
+		}
+		if session.ElevatorPitch != pitch {
+			t.Errorf("Expected pitch to be kept on the session, got %q", session.ElevatorPitch)
+		}
+	})
+
+	t.Run("WithProvider", func(t *testing.T) {
+		mock := NewMockProvider()
+		mock.responses[""] = "  Payout reconciliation in minutes.  "
+		engine := NewEngine(nil, mock, "test-model")
+
+		pitch, err := engine.GenerateElevatorPitch(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if pitch != "Payout reconciliation in minutes." {
+			t.Errorf("Unexpected pitch: %q", pitch)
+		}
+	})
+
+	t.Run("NoAnswers", func(t *testing.T) {
+		engine := NewEngine(nil, nil, "")
+		if _, err := engine.GenerateElevatorPitch(&InterviewSession{Answers: map[string]Answer{}}); err == nil {
+			t.Error("Expected error without the pitch answers")
+		}
+	})
+}
+
+func TestEngine_SuggestMissingTopics(t *testing.T) {
+	session := &InterviewSession{
+		Answers: map[string]Answer{
+			"pe_1": {QuestionID: "pe_1", Text: "An online store for handmade furniture"},
+			"pe_2": {QuestionID: "pe_2", Text: "Shoppers and small workshops"},
+		},
+	}
+
+	t.Run("WithoutProvider", func(t *testing.T) {
+		engine := NewEngine(nil, nil, "")
+		topics, err := engine.SuggestMissingTopics(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(topics) != 0 {
+			t.Errorf("Expected no topics without a provider, got %v", topics)
+		}
+	})
+
+	t.Run("WithProvider", func(t *testing.T) {
+		mock := NewMockProvider()
+		mock.responses[""] = "1. Payments\n- Shipping\n\npayments\nReturns and refunds\n3rd-party payment integrations"
+		engine := NewEngine(nil, mock, "test-model")
+
+		topics, err := engine.SuggestMissingTopics(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []string{"Payments", "Shipping", "Returns and refunds", "3rd-party payment integrations"}
+		if strings.Join(topics, "|") != strings.Join(expected, "|") {
+			t.Errorf("Expected topics %v, got %v", expected, topics)
+		}
+
+		questions := MissingTopicQuestions(topics)
+		if len(questions) != 4 || !strings.Contains(questions[0].Text, "Payments") {
+			t.Errorf("Expected one question per topic, got %v", questions)
+		}
+	})
+
+	t.Run("NothingMissing", func(t *testing.T) {
+		mock := NewMockProvider()
+		mock.responses[""] = "NONE"
+		engine := NewEngine(nil, mock, "test-model")
+
+		topics, err := engine.SuggestMissingTopics(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(topics) != 0 {
+			t.Errorf("Expected no topics, got %v", topics)
+		}
+	})
+}
+
+func TestEngine_RecordAnswers(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	engine.SetMaxAnswerLength(40)
+	session, err := engine.StartInterview("test-project")
+	if err != nil {
+		t.Fatalf("Failed to start interview: %v", err)
+	}
+
+	report, err := engine.RecordAnswers(session, map[string]string{
+		"pe_1":    "Teams lose track of shared chores",
+		"pe_2":    "Households of roommates",
+		"pe_3":    "   ",
+		"tc_1":    "A very long answer that goes well past the configured limit",
+		"made_up": "Not a question",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if strings.Join(report.Accepted, ",") != "pe_1,pe_2" {
+		t.Errorf("Expected pe_1 and pe_2 accepted, got %v", report.Accepted)
+	}
+	if len(report.Rejected) != 3 {
+		t.Fatalf("Expected 3 rejected answers, got %v", report.Rejected)
+	}
+	if report.Rejected["pe_3"] != "answer is empty" {
+		t.Errorf("Unexpected reason for pe_3: %q", report.Rejected["pe_3"])
+	}
+	if !strings.Contains(report.Rejected["tc_1"], "too long") {
+		t.Errorf("Unexpected reason for tc_1: %q", report.Rejected["tc_1"])
+	}
+	if report.Rejected["made_up"] != "unknown question" {
+		t.Errorf("Unexpected reason for made_up: %q", report.Rejected["made_up"])
+	}
+
+	if _, ok := session.Answers["pe_1"]; !ok {
+		t.Error("Expected accepted answer to be recorded")
+	}
+	if _, ok := session.Answers["pe_3"]; ok {
+		t.Error("Expected rejected answer not to be recorded")
+	}
+	_, missing := engine.ValidateCompleteness(session)
+	if len(report.RemainingRequired) != len(missing) || len(missing) == 0 {
+		t.Errorf("Expected remaining required questions to be reported, got %d want %d", len(report.RemainingRequired), len(missing))
+	}
+	if session.CurrentQuestion != 0 {
+		t.Errorf("Expected interview position unchanged, got %d", session.CurrentQuestion)
+	}
+}
+
+func TestEngine_SuggestSuccessMetrics(t *testing.T) {
+	session := &InterviewSession{
+		Answers: map[string]Answer{
+			"pe_1": {QuestionID: "pe_1", Text: "Small shops lose sales because checkout is slow"},
+			"pe_4": {QuestionID: "pe_4", Text: "One-click checkout"},
+		},
+	}
+
+	t.Run("WithProvider", func(t *testing.T) {
+		mock := NewMockProvider()
+		mock.responses[""] = "1. Checkout completes in under 3 seconds\n- Cart abandonment drops below 20%\n\ncheckout completes in under 3 seconds\n"
+		engine := NewEngine(nil, mock, "test-model")
+
+		metrics, err := engine.SuggestSuccessMetrics(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []string{"Checkout completes in under 3 seconds", "Cart abandonment drops below 20%"}
+		if strings.Join(metrics, "|") != strings.Join(expected, "|") {
+			t.Errorf("Expected metrics %v, got %v", expected, metrics)
+		}
+	})
+
+	t.Run("NumbersInMetrics", func(t *testing.T) {
+		mock := NewMockProvider()
+		mock.responses[""] = "1. 99.9% uptime over 30 days\n95th percentile latency under 200ms\n2) 3 support tickets per week at most\n"
+		engine := NewEngine(nil, mock, "test-model")
+
+		metrics, err := engine.SuggestSuccessMetrics(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []string{"99.9% uptime over 30 days", "95th percentile latency under 200ms", "3 support tickets per week at most"}
+		if strings.Join(metrics, "|") != strings.Join(expected, "|") {
+			t.Errorf("Expected only list markers stripped, got %v", metrics)
+		}
+	})
+
+	t.Run("WithoutProvider", func(t *testing.T) {
+		engine := NewEngine(nil, nil, "")
+		metrics, err := engine.SuggestSuccessMetrics(session)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(metrics) == 0 || len(metrics) > maxSuggestedMetrics {
+			t.Fatalf("Expected 1-%d metrics, got %v", maxSuggestedMetrics, metrics)
+		}
+		// Sales and speed keywords pick their categories ahead of the general metrics
+		if metrics[0] != metricCategories[0].metrics[0] || metrics[2] != metricCategories[1].metrics[0] {
+			t.Errorf("Expected category-based metrics first, got %v", metrics)
+		}
+
+		empty, err := engine.SuggestSuccessMetrics(&InterviewSession{Answers: map[string]Answer{}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(empty, "|") != strings.Join(generalMetrics, "|") {
+			t.Errorf("Expected general metrics with no answers, got %v", empty)
+		}
+	})
+}
+
+func TestEngine_ReadyForDesign(t *testing.T) {
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{ID: "test-project", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: state.StageInterview}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	engine := NewEngine(store, nil, "")
+	session, err := engine.StartInterview(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to start interview: %v", err)
+	}
+	for id, text := range map[string]string{
+		"pe_1": "Teams lose track of shared tasks",
+		"pe_2": "Small engineering teams",
+		"tc_1": "Go",
+		"ip_2": "   ",
+	} {
+		if err := engine.RecordAnswer(session, id, text); err != nil {
+			t.Fatalf("Failed to record answer %s: %v", id, err)
+		}
+	}
+
+	ready, blockers := engine.ReadyForDesign(session)
+	if ready {
+		t.Fatal("Expected a session with a blank database answer not to be ready")
+	}
+	if len(blockers) != 1 || !strings.Contains(blockers[0], "database") {
+		t.Errorf("Expected only the database blocker, got %v", blockers)
+	}
+
+	// Saved data is checked through its raw session
+	if err := engine.SaveSession(session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	data, err := store.GetInterviewData(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get interview data: %v", err)
+	}
+	if ready, _ := ReadyForDesignData(data); ready {
+		t.Error("Expected saved data without a database answer not to be ready")
+	}
+
+	if err := engine.RecordAnswer(session, "ip_2", "PostgreSQL"); err != nil {
+		t.Fatalf("Failed to record answer: %v", err)
+	}
+	if ready, blockers := engine.ReadyForDesign(session); !ready {
+		t.Errorf("Expected session to be ready, got blockers %v", blockers)
+	}
+
+	if ready, blockers := ReadyForDesignData(&state.InterviewData{ProblemStatement: "Tracking tasks"}); ready || len(blockers) != 1 {
+		t.Errorf("Expected data without target users not to be ready, got %v", blockers)
+	}
+}
+
+func TestEngine_OnPhaseComplete(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session := &InterviewSession{
+		ProjectID:    "test-project",
+		CurrentPhase: PhaseProjectEssence,
+		Answers:      make(map[string]Answer),
+	}
+
+	var calls []string
+	var completed []Phase
+	engine.OnPhaseComplete(func(s *InterviewSession, phase Phase) {
+		calls = append(calls, "first")
+		completed = append(completed, phase)
+		if s != session {
+			t.Error("Expected the hook to receive the session")
+		}
+	})
+	engine.OnPhaseComplete(func(s *InterviewSession, phase Phase) {
+		calls = append(calls, "second")
+	})
+
+	// Step through the first phase and onto the first question of the next
+	for i := 0; i <= len(engine.GetPhaseQuestions(PhaseProjectEssence)); i++ {
+		if _, err := engine.GetNextQuestion(session); err != nil {
+			t.Fatalf("Failed to get next question: %v", err)
+		}
+		if len(completed) > 0 {
+			break
+		}
+		session.CurrentQuestion++
+	}
+
+	if len(completed) != 1 || completed[0] != PhaseProjectEssence {
+		t.Fatalf("Expected the hook to run once for the first phase, got %v", completed)
+	}
+	if strings.Join(calls, ",") != "first,second" {
+		t.Errorf("Expected hooks to run in registration order, got %v", calls)
+	}
+	if session.CurrentPhase != PhaseTechnicalConstraints {
+		t.Errorf("Expected to move to the next phase, got %s", session.CurrentPhase)
+	}
+
+	// Asking again within the new phase doesn't fire the hook
+	if _, err := engine.GetNextQuestion(session); err != nil {
+		t.Fatalf("Failed to get next question: %v", err)
+	}
+	if len(completed) != 1 {
+		t.Errorf("Expected no further hook calls within a phase, got %v", completed)
+	}
+}
+
+func TestEngine_AutoFillOptional(t *testing.T) {
+	mock := NewMockProvider()
+	mock.responses[""] = "  A sensible default  "
+	engine := NewEngine(nil, mock, "test-model")
+	session, err := engine.StartInterview("test-project")
+	if err != nil {
+		t.Fatalf("Failed to start interview: %v", err)
+	}
+	if err := engine.RecordAnswer(session, "ip_1", "Stripe"); err != nil {
+		t.Fatalf("Failed to record answer: %v", err)
+	}
+	position := session.CurrentQuestion
+
+	filled, err := engine.AutoFillOptional(session)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if strings.Join(filled, ",") != "tc_4,ip_4,sd_3,sd_4" {
+		t.Errorf("Expected the unanswered optional questions to be filled, got %v", filled)
+	}
+	for _, id := range filled {
+		if session.Answers[id].Text != "A sensible default" {
+			t.Errorf("Expected the proposed default for %s, got %q", id, session.Answers[id].Text)
+		}
+	}
+	if session.Answers["ip_1"].Text != "Stripe" {
+		t.Errorf("Expected an existing answer to be kept, got %q", session.Answers["ip_1"].Text)
+	}
+	for _, id := range []string{"pe_1", "tc_1", "ip_2", "sd_1"} {
+		if _, ok := session.Answers[id]; ok {
+			t.Errorf("Expected required question %s to stay unanswered", id)
+		}
+	}
+	if session.CurrentQuestion != position {
+		t.Errorf("Expected the interview position to stay at %d, got %d", position, session.CurrentQuestion)
+	}
+
+	// Without a provider the optional questions have no default to use
+	bare := NewEngine(nil, nil, "")
+	bareSession, err := bare.StartInterview("test-project")
+	if err != nil {
+		t.Fatalf("Failed to start interview: %v", err)
+	}
+	if filled, err := bare.AutoFillOptional(bareSession); err != nil || len(filled) != 0 {
+		t.Errorf("Expected nothing filled without a provider, got %v (%v)", filled, err)
+	}
+}
+
+func TestEngine_ExportPhasesToFiles(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, err := engine.StartInterview("test-project")
+	if err != nil {
+		t.Fatalf("Failed to start interview: %v", err)
+	}
+
+	if err := engine.RecordAnswer(session, "pe_1", "Teams lose track of invoices"); err != nil {
+		t.Fatalf("Failed to record answer: %v", err)
+	}
+	if err := engine.RecordFollowUpAnswer(session, "pe_1", "How many?", "About 200 a month"); err != nil {
+		t.Fatalf("Failed to record follow-up: %v", err)
+	}
+	if err := engine.ReiterateAnswer(session, "pe_1", "Teams lose track of unpaid invoices", "more precise"); err != nil {
+		t.Fatalf("Failed to revise answer: %v", err)
+	}
+	session.Answers["tc_1"] = Answer{QuestionID: "tc_1", Text: "Go"}
+
+	dir := filepath.Join(t.TempDir(), "requirements")
+	if err := engine.ExportPhasesToFiles(session, dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	essence, err := os.ReadFile(filepath.Join(dir, "project-essence.md"))
+	if err != nil {
+		t.Fatalf("Expected project-essence.md to be written: %v", err)
+	}
+	for _, want := range []string{"# Project Essence", "Teams lose track of unpaid invoices", "About 200 a month", "more precise"} {
+		if !strings.Contains(string(essence), want) {
+			t.Errorf("Expected project-essence.md to contain %q, got:\n%s", want, essence)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "technical-constraints.md")); err != nil {
+		t.Errorf("Expected technical-constraints.md to be written: %v", err)
+	}
+
+	for _, name := range []string{"integration-points.md", "scope-definition.md", "refinement-validation.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected no file for unanswered phase %s, got %v", name, err)
+		}
+	}
+}
+
+func TestEngine_RestartInterview(t *testing.T) {
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{ID: "restart-project", Name: "Restart Project", CreatedAt: time.Now(), CurrentStage: state.StageInterview}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	engine := NewEngine(store, nil, "")
+	session, _ := engine.StartInterview(project.ID)
+	if err := engine.RecordAnswer(session, "pe_1", "Invoices get lost"); err != nil {
+		t.Fatalf("Failed to record answer: %v", err)
+	}
+	if err := engine.RecordAnswer(session, "pe_2", "Accountants"); err != nil {
+		t.Fatalf("Failed to record answer: %v", err)
+	}
+	if err := engine.SaveSession(session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+
+	restarted, err := engine.RestartInterview(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to restart interview: %v", err)
+	}
+	if len(restarted.Answers) != 0 || restarted.CurrentPhase != PhaseProjectEssence || restarted.CurrentQuestion != 0 {
+		t.Errorf("Expected a fresh session, got %d answers at %s/%d", len(restarted.Answers), restarted.CurrentPhase, restarted.CurrentQuestion)
+	}
+	if len(restarted.PreviousSessions) != 1 || restarted.PreviousSessions[0].Answers["pe_2"].Text != "Accountants" {
+		t.Fatalf("Expected the old answers to be archived, got %+v", restarted.PreviousSessions)
+	}
+
+	// The archive survives a reload and feeds defaults
+	loaded, err := engine.LoadSession(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+	if len(loaded.Answers) != 0 {
+		t.Errorf("Expected the saved session to be empty, got %d answers", len(loaded.Answers))
+	}
+	if previous, ok := engine.PreviousAnswer(loaded, "pe_1"); !ok || previous != "Invoices get lost" {
+		t.Errorf("Expected the archived answer as a default, got %q (%v)", previous, ok)
+	}
+	if _, ok := engine.PreviousAnswer(loaded, "tc_1"); ok {
+		t.Error("Expected no default for a question never answered")
+	}
+
+	// A second restart keeps the earlier archive and skips the empty run
+	again, err := engine.RestartInterview(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to restart interview again: %v", err)
+	}
+	if len(again.PreviousSessions) != 1 {
+		t.Errorf("Expected the archive to be kept without adding an empty run, got %d", len(again.PreviousSessions))
+	}
+}
+
+func TestEngine_ApplyAnswerVariables(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, err := engine.StartInterview("test-project")
+	if err != nil {
+		t.Fatalf("Failed to start interview: %v", err)
+	}
+	session.Answers["pe_1"] = Answer{QuestionID: "pe_1", Text: "{{product}} helps {{audience}} track invoices"}
+	session.Answers["pe_2"] = Answer{QuestionID: "pe_2", Text: "Target users: {{audience}} in {{region | default \"Europe\"}}"}
+	session.Answers["tc_1"] = Answer{QuestionID: "tc_1", Text: "Go"}
+
+	// An undefined variable without a default changes nothing
+	err = engine.ApplyAnswerVariables(session, map[string]string{"audience": "accountants"})
+	if err == nil || !strings.Contains(err.Error(), "product") {
+		t.Fatalf("Expected an undefined variable error naming product, got %v", err)
+	}
+	if session.Answers["pe_2"].Text != "Target users: {{audience}} in {{region | default \"Europe\"}}" {
+		t.Errorf("Expected answers untouched after an error, got %q", session.Answers["pe_2"].Text)
+	}
+
+	if err := engine.ApplyAnswerVariables(session, map[string]string{"audience": "accountants", "product": "Ledgerly"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"pe_1": "Ledgerly helps accountants track invoices",
+		"pe_2": "Target users: accountants in Europe",
+		"tc_1": "Go",
+	}
+	for id, want := range expected {
+		if got := session.Answers[id].Text; got != want {
+			t.Errorf("Answer %s: expected %q, got %q", id, want, got)
+		}
+	}
+
+	if err := engine.ApplyAnswerVariables(session, map[string]string{"not-valid": "x"}); err == nil {
+		t.Error("Expected an error for an invalid variable name")
+	}
+}
+
+func TestEngine_ApplyAnswerVariables_OnlyGivenQuestions(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, err := engine.StartInterview("test-project")
+	if err != nil {
+		t.Fatalf("Failed to start interview: %v", err)
+	}
+	session.Answers["pe_1"] = Answer{QuestionID: "pe_1", Text: "{{product}} for {{audience}}"}
+	session.Answers["pe_2"] = Answer{QuestionID: "pe_2", Text: "Literal {{braces}} typed by hand"}
+
+	// pe_2 isn't filled, so its undefined variable isn't an error
+	if err := engine.ApplyAnswerVariables(session, map[string]string{"product": "Ledgerly", "audience": "accountants"}, "pe_1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := session.Answers["pe_1"].Text; got != "Ledgerly for accountants" {
+		t.Errorf("Expected pe_1 to be filled, got %q", got)
+	}
+	if got := session.Answers["pe_2"].Text; got != "Literal {{braces}} typed by hand" {
+		t.Errorf("Expected pe_2 to be left alone, got %q", got)
+	}
+}
+
+func TestEngine_GetQuestionHelp(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+
+	for _, phase := range engine.GetAllPhases() {
+		for _, q := range engine.GetPhaseQuestions(phase) {
+			if !q.Required {
+				continue
+			}
+			help, example, err := engine.GetQuestionHelp(q.ID)
+			if err != nil {
+				t.Fatalf("Unexpected error for %s: %v", q.ID, err)
+			}
+			if help == "" {
+				t.Errorf("Expected help text for required question %s", q.ID)
+			}
+			if example == "" {
+				t.Errorf("Expected an example for required question %s", q.ID)
+			}
+			if q.HelpText != help {
+				t.Errorf("Expected question %s to carry its help text", q.ID)
+			}
+		}
+	}
+
+	// Custom questions keep their own help
+	custom := withQuestionHelp([]Question{{ID: "pe_1", HelpText: "Custom help"}})
+	if custom[0].HelpText != "Custom help" || custom[0].Example == "" {
+		t.Errorf("Expected custom help kept and example filled, got %+v", custom[0])
+	}
+
+	if _, _, err := engine.GetQuestionHelp("missing"); err == nil {
+		t.Error("Expected an error for an unknown question")
+	}
+}
+
+func TestEngine_DivergentDefaults(t *testing.T) {
+	engine := NewEngine(nil, nil, "")
+	session, err := engine.StartInterview("test-project")
+	if err != nil {
+		t.Fatalf("Failed to start interview: %v", err)
+	}
+
+	if err := engine.AcceptProposal(session, "ip_2", "PostgreSQL", ""); err != nil {
+		t.Fatalf("Failed to accept proposal: %v", err)
+	}
+	if err := engine.AcceptProposal(session, "ip_3", "JWT-based authentication", "JWT authentication with refresh tokens"); err != nil {
+		t.Fatalf("Failed to accept proposal: %v", err)
+	}
+	if err := engine.AcceptProposal(session, "tc_1", "Go", "Haskell and Elm on a custom runtime"); err != nil {
+		t.Fatalf("Failed to accept proposal: %v", err)
+	}
+
+	if session.Answers["ip_2"].Text != "PostgreSQL" {
+		t.Errorf("Expected an empty answer to accept the proposal, got %q", session.Answers["ip_2"].Text)
+	}
+	if got := strings.Join(engine.DivergentDefaults(session), ","); got != "tc_1" {
+		t.Errorf("Expected only tc_1 flagged, got %q", got)
+	}
+
+	// Changing an accepted default later is flagged as well
+	if err := engine.ReiterateAnswer(session, "ip_2", "A graph database (Neo4j)", "Data is highly connected"); err != nil {
+		t.Fatalf("Failed to reiterate answer: %v", err)
+	}
+	if got := strings.Join(engine.DivergentDefaults(session), ","); got != "tc_1,ip_2" {
+		t.Errorf("Expected tc_1 and ip_2 flagged, got %q", got)
+	}
+
+	summary, err := engine.GenerateSummary(session)
+	if err != nil {
+		t.Fatalf("Failed to generate summary: %v", err)
+	}
+	if !strings.Contains(summary, "## Unconventional Choices") || !strings.Contains(summary, `over the usual "Go"`) {
+		t.Errorf("Expected the summary to note unconventional choices, got:\n%s", summary)
+	}
+}
+
+func TestEngine_CompletedColumnBackfill(t *testing.T) {
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, id := range []string{"proj-saved", "proj-struct", "proj-open"} {
+		project := &state.Project{ID: id, Name: id, CreatedAt: time.Now(), CurrentStage: state.StageInterview}
+		if err := store.CreateProject(project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+
+	engine := NewEngine(store, nil, "")
+	for _, id := range []string{"proj-saved", "proj-open"} {
+		session, err := engine.StartInterview(id)
+		if err != nil {
+			t.Fatalf("Failed to start interview: %v", err)
+		}
+		session.Completed = id == "proj-saved"
+		if err := engine.SaveSession(session); err != nil {
+			t.Fatalf("Failed to save session: %v", err)
+		}
+	}
+
+	// A session marshalled as a struct has capitalised keys
+	session, err := engine.StartInterview("proj-struct")
+	if err != nil {
+		t.Fatalf("Failed to start interview: %v", err)
+	}
+	session.Completed = true
+	raw, err := json.Marshal(session)
+	if err != nil {
+		t.Fatalf("Failed to marshal session: %v", err)
+	}
+	if err := store.SaveInterviewData("proj-struct", &state.InterviewData{ProjectID: "proj-struct", RawSession: string(raw), CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to save interview data: %v", err)
+	}
+
+	// Re-run the migration that backfills the completed column
+	manager := store.MigrationManager()
+	if err := manager.MigrateToVersion(11); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if err := manager.MigrateToVersion(state.LatestVersion()); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	missing, err := store.ListProjectsMissingInterview()
+	if err != nil {
+		t.Fatalf("Failed to list projects missing an interview: %v", err)
+	}
+	if len(missing) != 1 || missing[0].ID != "proj-open" {
+		var ids []string
+		for _, p := range missing {
+			ids = append(ids, p.ID)
+		}
+		t.Errorf("Expected only proj-open to be missing an interview, got %v", ids)
+	}
+}
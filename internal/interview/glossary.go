@@ -0,0 +1,190 @@
+package interview
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxGlossaryTermLength keeps quoted phrases that are really sentences out
+// of the glossary
+const maxGlossaryTermLength = 40
+
+// quotedTermPattern matches double-quoted, curly-quoted and backticked
+// phrases. Single quotes are left out since they are mostly apostrophes.
+var quotedTermPattern = regexp.MustCompile("\"([^\"\n]+)\"|“([^”\n]+)”|`([^`\n]+)`")
+
+// ExtractGlossary collects project-specific terms from the session's answers
+// and, with a provider, asks for a one-line definition of each. Without a
+// provider the terms map to empty definitions. The glossary is kept on the
+// session so SaveSession stores it with the interview data, where the
+// design and plan prompts pick it up.
+func (e *Engine) ExtractGlossary(session *InterviewSession) (map[string]string, error) {
+	var texts []string
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			if answer, ok := session.Answers[q.ID]; ok {
+				texts = append(texts, answer.Text)
+			}
+			for _, followUp := range session.FollowUpAnswers[q.ID] {
+				texts = append(texts, followUp.Text)
+			}
+		}
+	}
+
+	terms := extractGlossaryTerms(texts)
+	glossary := make(map[string]string, len(terms))
+	for _, term := range terms {
+		glossary[term] = ""
+	}
+
+	if e.provider != nil && len(terms) > 0 {
+		prompt := fmt.Sprintf(`The following terms come from a software project's requirements interview. Define each term in one short sentence as it is used in this project, so later planning treats it consistently.
+
+Interview answers:
+%s
+
+Terms:
+%s
+
+Respond with one line per term in the form "Term: definition" and nothing else.`, strings.Join(texts, "\n"), strings.Join(terms, "\n"))
+
+		response, err := e.callProvider(prompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to define glossary terms: %w", err)
+		}
+		for _, line := range strings.Split(response.Content, "\n") {
+			term, definition, ok := strings.Cut(strings.TrimSpace(strings.TrimLeft(line, "-* ")), ":")
+			if !ok {
+				continue
+			}
+			if _, known := glossary[strings.TrimSpace(term)]; known {
+				glossary[strings.TrimSpace(term)] = strings.TrimSpace(definition)
+			}
+		}
+	}
+
+	session.Glossary = glossary
+	return glossary, nil
+}
+
+// extractGlossaryTerms finds quoted phrases, acronyms and runs of
+// capitalized words in the texts, in order of first appearance. Capitalized
+// words starting a sentence only count when they are acronyms or have
+// inner capitals like "PostgreSQL", since any word can start a sentence.
+func extractGlossaryTerms(texts []string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	add := func(term string) {
+		term = strings.TrimSpace(term)
+		key := strings.ToLower(term)
+		if term == "" || len(term) > maxGlossaryTermLength || seen[key] {
+			return
+		}
+		seen[key] = true
+		terms = append(terms, term)
+	}
+
+	for _, text := range texts {
+		for _, match := range quotedTermPattern.FindAllStringSubmatch(text, -1) {
+			for _, group := range match[1:] {
+				if group != "" {
+					add(group)
+				}
+			}
+		}
+		// Quoted phrases are handled; blank them so their words aren't
+		// picked up again as capitalized terms
+		text = quotedTermPattern.ReplaceAllString(text, ".")
+
+		for _, sentence := range strings.FieldsFunc(text, func(r rune) bool {
+			return r == '.' || r == '!' || r == '?' || r == '\n' || r == ';' || r == ':'
+		}) {
+			var run []string
+			flush := func() {
+				add(strings.Join(run, " "))
+				run = nil
+			}
+			words := strings.Fields(sentence)
+			for i, word := range words {
+				trimmed := strings.TrimFunc(word, isNotWordRune)
+				// Brackets and commas around a word separate terms
+				if strings.TrimLeftFunc(word, isNotWordRune) != word {
+					flush()
+				}
+				if isGlossaryWord(trimmed, i == 0) {
+					run = append(run, trimmed)
+				} else if i == 0 && len(words) > 1 && isGlossaryWord(strings.TrimFunc(words[1], isNotWordRune), false) &&
+					isGlossaryWord(trimmed, false) && !sentenceStarters[strings.ToLower(trimmed)] {
+					// A capitalized word starting a sentence belongs to the
+					// name that follows it, as in "Ledger Service handles..."
+					run = append(run, trimmed)
+				} else {
+					flush()
+				}
+				if strings.TrimRightFunc(word, isNotWordRune) != word {
+					flush()
+				}
+			}
+			flush()
+		}
+	}
+	return terms
+}
+
+// sentenceStarters are common capitalized sentence openers that never
+// start a term
+var sentenceStarters = map[string]bool{
+	"a": true, "an": true, "and": true, "but": true, "for": true, "if": true,
+	"in": true, "it": true, "my": true, "on": true, "our": true, "the": true,
+	"these": true, "they": true, "this": true, "we": true, "with": true,
+}
+
+// isNotWordRune reports whether r is punctuation around a word
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// isGlossaryWord reports whether a word can be part of a glossary term
+func isGlossaryWord(word string, sentenceStart bool) bool {
+	runes := []rune(word)
+	if len(runes) < 2 || !unicode.IsUpper(runes[0]) || strings.ContainsAny(word, "'’") {
+		return false
+	}
+	if !sentenceStart {
+		return true
+	}
+	for _, r := range runes[1:] {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatGlossaryForPrompt lists the project's terms, alphabetically, for the
+// design and planning prompts so they use the terms consistently
+func FormatGlossaryForPrompt(glossary map[string]string) string {
+	if len(glossary) == 0 {
+		return ""
+	}
+
+	terms := make([]string, 0, len(glossary))
+	for term := range glossary {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	var sb strings.Builder
+	sb.WriteString("\nGLOSSARY (project-specific terms; use them with these meanings):\n")
+	for _, term := range terms {
+		if definition := glossary[term]; definition != "" {
+			sb.WriteString("- " + term + ": " + definition + "\n")
+		} else {
+			sb.WriteString("- " + term + "\n")
+		}
+	}
+	return sb.String()
+}
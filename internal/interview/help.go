@@ -0,0 +1,107 @@
+package interview
+
+import "fmt"
+
+// questionHelp explains each built-in question and gives a sample answer.
+// Help is in English whatever the engine's language.
+var questionHelp = map[string]struct{ help, example string }{
+	"pe_1": {
+		"Describe the pain your project removes, for whom, and what happens today without it.",
+		"Small accounting firms lose track of unpaid invoices spread across email and spreadsheets.",
+	},
+	"pe_2": {
+		"Name the people who will use it day to day, and anyone else who depends on it.",
+		"Bookkeepers at firms of 2-20 people, plus their clients who pay invoices.",
+	},
+	"pe_3": {
+		"List the measurable outcomes that would tell you the project worked.",
+		"Overdue invoices drop by 30% within three months; weekly active users above 80%.",
+	},
+	"pe_4": {
+		"Say in one or two sentences why users would choose this over what they do now.",
+		"One place to see every outstanding invoice, with automatic reminders to clients.",
+	},
+	"tc_1": {
+		"Name the languages you want to build in, or that your team already knows.",
+		"Go for the backend, TypeScript with React for the web app.",
+	},
+	"tc_2": {
+		"Give response time, throughput or latency targets for the parts that matter.",
+		"API responses under 200ms at the 95th percentile; reports may take up to 10 seconds.",
+	},
+	"tc_3": {
+		"Estimate users, requests and data volume at launch and after a year.",
+		"500 users and 10k requests a day at launch, growing to 5,000 users within a year.",
+	},
+	"tc_4": {
+		"Mention any regulations or standards the data or process must meet, or say none.",
+		"GDPR, since we store EU client contact details.",
+	},
+	"ip_1": {
+		"List the third-party services the project calls or is called by.",
+		"Stripe for payments, SendGrid for reminder emails, QuickBooks for import.",
+	},
+	"ip_2": {
+		"Say which database you want, or the kind of data and access patterns if unsure.",
+		"PostgreSQL; mostly relational data with some full-text search.",
+	},
+	"ip_3": {
+		"Describe how users sign in and how services authenticate to each other.",
+		"Email and password with optional Google sign-in; JWTs between services.",
+	},
+	"ip_4": {
+		"Point to any code, services or data this must fit alongside, or say it is new.",
+		"A Rails admin app that shares the customers table.",
+	},
+	"sd_1": {
+		"List the smallest set of features worth releasing.",
+		"Import invoices, see what is overdue, send reminder emails.",
+	},
+	"sd_2": {
+		"Give the dates or durations you are working to, including any hard deadlines.",
+		"Beta in six weeks, public launch before the end of the quarter.",
+	},
+	"sd_3": {
+		"Describe the people, budget and tools available.",
+		"Two developers part time and a $200 monthly hosting budget.",
+	},
+	"sd_4": {
+		"Explain how you decide what comes first when everything can't fit.",
+		"Whatever reduces overdue invoices fastest; nice-to-haves wait for feedback.",
+	},
+	"rv_1": {
+		"Check the summary for anything wrong or missing, and say what to change.",
+		"Yes, except the timeline should be eight weeks rather than six.",
+	},
+}
+
+// withQuestionHelp fills in help text and examples for built-in questions
+// that don't carry their own
+func withQuestionHelp(questions []Question) []Question {
+	for i, q := range questions {
+		help, ok := questionHelp[q.ID]
+		if !ok {
+			continue
+		}
+		if q.HelpText == "" {
+			questions[i].HelpText = help.help
+		}
+		if q.Example == "" {
+			questions[i].Example = help.example
+		}
+	}
+	return questions
+}
+
+// GetQuestionHelp returns the help text and example answer for a built-in
+// question
+func (e *Engine) GetQuestionHelp(questionID string) (help, example string, err error) {
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			if q.ID == questionID {
+				return q.HelpText, q.Example, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("question not found: %s", questionID)
+}
@@ -0,0 +1,86 @@
+package interview
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the locale the built-in questions are written in
+const DefaultLanguage = "en"
+
+// questionTranslations holds question text by locale and question ID.
+// Questions missing from a locale keep their English text.
+var questionTranslations = map[string]map[string]string{
+	"es": {
+		"pe_1": "¿Qué problema resuelve tu proyecto?",
+		"pe_2": "¿Quiénes son los usuarios objetivo?",
+		"pe_3": "¿Cuáles son las métricas clave de éxito?",
+		"pe_4": "¿Cuál es la propuesta de valor principal?",
+		"tc_1": "¿Qué lenguaje(s) de programación prefieres?",
+		"tc_2": "¿Cuáles son los requisitos de rendimiento?",
+		"tc_3": "¿Qué escala esperas (usuarios, peticiones, datos)?",
+		"tc_4": "¿Hay requisitos de cumplimiento normativo (GDPR, HIPAA, etc.)?",
+		"ip_1": "¿Con qué APIs externas te integrarás?",
+		"ip_2": "¿Qué tipo de base de datos necesitas?",
+		"ip_3": "¿Qué método de autenticación usarás?",
+		"ip_4": "¿Hay un código existente con el que integrarse?",
+		"sd_1": "¿Cuáles son las funcionalidades del MVP?",
+		"sd_2": "¿Cuál es tu calendario?",
+		"sd_3": "¿Cuáles son tus limitaciones de recursos?",
+		"sd_4": "¿Cómo priorizas las funcionalidades?",
+		"rv_1": "Revisa el resumen. ¿Está todo correcto?",
+	},
+}
+
+// SupportedLanguages returns the default locale followed by the locales
+// with translated questions, sorted
+func SupportedLanguages() []string {
+	var translated []string
+	for locale := range questionTranslations {
+		translated = append(translated, locale)
+	}
+	sort.Strings(translated)
+	return append([]string{DefaultLanguage}, translated...)
+}
+
+// SetLanguage switches the locale of question text. Regional variants such
+// as "es-MX" fall back to their base language, and unknown locales to
+// English. Question IDs are the same in every locale.
+func (e *Engine) SetLanguage(locale string) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	locale = strings.ReplaceAll(locale, "_", "-")
+	if _, ok := questionTranslations[locale]; !ok {
+		if base, _, found := strings.Cut(locale, "-"); found {
+			if _, ok := questionTranslations[base]; ok {
+				locale = base
+			}
+		}
+	}
+	if locale == "" {
+		locale = DefaultLanguage
+	}
+	e.language = locale
+}
+
+// Language returns the engine's question locale
+func (e *Engine) Language() string {
+	if e.language == "" {
+		return DefaultLanguage
+	}
+	return e.language
+}
+
+// localizeQuestions replaces question text with the engine locale's
+// translation where one exists
+func (e *Engine) localizeQuestions(questions []Question) []Question {
+	translations, ok := questionTranslations[e.Language()]
+	if !ok {
+		return questions
+	}
+	for i, q := range questions {
+		if text, ok := translations[q.ID]; ok {
+			questions[i].Text = text
+		}
+	}
+	return questions
+}
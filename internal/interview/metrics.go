@@ -0,0 +1,132 @@
+package interview
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// listMarker matches a bullet or "1." / "1)" numbering at the start of a
+// line, but not digits that belong to the item itself
+var listMarker = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s+`)
+
+// trimListMarker strips surrounding space and any list marker from a line
+// of model output
+func trimListMarker(line string) string {
+	return strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+}
+
+// maxSuggestedMetrics caps how many success metrics are suggested at once
+const maxSuggestedMetrics = 5
+
+// metricCategory is a kind of success metric offered without a provider
+// when the problem statement or value proposition mentions its keywords
+type metricCategory struct {
+	keywords []string
+	metrics  []string
+}
+
+// metricCategories are matched in order, ahead of the general metrics
+var metricCategories = []metricCategory{
+	{
+		keywords: []string{"sell", "sale", "revenue", "store", "shop", "pay", "subscription", "customer"},
+		metrics: []string{
+			"Monthly revenue or conversion rate reaches a target",
+			"Customer churn stays below a set percentage per month",
+		},
+	},
+	{
+		keywords: []string{"slow", "fast", "speed", "latency", "performance", "time", "manual", "automate"},
+		metrics: []string{
+			"Time to complete the core task drops by a target percentage",
+			"95th percentile response time stays under a set number of milliseconds",
+		},
+	},
+	{
+		keywords: []string{"error", "mistake", "bug", "quality", "accurate", "reliable"},
+		metrics: []string{
+			"Error rate in the core workflow falls below a target percentage",
+		},
+	},
+	{
+		keywords: []string{"team", "collaborat", "share", "community", "social"},
+		metrics: []string{
+			"Share of users who invite or collaborate with others each week",
+		},
+	},
+}
+
+// generalMetrics apply to almost any project and fill out suggestions when
+// few categories match
+var generalMetrics = []string{
+	"Number of weekly active users reaches a target",
+	"Share of new users who complete the core task in their first session",
+	"Uptime of at least 99.9% per month",
+}
+
+// SuggestSuccessMetrics proposes measurable success metrics for pe_3 based
+// on the answers to the problem statement (pe_1) and value proposition
+// (pe_4), for users who aren't sure what to measure. Without a provider,
+// metrics are picked from categories whose keywords appear in those answers.
+func (e *Engine) SuggestSuccessMetrics(session *InterviewSession) ([]string, error) {
+	problem := strings.TrimSpace(session.Answers["pe_1"].Text)
+	value := strings.TrimSpace(session.Answers["pe_4"].Text)
+
+	if e.provider == nil {
+		return categoryMetricsFor(problem + " " + value), nil
+	}
+
+	prompt := fmt.Sprintf(`You are helping define success metrics for a software project. Suggest up to %d specific, measurable success metrics, each with a number or threshold to aim for.
+
+Problem statement: %s
+Value proposition: %s
+
+List one metric per line with no numbering or extra text.`, maxSuggestedMetrics, problem, value)
+
+	response, err := e.callProvider(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest success metrics: %w", err)
+	}
+
+	metrics := []string{}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(response.Content, "\n") {
+		metric := trimListMarker(line)
+		key := strings.ToLower(metric)
+		if metric == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		metrics = append(metrics, metric)
+		if len(metrics) == maxSuggestedMetrics {
+			break
+		}
+	}
+
+	if len(metrics) == 0 {
+		return categoryMetricsFor(problem + " " + value), nil
+	}
+	return metrics, nil
+}
+
+// categoryMetricsFor returns metrics from the categories matching text,
+// topped up with general metrics and capped at maxSuggestedMetrics
+func categoryMetricsFor(text string) []string {
+	normalized := strings.ToLower(text)
+
+	metrics := []string{}
+	for _, category := range metricCategories {
+		for _, keyword := range category.keywords {
+			if strings.Contains(normalized, keyword) {
+				metrics = append(metrics, category.metrics...)
+				break
+			}
+		}
+	}
+	metrics = append(metrics, generalMetrics...)
+
+	if len(metrics) > maxSuggestedMetrics {
+		metrics = metrics[:maxSuggestedMetrics]
+	}
+	return metrics
+}
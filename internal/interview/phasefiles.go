@@ -0,0 +1,79 @@
+package interview
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExportPhasesToFiles writes one markdown file per phase into dir, named
+// after the phase (project-essence.md, technical-constraints.md, ...), with
+// that phase's answers, follow-ups and revisions. The directory is created
+// if needed and phases with no answers get no file.
+func (e *Engine) ExportPhasesToFiles(session *InterviewSession, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	for _, phase := range e.GetAllPhases() {
+		content, ok := e.formatPhaseMarkdown(session, phase)
+		if !ok {
+			continue
+		}
+		path := filepath.Join(dir, phaseFileName(phase))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// phaseFileName converts a phase constant to its export file name
+func phaseFileName(phase Phase) string {
+	return strings.ReplaceAll(string(phase), "_", "-") + ".md"
+}
+
+// formatPhaseMarkdown renders the answered questions of one phase, and
+// reports false when the phase has none
+func (e *Engine) formatPhaseMarkdown(session *InterviewSession, phase Phase) (string, bool) {
+	var sb strings.Builder
+	answered := 0
+
+	fmt.Fprintf(&sb, "# %s\n\n", formatPhaseName(phase))
+	fmt.Fprintf(&sb, "**Project ID:** %s\n\n", session.ProjectID)
+
+	for _, q := range e.GetPhaseQuestions(phase) {
+		answer, ok := session.Answers[q.ID]
+		if !ok {
+			continue
+		}
+		answered++
+
+		fmt.Fprintf(&sb, "## %s\n\n", q.Text)
+		fmt.Fprintf(&sb, "%s\n\n", answer.Text)
+
+		if followUps := session.FollowUpAnswers[q.ID]; len(followUps) > 0 {
+			sb.WriteString("### Follow-ups\n\n")
+			for _, fu := range followUps {
+				fmt.Fprintf(&sb, "- %s\n", fu.Text)
+			}
+			sb.WriteString("\n")
+		}
+
+		if iterations := e.GetIterationHistory(session, q.ID); len(iterations) > 0 {
+			sb.WriteString("### Revisions\n\n")
+			for _, iter := range iterations {
+				fmt.Fprintf(&sb, "- %s: changed from %q", iter.Timestamp.Format("2006-01-02 15:04"), iter.OldAnswer)
+				if iter.Reason != "" {
+					fmt.Fprintf(&sb, " (%s)", iter.Reason)
+				}
+				sb.WriteString("\n")
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String(), answered > 0
+}
@@ -0,0 +1,88 @@
+package interview
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// GenerateElevatorPitch produces a 2-3 sentence pitch from the problem
+// statement, target users and value proposition answers. With a provider
+// the pitch is written by the LLM, otherwise the answers are composed into
+// a template. The pitch is kept on the session so SaveSession stores it
+// with the interview data for the architecture prompt.
+func (e *Engine) GenerateElevatorPitch(session *InterviewSession) (string, error) {
+	problem := strings.TrimSpace(session.Answers["pe_1"].Text)
+	users := strings.TrimSpace(session.Answers["pe_2"].Text)
+	value := strings.TrimSpace(session.Answers["pe_4"].Text)
+	if problem == "" && users == "" && value == "" {
+		return "", fmt.Errorf("no problem statement, target users or value proposition answered")
+	}
+
+	var pitch string
+	if e.provider != nil {
+		prompt := fmt.Sprintf(`Write a 2-3 sentence elevator pitch for a software project, suitable for sharing with someone who knows nothing about it.
+
+Problem: %s
+Target users: %s
+Value proposition: %s
+
+Respond with the pitch only, as plain prose.`, problem, users, value)
+
+		response, err := e.callProvider(prompt)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate elevator pitch: %w", err)
+		}
+		pitch = strings.TrimSpace(response.Content)
+	} else {
+		pitch = templateElevatorPitch(problem, users, value)
+	}
+
+	session.ElevatorPitch = pitch
+	return pitch, nil
+}
+
+// templateElevatorPitch composes the answers into up to three sentences,
+// skipping any that are empty
+func templateElevatorPitch(problem, users, value string) string {
+	var sentences []string
+	if problem != "" {
+		sentences = append(sentences, asSentence(problem))
+	}
+	if users != "" {
+		sentences = append(sentences, "Built for "+endSentence(lowerFirst(users)))
+	}
+	if value != "" {
+		sentences = append(sentences, asSentence(value))
+	}
+	return strings.Join(sentences, " ")
+}
+
+// asSentence capitalizes text and ends it with punctuation if it has none
+func asSentence(text string) string {
+	runes := []rune(endSentence(text))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// endSentence ends text with a full stop unless it already has punctuation
+func endSentence(text string) string {
+	if !strings.ContainsAny(text[len(text)-1:], ".!?") {
+		text += "."
+	}
+	return text
+}
+
+// lowerFirst lowercases the first letter of text unless the first word looks
+// like a name or acronym, with another capital letter in it
+func lowerFirst(text string) string {
+	runes := []rune(text)
+	first, _, _ := strings.Cut(text, " ")
+	for _, r := range []rune(first)[1:] {
+		if unicode.IsUpper(r) {
+			return text
+		}
+	}
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
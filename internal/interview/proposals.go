@@ -0,0 +1,59 @@
+package interview
+
+import "strings"
+
+// divergentDefaultThreshold is the word-overlap ratio below which an answer
+// is treated as rejecting the default proposed for its question
+const divergentDefaultThreshold = 0.2
+
+// AcceptProposal records the answer given to a question whose default was
+// proposed by ProposeDefault. An empty answer accepts the proposal as is.
+// The proposal is kept on the session so DivergentDefaults can tell where
+// the user went their own way.
+func (e *Engine) AcceptProposal(session *InterviewSession, questionID, proposal, answer string) error {
+	proposal = strings.TrimSpace(proposal)
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		answer = proposal
+	}
+
+	if err := e.RecordAnswer(session, questionID, answer); err != nil {
+		return err
+	}
+	if proposal != "" {
+		recordProposal(session, questionID, proposal)
+	}
+	return nil
+}
+
+// recordProposal remembers the default proposed for a question
+func recordProposal(session *InterviewSession, questionID, proposal string) {
+	if session.ProposedDefaults == nil {
+		session.ProposedDefaults = make(map[string]string)
+	}
+	session.ProposedDefaults[questionID] = proposal
+}
+
+// DivergentDefaults returns, in interview order, the questions whose current
+// answer has little in common with the default proposed for them. Answers
+// are compared as they stand, so a proposal accepted at first and later
+// replaced through ReiterateAnswer is flagged too.
+func (e *Engine) DivergentDefaults(session *InterviewSession) []string {
+	var divergent []string
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			proposal, ok := session.ProposedDefaults[q.ID]
+			if !ok {
+				continue
+			}
+			answer, ok := session.Answers[q.ID]
+			if !ok {
+				continue
+			}
+			if wordSimilarity(answerWords(answer.Text), answerWords(proposal)) < divergentDefaultThreshold {
+				divergent = append(divergent, q.ID)
+			}
+		}
+	}
+	return divergent
+}
@@ -0,0 +1,56 @@
+package interview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mojomast/geoffrussy/internal/state"
+)
+
+// designRequiredQuestions are the answers architecture generation can't do
+// without: the problem, the users, the language and the database
+var designRequiredQuestions = []string{"pe_1", "pe_2", "tc_1", "ip_2"}
+
+// ReadyForDesign reports whether the session has the minimum answers needed
+// to generate a design, returning a blocker for each one missing or blank.
+// Unlike ValidateCompleteness it doesn't need every required question.
+func (e *Engine) ReadyForDesign(session *InterviewSession) (bool, []string) {
+	required := make(map[string]bool, len(designRequiredQuestions))
+	for _, id := range designRequiredQuestions {
+		required[id] = true
+	}
+
+	var blockers []string
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			if !required[q.ID] {
+				continue
+			}
+			if answer, ok := session.Answers[q.ID]; !ok || strings.TrimSpace(answer.Text) == "" {
+				blockers = append(blockers, fmt.Sprintf("%s: %s", formatPhaseName(phase), q.Text))
+			}
+		}
+	}
+
+	return len(blockers) == 0, blockers
+}
+
+// ReadyForDesignData checks saved interview data with ReadyForDesign. Data
+// without a raw session, such as data built by hand, can only be checked for
+// the problem statement and target users it carries directly.
+func ReadyForDesignData(data *state.InterviewData) (bool, []string) {
+	if data.RawSession != "" {
+		if session, err := decodeRawSession(data.ProjectID, data); err == nil {
+			return NewEngine(nil, nil, "").ReadyForDesign(session)
+		}
+	}
+
+	var blockers []string
+	if strings.TrimSpace(data.ProblemStatement) == "" {
+		blockers = append(blockers, "problem statement")
+	}
+	if len(data.TargetUsers) == 0 || strings.TrimSpace(strings.Join(data.TargetUsers, "")) == "" {
+		blockers = append(blockers, "target users")
+	}
+	return len(blockers) == 0, blockers
+}
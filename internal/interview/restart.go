@@ -0,0 +1,59 @@
+package interview
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ArchivedSession keeps the answers of an interview run that was restarted,
+// so they can be consulted or offered as defaults in the new run
+type ArchivedSession struct {
+	StartedAt  time.Time
+	ArchivedAt time.Time
+	Answers    map[string]Answer
+}
+
+// RestartInterview starts the project's interview over from the first
+// phase. The saved session's answers are archived in PreviousSessions,
+// along with any archived by earlier restarts, and the new session is
+// saved in its place.
+func (e *Engine) RestartInterview(projectID string) (*InterviewSession, error) {
+	if e.store == nil {
+		return nil, fmt.Errorf("store is required to restart an interview")
+	}
+
+	previous, err := e.LoadSession(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	session, err := e.StartInterview(projectID)
+	if err != nil {
+		return nil, err
+	}
+	session.PreviousSessions = previous.PreviousSessions
+	if len(previous.Answers) > 0 {
+		session.PreviousSessions = append(session.PreviousSessions, ArchivedSession{
+			StartedAt:  previous.StartedAt,
+			ArchivedAt: time.Now(),
+			Answers:    previous.Answers,
+		})
+	}
+
+	if err := e.SaveSession(session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+	return session, nil
+}
+
+// PreviousAnswer returns the most recent archived answer to a question, for
+// offering as a default after a restart
+func (e *Engine) PreviousAnswer(session *InterviewSession, questionID string) (string, bool) {
+	for i := len(session.PreviousSessions) - 1; i >= 0; i-- {
+		if answer, ok := session.PreviousSessions[i].Answers[questionID]; ok && strings.TrimSpace(answer.Text) != "" {
+			return answer.Text, true
+		}
+	}
+	return "", false
+}
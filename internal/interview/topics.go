@@ -0,0 +1,68 @@
+package interview
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxMissingTopics caps how many absent topics are suggested at once
+const maxMissingTopics = 5
+
+// SuggestMissingTopics asks the provider to review the answers and name
+// topics a project like this would normally need but the interview never
+// mentioned, such as payments for an online store. Without a provider no
+// topics are suggested, since spotting an absence needs the model's domain
+// knowledge.
+func (e *Engine) SuggestMissingTopics(session *InterviewSession) ([]string, error) {
+	if e.provider == nil {
+		return []string{}, nil
+	}
+
+	var answers strings.Builder
+	for _, phase := range e.GetAllPhases() {
+		for _, q := range e.GetPhaseQuestions(phase) {
+			if answer, ok := session.Answers[q.ID]; ok && strings.TrimSpace(answer.Text) != "" {
+				fmt.Fprintf(&answers, "Q: %s\nA: %s\n", q.Text, answer.Text)
+			}
+		}
+	}
+
+	prompt := fmt.Sprintf(`You are reviewing a software project's requirements interview. Based on the problem and target users, list up to %d topics this kind of project would normally have to address but the answers never mention, such as payments for an online store or accessibility for a public service. Only list conspicuous gaps.
+
+%s
+List one short topic per line with no numbering or extra text. If nothing important is missing, respond with NONE.`, maxMissingTopics, answers.String())
+
+	response, err := e.callProvider(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest missing topics: %w", err)
+	}
+
+	topics := []string{}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(response.Content, "\n") {
+		topic := trimListMarker(line)
+		key := strings.ToLower(topic)
+		if topic == "" || key == "none" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		topics = append(topics, topic)
+		if len(topics) == maxMissingTopics {
+			break
+		}
+	}
+	return topics, nil
+}
+
+// MissingTopicQuestions turns suggested topics into questions for a
+// sub-interview, one per topic
+func MissingTopicQuestions(topics []string) []Question {
+	questions := make([]Question, 0, len(topics))
+	for _, topic := range topics {
+		questions = append(questions, Question{
+			Text:     fmt.Sprintf("What are your requirements for %s?", topic),
+			Category: topic,
+		})
+	}
+	return questions
+}
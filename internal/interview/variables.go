@@ -0,0 +1,107 @@
+package interview
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// variableNamePattern matches names usable as {{name}} placeholders
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// placeholderPattern finds {{name}} and {{name | default "value"}}
+// placeholders, capturing the name and whether a default follows
+var placeholderPattern = regexp.MustCompile(`\{\{-?\s*([A-Za-z_][A-Za-z0-9_]*)\s*(\|\s*default\b)?`)
+
+// ApplyAnswerVariables fills {{name}} placeholders in the answers to
+// questionIDs from vars, so answers copied from a template project can be
+// specialised. With no questionIDs every answer is filled.
+// Answers are text/template templates: {{name | default "value"}} falls back
+// to value when name is unset or empty. A placeholder without a default for
+// a variable that isn't in vars is an error, and no answer is changed.
+func (e *Engine) ApplyAnswerVariables(session *InterviewSession, vars map[string]string, questionIDs ...string) error {
+	if session == nil {
+		return fmt.Errorf("session cannot be nil")
+	}
+
+	funcs := template.FuncMap{
+		"default": func(fallback, value string) string {
+			if value == "" {
+				return fallback
+			}
+			return value
+		},
+	}
+	for name, value := range vars {
+		if !variableNamePattern.MatchString(name) || name == "default" {
+			return fmt.Errorf("invalid answer variable name: %q", name)
+		}
+		funcs[name] = func() string { return value }
+	}
+
+	candidates := questionIDs
+	if len(candidates) == 0 {
+		candidates = make([]string, 0, len(session.Answers))
+		for id := range session.Answers {
+			candidates = append(candidates, id)
+		}
+	}
+	ids := make([]string, 0, len(candidates))
+	for _, id := range candidates {
+		if answer, ok := session.Answers[id]; ok && strings.Contains(answer.Text, "{{") {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	// Variables only ever used with a default may be left out of vars
+	undefined := make(map[string]bool)
+	for _, id := range ids {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(session.Answers[id].Text, -1) {
+			name, hasDefault := match[1], match[2] != ""
+			if _, ok := funcs[name]; ok {
+				continue
+			}
+			undefined[name] = undefined[name] || !hasDefault
+		}
+	}
+	var missing []string
+	for name, required := range undefined {
+		if required {
+			missing = append(missing, name)
+			continue
+		}
+		funcs[name] = func() string { return "" }
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("undefined answer variables: %s", strings.Join(missing, ", "))
+	}
+
+	// Render everything before changing anything
+	rendered := make(map[string]string, len(ids))
+	for _, id := range ids {
+		tmpl, err := template.New(id).Funcs(funcs).Parse(session.Answers[id].Text)
+		if err != nil {
+			return fmt.Errorf("failed to parse answer %s: %w", id, err)
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, nil); err != nil {
+			return fmt.Errorf("failed to fill answer %s: %w", id, err)
+		}
+		rendered[id] = sb.String()
+	}
+
+	for id, text := range rendered {
+		answer := session.Answers[id]
+		answer.Text = text
+		session.Answers[id] = answer
+	}
+	if len(rendered) > 0 {
+		session.LastUpdatedAt = time.Now()
+	}
+	return nil
+}
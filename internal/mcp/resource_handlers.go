@@ -367,7 +367,7 @@ func (h *ResourceHandlers) getStore() (*state.Store, string, error) {
 	}
 
 	dbPath := filepath.Join(projectPath, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to open state store: %w", err)
 	}
@@ -127,7 +127,7 @@ func (h *SimpleToolHandlers) handleGetStatus(ctx context.Context, args map[strin
 
 	// Open state store
 	dbPath := filepath.Join(projectPath, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("Failed to open state store: %v", err)), nil
 	}
@@ -171,7 +171,7 @@ func (h *SimpleToolHandlers) handleGetStats(ctx context.Context, args map[string
 
 	// Open state store
 	dbPath := filepath.Join(projectPath, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("Failed to open state store: %v", err)), nil
 	}
@@ -224,7 +224,7 @@ func (h *SimpleToolHandlers) handleListPhases(ctx context.Context, args map[stri
 
 	// Open state store
 	dbPath := filepath.Join(projectPath, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("Failed to open state store: %v", err)), nil
 	}
@@ -277,7 +277,7 @@ func (h *SimpleToolHandlers) handleCreateCheckpoint(ctx context.Context, args ma
 
 	// Open state store
 	dbPath := filepath.Join(projectPath, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("Failed to open state store: %v", err)), nil
 	}
@@ -310,7 +310,7 @@ func (h *SimpleToolHandlers) handleListCheckpoints(ctx context.Context, args map
 
 	// Open state store
 	dbPath := filepath.Join(projectPath, ".geoffrussy", "state.db")
-	store, err := state.NewStore(dbPath)
+	store, err := state.OpenStore(dbPath)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("Failed to open state store: %v", err)), nil
 	}
@@ -35,9 +35,10 @@ type anthropicRequest struct {
 	Model       string             `json:"model"`
 	Messages    []anthropicMessage `json:"messages"`
 	Stream      bool               `json:"stream,omitempty"`
-	Temperature float64            `json:"temperature,omitempty"`
+	Temperature *float64           `json:"temperature,omitempty"`
 	MaxTokens   int                `json:"max_tokens"`
-	System      string             `json:"system,omitempty"`
+	TopP        float64            `json:"top_p,omitempty"`
+	System      []anthropicBlock   `json:"system,omitempty"`
 }
 
 type anthropicMessage struct {
@@ -45,6 +46,17 @@ type anthropicMessage struct {
 	Content string `json:"content"`
 }
 
+// anthropicBlock is a text content block, optionally marked for prompt caching
+type anthropicBlock struct {
+	Type         string                 `json:"type"`
+	Text         string                 `json:"text"`
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
+}
+
+type anthropicCacheControl struct {
+	Type string `json:"type"`
+}
+
 // anthropicResponse represents a response from Anthropic API
 type anthropicResponse struct {
 	ID      string `json:"id"`
@@ -54,13 +66,50 @@ type anthropicResponse struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"content"`
-	Model        string `json:"model"`
-	StopReason   string `json:"stop_reason"`
-	StopSequence string `json:"stop_sequence"`
-	Usage        struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
+	Model        string         `json:"model"`
+	StopReason   string         `json:"stop_reason"`
+	StopSequence string         `json:"stop_sequence"`
+	Usage        anthropicUsage `json:"usage"`
+}
+
+// anthropicUsage reports token counts, including prompt cache activity
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// anthropicToolRequest is a messages request offering tools
+type anthropicToolRequest struct {
+	Model       string             `json:"model"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	TopP        float64            `json:"top_p,omitempty"`
+	System      []anthropicBlock   `json:"system,omitempty"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+// anthropicToolResponse is a messages response whose content may include
+// tool_use blocks
+type anthropicToolResponse struct {
+	Content []struct {
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		ID    string          `json:"id"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
+	} `json:"content"`
+	Model      string         `json:"model"`
+	StopReason string         `json:"stop_reason"`
+	Usage      anthropicUsage `json:"usage"`
 }
 
 // anthropicStreamChunk represents a streaming response chunk
@@ -83,7 +132,9 @@ type anthropicStreamChunk struct {
 }
 
 // ListModels returns the list of available models from Anthropic
-func (a *AnthropicProvider) ListModels() ([]Model, error) {
+func (a *AnthropicProvider) ListModels() (_ []Model, err error) {
+	defer func() { err = a.RedactError(err) }()
+
 	if !a.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -91,58 +142,82 @@ func (a *AnthropicProvider) ListModels() ([]Model, error) {
 	// Anthropic doesn't have a models endpoint, so we return known models
 	models := []Model{
 		{
-			Provider:     "anthropic",
-			Name:         "claude-3-5-sonnet-20241022",
-			DisplayName:  "Claude 3.5 Sonnet (Latest)",
-			Capabilities: []string{"text", "vision", "streaming"},
-			PriceInput:   3.0,  // $3 per 1M tokens
-			PriceOutput:  15.0, // $15 per 1M tokens
+			Provider:      "anthropic",
+			Name:          "claude-3-5-sonnet-20241022",
+			DisplayName:   "Claude 3.5 Sonnet (Latest)",
+			Capabilities:  []string{"text", "vision", "streaming"},
+			PriceInput:    3.0,  // $3 per 1M tokens
+			PriceOutput:   15.0, // $15 per 1M tokens
+			ContextWindow: 200000,
 		},
 		{
-			Provider:     "anthropic",
-			Name:         "claude-3-5-haiku-20241022",
-			DisplayName:  "Claude 3.5 Haiku (Latest)",
-			Capabilities: []string{"text", "vision", "streaming"},
-			PriceInput:   1.0, // $1 per 1M tokens
-			PriceOutput:  5.0, // $5 per 1M tokens
+			Provider:      "anthropic",
+			Name:          "claude-3-5-haiku-20241022",
+			DisplayName:   "Claude 3.5 Haiku (Latest)",
+			Capabilities:  []string{"text", "vision", "streaming"},
+			PriceInput:    1.0, // $1 per 1M tokens
+			PriceOutput:   5.0, // $5 per 1M tokens
+			ContextWindow: 200000,
 		},
 		{
-			Provider:     "anthropic",
-			Name:         "claude-3-opus-20240229",
-			DisplayName:  "Claude 3 Opus",
-			Capabilities: []string{"text", "vision", "streaming"},
-			PriceInput:   15.0, // $15 per 1M tokens
-			PriceOutput:  75.0, // $75 per 1M tokens
+			Provider:      "anthropic",
+			Name:          "claude-3-opus-20240229",
+			DisplayName:   "Claude 3 Opus",
+			Capabilities:  []string{"text", "vision", "streaming"},
+			PriceInput:    15.0, // $15 per 1M tokens
+			PriceOutput:   75.0, // $75 per 1M tokens
+			ContextWindow: 200000,
 		},
 		{
-			Provider:     "anthropic",
-			Name:         "claude-3-sonnet-20240229",
-			DisplayName:  "Claude 3 Sonnet",
-			Capabilities: []string{"text", "vision", "streaming"},
-			PriceInput:   3.0,  // $3 per 1M tokens
-			PriceOutput:  15.0, // $15 per 1M tokens
+			Provider:      "anthropic",
+			Name:          "claude-3-sonnet-20240229",
+			DisplayName:   "Claude 3 Sonnet",
+			Capabilities:  []string{"text", "vision", "streaming"},
+			PriceInput:    3.0,  // $3 per 1M tokens
+			PriceOutput:   15.0, // $15 per 1M tokens
+			ContextWindow: 200000,
 		},
 		{
-			Provider:     "anthropic",
-			Name:         "claude-3-haiku-20240307",
-			DisplayName:  "Claude 3 Haiku",
-			Capabilities: []string{"text", "vision", "streaming"},
-			PriceInput:   0.25, // $0.25 per 1M tokens
-			PriceOutput:  1.25, // $1.25 per 1M tokens
+			Provider:      "anthropic",
+			Name:          "claude-3-haiku-20240307",
+			DisplayName:   "Claude 3 Haiku",
+			Capabilities:  []string{"text", "vision", "streaming"},
+			PriceInput:    0.25, // $0.25 per 1M tokens
+			PriceOutput:   1.25, // $1.25 per 1M tokens
+			ContextWindow: 200000,
 		},
 	}
 
 	return models, nil
 }
 
-// Call makes a non-streaming API call to Anthropic
+// setTransport routes the provider's HTTP requests through rt
+func (a *AnthropicProvider) setTransport(rt http.RoundTripper) {
+	a.httpClient.Transport = rt
+}
+
+// Call makes a non-streaming API call to Anthropic using the default options
 func (a *AnthropicProvider) Call(model string, prompt string) (*Response, error) {
+	return a.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions makes a non-streaming API call to Anthropic with per-call sampling options
+func (a *AnthropicProvider) CallWithOptions(model string, prompt string, opts CallOptions) (_ *Response, err error) {
+	defer func() { err = a.RedactError(err) }()
+
 	if !a.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
 
+	if prompt, err = fitContext(model, prompt, opts); err != nil {
+		return nil, err
+	}
+
+	opts = opts.withDefaults(CallOptions{Temperature: Float64(0.7), MaxTokens: 4096})
+
+	start := time.Now()
 	var response *Response
-	err := a.RetryWithBackoff(func() error {
+	err = a.RetryWithBackoff(func() error {
 		req := anthropicRequest{
 			Model: model,
 			Messages: []anthropicMessage{
@@ -151,8 +226,16 @@ func (a *AnthropicProvider) Call(model string, prompt string) (*Response, error)
 					Content: prompt,
 				},
 			},
-			MaxTokens:   4096,
-			Temperature: 0.7,
+			MaxTokens:   opts.MaxTokens,
+			Temperature: opts.Temperature,
+			TopP:        opts.TopP,
+		}
+		if opts.System != "" {
+			req.System = []anthropicBlock{{
+				Type:         "text",
+				Text:         opts.System,
+				CacheControl: &anthropicCacheControl{Type: "ephemeral"},
+			}}
 		}
 
 		jsonData, err := json.Marshal(req)
@@ -177,7 +260,7 @@ func (a *AnthropicProvider) Call(model string, prompt string) (*Response, error)
 
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
 		}
 
 		var anthropicResp anthropicResponse
@@ -209,16 +292,130 @@ func (a *AnthropicProvider) Call(model string, prompt string) (*Response, error)
 			Provider:           "anthropic",
 			Timestamp:          time.Now(),
 			RateLimitRemaining: rateLimitRemaining,
+			FinishReason:       normalizeFinishReason(anthropicResp.StopReason),
+			CacheReadTokens:    anthropicResp.Usage.CacheReadInputTokens,
+			CacheWriteTokens:   anthropicResp.Usage.CacheCreationInputTokens,
 		}
 
 		return nil
 	})
 
+	if response != nil {
+		response.Latency = time.Since(start)
+	}
+	return response, err
+}
+
+// CallWithTools makes a messages call offering tools. Tool calls the model
+// makes are returned in Response.ToolCalls with their input as JSON.
+func (a *AnthropicProvider) CallWithTools(model string, messages []Message, tools []Tool, opts CallOptions) (_ *Response, err error) {
+	defer func() { err = a.RedactError(err) }()
+
+	if !a.IsAuthenticated() {
+		return nil, fmt.Errorf("provider not authenticated")
+	}
+
+	if messages, err = fitMessages(model, messages, tools, opts); err != nil {
+		return nil, err
+	}
+
+	opts = opts.withDefaults(CallOptions{Temperature: Float64(0.7), MaxTokens: 4096})
+
+	system, conversation := splitSystemMessages(withSystemOption(messages, opts))
+	req := anthropicToolRequest{
+		Model:       model,
+		MaxTokens:   opts.MaxTokens,
+		Temperature: opts.Temperature,
+		TopP:        opts.TopP,
+	}
+	for _, m := range conversation {
+		req.Messages = append(req.Messages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+	if system != "" {
+		req.System = []anthropicBlock{{
+			Type:         "text",
+			Text:         system,
+			CacheControl: &anthropicCacheControl{Type: "ephemeral"},
+		}}
+	}
+	for _, tool := range tools {
+		schema := tool.Parameters
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object"}
+		}
+		req.Tools = append(req.Tools, anthropicTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: schema,
+		})
+	}
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	start := time.Now()
+	var response *Response
+	err = a.RetryWithBackoff(func() error {
+		httpReq, err := http.NewRequest("POST", a.baseURL+"/messages", bytes.NewBuffer(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("x-api-key", a.GetAPIKey())
+		httpReq.Header.Set("anthropic-version", "2023-06-01")
+
+		resp, err := a.httpClient.Do(httpReq)
+		if err != nil {
+			return fmt.Errorf("failed to make request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
+		}
+
+		var toolResp anthropicToolResponse
+		if err := json.NewDecoder(resp.Body).Decode(&toolResp); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		response = &Response{
+			TokensInput:  toolResp.Usage.InputTokens,
+			TokensOutput: toolResp.Usage.OutputTokens,
+			Model:        toolResp.Model,
+			Provider:     "anthropic",
+			Timestamp:    time.Now(),
+			FinishReason: normalizeFinishReason(toolResp.StopReason),
+		}
+		for _, block := range toolResp.Content {
+			switch block.Type {
+			case "text":
+				response.Content += block.Text
+			case "tool_use":
+				response.ToolCalls = append(response.ToolCalls, ToolCall{
+					ID:        block.ID,
+					Name:      block.Name,
+					Arguments: string(block.Input),
+				})
+			}
+		}
+		return nil
+	})
+
+	if response != nil {
+		response.Latency = time.Since(start)
+	}
 	return response, err
 }
 
 // Stream makes a streaming API call to Anthropic
-func (a *AnthropicProvider) Stream(model string, prompt string) (<-chan string, error) {
+func (a *AnthropicProvider) Stream(model string, prompt string) (_ <-chan string, err error) {
+	defer func() { err = a.RedactError(err) }()
+
 	if !a.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -233,7 +430,7 @@ func (a *AnthropicProvider) Stream(model string, prompt string) (<-chan string,
 		},
 		Stream:      true,
 		MaxTokens:   4096,
-		Temperature: 0.7,
+		Temperature: Float64(0.7),
 	}
 
 	jsonData, err := json.Marshal(req)
@@ -258,7 +455,7 @@ func (a *AnthropicProvider) Stream(model string, prompt string) (<-chan string,
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	ch := make(chan string, 100)
@@ -302,7 +499,9 @@ func (a *AnthropicProvider) Stream(model string, prompt string) (<-chan string,
 }
 
 // GetRateLimitInfo returns rate limit information from Anthropic
-func (a *AnthropicProvider) GetRateLimitInfo() (*RateLimitInfo, error) {
+func (a *AnthropicProvider) GetRateLimitInfo() (_ *RateLimitInfo, err error) {
+	defer func() { err = a.RedactError(err) }()
+
 	if !a.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -370,7 +569,9 @@ func (a *AnthropicProvider) GetRateLimitInfo() (*RateLimitInfo, error) {
 }
 
 // GetQuotaInfo returns quota information from Anthropic
-func (a *AnthropicProvider) GetQuotaInfo() (*QuotaInfo, error) {
+func (a *AnthropicProvider) GetQuotaInfo() (_ *QuotaInfo, err error) {
+	defer func() { err = a.RedactError(err) }()
+
 	if !a.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -430,3 +631,8 @@ func (a *AnthropicProvider) GetQuotaInfo() (*QuotaInfo, error) {
 
 	return info, nil
 }
+
+// Ping checks that the provider is reachable and the API key is accepted
+func (a *AnthropicProvider) Ping(model string) error {
+	return PingWithCall(a, model)
+}
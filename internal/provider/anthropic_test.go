@@ -114,10 +114,7 @@ func TestAnthropicProvider_Call(t *testing.T) {
 			},
 			Model:      "claude-3-haiku-20240307",
 			StopReason: "end_turn",
-			Usage: struct {
-				InputTokens  int `json:"input_tokens"`
-				OutputTokens int `json:"output_tokens"`
-			}{
+			Usage: anthropicUsage{
 				InputTokens:  10,
 				OutputTokens: 20,
 			},
@@ -160,6 +157,190 @@ func TestAnthropicProvider_Call(t *testing.T) {
 	}
 }
 
+func TestAnthropicProvider_CallWithOptions(t *testing.T) {
+	var requests []anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		requests = append(requests, req)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"content": [{"type": "text", "text": "ok"}], "usage": {"input_tokens": 1, "output_tokens": 1}}`))
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider()
+	provider.baseURL = server.URL
+	provider.Authenticate("test-api-key")
+
+	opts := CallOptions{Temperature: Float64(0.2), MaxTokens: 512, TopP: 0.9}
+	if _, err := provider.CallWithOptions("claude-3-haiku-20240307", "Hello", opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := provider.Call("claude-3-haiku-20240307", "Hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := provider.CallWithOptions("claude-3-haiku-20240307", "Hello", CallOptions{Temperature: Float64(0)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	if got := requests[0]; got.Temperature == nil || *got.Temperature != 0.2 || got.MaxTokens != 512 || got.TopP != 0.9 {
+		t.Errorf("expected options to be forwarded, got temperature=%v max_tokens=%d top_p=%v", got.Temperature, got.MaxTokens, got.TopP)
+	}
+	if got := requests[1]; got.Temperature == nil || *got.Temperature != 0.7 || got.MaxTokens != 4096 || got.TopP != 0 {
+		t.Errorf("expected Call to use defaults, got temperature=%v max_tokens=%d top_p=%v", got.Temperature, got.MaxTokens, got.TopP)
+	}
+	if got := requests[2]; got.Temperature == nil || *got.Temperature != 0 {
+		t.Errorf("expected an explicit zero temperature to be sent, got %v", got.Temperature)
+	}
+}
+
+func TestAnthropicProvider_FinishReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"content": [{"type": "text", "text": "[{\"number\": 0, \"ti"}], "stop_reason": "max_tokens", "usage": {"input_tokens": 10, "output_tokens": 16}}`))
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider()
+	provider.baseURL = server.URL
+	provider.Authenticate("test-api-key")
+
+	resp, err := provider.CallWithOptions("claude-3-haiku-20240307", "Plan the project", CallOptions{MaxTokens: 16})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.FinishReason != FinishReasonLength || !resp.Truncated() {
+		t.Errorf("expected finish reason %q, got %q", FinishReasonLength, resp.FinishReason)
+	}
+	if resp.Latency <= 0 {
+		t.Errorf("expected latency to be recorded, got %v", resp.Latency)
+	}
+}
+
+func TestAnthropicProvider_SystemPromptCaching(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"content": [{"type": "text", "text": "ok"}], "usage": {"input_tokens": 5, "output_tokens": 1, "cache_creation_input_tokens": 0, "cache_read_input_tokens": 1200}}`))
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider()
+	provider.baseURL = server.URL
+	provider.Authenticate("test-api-key")
+
+	preamble := "You are a software architect. Follow the output format exactly."
+	resp, err := provider.CallWithOptions("claude-3-haiku-20240307", "Design a task tracker", CallOptions{System: preamble})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	system, ok := body["system"].([]interface{})
+	if !ok || len(system) != 1 {
+		t.Fatalf("expected one system block, got %v", body["system"])
+	}
+	block := system[0].(map[string]interface{})
+	if block["text"] != preamble {
+		t.Errorf("expected system text %q, got %v", preamble, block["text"])
+	}
+	if cache, _ := block["cache_control"].(map[string]interface{}); cache["type"] != "ephemeral" {
+		t.Errorf("expected system block to be cacheable, got %v", block["cache_control"])
+	}
+
+	messages := body["messages"].([]interface{})
+	if len(messages) != 1 || messages[0].(map[string]interface{})["content"] != "Design a task tracker" {
+		t.Errorf("expected only the prompt in messages, got %v", messages)
+	}
+
+	if resp.CacheReadTokens != 1200 || resp.CacheWriteTokens != 0 {
+		t.Errorf("expected 1200 cache read and 0 cache write tokens, got %d and %d", resp.CacheReadTokens, resp.CacheWriteTokens)
+	}
+}
+
+func TestAnthropicProvider_CallWithTools(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"model": "claude-3-haiku-20240307",
+			"content": [
+				{"type": "text", "text": "Submitting"},
+				{"type": "tool_use", "id": "toolu_1", "name": "submit_phase", "input": {"number": 0}}
+			],
+			"stop_reason": "max_tokens",
+			"usage": {"input_tokens": 20, "output_tokens": 16384}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider()
+	provider.baseURL = server.URL
+	provider.Authenticate("test-api-key")
+
+	tools := []Tool{{Name: "submit_phase", Description: "Submit a phase"}}
+	messages := []Message{
+		{Role: "system", Content: "Use the tool"},
+		{Role: "user", Content: "Plan it"},
+	}
+	resp, err := provider.CallWithTools("claude-3-haiku-20240307", messages, tools, CallOptions{MaxTokens: 16384, Temperature: Float64(0.2), System: "You are a planner."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if body["max_tokens"] != float64(16384) {
+		t.Errorf("expected max_tokens 16384, got %v", body["max_tokens"])
+	}
+	if body["temperature"] != 0.2 {
+		t.Errorf("expected temperature 0.2, got %v", body["temperature"])
+	}
+	system, _ := body["system"].([]interface{})
+	if len(system) != 1 || system[0].(map[string]interface{})["text"] != "You are a planner.\n\nUse the tool" {
+		t.Errorf("expected the system option ahead of the system message, got %v", body["system"])
+	}
+	if len(system) == 1 && system[0].(map[string]interface{})["cache_control"] == nil {
+		t.Errorf("expected the system block to be cacheable, got %v", system[0])
+	}
+	if messages, _ := body["messages"].([]interface{}); len(messages) != 1 {
+		t.Errorf("expected only the user message in messages, got %v", body["messages"])
+	}
+	sentTools, _ := body["tools"].([]interface{})
+	if len(sentTools) != 1 || sentTools[0].(map[string]interface{})["name"] != "submit_phase" {
+		t.Errorf("expected submit_phase tool in request, got %v", body["tools"])
+	}
+
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Name != "submit_phase" || resp.ToolCalls[0].Arguments != `{"number": 0}` {
+		t.Errorf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+	if resp.Content != "Submitting" {
+		t.Errorf("expected text content 'Submitting', got %q", resp.Content)
+	}
+	if !resp.Truncated() {
+		t.Errorf("expected a max_tokens stop to be reported as truncated, got %q", resp.FinishReason)
+	}
+
+	// Without options the provider defaults apply
+	if _, err := provider.CallWithTools("claude-3-haiku-20240307", messages, tools, CallOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body["max_tokens"] != float64(4096) {
+		t.Errorf("expected default max_tokens 4096, got %v", body["max_tokens"])
+	}
+}
+
 func TestAnthropicProvider_Stream(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -250,10 +431,7 @@ func TestAnthropicProvider_GetRateLimitInfo(t *testing.T) {
 				{Type: "text", Text: "Hi"},
 			},
 			Model: "claude-3-haiku-20240307",
-			Usage: struct {
-				InputTokens  int `json:"input_tokens"`
-				OutputTokens int `json:"output_tokens"`
-			}{
+			Usage: anthropicUsage{
 				InputTokens:  1,
 				OutputTokens: 1,
 			},
@@ -307,10 +485,7 @@ func TestAnthropicProvider_GetQuotaInfo(t *testing.T) {
 				{Type: "text", Text: "Hi"},
 			},
 			Model: "claude-3-haiku-20240307",
-			Usage: struct {
-				InputTokens  int `json:"input_tokens"`
-				OutputTokens int `json:"output_tokens"`
-			}{
+			Usage: anthropicUsage{
 				InputTokens:  1,
 				OutputTokens: 1,
 			},
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mojomast/geoffrussy/internal/state"
+)
+
+// ErrSpendCapReached indicates a provider's spend cap for the project has
+// been reached
+var ErrSpendCapReached = errors.New("spend cap reached")
+
+// BudgetAdaptiveProvider switches calls to a cheaper model once a project's
+// spend reaches a threshold, so work can finish within budget instead of
+// stopping. All other methods pass through to the wrapped provider.
+type BudgetAdaptiveProvider struct {
+	Provider
+	store      *state.Store
+	projectID  string
+	cheapModel string
+	threshold  float64
+}
+
+// NewBudgetAdaptiveProvider wraps p so calls use cheapModel once the
+// project's total recorded cost reaches threshold dollars. A threshold of
+// zero or less never downgrades.
+func NewBudgetAdaptiveProvider(p Provider, store *state.Store, projectID string, cheapModel string, threshold float64) Provider {
+	return &BudgetAdaptiveProvider{
+		Provider:   p,
+		store:      store,
+		projectID:  projectID,
+		cheapModel: cheapModel,
+		threshold:  threshold,
+	}
+}
+
+// selectModel returns the model to call in place of the requested one. If
+// spend can't be read the requested model is kept, since a missed
+// downgrade costs less than failing the call.
+func (b *BudgetAdaptiveProvider) selectModel(model string) string {
+	if b.threshold <= 0 || b.cheapModel == "" || b.store == nil {
+		return model
+	}
+	spent, err := b.store.GetTotalCost(b.projectID)
+	if err != nil || spent < b.threshold {
+		return model
+	}
+	return b.cheapModel
+}
+
+// Call calls the wrapped provider with default options
+func (b *BudgetAdaptiveProvider) Call(model string, prompt string) (*Response, error) {
+	return b.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions calls the wrapped provider, substituting the cheap model
+// when over the threshold. The response's Model is the model actually used
+// and RequestedModel records the original one.
+func (b *BudgetAdaptiveProvider) CallWithOptions(model string, prompt string, opts CallOptions) (*Response, error) {
+	used := b.selectModel(model)
+	response, err := b.Provider.CallWithOptions(used, prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+	if used != model {
+		response.Model = used
+		response.RequestedModel = model
+	}
+	return response, nil
+}
+
+// Stream streams from the wrapped provider, substituting the cheap model
+// when over the threshold
+func (b *BudgetAdaptiveProvider) Stream(model string, prompt string) (<-chan string, error) {
+	return b.Provider.Stream(b.selectModel(model), prompt)
+}
+
+// Ping checks the wrapped provider with the model calls would use
+func (b *BudgetAdaptiveProvider) Ping(model string) error {
+	if model == "" {
+		return b.Provider.Ping(model)
+	}
+	return b.Provider.Ping(b.selectModel(model))
+}
+
+// SpendCappedProvider checks the provider's spend cap for a project before
+// every request, so a long command stops once the cap is reached instead of
+// only checking when it starts. All other methods pass through.
+type SpendCappedProvider struct {
+	Provider
+	store     *state.Store
+	projectID string
+	limit     float64
+}
+
+// NewSpendCappedProvider wraps p so requests fail with ErrSpendCapReached
+// once the project's recorded spend with p reaches limit dollars. A limit
+// of zero or less never blocks.
+func NewSpendCappedProvider(p Provider, store *state.Store, projectID string, limit float64) *SpendCappedProvider {
+	return &SpendCappedProvider{Provider: p, store: store, projectID: projectID, limit: limit}
+}
+
+// checkCap returns an error when the spend cap has been reached
+func (c *SpendCappedProvider) checkCap() error {
+	if c.limit <= 0 || c.store == nil {
+		return nil
+	}
+	exceeded, err := c.store.CheckProviderBudget(c.projectID, c.Name(), c.limit)
+	if err != nil {
+		return fmt.Errorf("failed to check provider budget: %w", err)
+	}
+	if exceeded {
+		return fmt.Errorf("%w: $%.2f for provider '%s'", ErrSpendCapReached, c.limit, c.Name())
+	}
+	return nil
+}
+
+// Call checks the spend cap, then calls the wrapped provider
+func (c *SpendCappedProvider) Call(model string, prompt string) (*Response, error) {
+	if err := c.checkCap(); err != nil {
+		return nil, err
+	}
+	return c.Provider.Call(model, prompt)
+}
+
+// CallWithOptions checks the spend cap, then calls the wrapped provider
+func (c *SpendCappedProvider) CallWithOptions(model string, prompt string, opts CallOptions) (*Response, error) {
+	if err := c.checkCap(); err != nil {
+		return nil, err
+	}
+	return c.Provider.CallWithOptions(model, prompt, opts)
+}
+
+// Stream checks the spend cap, then streams from the wrapped provider
+func (c *SpendCappedProvider) Stream(model string, prompt string) (<-chan string, error) {
+	if err := c.checkCap(); err != nil {
+		return nil, err
+	}
+	return c.Provider.Stream(model, prompt)
+}
+
+// CallWithTools checks the spend cap, then passes tool calls through when
+// the wrapped provider supports them
+func (c *SpendCappedProvider) CallWithTools(model string, messages []Message, tools []Tool, opts CallOptions) (*Response, error) {
+	caller, ok := c.Provider.(ToolCaller)
+	if !ok {
+		return nil, fmt.Errorf("%w by provider %s", ErrToolsUnsupported, c.Name())
+	}
+	if err := c.checkCap(); err != nil {
+		return nil, err
+	}
+	return caller.CallWithTools(model, messages, tools, opts)
+}
+
+// Embed checks the spend cap, then passes embedding requests through when
+// the wrapped provider supports them
+func (c *SpendCappedProvider) Embed(model string, texts []string) ([][]float32, error) {
+	embedder, ok := c.Provider.(Embedder)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support embeddings", c.Name())
+	}
+	if err := c.checkCap(); err != nil {
+		return nil, err
+	}
+	return embedder.Embed(model, texts)
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mojomast/geoffrussy/internal/state"
+)
+
+func TestBudgetAdaptiveProvider(t *testing.T) {
+	store, err := state.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{ID: "proj-1", Name: "Test", CreatedAt: time.Now(), CurrentStage: state.StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	p := NewBudgetAdaptiveProvider(NewEstimateOnlyProvider(Pricing{}), store, project.ID, "gpt-4o-mini", 5.0)
+
+	record := func(cost float64) {
+		t.Helper()
+		usage := &state.TokenUsage{ProjectID: project.ID, Provider: "openai", Model: "gpt-4o", Cost: cost, Timestamp: time.Now()}
+		if err := store.RecordTokenUsage(usage); err != nil {
+			t.Fatalf("failed to record usage: %v", err)
+		}
+	}
+
+	record(4.0)
+	response, err := p.Call("gpt-4o", "Hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Model != "gpt-4o" || response.RequestedModel != "" {
+		t.Errorf("expected requested model below threshold, got %q (requested %q)", response.Model, response.RequestedModel)
+	}
+
+	record(1.5)
+	response, err = p.Call("gpt-4o", "Hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Model != "gpt-4o-mini" {
+		t.Errorf("expected cheap model above threshold, got %q", response.Model)
+	}
+	if response.RequestedModel != "gpt-4o" {
+		t.Errorf("expected requested model to be recorded, got %q", response.RequestedModel)
+	}
+
+	// Other methods pass through to the wrapped provider
+	if p.Name() != "estimate" {
+		t.Errorf("expected wrapped provider name, got %q", p.Name())
+	}
+
+	disabled := NewBudgetAdaptiveProvider(NewEstimateOnlyProvider(Pricing{}), store, project.ID, "gpt-4o-mini", 0)
+	if response, _ := disabled.Call("gpt-4o", "Hello"); response.Model != "gpt-4o" {
+		t.Errorf("expected no downgrade with a zero threshold, got %q", response.Model)
+	}
+}
+
+func TestSpendCappedProvider(t *testing.T) {
+	store, err := state.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{ID: "proj-1", Name: "Test", CreatedAt: time.Now(), CurrentStage: state.StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	p := NewSpendCappedProvider(NewEchoProvider(), store, project.ID, 5.0)
+	if _, err := p.Call("echo", "Hello"); err != nil {
+		t.Fatalf("expected calls under the cap to go through, got %v", err)
+	}
+
+	usage := &state.TokenUsage{ProjectID: project.ID, Provider: p.Name(), Model: "echo", Cost: 5.0, Timestamp: time.Now()}
+	if err := store.RecordTokenUsage(usage); err != nil {
+		t.Fatalf("failed to record usage: %v", err)
+	}
+	if _, err := p.Call("echo", "Hello"); !errors.Is(err, ErrSpendCapReached) {
+		t.Errorf("expected ErrSpendCapReached once the cap is reached, got %v", err)
+	}
+	if _, err := p.CallWithOptions("echo", "Hello", CallOptions{}); !errors.Is(err, ErrSpendCapReached) {
+		t.Errorf("expected CallWithOptions to be capped too, got %v", err)
+	}
+	if _, err := p.Stream("echo", "Hello"); !errors.Is(err, ErrSpendCapReached) {
+		t.Errorf("expected Stream to be capped too, got %v", err)
+	}
+
+	uncapped := NewSpendCappedProvider(NewEchoProvider(), store, project.ID, 0)
+	if _, err := uncapped.Call("echo", "Hello"); err != nil {
+		t.Errorf("expected no cap with a zero limit, got %v", err)
+	}
+	if _, err := uncapped.CallWithTools("echo", nil, nil, CallOptions{}); !errors.Is(err, ErrToolsUnsupported) {
+		t.Errorf("expected ErrToolsUnsupported from a provider without tools, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mojomast/geoffrussy/internal/token"
+)
+
+// ErrContextExceeded is returned before a call is made when the prompt can't
+// fit in the model's context window, and wraps provider errors rejecting a
+// prompt as too long
+var ErrContextExceeded = errors.New("prompt exceeds the model's context window")
+
+// ContextStrategy decides what a call does with a prompt that is too large
+// for the model's context window
+type ContextStrategy int
+
+const (
+	// ContextStrategyError fails the call with ErrContextExceeded
+	ContextStrategyError ContextStrategy = iota
+	// ContextStrategyTruncate drops sections from the middle of the prompt,
+	// largest first, keeping the leading instructions and the trailing
+	// output format
+	ContextStrategyTruncate
+)
+
+// defaultReservedOutputTokens is the room left for the response when the
+// call doesn't set MaxTokens
+const defaultReservedOutputTokens = 4096
+
+// contextWindows maps exact model names to context window sizes in tokens.
+// Models that aren't listed have an unknown window, so their prompts are
+// sent unchecked rather than rejected against a guessed limit.
+var contextWindows = map[string]int{
+	"claude-3-5-sonnet-20241022": 200000,
+	"claude-3-5-haiku-20241022":  200000,
+	"claude-3-opus-20240229":     200000,
+	"claude-3-sonnet-20240229":   200000,
+	"claude-3-haiku-20240307":    200000,
+	"gpt-4o":                     128000,
+	"gpt-4o-mini":                128000,
+	"gpt-4-turbo":                128000,
+	"gpt-4-turbo-preview":        128000,
+	"gpt-4-1106-preview":         128000,
+	"gpt-4-0125-preview":         128000,
+	"gpt-4":                      8192,
+	"gpt-4-0613":                 8192,
+	"gpt-4-32k":                  32768,
+	"gpt-3.5-turbo":              16385,
+	"moonshot-v1-8k":             8192,
+	"moonshot-v1-32k":            32768,
+	"moonshot-v1-128k":           131072,
+	"glm-4.7":                    128000,
+	"glm-4.6":                    128000,
+	"glm-4.6v":                   128000,
+}
+
+// ContextWindow returns the known context window of a model in tokens, or 0
+// when it is unknown
+func ContextWindow(model string) int {
+	return contextWindows[model]
+}
+
+// contextSectionSeparator splits prompts into sections for truncation
+const contextSectionSeparator = "\n\n"
+
+// fitContext checks a prompt against the model's context window, leaving
+// room for the system prompt and the response. Prompts for models with an
+// unknown window are passed through unchanged.
+func fitContext(model string, prompt string, opts CallOptions) (string, error) {
+	window := ContextWindow(model)
+	if window == 0 {
+		return prompt, nil
+	}
+
+	counter := token.NewCounter(nil)
+	reserved := opts.MaxTokens
+	if reserved <= 0 {
+		reserved = defaultReservedOutputTokens
+	}
+	systemTokens, _ := counter.CountTokens(opts.System, model)
+	budget := window - reserved - systemTokens
+
+	promptTokens, _ := counter.CountTokens(prompt, model)
+	if promptTokens <= budget {
+		return prompt, nil
+	}
+	exceeded := fmt.Errorf("%w: prompt is about %d tokens but %s leaves room for %d", ErrContextExceeded, promptTokens, model, budget)
+	if opts.ContextStrategy != ContextStrategyTruncate {
+		return "", exceeded
+	}
+
+	sections := strings.Split(prompt, contextSectionSeparator)
+	if len(sections) < 3 {
+		return "", exceeded
+	}
+
+	// Drop the largest middle sections until the prompt fits
+	middle := make([]int, 0, len(sections)-2)
+	for i := 1; i < len(sections)-1; i++ {
+		middle = append(middle, i)
+	}
+	sort.SliceStable(middle, func(a, b int) bool {
+		return len(sections[middle[a]]) > len(sections[middle[b]])
+	})
+	dropped := make(map[int]bool)
+	for _, i := range middle {
+		dropped[i] = true
+		truncated := joinKeptSections(sections, dropped)
+		if tokens, _ := counter.CountTokens(truncated, model); tokens <= budget {
+			return truncated, nil
+		}
+	}
+
+	return "", exceeded
+}
+
+// joinKeptSections rebuilds a prompt without the dropped sections, marking
+// where they were removed
+func joinKeptSections(sections []string, dropped map[int]bool) string {
+	var kept []string
+	omitted := 0
+	for i, section := range sections {
+		if dropped[i] {
+			omitted++
+			continue
+		}
+		if omitted > 0 {
+			kept = append(kept, fmt.Sprintf("[... %d section(s) omitted to fit the context window ...]", omitted))
+			omitted = 0
+		}
+		kept = append(kept, section)
+	}
+	return strings.Join(kept, contextSectionSeparator)
+}
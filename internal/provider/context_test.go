@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		model    string
+		expected int
+	}{
+		{"claude-3-5-sonnet-20241022", 200000},
+		{"gpt-4o-mini", 128000},
+		{"gpt-4-0613", 8192},
+		{"moonshot-v1-8k", 8192},
+		{"moonshot-v1-128k", 131072},
+		{"glm-4.7", 128000},
+		{"gpt-4-1106-preview", 128000},
+		{"gpt-4-2025-preview", 0},
+		{"claude-4-opus", 0},
+		{"llama3", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ContextWindow(tt.model); got != tt.expected {
+			t.Errorf("ContextWindow(%q) = %d, expected %d", tt.model, got, tt.expected)
+		}
+	}
+}
+
+func TestKimiProvider_CallContextExceeded(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	provider := NewKimiProvider()
+	provider.baseURL = server.URL
+	provider.Authenticate("test-key")
+	provider.SetMaxRetries(0)
+
+	// About 12K tokens, well past the 8K window
+	prompt := strings.Repeat("word ", 12000)
+	_, err := provider.Call("moonshot-v1-8k", prompt)
+	if !errors.Is(err, ErrContextExceeded) {
+		t.Fatalf("expected ErrContextExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "moonshot-v1-8k") {
+		t.Errorf("expected error to name the model, got %q", err.Error())
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("expected no request to be sent, got %d", n)
+	}
+}
+
+func TestFitContext_Truncate(t *testing.T) {
+	head := "Generate phases for the project."
+	tail := "Respond with JSON only."
+	large := strings.Repeat("interview detail ", 5000)
+	small := "Architecture: a CLI in Go."
+	prompt := strings.Join([]string{head, large, small, tail}, "\n\n")
+
+	opts := CallOptions{ContextStrategy: ContextStrategyTruncate}
+	fitted, err := fitContext("moonshot-v1-8k", prompt, opts)
+	if err != nil {
+		t.Fatalf("expected prompt to be truncated, got %v", err)
+	}
+	if !strings.HasPrefix(fitted, head) || !strings.HasSuffix(fitted, tail) {
+		t.Errorf("expected first and last sections to be kept, got %q", fitted)
+	}
+	if strings.Contains(fitted, "interview detail") {
+		t.Error("expected the largest section to be dropped")
+	}
+	if !strings.Contains(fitted, small) {
+		t.Error("expected the small section to be kept")
+	}
+	if !strings.Contains(fitted, "omitted") {
+		t.Error("expected an omission marker")
+	}
+
+	// Nothing can be dropped from a single section
+	if _, err := fitContext("moonshot-v1-8k", large, opts); !errors.Is(err, ErrContextExceeded) {
+		t.Errorf("expected ErrContextExceeded, got %v", err)
+	}
+
+	// Unknown models are passed through
+	if fitted, err := fitContext("llama3", large, CallOptions{}); err != nil || fitted != large {
+		t.Errorf("expected unknown model to pass through, got err %v", err)
+	}
+}
+
+func TestOpenAIProvider_CallWithToolsFitsContext(t *testing.T) {
+	var requests []openAIToolRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIToolRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		requests = append(requests, req)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"choices": [{"message": {"content": "ok"}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 1, "completion_tokens": 1}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider()
+	provider.baseURL = server.URL
+	provider.Authenticate("test-key")
+	provider.SetMaxRetries(0)
+
+	head := "Generate phases for the project."
+	tail := "Respond with JSON only."
+	prompt := strings.Join([]string{head, strings.Repeat("interview detail ", 5000), tail}, "\n\n")
+	messages := []Message{{Role: "system", Content: "Use the tool"}, {Role: "user", Content: prompt}}
+	tools := []Tool{{Name: "submit_phase", Description: "Submit a phase"}}
+
+	if _, err := provider.CallWithTools("gpt-4", messages, tools, CallOptions{}); !errors.Is(err, ErrContextExceeded) {
+		t.Fatalf("expected ErrContextExceeded, got %v", err)
+	}
+	if len(requests) != 0 {
+		t.Fatalf("expected no request to be sent, got %d", len(requests))
+	}
+
+	if _, err := provider.CallWithTools("gpt-4", messages, tools, CallOptions{ContextStrategy: ContextStrategyTruncate}); err != nil {
+		t.Fatalf("expected the prompt to be truncated, got %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected one request, got %d", len(requests))
+	}
+	sent := requests[0].Messages[len(requests[0].Messages)-1].Content
+	if !strings.HasPrefix(sent, head) || !strings.HasSuffix(sent, tail) || strings.Contains(sent, "interview detail") {
+		t.Errorf("expected the large section to be dropped, got %q", sent)
+	}
+	if messages[1].Content != prompt {
+		t.Error("expected the caller's messages to be left alone")
+	}
+}
@@ -0,0 +1,51 @@
+package provider
+
+import "sync"
+
+// modelPricing caches model prices by provider and model name so pricing a
+// call lists a provider's models at most once
+var modelPricing sync.Map // "provider/model" -> Model
+
+// CallCost returns the cost in USD of a call made through p. Responses that
+// carry their own cost keep it; otherwise the price is looked up from the
+// model's per-1K-token pricing. Models without known pricing cost zero.
+func CallCost(p Provider, response *Response) float64 {
+	if response == nil {
+		return 0
+	}
+	if response.Cost > 0 || response.Estimated {
+		return response.Cost
+	}
+
+	model, ok := lookupModelPricing(p, response.Model)
+	if !ok {
+		return 0
+	}
+	return (float64(response.TokensInput)/1000.0)*model.PriceInput +
+		(float64(response.TokensOutput)/1000.0)*model.PriceOutput
+}
+
+// lookupModelPricing finds a model's pricing, listing p's models on a cache miss
+func lookupModelPricing(p Provider, name string) (Model, bool) {
+	if p == nil || name == "" {
+		return Model{}, false
+	}
+	key := p.Name() + "/" + name
+	if cached, ok := modelPricing.Load(key); ok {
+		return cached.(Model), true
+	}
+
+	models, err := p.ListModels()
+	if err != nil {
+		return Model{}, false
+	}
+	var found Model
+	ok := false
+	for _, model := range models {
+		modelPricing.Store(p.Name()+"/"+model.Name, model)
+		if model.Name == name {
+			found, ok = model, true
+		}
+	}
+	return found, ok
+}
@@ -0,0 +1,49 @@
+package provider
+
+import "testing"
+
+// pricedProvider lists a fixed set of priced models and counts the listings
+type pricedProvider struct {
+	*EchoProvider
+	models []Model
+	lists  int
+}
+
+func (p *pricedProvider) Name() string { return "priced" }
+
+func (p *pricedProvider) ListModels() ([]Model, error) {
+	p.lists++
+	return p.models, nil
+}
+
+func TestCallCost(t *testing.T) {
+	p := &pricedProvider{
+		EchoProvider: NewEchoProvider().(*EchoProvider),
+		models: []Model{
+			{Name: "priced-large", PriceInput: 0.01, PriceOutput: 0.03},
+			{Name: "priced-small", PriceInput: 0.001, PriceOutput: 0.002},
+		},
+	}
+
+	cost := CallCost(p, &Response{Model: "priced-large", TokensInput: 2000, TokensOutput: 1000})
+	if diff := cost - 0.05; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected cost 0.05, got %f", cost)
+	}
+	cost = CallCost(p, &Response{Model: "priced-small", TokensInput: 1000, TokensOutput: 1000})
+	if diff := cost - 0.003; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected cost 0.003, got %f", cost)
+	}
+	if p.lists != 1 {
+		t.Errorf("Expected models to be listed once, got %d", p.lists)
+	}
+
+	if cost := CallCost(p, &Response{Model: "priced-large", TokensInput: 1000, Cost: 0.5}); cost != 0.5 {
+		t.Errorf("Expected the provider's own cost to be kept, got %f", cost)
+	}
+	if cost := CallCost(p, &Response{Model: "unpriced", TokensInput: 1000}); cost != 0 {
+		t.Errorf("Expected unknown models to cost nothing, got %f", cost)
+	}
+	if cost := CallCost(p, nil); cost != 0 {
+		t.Errorf("Expected a nil response to cost nothing, got %f", cost)
+	}
+}
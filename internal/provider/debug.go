@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// transportSetter is implemented by providers that talk HTTP, so a debugging
+// transport can be slipped under them
+type transportSetter interface {
+	setTransport(rt http.RoundTripper)
+}
+
+// DebugProvider keeps the raw body of the last HTTP request its provider
+// sent and the response it got back, for inspecting a call that went
+// wrong. The API key is redacted from both. Providers that don't make HTTP
+// calls pass through without recording anything.
+type DebugProvider struct {
+	Provider
+	recorder *recordingTransport
+}
+
+// NewDebugProvider wraps p so its last raw request and response can be read
+// back with DebugLastCall
+func NewDebugProvider(p Provider) *DebugProvider {
+	recorder := &recordingTransport{next: http.DefaultTransport}
+	if keyed, ok := p.(interface{ GetAPIKey() string }); ok {
+		recorder.apiKey = keyed.GetAPIKey
+	}
+	if setter, ok := p.(transportSetter); ok {
+		setter.setTransport(recorder)
+	}
+	return &DebugProvider{Provider: p, recorder: recorder}
+}
+
+// DebugLastCall returns the body of the last request sent and the response
+// received, with the API key redacted. Both are empty before any call.
+func (d *DebugProvider) DebugLastCall() (request, response string) {
+	return d.recorder.last()
+}
+
+// CallWithTools passes tool calls through when the wrapped provider
+// supports them
+func (d *DebugProvider) CallWithTools(model string, messages []Message, tools []Tool, opts CallOptions) (*Response, error) {
+	caller, ok := d.Provider.(ToolCaller)
+	if !ok {
+		return nil, fmt.Errorf("%w by provider %s", ErrToolsUnsupported, d.Name())
+	}
+	return caller.CallWithTools(model, messages, tools, opts)
+}
+
+// recordingTransport is an http.RoundTripper that keeps copies of the last
+// request and response bodies
+type recordingTransport struct {
+	next   http.RoundTripper
+	apiKey func() string
+
+	mu           sync.Mutex
+	lastRequest  []byte
+	lastResponse bytes.Buffer
+}
+
+// RoundTrip records the request body before sending it and the response
+// body as the caller reads it, so streamed responses still reach the
+// caller as they arrive
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	t.mu.Lock()
+	t.lastRequest = requestBody
+	t.lastResponse.Reset()
+	t.mu.Unlock()
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &recordingBody{ReadCloser: resp.Body, transport: t}
+	return resp, nil
+}
+
+// last returns the recorded request and response bodies, redacted
+func (t *recordingTransport) last() (string, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.redact(string(t.lastRequest)), t.redact(t.lastResponse.String())
+}
+
+// redact replaces the API key, if any, with a placeholder
+func (t *recordingTransport) redact(text string) string {
+	if t.apiKey == nil {
+		return text
+	}
+	if key := t.apiKey(); key != "" {
+		return strings.ReplaceAll(text, key, redactedPlaceholder)
+	}
+	return text
+}
+
+// recordingBody copies a response body into its transport as it is read
+type recordingBody struct {
+	io.ReadCloser
+	transport *recordingTransport
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.transport.mu.Lock()
+		b.transport.lastResponse.Write(p[:n])
+		b.transport.mu.Unlock()
+	}
+	return n, err
+}
+
+// Embed passes embedding requests through when the wrapped provider
+// supports them
+func (d *DebugProvider) Embed(model string, texts []string) ([][]float32, error) {
+	embedder, ok := d.Provider.(Embedder)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support embeddings", d.Name())
+	}
+	return embedder.Embed(model, texts)
+}
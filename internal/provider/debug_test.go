@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugProvider_DebugLastCall(t *testing.T) {
+	const apiKey = "sk-debug-secret"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "Plan a todo app") {
+			t.Errorf("Expected the request body to reach the server intact, got %s", body)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"model": "gpt-4", "choices": [{"message": {"role": "assistant", "content": "Echoing ` + apiKey + `"}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 3, "completion_tokens": 2}}`))
+	}))
+	defer server.Close()
+
+	openai := NewOpenAIProvider()
+	openai.baseURL = server.URL + "/v1"
+	openai.Authenticate(apiKey)
+
+	debug := NewDebugProvider(openai)
+	if request, response := debug.DebugLastCall(); request != "" || response != "" {
+		t.Errorf("Expected nothing recorded before a call, got %q and %q", request, response)
+	}
+
+	resp, err := debug.CallWithOptions("gpt-4", "Plan a todo app with key "+apiKey, CallOptions{})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if resp.Content != "Echoing "+apiKey {
+		t.Errorf("Expected the caller to get the unredacted response, got %q", resp.Content)
+	}
+
+	request, response := debug.DebugLastCall()
+	if !strings.Contains(request, `"model":"gpt-4"`) || !strings.Contains(request, "Plan a todo app") {
+		t.Errorf("Expected the raw request body, got %s", request)
+	}
+	if !strings.Contains(response, `"finish_reason": "stop"`) {
+		t.Errorf("Expected the raw response body, got %s", response)
+	}
+	for _, recorded := range []string{request, response} {
+		if strings.Contains(recorded, apiKey) {
+			t.Errorf("Expected the API key to be redacted, got %s", recorded)
+		}
+		if !strings.Contains(recorded, redactedPlaceholder) {
+			t.Errorf("Expected a redaction placeholder, got %s", recorded)
+		}
+	}
+
+	// Providers without HTTP calls pass through without recording
+	echo := NewDebugProvider(NewEchoProvider())
+	if _, err := echo.Call("test-model", "hello"); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if request, _ := echo.DebugLastCall(); request != "" {
+		t.Errorf("Expected nothing recorded for a non-HTTP provider, got %q", request)
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/mojomast/geoffrussy/internal/token"
+)
+
+// EchoProvider never calls an API. Each call returns its prompt as the
+// content, so pipeline tests and demos run offline and produce the same
+// output every time.
+type EchoProvider struct {
+	*BaseProvider
+	counter *token.Counter
+}
+
+// NewEchoProvider creates a provider that answers every call with its prompt
+func NewEchoProvider() Provider {
+	base := NewBaseProvider("echo")
+	base.authenticated = true
+	return &EchoProvider{
+		BaseProvider: base,
+		counter:      token.NewCounter(nil),
+	}
+}
+
+// Authenticate is a no-op since no API is contacted
+func (e *EchoProvider) Authenticate(apiKey string) error {
+	e.authenticated = true
+	return nil
+}
+
+// ListModels returns no models; any model name is accepted
+func (e *EchoProvider) ListModels() ([]Model, error) {
+	return []Model{}, nil
+}
+
+// Call echoes the prompt with default options
+func (e *EchoProvider) Call(model string, prompt string) (*Response, error) {
+	return e.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions echoes the system prompt and prompt. The output depends
+// only on them, so seeded calls trivially reproduce; the seed is recorded
+// in the response like a provider that sends it.
+func (e *EchoProvider) CallWithOptions(model string, prompt string, opts CallOptions) (*Response, error) {
+	if opts.System != "" {
+		prompt = opts.System + "\n\n" + prompt
+	}
+
+	tokens, err := e.counter.CountTokens(prompt, model)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		Content:      prompt,
+		TokensInput:  tokens,
+		TokensOutput: tokens,
+		Model:        model,
+		Provider:     e.Name(),
+		Timestamp:    time.Now(),
+		FinishReason: FinishReasonStop,
+		Seed:         opts.Seed,
+	}, nil
+}
+
+// Stream sends the echoed prompt as a single chunk
+func (e *EchoProvider) Stream(model string, prompt string) (<-chan string, error) {
+	response, err := e.Call(model, prompt)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan string, 1)
+	ch <- response.Content
+	close(ch)
+	return ch, nil
+}
+
+// Ping always succeeds since no API is contacted
+func (e *EchoProvider) Ping(model string) error {
+	return nil
+}
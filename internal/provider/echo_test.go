@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestEchoProvider_SeededCallsMatch(t *testing.T) {
+	p := NewEchoProvider()
+	opts := CallOptions{Seed: 42, System: "You are a planner."}
+
+	first, err := p.CallWithOptions("test-model", "Plan a todo app", opts)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	second, err := p.CallWithOptions("test-model", "Plan a todo app", opts)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if first.Content != second.Content {
+		t.Errorf("Expected identical output for the same seed, got %q and %q", first.Content, second.Content)
+	}
+	if first.Content != "You are a planner.\n\nPlan a todo app" {
+		t.Errorf("Expected the prompt to be echoed, got %q", first.Content)
+	}
+	if first.Seed != 42 || second.Seed != 42 {
+		t.Errorf("Expected seed to be recorded, got %d and %d", first.Seed, second.Seed)
+	}
+
+	unseeded, err := p.Call("test-model", "Plan a todo app")
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if unseeded.Seed != 0 {
+		t.Errorf("Expected no seed on an unseeded call, got %d", unseeded.Seed)
+	}
+}
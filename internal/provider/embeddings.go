@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+)
+
+// Embedder is implemented by providers that can turn text into embedding
+// vectors. Callers check for it with a type assertion and fall back to
+// keyword heuristics when it is missing.
+type Embedder interface {
+	Embed(model string, texts []string) ([][]float32, error)
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors, from
+// -1 for opposite to 1 for the same direction. Vectors of different lengths
+// or with no magnitude have a similarity of 0.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// RankBySimilarity returns the indexes of candidates ordered from most to
+// least similar to query. Ties keep their original order.
+func RankBySimilarity(query []float32, candidates [][]float32) []int {
+	scores := make([]float64, len(candidates))
+	ranked := make([]int, len(candidates))
+	for i, candidate := range candidates {
+		scores[i] = CosineSimilarity(query, candidate)
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i]] > scores[ranked[j]]
+	})
+	return ranked
+}
+
+// openAIEmbeddingRequest is the request body of the embeddings endpoint
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// openAIEmbeddingResponse is the response of the embeddings endpoint
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed returns one embedding per text, in the order given
+func (o *OpenAIProvider) Embed(model string, texts []string) (_ [][]float32, err error) {
+	defer func() { err = o.RedactError(err) }()
+
+	if !o.IsAuthenticated() {
+		return nil, fmt.Errorf("provider not authenticated")
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	jsonData, err := json.Marshal(openAIEmbeddingRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := o.postJSON("/embeddings", jsonData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var embedResp openAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// The API returns an index per embedding; don't rely on list order
+	vectors := make([][]float32, len(texts))
+	for _, item := range embedResp.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of range", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for i, vector := range vectors {
+		if vector == nil {
+			return nil, fmt.Errorf("no embedding returned for input %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// ollamaEmbedRequest is the request body of Ollama's embed endpoint
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// ollamaEmbedResponse is the response of Ollama's embed endpoint
+type ollamaEmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// Embed returns one embedding per text, in the order given
+func (o *OllamaProvider) Embed(model string, texts []string) ([][]float32, error) {
+	if !o.IsAuthenticated() {
+		return nil, fmt.Errorf("provider not authenticated")
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	jsonData, err := json.Marshal(ollamaEmbedRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	var vectors [][]float32
+	err = o.RetryWithBackoff(func() error {
+		httpReq, err := http.NewRequest("POST", o.baseURL+"/api/embed", bytes.NewBuffer(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := o.httpClient.Do(httpReq)
+		if err != nil {
+			return fmt.Errorf("failed to make request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
+		}
+
+		var embedResp ollamaEmbedResponse
+		if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		vectors = embedResp.Embeddings
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(vectors))
+	}
+	return vectors, nil
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// fixedEmbedder returns a preset vector for each known text
+type fixedEmbedder struct {
+	vectors map[string][]float32
+}
+
+func (f *fixedEmbedder) Embed(model string, texts []string) ([][]float32, error) {
+	result := make([][]float32, len(texts))
+	for i, text := range texts {
+		result[i] = f.vectors[text]
+	}
+	return result, nil
+}
+
+func TestRankBySimilarity(t *testing.T) {
+	var embedder Embedder = &fixedEmbedder{vectors: map[string][]float32{
+		"user login":        {1, 0, 0},
+		"password reset":    {0.9, 0.1, 0},
+		"invoice export":    {0, 1, 0},
+		"authentication":    {1, 0.05, 0},
+		"dark mode styling": {0, 0, 1},
+	}}
+
+	candidates := []string{"invoice export", "dark mode styling", "password reset", "user login"}
+	vectors, err := embedder.Embed("test-model", append([]string{"authentication"}, candidates...))
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	ranked := RankBySimilarity(vectors[0], vectors[1:])
+	var order []string
+	for _, i := range ranked {
+		order = append(order, candidates[i])
+	}
+	want := []string{"user login", "password reset", "invoice export", "dark mode styling"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected ranking %v, got %v", want, order)
+	}
+
+	if sim := CosineSimilarity([]float32{1, 2}, []float32{2, 4}); sim < 0.9999 {
+		t.Errorf("Expected parallel vectors to have similarity 1, got %f", sim)
+	}
+	if sim := CosineSimilarity([]float32{1, 0}, []float32{-1, 0}); sim > -0.9999 {
+		t.Errorf("Expected opposite vectors to have similarity -1, got %f", sim)
+	}
+	if sim := CosineSimilarity([]float32{1, 0}, []float32{1, 0, 0}); sim != 0 {
+		t.Errorf("Expected mismatched lengths to have similarity 0, got %f", sim)
+	}
+	if sim := CosineSimilarity([]float32{0, 0}, []float32{1, 0}); sim != 0 {
+		t.Errorf("Expected a zero vector to have similarity 0, got %f", sim)
+	}
+}
+
+func TestOpenAIProvider_Embed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			t.Errorf("Expected the embeddings endpoint, got %s", r.URL.Path)
+		}
+		var req openAIEmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		if req.Model != "text-embedding-3-small" || len(req.Input) != 2 {
+			t.Errorf("Unexpected request: %+v", req)
+		}
+
+		// Returned out of order to check the index is honoured
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [
+			{"index": 1, "embedding": [0, 1]},
+			{"index": 0, "embedding": [1, 0]}
+		]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProviderWithBaseURL(server.URL + "/v1")
+	if err := provider.Authenticate(""); err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+
+	vectors, err := provider.Embed("text-embedding-3-small", []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	want := [][]float32{{1, 0}, {0, 1}}
+	if !reflect.DeepEqual(vectors, want) {
+		t.Errorf("Expected %v, got %v", want, vectors)
+	}
+
+	// Wrappers pass embeddings through
+	var wrapped Embedder = NewDebugProvider(provider)
+	if _, err := wrapped.Embed("text-embedding-3-small", []string{"first", "second"}); err != nil {
+		t.Errorf("Expected the debug wrapper to pass Embed through, got %v", err)
+	}
+	if _, err := NewDebugProvider(NewEchoProvider()).Embed("m", []string{"x"}); err == nil {
+		t.Error("Expected an error embedding with a provider that doesn't support it")
+	}
+}
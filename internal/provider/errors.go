@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrAuth indicates the provider rejected the configured credentials
+	ErrAuth = errors.New("authentication failed")
+	// ErrRateLimited indicates the provider refused the request because a
+	// rate limit or quota was hit
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer indicates the provider failed on its side
+	ErrServer = errors.New("provider server error")
+)
+
+// contextExceededMarkers are phrases providers use in error bodies when a
+// request is rejected for being too long for the model
+var contextExceededMarkers = []string{
+	"context_length_exceeded",
+	"context length",
+	"context window",
+	"maximum context",
+	"prompt is too long",
+	"too many tokens",
+}
+
+// classifiedError keeps a provider's own error message while letting
+// errors.Is match the kind of failure it represents
+type classifiedError struct {
+	err  error
+	kind error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.err, e.kind}
+}
+
+// statusError classifies err, returned for a response with the given status
+// code and body, as ErrAuth, ErrRateLimited, ErrContextExceeded or
+// ErrServer. Errors for other statuses are returned unchanged.
+func statusError(status int, body []byte, err error) error {
+	kind := statusKind(status, body)
+	if kind == nil {
+		return err
+	}
+	return &classifiedError{err: err, kind: kind}
+}
+
+// statusKind maps an HTTP status code, and for bad requests the error body,
+// to the matching error kind, or nil when there is none
+func statusKind(status int, body []byte) error {
+	switch {
+	case status == http.StatusUnauthorized:
+		return ErrAuth
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusRequestEntityTooLarge:
+		return ErrContextExceeded
+	case status == http.StatusBadRequest:
+		message := strings.ToLower(string(body))
+		for _, marker := range contextExceededMarkers {
+			if strings.Contains(message, marker) {
+				return ErrContextExceeded
+			}
+		}
+		return nil
+	case status >= 500:
+		return ErrServer
+	}
+	return nil
+}
+
+// isPermanentError reports whether retrying the request can't help: the
+// credentials were rejected or the prompt doesn't fit the model
+func isPermanentError(err error) bool {
+	return errors.Is(err, ErrAuth) || errors.Is(err, ErrContextExceeded)
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStatusError_Classification(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{http.StatusUnauthorized, `{"error": "invalid api key"}`, ErrAuth},
+		{http.StatusTooManyRequests, `{"error": "slow down"}`, ErrRateLimited},
+		{http.StatusBadRequest, `{"error": {"code": "context_length_exceeded"}}`, ErrContextExceeded},
+		{http.StatusBadRequest, `{"error": "prompt is too long: 250000 tokens"}`, ErrContextExceeded},
+		{http.StatusServiceUnavailable, ``, ErrServer},
+	}
+
+	for _, tt := range tests {
+		original := fmt.Errorf("API error %d: %s", tt.status, tt.body)
+		err := statusError(tt.status, []byte(tt.body), original)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.want, err)
+		}
+		if !errors.Is(err, original) || err.Error() != original.Error() {
+			t.Errorf("status %d: expected the original error kept, got %q", tt.status, err)
+		}
+	}
+
+	plain := fmt.Errorf("API error 400: bad field")
+	if err := statusError(http.StatusBadRequest, []byte("bad field"), plain); err != plain {
+		t.Errorf("expected an unclassified error unchanged, got %v", err)
+	}
+
+	// A forbidden request is a permissions problem, not bad credentials
+	forbidden := fmt.Errorf("API error 403: model not enabled for this key")
+	if err := statusError(http.StatusForbidden, nil, forbidden); err != forbidden {
+		t.Errorf("expected a 403 left unclassified, got %v", err)
+	}
+}
+
+func TestProviders_RetryRateLimited(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if body, _ := io.ReadAll(r.Body); len(body) == 0 {
+			t.Errorf("request %d was sent without a body", requests)
+		}
+		if requests%2 == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": "slow down"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"model": "m", "choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 1, "completion_tokens": 1}}`))
+	}))
+	defer server.Close()
+
+	openai := NewOpenAIProvider()
+	openai.baseURL = server.URL
+	openai.Authenticate("sk-test123")
+	openai.SetBaseDelay(time.Millisecond)
+
+	requesty := NewRequestyProvider()
+	requesty.baseURL = server.URL
+	requesty.Authenticate("test-key")
+	requesty.SetBaseDelay(time.Millisecond)
+
+	firmware := NewFirmwareProvider()
+	firmware.baseURL = server.URL
+	firmware.Authenticate("test-key")
+	firmware.SetBaseDelay(time.Millisecond)
+
+	for _, p := range []Provider{openai, requesty, firmware} {
+		requests = 0
+		resp, err := p.Call("m", "Hello")
+		if err != nil {
+			t.Errorf("%s: expected the call to succeed after a 429, got %v", p.Name(), err)
+			continue
+		}
+		if resp.Content != "ok" || requests != 2 {
+			t.Errorf("%s: expected one retry after a 429, got %d requests and content %q", p.Name(), requests, resp.Content)
+		}
+	}
+
+	// Persistent rate limiting gives up with ErrRateLimited
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+	openai.baseURL = limited.URL
+	openai.SetMaxRetries(1)
+	if _, err := openai.Call("m", "Hello"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited once retries run out, got %v", err)
+	}
+}
+
+func TestProviders_TypedHTTPErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrAuth},
+		{http.StatusTooManyRequests, ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(tt.status)
+			w.Write([]byte(`{"error": "rejected"}`))
+		}))
+
+		anthropic := NewAnthropicProvider()
+		anthropic.baseURL = server.URL
+		anthropic.Authenticate("test-api-key")
+		anthropic.SetMaxRetries(1)
+		anthropic.SetBaseDelay(time.Millisecond)
+		if _, err := anthropic.Call("claude-3-haiku-20240307", "Hello"); !errors.Is(err, tt.want) {
+			t.Errorf("anthropic status %d: expected %v, got %v", tt.status, tt.want, err)
+		}
+		if tt.want == ErrAuth && requests != 1 {
+			t.Errorf("expected auth failures not to be retried, got %d requests", requests)
+		}
+
+		openai := NewOpenAIProvider()
+		openai.baseURL = server.URL + "/v1"
+		openai.SetBaseDelay(time.Millisecond)
+		openai.Authenticate("sk-test123")
+		if _, err := openai.Call("gpt-4", "Hello"); !errors.Is(err, tt.want) {
+			t.Errorf("openai status %d: expected %v, got %v", tt.status, tt.want, err)
+		}
+
+		server.Close()
+	}
+}
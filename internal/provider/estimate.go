@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mojomast/geoffrussy/internal/token"
+)
+
+// defaultEstimatedOutputTokens is the assumed completion size when Pricing
+// doesn't specify one
+const defaultEstimatedOutputTokens = 1000
+
+// Pricing describes how an estimate-only provider prices calls
+type Pricing struct {
+	PriceInput   float64 // per 1K tokens
+	PriceOutput  float64 // per 1K tokens
+	OutputTokens int     // Assumed completion size per call; defaults to 1000
+}
+
+// EstimateOnlyProvider never calls an API. Each Call returns empty content
+// with estimated token counts and cost so the pipeline can be run end to end
+// to total projected spend.
+type EstimateOnlyProvider struct {
+	*BaseProvider
+	pricing   Pricing
+	counter   *token.Counter
+	mu        sync.Mutex
+	totalCost float64
+	calls     int
+}
+
+// NewEstimateOnlyProvider creates a provider that prices calls without
+// making them
+func NewEstimateOnlyProvider(pricing Pricing) Provider {
+	if pricing.OutputTokens <= 0 {
+		pricing.OutputTokens = defaultEstimatedOutputTokens
+	}
+	base := NewBaseProvider("estimate")
+	base.authenticated = true
+	return &EstimateOnlyProvider{
+		BaseProvider: base,
+		pricing:      pricing,
+		counter:      token.NewCounter(nil),
+	}
+}
+
+// Authenticate is a no-op since no API is contacted
+func (e *EstimateOnlyProvider) Authenticate(apiKey string) error {
+	e.authenticated = true
+	return nil
+}
+
+// ListModels returns no models; any model name is accepted
+func (e *EstimateOnlyProvider) ListModels() ([]Model, error) {
+	return []Model{}, nil
+}
+
+// Call estimates the tokens and cost of a call without making it
+func (e *EstimateOnlyProvider) Call(model string, prompt string) (*Response, error) {
+	return e.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions estimates a call like Call, using opts.MaxTokens as the
+// projected output length when set
+func (e *EstimateOnlyProvider) CallWithOptions(model string, prompt string, opts CallOptions) (*Response, error) {
+	if opts.System != "" {
+		prompt = opts.System + "\n\n" + prompt
+	}
+
+	tokensInput, err := e.counter.CountTokens(prompt, model)
+	if err != nil {
+		return nil, err
+	}
+	tokensOutput := e.pricing.OutputTokens
+	if opts.MaxTokens > 0 {
+		tokensOutput = opts.MaxTokens
+	}
+
+	cost := (float64(tokensInput)/1000.0)*e.pricing.PriceInput +
+		(float64(tokensOutput)/1000.0)*e.pricing.PriceOutput
+
+	e.mu.Lock()
+	e.totalCost += cost
+	e.calls++
+	e.mu.Unlock()
+
+	return &Response{
+		Content:      "",
+		TokensInput:  tokensInput,
+		TokensOutput: tokensOutput,
+		Model:        model,
+		Provider:     e.Name(),
+		Timestamp:    time.Now(),
+		Cost:         cost,
+		Estimated:    true,
+	}, nil
+}
+
+// Stream returns a closed channel since there is no content to stream
+func (e *EstimateOnlyProvider) Stream(model string, prompt string) (<-chan string, error) {
+	if _, err := e.Call(model, prompt); err != nil {
+		return nil, err
+	}
+	ch := make(chan string)
+	close(ch)
+	return ch, nil
+}
+
+// Ping always succeeds since no API is contacted
+func (e *EstimateOnlyProvider) Ping(model string) error {
+	return nil
+}
+
+// TotalEstimatedCost returns the projected cost of every call made so far
+// and how many calls that covers
+func (e *EstimateOnlyProvider) TotalEstimatedCost() (float64, int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.totalCost, e.calls
+}
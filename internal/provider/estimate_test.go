@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestEstimateOnlyProvider_Call(t *testing.T) {
+	p := NewEstimateOnlyProvider(Pricing{PriceInput: 0.01, PriceOutput: 0.03, OutputTokens: 500})
+
+	if !p.IsAuthenticated() {
+		t.Fatal("Estimate-only provider should not need authentication")
+	}
+
+	resp, err := p.Call("gpt-4", "Design a task management system with user accounts and sharing")
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if resp.Content != "" {
+		t.Errorf("Expected empty content, got %q", resp.Content)
+	}
+	if !resp.Estimated {
+		t.Error("Expected response to be flagged as estimated")
+	}
+	if resp.TokensInput <= 0 {
+		t.Errorf("Expected nonzero estimated input tokens, got %d", resp.TokensInput)
+	}
+	if resp.TokensOutput != 500 {
+		t.Errorf("Expected 500 estimated output tokens, got %d", resp.TokensOutput)
+	}
+
+	expected := float64(resp.TokensInput)/1000.0*0.01 + 500.0/1000.0*0.03
+	if resp.Cost <= 0 || resp.Cost != expected {
+		t.Errorf("Expected cost %f, got %f", expected, resp.Cost)
+	}
+
+	p.Call("gpt-4", "Another prompt")
+	total, calls := p.(*EstimateOnlyProvider).TotalEstimatedCost()
+	if calls != 2 || total <= resp.Cost {
+		t.Errorf("Expected running total over 2 calls, got $%f over %d", total, calls)
+	}
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateGovernor is a token bucket that spaces out requests to stay under a
+// requests-per-minute limit. One governor can be shared by every wrapper
+// and goroutine calling the same provider, so the limit holds across all of
+// them. Callers beyond the limit queue in the order they asked.
+type RateGovernor struct {
+	interval time.Duration // Time for one request's token to refill
+	burst    float64
+
+	mu     sync.Mutex
+	tokens float64 // Negative when callers are queued for future tokens
+	last   time.Time
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewRateGovernor creates a governor allowing requestsPerMinute requests a
+// minute. Requests are spaced evenly, one per interval, so even an idle
+// governor never lets a burst through. A limit of zero or less allows
+// everything.
+func NewRateGovernor(requestsPerMinute int) *RateGovernor {
+	g := &RateGovernor{
+		now:   time.Now,
+		sleep: time.Sleep,
+	}
+	if requestsPerMinute > 0 {
+		g.interval = time.Minute / time.Duration(requestsPerMinute)
+		g.burst = 1
+		g.tokens = g.burst
+	}
+	g.last = g.now()
+	return g
+}
+
+// Wait blocks until the caller may make a request
+func (g *RateGovernor) Wait() {
+	if delay := g.reserve(); delay > 0 {
+		g.sleep(delay)
+	}
+}
+
+// reserve takes a token, refilling the bucket for the time since the last
+// reservation, and returns how long to wait before it is usable
+func (g *RateGovernor) reserve() time.Duration {
+	if g.interval <= 0 {
+		return 0
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	if elapsed := now.Sub(g.last); elapsed > 0 {
+		g.tokens += float64(elapsed) / float64(g.interval)
+		if g.tokens > g.burst {
+			g.tokens = g.burst
+		}
+		g.last = now
+	}
+
+	g.tokens--
+	if g.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-g.tokens * float64(g.interval))
+}
+
+var (
+	sharedGovernorsMu sync.Mutex
+	sharedGovernors   = make(map[string]*RateGovernor)
+)
+
+// SharedRateGovernor returns the process-wide governor for a provider,
+// creating it with requestsPerMinute on first use. Later calls return the
+// same governor whatever limit they pass.
+func SharedRateGovernor(providerName string, requestsPerMinute int) *RateGovernor {
+	sharedGovernorsMu.Lock()
+	defer sharedGovernorsMu.Unlock()
+
+	if gov, ok := sharedGovernors[providerName]; ok {
+		return gov
+	}
+	gov := NewRateGovernor(requestsPerMinute)
+	sharedGovernors[providerName] = gov
+	return gov
+}
+
+// GovernedProvider waits on a RateGovernor before every request to the
+// wrapped provider. All other methods pass through.
+type GovernedProvider struct {
+	Provider
+	gov *RateGovernor
+}
+
+// NewGovernedProvider wraps p so its requests are limited by gov, which may
+// be shared with other wrappers of the same provider
+func NewGovernedProvider(p Provider, gov *RateGovernor) *GovernedProvider {
+	return &GovernedProvider{Provider: p, gov: gov}
+}
+
+// Call waits for the governor, then calls the wrapped provider
+func (g *GovernedProvider) Call(model string, prompt string) (*Response, error) {
+	g.gov.Wait()
+	return g.Provider.Call(model, prompt)
+}
+
+// CallWithOptions waits for the governor, then calls the wrapped provider
+func (g *GovernedProvider) CallWithOptions(model string, prompt string, opts CallOptions) (*Response, error) {
+	g.gov.Wait()
+	return g.Provider.CallWithOptions(model, prompt, opts)
+}
+
+// Stream waits for the governor, then streams from the wrapped provider
+func (g *GovernedProvider) Stream(model string, prompt string) (<-chan string, error) {
+	g.gov.Wait()
+	return g.Provider.Stream(model, prompt)
+}
+
+// CallWithTools waits for the governor, then passes tool calls through
+// when the wrapped provider supports them
+func (g *GovernedProvider) CallWithTools(model string, messages []Message, tools []Tool, opts CallOptions) (*Response, error) {
+	caller, ok := g.Provider.(ToolCaller)
+	if !ok {
+		return nil, fmt.Errorf("%w by provider %s", ErrToolsUnsupported, g.Name())
+	}
+	g.gov.Wait()
+	return caller.CallWithTools(model, messages, tools, opts)
+}
+
+// Embed waits for the governor, then passes embedding requests through when
+// the wrapped provider supports them
+func (g *GovernedProvider) Embed(model string, texts []string) ([][]float32, error) {
+	embedder, ok := g.Provider.(Embedder)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support embeddings", g.Name())
+	}
+	g.gov.Wait()
+	return embedder.Embed(model, texts)
+}
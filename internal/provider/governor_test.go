@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGovernedProvider_SerializesConcurrentCalls(t *testing.T) {
+	gov := NewRateGovernor(2)
+
+	// Freeze the clock and record waits instead of sleeping through them
+	start := time.Now()
+	gov.now = func() time.Time { return start }
+	gov.last = start
+	var mu sync.Mutex
+	var waits []time.Duration
+	gov.sleep = func(d time.Duration) {
+		mu.Lock()
+		waits = append(waits, d)
+		mu.Unlock()
+	}
+
+	echo := NewEchoProvider()
+	first := NewGovernedProvider(echo, gov)
+	second := NewGovernedProvider(echo, gov) // A second wrapper sharing the limit
+
+	var wg sync.WaitGroup
+	for i, p := range []*GovernedProvider{first, second, first} {
+		wg.Add(1)
+		go func(i int, p *GovernedProvider) {
+			defer wg.Done()
+			if _, err := p.Call("test-model", "hello"); err != nil {
+				t.Errorf("Call %d failed: %v", i, err)
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	// Calls are spaced 30s apart even from idle: the first goes at once and
+	// the others wait one and two intervals
+	sort.Slice(waits, func(i, j int) bool { return waits[i] < waits[j] })
+	if len(waits) != 2 || waits[0] != 30*time.Second || waits[1] != time.Minute {
+		t.Fatalf("Expected calls to wait 30s and 1m, got %v", waits)
+	}
+
+	// A fourth call queues behind the third
+	if delay := gov.reserve(); delay != 90*time.Second {
+		t.Errorf("Expected the next call to wait 90s, got %v", delay)
+	}
+
+	// Idle time refills a single token, never a burst
+	gov.now = func() time.Time { return start.Add(10 * time.Minute) }
+	if delay := gov.reserve(); delay != 0 {
+		t.Errorf("Expected no wait after the governor idles, got %v", delay)
+	}
+	if delay := gov.reserve(); delay != 30*time.Second {
+		t.Errorf("Expected the next call to wait 30s after idling, got %v", delay)
+	}
+}
+
+func TestRateGovernor_Unlimited(t *testing.T) {
+	gov := NewRateGovernor(0)
+	for i := 0; i < 5; i++ {
+		if delay := gov.reserve(); delay != 0 {
+			t.Fatalf("Expected no limit, got a %v wait", delay)
+		}
+	}
+}
+
+func TestSharedRateGovernor(t *testing.T) {
+	a := SharedRateGovernor("governor-test", 10)
+	b := SharedRateGovernor("governor-test", 99)
+	if a != b {
+		t.Error("Expected the same governor for the same provider")
+	}
+	if SharedRateGovernor("governor-test-other", 10) == a {
+		t.Error("Expected a separate governor for another provider")
+	}
+	if a.interval != 6*time.Second {
+		t.Errorf("Expected the first limit to stick, got interval %v", a.interval)
+	}
+}
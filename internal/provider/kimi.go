@@ -35,8 +35,9 @@ type kimiRequest struct {
 	Model        string        `json:"model"`
 	Messages     []kimiMessage `json:"messages"`
 	Stream       bool          `json:"stream,omitempty"`
-	Temperature  float64       `json:"temperature,omitempty"`
+	Temperature  *float64      `json:"temperature,omitempty"`
 	MaxTokens    int           `json:"max_tokens,omitempty"`
+	TopP         float64       `json:"top_p,omitempty"`
 	CodingPlan   bool          `json:"coding_plan,omitempty"`
 	ProjectFiles []string      `json:"project_files,omitempty"`
 }
@@ -91,7 +92,9 @@ type kimiStreamChunk struct {
 }
 
 // ListModels returns the list of available models from Kimi
-func (k *KimiProvider) ListModels() ([]Model, error) {
+func (k *KimiProvider) ListModels() (_ []Model, err error) {
+	defer func() { err = k.RedactError(err) }()
+
 	if !k.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -99,42 +102,64 @@ func (k *KimiProvider) ListModels() ([]Model, error) {
 	// Kimi has a limited set of known models
 	models := []Model{
 		{
-			Provider:     "kimi",
-			Name:         "moonshot-v1-8k",
-			DisplayName:  "Moonshot v1 8K",
-			Capabilities: []string{"text", "code", "streaming", "coding-plan"},
-			PriceInput:   0.012, // 12 CNY per 1M tokens (approx $1.7)
-			PriceOutput:  0.012,
+			Provider:      "kimi",
+			Name:          "moonshot-v1-8k",
+			DisplayName:   "Moonshot v1 8K",
+			Capabilities:  []string{"text", "code", "streaming", "coding-plan"},
+			PriceInput:    0.012, // 12 CNY per 1M tokens (approx $1.7)
+			PriceOutput:   0.012,
+			ContextWindow: 8192,
 		},
 		{
-			Provider:     "kimi",
-			Name:         "moonshot-v1-32k",
-			DisplayName:  "Moonshot v1 32K",
-			Capabilities: []string{"text", "code", "streaming", "coding-plan"},
-			PriceInput:   0.024, // 24 CNY per 1M tokens (approx $3.4)
-			PriceOutput:  0.024,
+			Provider:      "kimi",
+			Name:          "moonshot-v1-32k",
+			DisplayName:   "Moonshot v1 32K",
+			Capabilities:  []string{"text", "code", "streaming", "coding-plan"},
+			PriceInput:    0.024, // 24 CNY per 1M tokens (approx $3.4)
+			PriceOutput:   0.024,
+			ContextWindow: 32768,
 		},
 		{
-			Provider:     "kimi",
-			Name:         "moonshot-v1-128k",
-			DisplayName:  "Moonshot v1 128K",
-			Capabilities: []string{"text", "code", "streaming", "coding-plan"},
-			PriceInput:   0.060, // 60 CNY per 1M tokens (approx $8.5)
-			PriceOutput:  0.060,
+			Provider:      "kimi",
+			Name:          "moonshot-v1-128k",
+			DisplayName:   "Moonshot v1 128K",
+			Capabilities:  []string{"text", "code", "streaming", "coding-plan"},
+			PriceInput:    0.060, // 60 CNY per 1M tokens (approx $8.5)
+			PriceOutput:   0.060,
+			ContextWindow: 131072,
 		},
 	}
 
 	return models, nil
 }
 
-// Call makes a non-streaming API call to Kimi
+// setTransport routes the provider's HTTP requests through rt
+func (k *KimiProvider) setTransport(rt http.RoundTripper) {
+	k.httpClient.Transport = rt
+}
+
+// Call makes a non-streaming API call to Kimi using the default options
 func (k *KimiProvider) Call(model string, prompt string) (*Response, error) {
+	return k.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions makes a non-streaming API call to Kimi with per-call sampling options
+func (k *KimiProvider) CallWithOptions(model string, prompt string, opts CallOptions) (_ *Response, err error) {
+	defer func() { err = k.RedactError(err) }()
+
 	if !k.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
 
+	if prompt, err = fitContext(model, prompt, opts); err != nil {
+		return nil, err
+	}
+
+	opts = opts.withDefaults(CallOptions{Temperature: Float64(0.7), MaxTokens: 4096})
+
+	start := time.Now()
 	var response *Response
-	err := k.RetryWithBackoff(func() error {
+	err = k.RetryWithBackoff(func() error {
 		req := kimiRequest{
 			Model: model,
 			Messages: []kimiMessage{
@@ -143,8 +168,12 @@ func (k *KimiProvider) Call(model string, prompt string) (*Response, error) {
 					Content: prompt,
 				},
 			},
-			Temperature: 0.7,
-			MaxTokens:   4096,
+			Temperature: opts.Temperature,
+			MaxTokens:   opts.MaxTokens,
+			TopP:        opts.TopP,
+		}
+		if opts.System != "" {
+			req.Messages = append([]kimiMessage{{Role: "system", Content: opts.System}}, req.Messages...)
 		}
 
 		jsonData, err := json.Marshal(req)
@@ -168,7 +197,7 @@ func (k *KimiProvider) Call(model string, prompt string) (*Response, error) {
 
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
 		}
 
 		var kimiResp kimiResponse
@@ -177,9 +206,10 @@ func (k *KimiProvider) Call(model string, prompt string) (*Response, error) {
 		}
 
 		// Extract content
-		var content string
+		var content, finishReason string
 		if len(kimiResp.Choices) > 0 {
 			content = kimiResp.Choices[0].Message.Content
+			finishReason = kimiResp.Choices[0].FinishReason
 		}
 
 		// Extract rate limit info from headers
@@ -198,16 +228,22 @@ func (k *KimiProvider) Call(model string, prompt string) (*Response, error) {
 			Provider:           "kimi",
 			Timestamp:          time.Now(),
 			RateLimitRemaining: rateLimitRemaining,
+			FinishReason:       finishReason,
 		}
 
 		return nil
 	})
 
+	if response != nil {
+		response.Latency = time.Since(start)
+	}
 	return response, err
 }
 
 // Stream makes a streaming API call to Kimi
-func (k *KimiProvider) Stream(model string, prompt string) (<-chan string, error) {
+func (k *KimiProvider) Stream(model string, prompt string) (_ <-chan string, err error) {
+	defer func() { err = k.RedactError(err) }()
+
 	if !k.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -221,7 +257,7 @@ func (k *KimiProvider) Stream(model string, prompt string) (<-chan string, error
 			},
 		},
 		Stream:      true,
-		Temperature: 0.7,
+		Temperature: Float64(0.7),
 		MaxTokens:   4096,
 	}
 
@@ -246,7 +282,7 @@ func (k *KimiProvider) Stream(model string, prompt string) (<-chan string, error
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	ch := make(chan string, 100)
@@ -282,7 +318,9 @@ func (k *KimiProvider) Stream(model string, prompt string) (<-chan string, error
 }
 
 // GetRateLimitInfo returns rate limit information from Kimi
-func (k *KimiProvider) GetRateLimitInfo() (*RateLimitInfo, error) {
+func (k *KimiProvider) GetRateLimitInfo() (_ *RateLimitInfo, err error) {
+	defer func() { err = k.RedactError(err) }()
+
 	if !k.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -349,7 +387,9 @@ func (k *KimiProvider) GetRateLimitInfo() (*RateLimitInfo, error) {
 }
 
 // GetQuotaInfo returns quota information from Kimi
-func (k *KimiProvider) GetQuotaInfo() (*QuotaInfo, error) {
+func (k *KimiProvider) GetQuotaInfo() (_ *QuotaInfo, err error) {
+	defer func() { err = k.RedactError(err) }()
+
 	if !k.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -407,3 +447,8 @@ func (k *KimiProvider) GetQuotaInfo() (*QuotaInfo, error) {
 func (k *KimiProvider) SupportsCodingPlan() bool {
 	return true
 }
+
+// Ping checks that the provider is reachable and the API key is accepted
+func (k *KimiProvider) Ping(model string) error {
+	return PingWithCall(k, model)
+}
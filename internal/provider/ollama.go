@@ -65,6 +65,7 @@ type ollamaResponse struct {
 	PromptEvalDuration int64         `json:"prompt_eval_duration,omitempty"`
 	EvalCount          int           `json:"eval_count,omitempty"`
 	EvalDuration       int64         `json:"eval_duration,omitempty"`
+	DoneReason         string        `json:"done_reason,omitempty"`
 }
 
 // ollamaModelsResponse represents the models list response
@@ -94,7 +95,7 @@ func (o *OllamaProvider) Authenticate(apiKey string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Ollama server returned status %d", resp.StatusCode)
+		return statusError(resp.StatusCode, nil, fmt.Errorf("Ollama server returned status %d", resp.StatusCode))
 	}
 
 	o.authenticated = true
@@ -115,7 +116,7 @@ func (o *OllamaProvider) ListModels() ([]Model, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	var ollamaResp ollamaModelsResponse
@@ -139,14 +140,48 @@ func (o *OllamaProvider) ListModels() ([]Model, error) {
 	return models, nil
 }
 
-// Call makes a non-streaming API call to Ollama
+// ollamaOptions maps call options onto Ollama's model options
+func ollamaOptions(opts CallOptions) map[string]interface{} {
+	opts = opts.withDefaults(CallOptions{Temperature: Float64(0.7)})
+	options := map[string]interface{}{
+		"temperature": *opts.Temperature,
+	}
+	if opts.MaxTokens > 0 {
+		options["num_predict"] = opts.MaxTokens
+	}
+	if opts.TopP > 0 {
+		options["top_p"] = opts.TopP
+	}
+	if opts.Seed != 0 {
+		options["seed"] = opts.Seed
+	}
+	return options
+}
+
+// setTransport routes the provider's HTTP requests through rt
+func (o *OllamaProvider) setTransport(rt http.RoundTripper) {
+	o.httpClient.Transport = rt
+}
+
+// Call makes a non-streaming API call to Ollama using the default options
 func (o *OllamaProvider) Call(model string, prompt string) (*Response, error) {
+	return o.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions makes a non-streaming API call to Ollama with per-call sampling options
+func (o *OllamaProvider) CallWithOptions(model string, prompt string, opts CallOptions) (*Response, error) {
 	if !o.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
 
+	prompt, err := fitContext(model, prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
 	var response *Response
-	err := o.RetryWithBackoff(func() error {
+	err = o.RetryWithBackoff(func() error {
 		// Use chat endpoint for better compatibility
 		req := ollamaChatRequest{
 			Model: model,
@@ -156,10 +191,11 @@ func (o *OllamaProvider) Call(model string, prompt string) (*Response, error) {
 					Content: prompt,
 				},
 			},
-			Stream: false,
-			Options: map[string]interface{}{
-				"temperature": 0.7,
-			},
+			Stream:  false,
+			Options: ollamaOptions(opts),
+		}
+		if opts.System != "" {
+			req.Messages = append([]ollamaMessage{{Role: "system", Content: opts.System}}, req.Messages...)
 		}
 
 		jsonData, err := json.Marshal(req)
@@ -182,7 +218,7 @@ func (o *OllamaProvider) Call(model string, prompt string) (*Response, error) {
 
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
 		}
 
 		var ollamaResp ollamaResponse
@@ -203,11 +239,16 @@ func (o *OllamaProvider) Call(model string, prompt string) (*Response, error) {
 			Model:        ollamaResp.Model,
 			Provider:     "ollama",
 			Timestamp:    time.Now(),
+			FinishReason: ollamaResp.DoneReason,
+			Seed:         opts.Seed,
 		}
 
 		return nil
 	})
 
+	if response != nil {
+		response.Latency = time.Since(start)
+	}
 	return response, err
 }
 
@@ -252,7 +293,7 @@ func (o *OllamaProvider) Stream(model string, prompt string) (<-chan string, err
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	ch := make(chan string, 100)
@@ -303,3 +344,8 @@ func (o *OllamaProvider) GetQuotaInfo() (*QuotaInfo, error) {
 	// Ollama doesn't have quotas (local deployment)
 	return nil, nil
 }
+
+// Ping checks that the provider is reachable and the API key is accepted
+func (o *OllamaProvider) Ping(model string) error {
+	return PingWithCall(o, model)
+}
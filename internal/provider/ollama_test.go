@@ -371,3 +371,12 @@ func TestOllamaProvider_CallError(t *testing.T) {
 		t.Error("expected error, got nil")
 	}
 }
+
+func TestOllamaOptions_Seed(t *testing.T) {
+	if _, ok := ollamaOptions(CallOptions{})["seed"]; ok {
+		t.Error("Expected no seed option when unset")
+	}
+	if seed := ollamaOptions(CallOptions{Seed: 7})["seed"]; seed != int64(7) {
+		t.Errorf("Expected seed 7 in options, got %v", seed)
+	}
+}
@@ -16,27 +16,69 @@ import (
 type OpenAIProvider struct {
 	*BaseProvider
 	baseURL    string
+	custom     bool // Set when pointed at a non-OpenAI endpoint
 	httpClient *http.Client
 }
 
+// defaultOpenAIBaseURL is the official OpenAI API endpoint
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
 // NewOpenAIProvider creates a new OpenAI provider
 func NewOpenAIProvider() *OpenAIProvider {
+	return NewOpenAIProviderWithBaseURL("")
+}
+
+// NewOpenAIProviderWithBaseURL creates an OpenAI provider that talks to an
+// OpenAI-compatible endpoint such as LM Studio or vLLM. An empty baseURL
+// uses the official API.
+func NewOpenAIProviderWithBaseURL(baseURL string) *OpenAIProvider {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
 	return &OpenAIProvider{
 		BaseProvider: NewBaseProvider("openai"),
-		baseURL:      "https://api.openai.com/v1",
+		baseURL:      baseURL,
+		custom:       baseURL != defaultOpenAIBaseURL,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
 	}
 }
 
+// BaseURL returns the endpoint requests are sent to
+func (o *OpenAIProvider) BaseURL() string {
+	return o.baseURL
+}
+
+// Authenticate stores the API key. Custom endpoints are usually local
+// servers without auth, so an empty key is accepted for them.
+func (o *OpenAIProvider) Authenticate(apiKey string) (err error) {
+	defer func() { err = redactSecret(err, apiKey) }()
+
+	if apiKey == "" && o.custom {
+		o.authenticated = true
+		return nil
+	}
+	return o.BaseProvider.Authenticate(apiKey)
+}
+
+// setAuthHeader adds the bearer token when an API key is configured
+func (o *OpenAIProvider) setAuthHeader(req *http.Request) {
+	if apiKey := o.GetAPIKey(); apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+}
+
 // openAIRequest represents a request to OpenAI API
 type openAIRequest struct {
 	Model       string          `json:"model"`
 	Messages    []openAIMessage `json:"messages"`
 	Stream      bool            `json:"stream,omitempty"`
-	Temperature float64         `json:"temperature,omitempty"`
+	Temperature *float64        `json:"temperature,omitempty"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
+	TopP        float64         `json:"top_p,omitempty"`
+	Seed        int64           `json:"seed,omitempty"`
 }
 
 type openAIMessage struct {
@@ -65,6 +107,50 @@ type openAIResponse struct {
 	} `json:"usage"`
 }
 
+// openAIToolRequest is a chat completion request offering tools
+type openAIToolRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Tools       []openAITool    `json:"tools,omitempty"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	TopP        float64         `json:"top_p,omitempty"`
+	Seed        int64           `json:"seed,omitempty"`
+}
+
+type openAITool struct {
+	Type     string             `json:"type"`
+	Function openAIToolFunction `json:"function"`
+}
+
+type openAIToolFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// openAIToolResponse is a chat completion response that may hold tool calls
+type openAIToolResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				ID       string `json:"id"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
 // openAIModelsResponse represents the models list response
 type openAIModelsResponse struct {
 	Data []struct {
@@ -92,7 +178,9 @@ type openAIStreamChunk struct {
 }
 
 // ListModels returns the list of available models from OpenAI
-func (o *OpenAIProvider) ListModels() ([]Model, error) {
+func (o *OpenAIProvider) ListModels() (_ []Model, err error) {
+	defer func() { err = o.RedactError(err) }()
+
 	if !o.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -102,7 +190,7 @@ func (o *OpenAIProvider) ListModels() ([]Model, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+o.GetAPIKey())
+	o.setAuthHeader(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	var resp *http.Response
@@ -112,9 +200,15 @@ func (o *OpenAIProvider) ListModels() ([]Model, error) {
 		if reqErr != nil {
 			return reqErr
 		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			// Rate limits clear with time, so back off and try again
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
+		}
 		if resp.StatusCode >= 500 {
 			resp.Body.Close()
-			return fmt.Errorf("server error: %d", resp.StatusCode)
+			return statusError(resp.StatusCode, nil, fmt.Errorf("server error: %d", resp.StatusCode))
 		}
 		return nil
 	})
@@ -126,7 +220,7 @@ func (o *OpenAIProvider) ListModels() ([]Model, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
 	}
 
 	var modelsResp openAIModelsResponse
@@ -136,12 +230,13 @@ func (o *OpenAIProvider) ListModels() ([]Model, error) {
 
 	models := make([]Model, 0, len(modelsResp.Data))
 	for _, m := range modelsResp.Data {
-		// Only include chat models
-		if strings.Contains(m.ID, "gpt") {
+		// Only include chat models; custom endpoints serve arbitrary model names
+		if o.custom || strings.Contains(m.ID, "gpt") {
 			model := Model{
-				Provider:    o.Name(),
-				Name:        m.ID,
-				DisplayName: m.ID,
+				Provider:      o.Name(),
+				Name:          m.ID,
+				DisplayName:   m.ID,
+				ContextWindow: ContextWindow(m.ID),
 			}
 
 			// Set pricing based on known models
@@ -165,12 +260,28 @@ func (o *OpenAIProvider) ListModels() ([]Model, error) {
 	return models, nil
 }
 
-// Call makes a synchronous API call to OpenAI
+// setTransport routes the provider's HTTP requests through rt
+func (o *OpenAIProvider) setTransport(rt http.RoundTripper) {
+	o.httpClient.Transport = rt
+}
+
+// Call makes a synchronous API call to OpenAI using the default options
 func (o *OpenAIProvider) Call(model string, prompt string) (*Response, error) {
+	return o.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions makes a synchronous API call to OpenAI with per-call sampling options
+func (o *OpenAIProvider) CallWithOptions(model string, prompt string, opts CallOptions) (_ *Response, err error) {
+	defer func() { err = o.RedactError(err) }()
+
 	if !o.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
 
+	if prompt, err = fitContext(model, prompt, opts); err != nil {
+		return nil, err
+	}
+
 	reqBody := openAIRequest{
 		Model: model,
 		Messages: []openAIMessage{
@@ -179,7 +290,14 @@ func (o *OpenAIProvider) Call(model string, prompt string) (*Response, error) {
 				Content: prompt,
 			},
 		},
-		Stream: false,
+		Stream:      false,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+		TopP:        opts.TopP,
+		Seed:        opts.Seed,
+	}
+	if opts.System != "" {
+		reqBody.Messages = append([]openAIMessage{{Role: "system", Content: opts.System}}, reqBody.Messages...)
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -187,39 +305,13 @@ func (o *OpenAIProvider) Call(model string, prompt string) (*Response, error) {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	var resp *http.Response
-	err = o.RetryWithBackoff(func() error {
-		// Create a new request for each retry attempt
-		req, reqErr := http.NewRequest("POST", o.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
-		if reqErr != nil {
-			return fmt.Errorf("failed to create request: %w", reqErr)
-		}
-
-		req.Header.Set("Authorization", "Bearer "+o.GetAPIKey())
-		req.Header.Set("Content-Type", "application/json")
-
-		var httpErr error
-		resp, httpErr = o.httpClient.Do(req)
-		if httpErr != nil {
-			return httpErr
-		}
-		if resp.StatusCode >= 500 {
-			resp.Body.Close()
-			return fmt.Errorf("server error: %d", resp.StatusCode)
-		}
-		return nil
-	})
-
+	start := time.Now()
+	resp, err := o.postChatCompletion(jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-
 	// Extract rate limit info from headers
 	rateLimitRemaining := 0
 	if val := resp.Header.Get("X-RateLimit-Remaining-Requests"); val != "" {
@@ -253,12 +345,142 @@ func (o *OpenAIProvider) Call(model string, prompt string) (*Response, error) {
 		Provider:           o.Name(),
 		Timestamp:          time.Now(),
 		RateLimitRemaining: rateLimitRemaining,
+		FinishReason:       openAIResp.Choices[0].FinishReason,
+		Latency:            time.Since(start),
+		Seed:               opts.Seed,
 		QuotaRemaining:     quotaRemaining,
 	}, nil
 }
 
+// CallWithTools makes a chat completion call offering tools as functions.
+// Tool calls the model makes are returned in Response.ToolCalls.
+func (o *OpenAIProvider) CallWithTools(model string, messages []Message, tools []Tool, opts CallOptions) (_ *Response, err error) {
+	defer func() { err = o.RedactError(err) }()
+
+	if !o.IsAuthenticated() {
+		return nil, fmt.Errorf("provider not authenticated")
+	}
+
+	if messages, err = fitMessages(model, messages, tools, opts); err != nil {
+		return nil, err
+	}
+
+	reqBody := openAIToolRequest{
+		Model:       model,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+		TopP:        opts.TopP,
+		Seed:        opts.Seed,
+	}
+	for _, m := range withSystemOption(messages, opts) {
+		reqBody.Messages = append(reqBody.Messages, openAIMessage{Role: m.Role, Content: m.Content})
+	}
+	for _, tool := range tools {
+		reqBody.Tools = append(reqBody.Tools, openAITool{
+			Type: "function",
+			Function: openAIToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := o.postChatCompletion(jsonData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var toolResp openAIToolResponse
+	if err := json.NewDecoder(resp.Body).Decode(&toolResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(toolResp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in response")
+	}
+
+	choice := toolResp.Choices[0]
+	response := &Response{
+		Content:      choice.Message.Content,
+		TokensInput:  toolResp.Usage.PromptTokens,
+		TokensOutput: toolResp.Usage.CompletionTokens,
+		Model:        model,
+		Provider:     o.Name(),
+		Timestamp:    time.Now(),
+		FinishReason: choice.FinishReason,
+		Latency:      time.Since(start),
+	}
+	for _, call := range choice.Message.ToolCalls {
+		response.ToolCalls = append(response.ToolCalls, ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	return response, nil
+}
+
+// postChatCompletion sends a chat completion request body, retrying server
+// errors. The caller closes the body of the returned OK response.
+func (o *OpenAIProvider) postChatCompletion(jsonData []byte) (*http.Response, error) {
+	return o.postJSON("/chat/completions", jsonData)
+}
+
+// postJSON sends a JSON request body to an API path, retrying server errors.
+// The caller closes the body of the returned OK response.
+func (o *OpenAIProvider) postJSON(path string, jsonData []byte) (*http.Response, error) {
+	var resp *http.Response
+	err := o.RetryWithBackoff(func() error {
+		// Create a new request for each retry attempt
+		req, reqErr := http.NewRequest("POST", o.baseURL+path, bytes.NewBuffer(jsonData))
+		if reqErr != nil {
+			return fmt.Errorf("failed to create request: %w", reqErr)
+		}
+
+		o.setAuthHeader(req)
+		req.Header.Set("Content-Type", "application/json")
+
+		var httpErr error
+		resp, httpErr = o.httpClient.Do(req)
+		if httpErr != nil {
+			return httpErr
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			// Rate limits clear with time, so back off and try again
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
+		}
+		if resp.StatusCode >= 500 {
+			resp.Body.Close()
+			return statusError(resp.StatusCode, nil, fmt.Errorf("server error: %d", resp.StatusCode))
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
+	}
+	return resp, nil
+}
+
 // Stream makes a streaming API call to OpenAI
-func (o *OpenAIProvider) Stream(model string, prompt string) (<-chan string, error) {
+func (o *OpenAIProvider) Stream(model string, prompt string) (_ <-chan string, err error) {
+	defer func() { err = o.RedactError(err) }()
+
 	if !o.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -284,7 +506,7 @@ func (o *OpenAIProvider) Stream(model string, prompt string) (<-chan string, err
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+o.GetAPIKey())
+	o.setAuthHeader(req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "text/event-stream")
 
@@ -296,7 +518,7 @@ func (o *OpenAIProvider) Stream(model string, prompt string) (<-chan string, err
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
 	}
 
 	ch := make(chan string, 10)
@@ -367,3 +589,8 @@ func (o *OpenAIProvider) GetQuotaInfo() (*QuotaInfo, error) {
 		ResetAt:         time.Time{},
 	}, nil
 }
+
+// Ping checks that the provider is reachable and the API key is accepted
+func (o *OpenAIProvider) Ping(model string) error {
+	return PingWithCall(o, model)
+}
@@ -25,6 +25,61 @@ func TestNewOpenAIProvider(t *testing.T) {
 	}
 }
 
+func TestOpenAIProvider_CustomBaseURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Expected no Authorization header for keyless server, got %q", auth)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/models":
+			w.Write([]byte(`{"data": [{"id": "llama3", "object": "model"}]}`))
+		case "/v1/chat/completions":
+			w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "hi"}}], "usage": {"prompt_tokens": 1, "completion_tokens": 1}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProviderWithBaseURL(server.URL + "/v1/")
+	if provider.BaseURL() != server.URL+"/v1" {
+		t.Errorf("Expected trailing slash to be trimmed, got %s", provider.BaseURL())
+	}
+
+	// Local servers don't need a key
+	if err := provider.Authenticate(""); err != nil {
+		t.Fatalf("Expected keyless auth for custom base URL, got %v", err)
+	}
+
+	models, err := provider.ListModels()
+	if err != nil {
+		t.Fatalf("ListModels failed: %v", err)
+	}
+	if len(models) != 1 || models[0].Name != "llama3" {
+		t.Errorf("Expected non-GPT model from custom endpoint, got %+v", models)
+	}
+
+	resp, err := provider.Call("llama3", "hello")
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if resp.Content != "hi" {
+		t.Errorf("Expected content 'hi', got %q", resp.Content)
+	}
+
+	if len(paths) != 2 || paths[0] != "/v1/models" || paths[1] != "/v1/chat/completions" {
+		t.Errorf("Expected requests against the custom base URL, got %v", paths)
+	}
+
+	if NewOpenAIProviderWithBaseURL("").BaseURL() != "https://api.openai.com/v1" {
+		t.Error("Expected empty base URL to default to the official API")
+	}
+}
+
 func TestOpenAIProvider_Authenticate(t *testing.T) {
 	provider := NewOpenAIProvider()
 
@@ -286,6 +341,7 @@ func TestOpenAIProvider_Call(t *testing.T) {
 
 			provider := NewOpenAIProvider()
 			provider.baseURL = server.URL + "/v1"
+			provider.SetBaseDelay(time.Millisecond)
 
 			if tt.authenticated {
 				provider.Authenticate("sk-test123")
@@ -331,6 +387,84 @@ func TestOpenAIProvider_Call(t *testing.T) {
 	}
 }
 
+func TestOpenAIProvider_CallWithTools(t *testing.T) {
+	var gotRequest map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotRequest); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"model": "gpt-4",
+			"choices": [{
+				"index": 0,
+				"message": {
+					"role": "assistant",
+					"content": null,
+					"tool_calls": [{
+						"id": "call_abc",
+						"type": "function",
+						"function": {"name": "submit_phase", "arguments": "{\"number\": 0}"}
+					}]
+				},
+				"finish_reason": "tool_calls"
+			}],
+			"usage": {"prompt_tokens": 12, "completion_tokens": 8, "total_tokens": 20}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider()
+	provider.baseURL = server.URL + "/v1"
+	provider.Authenticate("sk-test123")
+
+	tools := []Tool{{
+		Name:        "submit_phase",
+		Description: "Submit a phase",
+		Parameters:  map[string]interface{}{"type": "object"},
+	}}
+	messages := []Message{
+		{Role: "system", Content: "Use the tool"},
+		{Role: "user", Content: "Plan it"},
+	}
+
+	resp, err := provider.CallWithTools("gpt-4", messages, tools, CallOptions{})
+	if err != nil {
+		t.Fatalf("CallWithTools() error = %v", err)
+	}
+
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("Expected 1 tool call, got %d", len(resp.ToolCalls))
+	}
+	call := resp.ToolCalls[0]
+	if call.ID != "call_abc" || call.Name != "submit_phase" || call.Arguments != `{"number": 0}` {
+		t.Errorf("Unexpected tool call: %+v", call)
+	}
+	if resp.FinishReason != FinishReasonToolCalls {
+		t.Errorf("Expected finish reason %q, got %q", FinishReasonToolCalls, resp.FinishReason)
+	}
+	if resp.TokensInput != 12 || resp.TokensOutput != 8 {
+		t.Errorf("Expected 12/8 tokens, got %d/%d", resp.TokensInput, resp.TokensOutput)
+	}
+
+	sentTools, _ := gotRequest["tools"].([]interface{})
+	if len(sentTools) != 1 {
+		t.Fatalf("Expected 1 tool in request, got %v", gotRequest["tools"])
+	}
+	function, _ := sentTools[0].(map[string]interface{})["function"].(map[string]interface{})
+	if function["name"] != "submit_phase" {
+		t.Errorf("Expected submit_phase function in request, got %v", sentTools[0])
+	}
+	if sentMessages, _ := gotRequest["messages"].([]interface{}); len(sentMessages) != 2 {
+		t.Errorf("Expected 2 messages in request, got %v", gotRequest["messages"])
+	}
+
+	unauthenticated := NewOpenAIProvider()
+	if _, err := unauthenticated.CallWithTools("gpt-4", messages, tools, CallOptions{}); err == nil {
+		t.Error("Expected error when not authenticated")
+	}
+}
+
 func TestOpenAIProvider_Stream(t *testing.T) {
 	tests := []struct {
 		name           string
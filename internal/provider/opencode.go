@@ -117,12 +117,27 @@ func (o *OpenCodeProvider) ListModels() ([]Model, error) {
 
 // Call makes a non-streaming API call using OpenCode CLI
 func (o *OpenCodeProvider) Call(model string, prompt string) (*Response, error) {
+	return o.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions makes a non-streaming API call using OpenCode CLI. The CLI
+// has no sampling flags, so only opts.System is used, prepended to the prompt.
+func (o *OpenCodeProvider) CallWithOptions(model string, prompt string, opts CallOptions) (*Response, error) {
 	if !o.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
 
+	prompt, err := fitContext(model, prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.System != "" {
+		prompt = opts.System + "\n\n" + prompt
+	}
+
 	var response *Response
-	err := o.RetryWithBackoff(func() error {
+	err = o.RetryWithBackoff(func() error {
 		// Use opencode run command
 		cmd := exec.Command(o.opencodeCmd, "run", "--model", model, "--prompt", prompt, "--no-stream")
 
@@ -149,6 +164,7 @@ func (o *OpenCodeProvider) Call(model string, prompt string) (*Response, error)
 			Model:        model,
 			Provider:     "opencode",
 			Timestamp:    startTime,
+			Latency:      time.Since(startTime),
 		}
 
 		return nil
@@ -204,3 +220,8 @@ func (o *OpenCodeProvider) GetQuotaInfo() (*QuotaInfo, error) {
 	// OpenCode abstracts away provider-specific quotas
 	return nil, nil
 }
+
+// Ping checks that the provider is reachable and the API key is accepted
+func (o *OpenCodeProvider) Ping(model string) error {
+	return PingWithCall(o, model)
+}
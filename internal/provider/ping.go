@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNetwork indicates the provider could not be reached
+var ErrNetwork = errors.New("network error")
+
+// pingPrompt is the smallest useful prompt for checking a provider end to end
+const pingPrompt = "Reply with the single word: pong"
+
+// apiStatusPattern matches the status code in provider API error messages
+var apiStatusPattern = regexp.MustCompile(`API error (?:\(status )?(\d{3})`)
+
+// PingWithCall checks a provider by issuing a tiny Call. When model is empty
+// the first model reported by the provider is used. Auth and network failures
+// are wrapped with ErrAuth and ErrNetwork respectively.
+func PingWithCall(p Provider, model string) error {
+	if !p.IsAuthenticated() {
+		return fmt.Errorf("%w: provider %s is not authenticated", ErrAuth, p.Name())
+	}
+
+	if model == "" {
+		models, err := p.ListModels()
+		if err != nil {
+			return classifyPingError(fmt.Errorf("failed to list models: %w", err))
+		}
+		if len(models) == 0 {
+			return fmt.Errorf("provider %s has no models to ping", p.Name())
+		}
+		model = models[0].Name
+	}
+
+	if _, err := p.Call(model, pingPrompt); err != nil {
+		return classifyPingError(err)
+	}
+	return nil
+}
+
+// PingAll pings every provider concurrently with its default model and
+// returns the result keyed by provider name; a nil value means healthy
+func PingAll(providers map[string]Provider) map[string]error {
+	results := make(map[string]error, len(providers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, p := range providers {
+		wg.Add(1)
+		go func(name string, p Provider) {
+			defer wg.Done()
+			err := p.Ping("")
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}(name, p)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// classifyPingError wraps err with ErrAuth or ErrNetwork when it can tell
+// which kind of failure occurred
+func classifyPingError(err error) error {
+	if errors.Is(err, ErrAuth) || errors.Is(err, ErrNetwork) {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %v", ErrNetwork, err)
+	}
+
+	message := err.Error()
+	if match := apiStatusPattern.FindStringSubmatch(message); match != nil {
+		if status, _ := strconv.Atoi(match[1]); status == 401 || status == 403 {
+			return fmt.Errorf("%w: %v", ErrAuth, err)
+		}
+	}
+	if strings.Contains(message, "not authenticated") {
+		return fmt.Errorf("%w: %v", ErrAuth, err)
+	}
+
+	return err
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+// pingMockProvider is a minimal Provider whose Call returns a fixed error
+type pingMockProvider struct {
+	*BaseProvider
+	callErr error
+}
+
+func newPingMockProvider(name string, callErr error) *pingMockProvider {
+	p := &pingMockProvider{BaseProvider: NewBaseProvider(name), callErr: callErr}
+	p.Authenticate("test-key")
+	return p
+}
+
+func (m *pingMockProvider) ListModels() ([]Model, error) {
+	return []Model{{Provider: m.Name(), Name: "mock-model"}}, nil
+}
+
+func (m *pingMockProvider) Call(model string, prompt string) (*Response, error) {
+	if m.callErr != nil {
+		return nil, m.callErr
+	}
+	return &Response{Content: "pong", Model: model, Provider: m.Name()}, nil
+}
+
+func (m *pingMockProvider) CallWithOptions(model string, prompt string, opts CallOptions) (*Response, error) {
+	return m.Call(model, prompt)
+}
+
+func (m *pingMockProvider) Stream(model string, prompt string) (<-chan string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *pingMockProvider) Ping(model string) error {
+	return PingWithCall(m, model)
+}
+
+func TestPingWithCall_AuthError(t *testing.T) {
+	p := newPingMockProvider("mock", fmt.Errorf("API error 401: invalid api key"))
+
+	err := p.Ping("")
+	if !errors.Is(err, ErrAuth) {
+		t.Errorf("expected ErrAuth, got %v", err)
+	}
+	if errors.Is(err, ErrNetwork) {
+		t.Error("auth error should not be reported as a network error")
+	}
+}
+
+func TestPingWithCall_NetworkError(t *testing.T) {
+	netErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	p := newPingMockProvider("mock", fmt.Errorf("failed to send request: %w", netErr))
+
+	if err := p.Ping("mock-model"); !errors.Is(err, ErrNetwork) {
+		t.Errorf("expected ErrNetwork, got %v", err)
+	}
+}
+
+func TestPingWithCall_NotAuthenticated(t *testing.T) {
+	p := &pingMockProvider{BaseProvider: NewBaseProvider("mock")}
+
+	if err := p.Ping("mock-model"); !errors.Is(err, ErrAuth) {
+		t.Errorf("expected ErrAuth, got %v", err)
+	}
+}
+
+func TestPingAll(t *testing.T) {
+	providers := map[string]Provider{
+		"healthy": newPingMockProvider("healthy", nil),
+		"badkey":  newPingMockProvider("badkey", fmt.Errorf("API error (status 403): forbidden")),
+	}
+
+	results := PingAll(providers)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results["healthy"] != nil {
+		t.Errorf("expected healthy provider to pass, got %v", results["healthy"])
+	}
+	if !errors.Is(results["badkey"], ErrAuth) {
+		t.Errorf("expected ErrAuth for badkey, got %v", results["badkey"])
+	}
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"fmt"
 	"math"
+	"strings"
 	"time"
 )
 
@@ -14,10 +15,56 @@ type Provider interface {
 	ListModels() ([]Model, error)
 	DiscoverModels() ([]Model, error) // For dynamic discovery (OpenCode)
 	Call(model string, prompt string) (*Response, error)
+	CallWithOptions(model string, prompt string, opts CallOptions) (*Response, error)
 	Stream(model string, prompt string) (<-chan string, error)
 	GetRateLimitInfo() (*RateLimitInfo, error)
 	GetQuotaInfo() (*QuotaInfo, error)
 	SupportsCodingPlan() bool // For Z.ai and Kimi
+	Ping(model string) error  // Health check; empty model uses the provider default
+}
+
+// CallOptions tunes a single call. Zero values keep the provider's defaults.
+type CallOptions struct {
+	// Temperature is a pointer so zero can be asked for; nil keeps the
+	// provider's default. Float64 makes one from a literal.
+	Temperature *float64
+	MaxTokens   int
+	TopP        float64
+
+	// System is sent separately from the prompt. Providers that support
+	// prompt caching mark it cacheable, so a large preamble shared by
+	// repeated calls is billed at the cached rate.
+	System string
+
+	// ContextStrategy decides what happens when the prompt doesn't fit in
+	// the model's context window. The default fails the call before any
+	// request is sent.
+	ContextStrategy ContextStrategy
+
+	// Seed asks providers that support it to sample deterministically, so
+	// the same prompt and seed give the same output. Zero leaves sampling
+	// unseeded.
+	Seed int64
+}
+
+// Float64 returns a pointer to v, for optional settings such as
+// CallOptions.Temperature
+func Float64(v float64) *float64 {
+	return &v
+}
+
+// withDefaults fills any unset options from the provider's defaults
+func (o CallOptions) withDefaults(defaults CallOptions) CallOptions {
+	if o.Temperature == nil {
+		o.Temperature = defaults.Temperature
+	}
+	if o.MaxTokens == 0 {
+		o.MaxTokens = defaults.MaxTokens
+	}
+	if o.TopP == 0 {
+		o.TopP = defaults.TopP
+	}
+	return o
 }
 
 // Response represents a response from an AI model provider
@@ -30,6 +77,41 @@ type Response struct {
 	Timestamp          time.Time
 	RateLimitRemaining int
 	QuotaRemaining     int
+	Cost               float64       // Only set by providers that price their own calls
+	Estimated          bool          // True when no API call was made and tokens are estimates
+	CacheReadTokens    int           // Input tokens served from the provider's prompt cache
+	CacheWriteTokens   int           // Input tokens written to the provider's prompt cache
+	FinishReason       string        // Why generation stopped: FinishReasonStop, FinishReasonLength or a provider-specific value
+	Latency            time.Duration // Wall-clock time of the call, including retries
+	RequestedModel     string        // Model the caller asked for, set only when a wrapper substituted Model
+	Seed               int64         // Seed sent with the request, zero when the call was unseeded or the provider doesn't support seeding
+	ToolCalls          []ToolCall    // Tools the model asked to call, only from CallWithTools
+}
+
+// Finish reasons reported in Response.FinishReason
+const (
+	FinishReasonStop      = "stop"
+	FinishReasonLength    = "length"
+	FinishReasonToolCalls = "tool_calls"
+)
+
+// Truncated reports whether the output was cut off by the token limit
+func (r *Response) Truncated() bool {
+	return r.FinishReason == FinishReasonLength
+}
+
+// normalizeFinishReason maps provider-specific stop reasons onto the shared
+// FinishReason values, passing unknown ones through unchanged
+func normalizeFinishReason(reason string) string {
+	switch reason {
+	case "end_turn", "stop_sequence":
+		return FinishReasonStop
+	case "max_tokens":
+		return FinishReasonLength
+	case "tool_use":
+		return FinishReasonToolCalls
+	}
+	return reason
 }
 
 // RateLimitInfo contains rate limiting information from a provider
@@ -57,6 +139,8 @@ type Model struct {
 	Capabilities []string
 	PriceInput   float64 // per 1K tokens
 	PriceOutput  float64 // per 1K tokens
+	// ContextWindow is the model's context size in tokens, 0 when unknown
+	ContextWindow int
 }
 
 // BaseProvider provides common functionality for all providers
@@ -83,7 +167,9 @@ func (b *BaseProvider) Name() string {
 }
 
 // Authenticate stores the API key
-func (b *BaseProvider) Authenticate(apiKey string) error {
+func (b *BaseProvider) Authenticate(apiKey string) (err error) {
+	defer func() { err = redactSecret(err, apiKey) }()
+
 	if apiKey == "" {
 		return fmt.Errorf("API key cannot be empty")
 	}
@@ -102,6 +188,60 @@ func (b *BaseProvider) GetAPIKey() string {
 	return b.apiKey
 }
 
+// redactedPlaceholder replaces secrets scrubbed from error messages
+const redactedPlaceholder = "[REDACTED]"
+
+// redactedError carries a scrubbed message. Its chain holds scrubbed copies
+// of the errors it wraps, so errors.Is and errors.As still match sentinels
+// and other errors that never contained the secret, but unwrapping can't
+// reach the unredacted original.
+type redactedError struct {
+	msg  string
+	errs []error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() []error {
+	return e.errs
+}
+
+// RedactError scrubs the configured API key from an error message so it
+// cannot leak into logs or blocker descriptions. Returns err unchanged when
+// it is nil or does not contain the key.
+func (b *BaseProvider) RedactError(err error) error {
+	return redactSecret(err, b.apiKey)
+}
+
+// redactSecret replaces secret in err's message, and in the messages of
+// every error it wraps, with a placeholder. Errors without the secret are
+// kept as they are.
+func redactSecret(err error, secret string) error {
+	if err == nil || secret == "" {
+		return err
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, secret) {
+		return err
+	}
+
+	redacted := &redactedError{msg: strings.ReplaceAll(msg, secret, redactedPlaceholder)}
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := wrapped.Unwrap(); inner != nil {
+			redacted.errs = []error{redactSecret(inner, secret)}
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range wrapped.Unwrap() {
+			redacted.errs = append(redacted.errs, redactSecret(inner, secret))
+		}
+	}
+	return redacted
+}
+
 // SetMaxRetries sets the maximum number of retries
 func (b *BaseProvider) SetMaxRetries(maxRetries int) {
 	b.maxRetries = maxRetries
@@ -112,7 +252,8 @@ func (b *BaseProvider) SetBaseDelay(delay time.Duration) {
 	b.baseDelay = delay
 }
 
-// RetryWithBackoff executes a function with exponential backoff retry logic
+// RetryWithBackoff executes a function with exponential backoff retry logic.
+// Auth and context length errors are returned at once without retrying.
 func (b *BaseProvider) RetryWithBackoff(fn func() error) error {
 	var lastErr error
 
@@ -122,6 +263,11 @@ func (b *BaseProvider) RetryWithBackoff(fn func() error) error {
 			return nil
 		}
 
+		// Another attempt would be rejected the same way
+		if isPermanentError(err) {
+			return err
+		}
+
 		lastErr = err
 
 		// Don't retry on last attempt
@@ -2,6 +2,10 @@ package provider
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -215,3 +219,74 @@ func TestBaseProvider_SupportsCodingPlan(t *testing.T) {
 		t.Error("Expected false for default implementation")
 	}
 }
+
+func TestBaseProvider_RedactError(t *testing.T) {
+	bp := NewBaseProvider("test-provider")
+	bp.Authenticate("sk-secret-123")
+
+	original := errors.New("request to https://api.example.com/?key=sk-secret-123 failed: invalid key sk-secret-123")
+	redacted := bp.RedactError(original)
+
+	if strings.Contains(redacted.Error(), "sk-secret-123") {
+		t.Errorf("Expected key to be scrubbed, got: %s", redacted.Error())
+	}
+	expected := "request to https://api.example.com/?key=[REDACTED] failed: invalid key [REDACTED]"
+	if redacted.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, redacted.Error())
+	}
+	if errors.Is(redacted, original) {
+		t.Error("Expected the unredacted original to be unreachable")
+	}
+
+	classified := bp.RedactError(statusError(http.StatusUnauthorized, nil, fmt.Errorf("invalid key sk-secret-123: %w", errors.New("rejected sk-secret-123"))))
+	if !errors.Is(classified, ErrAuth) {
+		t.Error("Expected the redacted error to still match ErrAuth")
+	}
+	for chain := []error{classified}; len(chain) > 0; {
+		err := chain[0]
+		chain = chain[1:]
+		if strings.Contains(err.Error(), "sk-secret-123") {
+			t.Errorf("Expected every wrapped error to be scrubbed, got: %s", err.Error())
+		}
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() error }:
+			if inner := wrapped.Unwrap(); inner != nil {
+				chain = append(chain, inner)
+			}
+		case interface{ Unwrap() []error }:
+			chain = append(chain, wrapped.Unwrap()...)
+		}
+	}
+
+	unrelated := errors.New("connection refused")
+	if bp.RedactError(unrelated) != unrelated {
+		t.Error("Expected error without the key to be returned unchanged")
+	}
+	if bp.RedactError(nil) != nil {
+		t.Error("Expected nil to stay nil")
+	}
+}
+
+func TestOpenAIProvider_Call_RedactsKeyInError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Echo the bearer token back the way some APIs do on auth failures
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": "invalid api key: ` + strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") + `"}`))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider()
+	p.baseURL = server.URL + "/v1"
+	p.Authenticate("sk-secret-123")
+
+	_, err := p.Call("gpt-4", "hello")
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if strings.Contains(err.Error(), "sk-secret-123") {
+		t.Errorf("Expected key to be scrubbed, got: %s", err.Error())
+	}
+	if !strings.Contains(err.Error(), "[REDACTED]") {
+		t.Errorf("Expected [REDACTED] placeholder, got: %s", err.Error())
+	}
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"os"
 	"sort"
 )
 
@@ -14,7 +15,7 @@ var Registry = map[string]ProviderFactory{
 	"firmware":  func() Provider { return NewFirmwareProvider() },
 	"kimi":      func() Provider { return NewKimiProvider() },
 	"ollama":    func() Provider { return NewOllamaProvider("") }, // Default URL
-	"openai":    func() Provider { return NewOpenAIProvider() },
+	"openai":    func() Provider { return NewOpenAIProviderWithBaseURL(os.Getenv("GEOFFRUSSY_OPENAI_BASE_URL")) },
 	"opencode":  func() Provider { return NewOpenCodeProvider() },
 	"requesty":  func() Provider { return NewRequestyProvider() },
 	"zai":       func() Provider { return NewZAIProvider() },
@@ -33,8 +33,10 @@ type requestyRequest struct {
 	Model       string            `json:"model"`
 	Messages    []requestyMessage `json:"messages"`
 	Stream      bool              `json:"stream,omitempty"`
-	Temperature float64           `json:"temperature,omitempty"`
+	Temperature *float64          `json:"temperature,omitempty"`
 	MaxTokens   int               `json:"max_tokens,omitempty"`
+	TopP        float64           `json:"top_p,omitempty"`
+	Seed        int64             `json:"seed,omitempty"`
 }
 
 type requestyMessage struct {
@@ -74,7 +76,9 @@ type requestyModelsResponse struct {
 }
 
 // ListModels returns the list of available models from Requesty.ai
-func (r *RequestyProvider) ListModels() ([]Model, error) {
+func (r *RequestyProvider) ListModels() (_ []Model, err error) {
+	defer func() { err = r.RedactError(err) }()
+
 	if !r.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -94,9 +98,15 @@ func (r *RequestyProvider) ListModels() ([]Model, error) {
 		if reqErr != nil {
 			return reqErr
 		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			// Rate limits clear with time, so back off and try again
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
+		}
 		if resp.StatusCode >= 500 {
 			resp.Body.Close()
-			return fmt.Errorf("server error: %d", resp.StatusCode)
+			return statusError(resp.StatusCode, nil, fmt.Errorf("server error: %d", resp.StatusCode))
 		}
 		return nil
 	})
@@ -108,7 +118,7 @@ func (r *RequestyProvider) ListModels() ([]Model, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
 	}
 
 	var modelsResp requestyModelsResponse
@@ -131,12 +141,28 @@ func (r *RequestyProvider) ListModels() ([]Model, error) {
 	return models, nil
 }
 
-// Call makes a synchronous API call to Requesty.ai
+// setTransport routes the provider's HTTP requests through rt
+func (r *RequestyProvider) setTransport(rt http.RoundTripper) {
+	r.httpClient.Transport = rt
+}
+
+// Call makes a synchronous API call to Requesty.ai using the default options
 func (r *RequestyProvider) Call(model string, prompt string) (*Response, error) {
+	return r.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions makes a synchronous API call to Requesty.ai with per-call sampling options
+func (r *RequestyProvider) CallWithOptions(model string, prompt string, opts CallOptions) (_ *Response, err error) {
+	defer func() { err = r.RedactError(err) }()
+
 	if !r.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
 
+	if prompt, err = fitContext(model, prompt, opts); err != nil {
+		return nil, err
+	}
+
 	reqBody := requestyRequest{
 		Model: model,
 		Messages: []requestyMessage{
@@ -145,7 +171,14 @@ func (r *RequestyProvider) Call(model string, prompt string) (*Response, error)
 				Content: prompt,
 			},
 		},
-		Stream: false,
+		Stream:      false,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+		TopP:        opts.TopP,
+		Seed:        opts.Seed,
+	}
+	if opts.System != "" {
+		reqBody.Messages = append([]requestyMessage{{Role: "system", Content: opts.System}}, reqBody.Messages...)
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -153,24 +186,30 @@ func (r *RequestyProvider) Call(model string, prompt string) (*Response, error)
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", r.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+r.GetAPIKey())
-	req.Header.Set("Content-Type", "application/json")
-
+	start := time.Now()
 	var resp *http.Response
 	err = r.RetryWithBackoff(func() error {
-		var reqErr error
+		// Create a new request for each retry attempt so the body is resent
+		req, reqErr := http.NewRequest("POST", r.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+		if reqErr != nil {
+			return fmt.Errorf("failed to create request: %w", reqErr)
+		}
+		req.Header.Set("Authorization", "Bearer "+r.GetAPIKey())
+		req.Header.Set("Content-Type", "application/json")
+
 		resp, reqErr = r.httpClient.Do(req)
 		if reqErr != nil {
 			return reqErr
 		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			// Rate limits clear with time, so back off and try again
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
+		}
 		if resp.StatusCode >= 500 {
 			resp.Body.Close()
-			return fmt.Errorf("server error: %d", resp.StatusCode)
+			return statusError(resp.StatusCode, nil, fmt.Errorf("server error: %d", resp.StatusCode))
 		}
 		return nil
 	})
@@ -182,7 +221,7 @@ func (r *RequestyProvider) Call(model string, prompt string) (*Response, error)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
 	}
 
 	// Extract rate limit info from headers
@@ -217,12 +256,17 @@ func (r *RequestyProvider) Call(model string, prompt string) (*Response, error)
 		Provider:           r.Name(),
 		Timestamp:          time.Now(),
 		RateLimitRemaining: rateLimitRemaining,
+		FinishReason:       requestyResp.Choices[0].FinishReason,
+		Latency:            time.Since(start),
+		Seed:               opts.Seed,
 		QuotaRemaining:     quotaRemaining,
 	}, nil
 }
 
 // Stream makes a streaming API call to Requesty.ai
-func (r *RequestyProvider) Stream(model string, prompt string) (<-chan string, error) {
+func (r *RequestyProvider) Stream(model string, prompt string) (_ <-chan string, err error) {
+	defer func() { err = r.RedactError(err) }()
+
 	if !r.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -260,7 +304,7 @@ func (r *RequestyProvider) Stream(model string, prompt string) (<-chan string, e
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body)))
 	}
 
 	ch := make(chan string, 10)
@@ -319,3 +363,8 @@ func (r *RequestyProvider) GetQuotaInfo() (*QuotaInfo, error) {
 		ResetAt:         time.Time{},
 	}, nil
 }
+
+// Ping checks that the provider is reachable and the API key is accepted
+func (r *RequestyProvider) Ping(model string) error {
+	return PingWithCall(r, model)
+}
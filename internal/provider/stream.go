@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mojomast/geoffrussy/internal/token"
+)
+
+// ErrStreamIdle is reported by StreamCtx when a stream stops sending chunks
+// for longer than its idle timeout without closing
+var ErrStreamIdle = errors.New("stream stalled")
+
+// DefaultStreamIdleTimeout is a reasonable idle timeout for StreamCtx:
+// long enough for a slow model to start answering, short enough that a
+// stalled stream doesn't hang an interactive session
+const DefaultStreamIdleTimeout = 60 * time.Second
+
+// StreamCtx streams from p like Stream, but gives up when ctx is done or,
+// if idleTimeout is positive, when no chunk arrives within idleTimeout.
+// Chunks arrive on the first channel, which is closed when the stream ends
+// for any reason. The second channel then carries ctx's error or one
+// wrapping ErrStreamIdle, or nothing if the stream finished normally, and is
+// closed too.
+//
+// Provider streams can't be interrupted, so a stream that is given up on is
+// drained in the background until the provider closes it.
+func StreamCtx(ctx context.Context, p Provider, model, prompt string, idleTimeout time.Duration) (<-chan string, <-chan error, error) {
+	src, err := p.Stream(model, prompt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan string)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(out)
+
+		var idle <-chan time.Time
+		var timer *time.Timer
+		if idleTimeout > 0 {
+			timer = time.NewTimer(idleTimeout)
+			defer timer.Stop()
+			idle = timer.C
+		}
+
+		stop := func(err error) {
+			errs <- err
+			go drainStream(src)
+		}
+
+		for {
+			select {
+			case chunk, ok := <-src:
+				if !ok {
+					return
+				}
+				select {
+				case out <- chunk:
+				case <-ctx.Done():
+					stop(ctx.Err())
+					return
+				}
+				// Time spent waiting on the consumer doesn't count as idle
+				if timer != nil {
+					timer.Reset(idleTimeout)
+				}
+			case <-idle:
+				stop(fmt.Errorf("%w: no data for %s", ErrStreamIdle, idleTimeout))
+				return
+			case <-ctx.Done():
+				stop(ctx.Err())
+				return
+			}
+		}
+	}()
+
+	return out, errs, nil
+}
+
+// StreamCall makes a call through StreamCtx and assembles the chunks into a
+// Response, handing each chunk to onChunk as it arrives if onChunk isn't nil.
+// Streams take no options, so opts.System is sent ahead of the prompt and the
+// other options are ignored. Streams don't report usage either, so the token
+// counts are estimates.
+func StreamCall(ctx context.Context, p Provider, model, prompt string, opts CallOptions, idleTimeout time.Duration, onChunk func(chunk string)) (*Response, error) {
+	if opts.System != "" {
+		prompt = opts.System + "\n\n" + prompt
+	}
+
+	start := time.Now()
+	chunks, errs, err := StreamCtx(ctx, p, model, prompt, idleTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	for chunk := range chunks {
+		content.WriteString(chunk)
+		if onChunk != nil {
+			onChunk(chunk)
+		}
+	}
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+
+	response := &Response{
+		Content:   content.String(),
+		Model:     model,
+		Provider:  p.Name(),
+		Timestamp: time.Now(),
+		Latency:   time.Since(start),
+	}
+	counter := token.NewCounter(nil)
+	if n, err := counter.CountTokens(prompt, model); err == nil {
+		response.TokensInput = n
+	}
+	if n, err := counter.CountTokens(response.Content, model); err == nil {
+		response.TokensOutput = n
+	}
+	return response, nil
+}
+
+// drainStream discards the rest of an abandoned stream so the provider's
+// sending goroutine can finish
+func drainStream(src <-chan string) {
+	for range src {
+	}
+}
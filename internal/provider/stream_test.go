@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stallingProvider streams a few chunks, then goes quiet until released
+type stallingProvider struct {
+	Provider
+	chunks  []string
+	release chan struct{}
+}
+
+func (s *stallingProvider) Stream(model string, prompt string) (<-chan string, error) {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, chunk := range s.chunks {
+			ch <- chunk
+		}
+		<-s.release
+	}()
+	return ch, nil
+}
+
+func collectStream(chunks <-chan string, errs <-chan error) (string, error) {
+	var sb strings.Builder
+	for chunk := range chunks {
+		sb.WriteString(chunk)
+	}
+	return sb.String(), <-errs
+}
+
+func TestStreamCtx_IdleTimeout(t *testing.T) {
+	stalled := &stallingProvider{Provider: NewEchoProvider(), chunks: []string{"Hello", ", wor"}, release: make(chan struct{})}
+	defer close(stalled.release)
+
+	chunks, errs, err := StreamCtx(context.Background(), stalled, "test-model", "hi", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("StreamCtx failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	var text string
+	var streamErr error
+	go func() {
+		text, streamErr = collectStream(chunks, errs)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the idle timeout to end the stalled stream")
+	}
+	if text != "Hello, wor" {
+		t.Errorf("Expected the chunks sent before the stall, got %q", text)
+	}
+	if !errors.Is(streamErr, ErrStreamIdle) {
+		t.Errorf("Expected ErrStreamIdle, got %v", streamErr)
+	}
+}
+
+func TestStreamCtx_CompletesAndCancels(t *testing.T) {
+	chunks, errs, err := StreamCtx(context.Background(), NewEchoProvider(), "test-model", "hi", time.Second)
+	if err != nil {
+		t.Fatalf("StreamCtx failed: %v", err)
+	}
+	text, streamErr := collectStream(chunks, errs)
+	if text == "" || streamErr != nil {
+		t.Errorf("Expected a complete stream without error, got %q (%v)", text, streamErr)
+	}
+
+	stalled := &stallingProvider{Provider: NewEchoProvider(), release: make(chan struct{})}
+	defer close(stalled.release)
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, errs, err = StreamCtx(ctx, stalled, "test-model", "hi", 0)
+	if err != nil {
+		t.Fatalf("StreamCtx failed: %v", err)
+	}
+	cancel()
+	if _, streamErr := collectStream(chunks, errs); !errors.Is(streamErr, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", streamErr)
+	}
+}
+
+func TestStreamCtx_StopsWhenConsumerStopsReading(t *testing.T) {
+	stalled := &stallingProvider{Provider: NewEchoProvider(), chunks: []string{"one", "two", "three"}, release: make(chan struct{})}
+	defer close(stalled.release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, errs, err := StreamCtx(ctx, stalled, "test-model", "hi", 0)
+	if err != nil {
+		t.Fatalf("StreamCtx failed: %v", err)
+	}
+	if chunk := <-chunks; chunk != "one" {
+		t.Fatalf("Expected the first chunk, got %q", chunk)
+	}
+
+	// Stop reading with chunks still pending; cancelling must free the sender
+	cancel()
+	select {
+	case streamErr := <-errs:
+		if !errors.Is(streamErr, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", streamErr)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected cancellation to end a stream nobody is reading")
+	}
+}
+
+func TestStreamCall(t *testing.T) {
+	var streamed strings.Builder
+	response, err := StreamCall(context.Background(), NewEchoProvider(), "test-model", "hi", CallOptions{System: "Be brief."}, time.Second, func(chunk string) {
+		streamed.WriteString(chunk)
+	})
+	if err != nil {
+		t.Fatalf("StreamCall failed: %v", err)
+	}
+	if response.Content == "" || response.Content != streamed.String() {
+		t.Errorf("Expected the content to match the streamed chunks, got %q and %q", response.Content, streamed.String())
+	}
+	if !strings.Contains(response.Content, "Be brief.") {
+		t.Errorf("Expected the system prompt to be sent ahead of the prompt, got %q", response.Content)
+	}
+	if response.Provider != "echo" || response.TokensInput == 0 || response.TokensOutput == 0 {
+		t.Errorf("Expected provider and estimated token counts, got %+v", response)
+	}
+
+	stalled := &stallingProvider{Provider: NewEchoProvider(), chunks: []string{"Hel"}, release: make(chan struct{})}
+	defer close(stalled.release)
+	if _, err := StreamCall(context.Background(), stalled, "test-model", "hi", CallOptions{}, 50*time.Millisecond, nil); !errors.Is(err, ErrStreamIdle) {
+		t.Errorf("Expected a stalled stream to fail with ErrStreamIdle, got %v", err)
+	}
+}
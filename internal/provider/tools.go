@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrToolsUnsupported is returned by CallWithTools on wrappers whose
+// wrapped provider has no tool calling, so callers can fall back to
+// CallWithOptions
+var ErrToolsUnsupported = errors.New("tool calling not supported")
+
+// Message is one turn of a conversation sent with CallWithTools. Role is
+// "system", "user" or "assistant".
+type Message struct {
+	Role    string
+	Content string
+}
+
+// Tool describes a function the model may call instead of answering in
+// prose. Parameters is a JSON Schema object describing the arguments.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{}
+}
+
+// ToolCall is a model's request to invoke a tool
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string // JSON object matching the tool's Parameters
+}
+
+// ToolCaller is implemented by providers that support tool calling. Callers
+// check for it with a type assertion and fall back to CallWithOptions, or
+// when a wrapper returns ErrToolsUnsupported. opts
+// tunes the call as it does for CallWithOptions; opts.System is sent ahead of
+// any system messages.
+type ToolCaller interface {
+	CallWithTools(model string, messages []Message, tools []Tool, opts CallOptions) (*Response, error)
+}
+
+// withSystemOption puts opts.System, when set, at the front of messages as a
+// system message
+func withSystemOption(messages []Message, opts CallOptions) []Message {
+	if opts.System == "" {
+		return messages
+	}
+	return append([]Message{{Role: "system", Content: opts.System}}, messages...)
+}
+
+// splitSystemMessages separates system messages, joined in order, from the
+// rest of the conversation for APIs that take the system prompt apart
+func splitSystemMessages(messages []Message) (string, []Message) {
+	var system string
+	rest := make([]Message, 0, len(messages))
+	for _, m := range messages {
+		if m.Role == "system" {
+			if system != "" {
+				system += "\n\n"
+			}
+			system += m.Content
+			continue
+		}
+		rest = append(rest, m)
+	}
+	return system, rest
+}
+
+// fitMessages applies fitContext to the last user message, which carries the
+// prompt, counting opts.System, the other messages and the tool definitions
+// against the context window as well
+func fitMessages(model string, messages []Message, tools []Tool, opts CallOptions) ([]Message, error) {
+	last := -1
+	for i, m := range messages {
+		if m.Role == "user" {
+			last = i
+		}
+	}
+	if last < 0 {
+		return messages, nil
+	}
+
+	overhead := []string{opts.System}
+	for i, m := range messages {
+		if i != last {
+			overhead = append(overhead, m.Content)
+		}
+	}
+	if len(tools) > 0 {
+		if schema, err := json.Marshal(tools); err == nil {
+			overhead = append(overhead, string(schema))
+		}
+	}
+	fitOpts := opts
+	fitOpts.System = strings.Join(overhead, "\n\n")
+
+	prompt, err := fitContext(model, messages[last].Content, fitOpts)
+	if err != nil {
+		return nil, err
+	}
+	fitted := append([]Message{}, messages...)
+	fitted[last].Content = prompt
+	return fitted, nil
+}
@@ -35,8 +35,9 @@ type zaiRequest struct {
 	Model          string       `json:"model"`
 	Messages       []zaiMessage `json:"messages"`
 	Stream         bool         `json:"stream,omitempty"`
-	Temperature    float64      `json:"temperature,omitempty"`
+	Temperature    *float64     `json:"temperature,omitempty"`
 	MaxTokens      int          `json:"max_tokens,omitempty"`
+	TopP           float64      `json:"top_p,omitempty"`
 	CodingPlan     bool         `json:"coding_plan,omitempty"`
 	ProjectContext string       `json:"project_context,omitempty"`
 }
@@ -91,7 +92,9 @@ type zaiStreamChunk struct {
 }
 
 // ListModels returns the list of available models from Z.ai
-func (z *ZAIProvider) ListModels() ([]Model, error) {
+func (z *ZAIProvider) ListModels() (_ []Model, err error) {
+	defer func() { err = z.RedactError(err) }()
+
 	if !z.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -99,42 +102,64 @@ func (z *ZAIProvider) ListModels() ([]Model, error) {
 	// Z.ai available models (updated with GLM-4.7 and GLM-4.6V)
 	models := []Model{
 		{
-			Provider:     "z.ai",
-			Name:         "glm-4.7",
-			DisplayName:  "GLM-4.7",
-			Capabilities: []string{"text", "code", "streaming", "coding-plan"},
-			PriceInput:   0.0005,
-			PriceOutput:  0.0015,
+			Provider:      "z.ai",
+			Name:          "glm-4.7",
+			DisplayName:   "GLM-4.7",
+			Capabilities:  []string{"text", "code", "streaming", "coding-plan"},
+			PriceInput:    0.0005,
+			PriceOutput:   0.0015,
+			ContextWindow: 128000,
 		},
 		{
-			Provider:     "z.ai",
-			Name:         "glm-4.6",
-			DisplayName:  "GLM-4.6",
-			Capabilities: []string{"text", "code", "streaming", "coding-plan"},
-			PriceInput:   0.0006,
-			PriceOutput:  0.0022,
+			Provider:      "z.ai",
+			Name:          "glm-4.6",
+			DisplayName:   "GLM-4.6",
+			Capabilities:  []string{"text", "code", "streaming", "coding-plan"},
+			PriceInput:    0.0006,
+			PriceOutput:   0.0022,
+			ContextWindow: 128000,
 		},
 		{
-			Provider:     "z.ai",
-			Name:         "glm-4.6v",
-			DisplayName:  "GLM-4.6V (Multimodal)",
-			Capabilities: []string{"text", "code", "streaming", "vision"},
-			PriceInput:   0.0008,
-			PriceOutput:  0.0028,
+			Provider:      "z.ai",
+			Name:          "glm-4.6v",
+			DisplayName:   "GLM-4.6V (Multimodal)",
+			Capabilities:  []string{"text", "code", "streaming", "vision"},
+			PriceInput:    0.0008,
+			PriceOutput:   0.0028,
+			ContextWindow: 128000,
 		},
 	}
 
 	return models, nil
 }
 
-// Call makes a non-streaming API call to Z.ai
+// setTransport routes the provider's HTTP requests through rt
+func (z *ZAIProvider) setTransport(rt http.RoundTripper) {
+	z.httpClient.Transport = rt
+}
+
+// Call makes a non-streaming API call to Z.ai using the default options
 func (z *ZAIProvider) Call(model string, prompt string) (*Response, error) {
+	return z.CallWithOptions(model, prompt, CallOptions{})
+}
+
+// CallWithOptions makes a non-streaming API call to Z.ai with per-call sampling options
+func (z *ZAIProvider) CallWithOptions(model string, prompt string, opts CallOptions) (_ *Response, err error) {
+	defer func() { err = z.RedactError(err) }()
+
 	if !z.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
 
+	if prompt, err = fitContext(model, prompt, opts); err != nil {
+		return nil, err
+	}
+
+	opts = opts.withDefaults(CallOptions{Temperature: Float64(0.7), MaxTokens: 4096})
+
+	start := time.Now()
 	var response *Response
-	err := z.RetryWithBackoff(func() error {
+	err = z.RetryWithBackoff(func() error {
 		req := zaiRequest{
 			Model: model,
 			Messages: []zaiMessage{
@@ -143,8 +168,12 @@ func (z *ZAIProvider) Call(model string, prompt string) (*Response, error) {
 					Content: prompt,
 				},
 			},
-			Temperature: 0.7,
-			MaxTokens:   4096,
+			Temperature: opts.Temperature,
+			MaxTokens:   opts.MaxTokens,
+			TopP:        opts.TopP,
+		}
+		if opts.System != "" {
+			req.Messages = append([]zaiMessage{{Role: "system", Content: opts.System}}, req.Messages...)
 		}
 
 		jsonData, err := json.Marshal(req)
@@ -168,7 +197,7 @@ func (z *ZAIProvider) Call(model string, prompt string) (*Response, error) {
 
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+			return statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
 		}
 
 		var zaiResp zaiResponse
@@ -177,9 +206,10 @@ func (z *ZAIProvider) Call(model string, prompt string) (*Response, error) {
 		}
 
 		// Extract content
-		var content string
+		var content, finishReason string
 		if len(zaiResp.Choices) > 0 {
 			content = zaiResp.Choices[0].Message.Content
+			finishReason = zaiResp.Choices[0].FinishReason
 		}
 
 		// Extract rate limit info from headers
@@ -198,16 +228,22 @@ func (z *ZAIProvider) Call(model string, prompt string) (*Response, error) {
 			Provider:           "z.ai",
 			Timestamp:          time.Now(),
 			RateLimitRemaining: rateLimitRemaining,
+			FinishReason:       finishReason,
 		}
 
 		return nil
 	})
 
+	if response != nil {
+		response.Latency = time.Since(start)
+	}
 	return response, err
 }
 
 // Stream makes a streaming API call to Z.ai
-func (z *ZAIProvider) Stream(model string, prompt string) (<-chan string, error) {
+func (z *ZAIProvider) Stream(model string, prompt string) (_ <-chan string, err error) {
+	defer func() { err = z.RedactError(err) }()
+
 	if !z.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -221,7 +257,7 @@ func (z *ZAIProvider) Stream(model string, prompt string) (<-chan string, error)
 			},
 		},
 		Stream:      true,
-		Temperature: 0.7,
+		Temperature: Float64(0.7),
 		MaxTokens:   4096,
 	}
 
@@ -246,7 +282,7 @@ func (z *ZAIProvider) Stream(model string, prompt string) (<-chan string, error)
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, body, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	ch := make(chan string, 100)
@@ -282,7 +318,9 @@ func (z *ZAIProvider) Stream(model string, prompt string) (<-chan string, error)
 }
 
 // GetRateLimitInfo returns rate limit information from Z.ai
-func (z *ZAIProvider) GetRateLimitInfo() (*RateLimitInfo, error) {
+func (z *ZAIProvider) GetRateLimitInfo() (_ *RateLimitInfo, err error) {
+	defer func() { err = z.RedactError(err) }()
+
 	if !z.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -349,7 +387,9 @@ func (z *ZAIProvider) GetRateLimitInfo() (*RateLimitInfo, error) {
 }
 
 // GetQuotaInfo returns quota information from Z.ai
-func (z *ZAIProvider) GetQuotaInfo() (*QuotaInfo, error) {
+func (z *ZAIProvider) GetQuotaInfo() (_ *QuotaInfo, err error) {
+	defer func() { err = z.RedactError(err) }()
+
 	if !z.IsAuthenticated() {
 		return nil, fmt.Errorf("provider not authenticated")
 	}
@@ -407,3 +447,8 @@ func (z *ZAIProvider) GetQuotaInfo() (*QuotaInfo, error) {
 func (z *ZAIProvider) SupportsCodingPlan() bool {
 	return true
 }
+
+// Ping checks that the provider is reachable and the API key is accepted
+func (z *ZAIProvider) Ping(model string) error {
+	return PingWithCall(z, model)
+}
@@ -1,6 +1,7 @@
 package quota
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -8,6 +9,10 @@ import (
 	"github.com/mojomast/geoffrussy/internal/state"
 )
 
+// cachedStatusMaxAge is how old cached limits can be before they are only
+// reported, not acted on
+const cachedStatusMaxAge = time.Minute
+
 // Monitor handles rate limit and quota monitoring
 type Monitor struct {
 	store *state.Store
@@ -271,17 +276,16 @@ func (m *Monitor) GetCachedStatus(providerName string) (*ProviderStatus, error)
 	}
 
 	// Get cached rate limit info
-	rateLimitInfo, err := m.store.GetRateLimit(providerName)
-	if err == nil && rateLimitInfo != nil {
+	rateLimitInfo, err := m.store.GetRateLimitFresh(providerName, cachedStatusMaxAge)
+	if rateLimitInfo != nil && (err == nil || errors.Is(err, state.ErrStale)) {
 		status.RateLimitInfo = rateLimitInfo
 		status.LastChecked = rateLimitInfo.CheckedAt
 
-		// Check if data is stale (older than 1 minute)
-		if time.Since(rateLimitInfo.CheckedAt) > time.Minute {
+		if err != nil {
 			// Data is stale, but still show it
 			status.RateLimitWarning = &Warning{
 				Level:   WarningInfo,
-				Message: fmt.Sprintf("Data is stale (last checked %s ago)", formatDuration(time.Since(rateLimitInfo.CheckedAt))),
+				Message: fmt.Sprintf("Data is stale (last checked %s ago)", formatDuration(rateLimitInfo.Age())),
 			}
 		} else {
 			warning := m.checkRateLimitWarning(rateLimitInfo)
@@ -299,16 +303,16 @@ func (m *Monitor) GetCachedStatus(providerName string) (*ProviderStatus, error)
 	}
 
 	// Get cached quota info
-	quotaInfo, err := m.store.GetQuota(providerName)
-	if err == nil && quotaInfo != nil {
+	quotaInfo, err := m.store.GetQuotaFresh(providerName, cachedStatusMaxAge)
+	if quotaInfo != nil && (err == nil || errors.Is(err, state.ErrStale)) {
 		status.QuotaInfo = quotaInfo
 
-		if time.Since(quotaInfo.CheckedAt) > time.Minute {
+		if err != nil {
 			// Data is stale
 			if status.QuotaWarning == nil {
 				status.QuotaWarning = &Warning{
 					Level:   WarningInfo,
-					Message: fmt.Sprintf("Data is stale (last checked %s ago)", formatDuration(time.Since(quotaInfo.CheckedAt))),
+					Message: fmt.Sprintf("Data is stale (last checked %s ago)", formatDuration(quotaInfo.Age())),
 				}
 			}
 		} else {
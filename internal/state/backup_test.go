@@ -167,3 +167,58 @@ func TestStore_Backup_SpecialChars(t *testing.T) {
 		t.Fatalf("Backup file not created")
 	}
 }
+
+func TestStore_BackupTo(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+	backupPath := filepath.Join(tmpDir, "backups", "nightly", "backup.db")
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageInterview,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	if err := store.BackupTo(backupPath); err != nil {
+		t.Fatalf("Failed to back up: %v", err)
+	}
+
+	// The live store keeps working and a second backup replaces the first
+	if err := store.UpdateProjectStage(project.ID, StageDesign); err != nil {
+		t.Fatalf("Failed to update stage: %v", err)
+	}
+	if err := store.BackupTo(backupPath); err != nil {
+		t.Fatalf("Failed to overwrite backup: %v", err)
+	}
+	if _, err := os.Stat(backupPath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Expected temporary backup file to be cleaned up, got %v", err)
+	}
+
+	backup, err := NewStore(backupPath)
+	if err != nil {
+		t.Fatalf("Failed to open backup as a store: %v", err)
+	}
+	defer backup.Close()
+
+	restored, err := backup.GetProject(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to read project from backup: %v", err)
+	}
+	if restored.Name != project.Name || restored.CurrentStage != StageDesign {
+		t.Errorf("Expected latest project data in backup, got %+v", restored)
+	}
+
+	if err := store.BackupTo(tmpDir); err == nil {
+		t.Error("Expected error backing up to a directory")
+	}
+}
@@ -0,0 +1,69 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaskCommit links a task to a git commit that implemented it
+type TaskCommit struct {
+	TaskID   string
+	SHA      string
+	Message  string
+	LinkedAt time.Time
+}
+
+// LinkTaskCommit records that the commit sha implemented part of a task.
+// Linking the same commit to a task again is a no-op.
+func (s *Store) LinkTaskCommit(taskID, sha, message string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	sha = strings.TrimSpace(sha)
+	if sha == "" {
+		return fmt.Errorf("commit SHA cannot be empty")
+	}
+
+	_, err := s.handle().Exec(`
+		INSERT OR IGNORE INTO task_commits (task_id, sha, message, linked_at)
+		VALUES (?, ?, ?, ?)
+	`, taskID, sha, message, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to link task commit: %w", err)
+	}
+	return nil
+}
+
+// GetTaskCommits returns the commits linked to a task in the order they
+// were linked
+func (s *Store) GetTaskCommits(taskID string) ([]*TaskCommit, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.handle().Query(`
+		SELECT task_id, sha, message, linked_at
+		FROM task_commits
+		WHERE task_id = ?
+		ORDER BY id
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task commits: %w", err)
+	}
+	defer rows.Close()
+
+	var commits []*TaskCommit
+	for rows.Next() {
+		commit := &TaskCommit{}
+		if err := rows.Scan(&commit.TaskID, &commit.SHA, &commit.Message, &commit.LinkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task commit: %w", err)
+		}
+		commits = append(commits, commit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task commits: %w", err)
+	}
+	return commits, nil
+}
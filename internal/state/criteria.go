@@ -0,0 +1,144 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrUnmetCriteria is returned when completing a phase that still has
+// success criteria not marked as met
+var ErrUnmetCriteria = errors.New("phase has unmet success criteria")
+
+// SuccessCriterion is one tracked success criterion of a phase
+type SuccessCriterion struct {
+	PhaseID string
+	Index   int
+	Text    string
+	Met     bool
+	Check   string // Shell command verifying the criterion, empty when it has none
+}
+
+// saveSuccessCriteria replaces a phase's criteria within tx, with the shell
+// check for each criterion taken from checks by text. A criterion whose text
+// was already tracked keeps its met state, so re-saving a phase doesn't undo
+// progress.
+func saveSuccessCriteria(tx *sql.Tx, phaseID string, criteria []string, checks map[string]string) error {
+	rows, err := tx.Query("SELECT text, met FROM success_criteria WHERE phase_id = ?", phaseID)
+	if err != nil {
+		return fmt.Errorf("failed to query success criteria: %w", err)
+	}
+	met := make(map[string]bool)
+	for rows.Next() {
+		var text string
+		var isMet bool
+		if err := rows.Scan(&text, &isMet); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan success criterion: %w", err)
+		}
+		met[text] = met[text] || isMet
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error iterating success criteria: %w", err)
+	}
+	rows.Close()
+
+	if _, err := tx.Exec("DELETE FROM success_criteria WHERE phase_id = ?", phaseID); err != nil {
+		return fmt.Errorf("failed to clear success criteria: %w", err)
+	}
+	for i, text := range criteria {
+		_, err := tx.Exec(`
+			INSERT INTO success_criteria (phase_id, position, text, met, check_command)
+			VALUES (?, ?, ?, ?, ?)
+		`, phaseID, i, text, met[text], checks[text])
+		if err != nil {
+			return fmt.Errorf("failed to save success criterion: %w", err)
+		}
+	}
+	return nil
+}
+
+// MarkCriterionMet sets whether the phase's success criterion at index
+// (zero-based, in the order they were saved) is met
+func (s *Store) MarkCriterionMet(phaseID string, index int, met bool) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	result, err := s.handle().Exec(`
+		UPDATE success_criteria
+		SET met = ?
+		WHERE phase_id = ? AND position = ?
+	`, met, phaseID, index)
+	if err != nil {
+		return fmt.Errorf("failed to mark success criterion: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("success criterion not found: %s #%d", phaseID, index)
+	}
+	return nil
+}
+
+// ListSuccessCriteria returns all tracked criteria for a phase in order
+func (s *Store) ListSuccessCriteria(phaseID string) ([]*SuccessCriterion, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+	return s.querySuccessCriteria(`
+		SELECT phase_id, position, text, met, check_command
+		FROM success_criteria
+		WHERE phase_id = ?
+		ORDER BY position
+	`, phaseID)
+}
+
+// ListOutstandingCriteria returns the phase's criteria not yet met, in order
+func (s *Store) ListOutstandingCriteria(phaseID string) ([]*SuccessCriterion, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+	return s.querySuccessCriteria(`
+		SELECT phase_id, position, text, met, check_command
+		FROM success_criteria
+		WHERE phase_id = ? AND met = 0
+		ORDER BY position
+	`, phaseID)
+}
+
+// querySuccessCriteria runs a criteria query and scans the rows
+func (s *Store) querySuccessCriteria(query string, args ...interface{}) ([]*SuccessCriterion, error) {
+	rows, err := s.handle().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query success criteria: %w", err)
+	}
+	defer rows.Close()
+
+	var criteria []*SuccessCriterion
+	for rows.Next() {
+		criterion := &SuccessCriterion{}
+		if err := rows.Scan(&criterion.PhaseID, &criterion.Index, &criterion.Text, &criterion.Met, &criterion.Check); err != nil {
+			return nil, fmt.Errorf("failed to scan success criterion: %w", err)
+		}
+		criteria = append(criteria, criterion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating success criteria: %w", err)
+	}
+	return criteria, nil
+}
+
+// countUnmetCriteria returns how many of the phase's criteria are not met
+func (s *Store) countUnmetCriteria(phaseID string) (int, error) {
+	var count int
+	err := s.handle().QueryRow("SELECT COUNT(*) FROM success_criteria WHERE phase_id = ? AND met = 0", phaseID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unmet success criteria: %w", err)
+	}
+	return count, nil
+}
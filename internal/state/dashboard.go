@@ -0,0 +1,173 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProjectSummary is one project's row in the multi-project dashboard
+type ProjectSummary struct {
+	ProjectID            string
+	Name                 string
+	CreatedAt            time.Time
+	CurrentStage         Stage
+	Paused               bool
+	TotalTasks           int
+	CompletedTasks       int
+	CompletionPercentage float64
+	TotalCost            float64
+	ActiveBlockers       int
+}
+
+// dashboardQuery aggregates tasks, cost and blockers per project in grouped
+// subqueries, so the dashboard is a single query however many projects
+// there are
+const dashboardQuery = `
+	SELECT p.id, p.name, p.created_at, p.current_stage, p.paused,
+		COALESCE(t.total, 0), COALESCE(t.completed, 0),
+		COALESCE(u.cost, 0), COALESCE(b.active, 0)
+	FROM projects p
+	LEFT JOIN (
+		SELECT ph.project_id,
+			COUNT(*) AS total,
+			SUM(CASE WHEN tk.status = 'completed' THEN 1 ELSE 0 END) AS completed
+		FROM tasks tk
+		JOIN phases ph ON tk.phase_id = ph.id
+		GROUP BY ph.project_id
+	) t ON t.project_id = p.id
+	LEFT JOIN (
+		SELECT project_id, SUM(cost) AS cost
+		FROM token_usage
+		GROUP BY project_id
+	) u ON u.project_id = p.id
+	LEFT JOIN (
+		SELECT ph.project_id, COUNT(*) AS active
+		FROM blockers bl
+		JOIN tasks tk ON bl.task_id = tk.id
+		JOIN phases ph ON tk.phase_id = ph.id
+		WHERE bl.resolved_at IS NULL
+		GROUP BY ph.project_id
+	) b ON b.project_id = p.id
+	WHERE p.archived_at IS NULL
+	ORDER BY p.created_at, p.id
+`
+
+// GetDashboard summarizes every project that isn't archived, oldest first
+func (s *Store) GetDashboard() ([]ProjectSummary, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.handle().Query(dashboardQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboard: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []ProjectSummary
+	for rows.Next() {
+		var summary ProjectSummary
+		err := rows.Scan(
+			&summary.ProjectID,
+			&summary.Name,
+			&summary.CreatedAt,
+			&summary.CurrentStage,
+			&summary.Paused,
+			&summary.TotalTasks,
+			&summary.CompletedTasks,
+			&summary.TotalCost,
+			&summary.ActiveBlockers,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project summary: %w", err)
+		}
+		if summary.TotalTasks > 0 {
+			summary.CompletionPercentage = float64(summary.CompletedTasks) / float64(summary.TotalTasks) * 100
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating project summaries: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// ProjectWithStats is a project together with the figures the CLI shows
+// alongside it
+type ProjectWithStats struct {
+	Project
+	TotalPhases          int
+	CompletedPhases      int
+	TotalTasks           int
+	CompletedTasks       int
+	CompletionPercentage float64
+	TotalCost            float64
+	ActiveBlockers       int
+}
+
+// projectStatsQuery aggregates one project's phases, tasks, cost and
+// blockers in grouped subqueries like dashboardQuery
+const projectStatsQuery = `
+	SELECT COALESCE(ph.total, 0), COALESCE(ph.completed, 0),
+		COALESCE(t.total, 0), COALESCE(t.completed, 0),
+		COALESCE(u.cost, 0), COALESCE(b.active, 0)
+	FROM projects p
+	LEFT JOIN (
+		SELECT project_id,
+			COUNT(*) AS total,
+			SUM(CASE WHEN status = 'completed' THEN 1 ELSE 0 END) AS completed
+		FROM phases
+		GROUP BY project_id
+	) ph ON ph.project_id = p.id
+	LEFT JOIN (
+		SELECT ph.project_id,
+			COUNT(*) AS total,
+			SUM(CASE WHEN tk.status = 'completed' THEN 1 ELSE 0 END) AS completed
+		FROM tasks tk
+		JOIN phases ph ON tk.phase_id = ph.id
+		GROUP BY ph.project_id
+	) t ON t.project_id = p.id
+	LEFT JOIN (
+		SELECT project_id, SUM(cost) AS cost
+		FROM token_usage
+		GROUP BY project_id
+	) u ON u.project_id = p.id
+	LEFT JOIN (
+		SELECT ph.project_id, COUNT(*) AS active
+		FROM blockers bl
+		JOIN tasks tk ON bl.task_id = tk.id
+		JOIN phases ph ON tk.phase_id = ph.id
+		WHERE bl.resolved_at IS NULL
+		GROUP BY ph.project_id
+	) b ON b.project_id = p.id
+	WHERE p.id = ?
+`
+
+// GetProjectWithStats retrieves a project with its phase and task counts,
+// completion percentage, total cost and active blocker count
+func (s *Store) GetProjectWithStats(id string) (*ProjectWithStats, error) {
+	project, err := s.GetProject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ProjectWithStats{Project: *project}
+	err = s.handle().QueryRow(projectStatsQuery, id).Scan(
+		&stats.TotalPhases,
+		&stats.CompletedPhases,
+		&stats.TotalTasks,
+		&stats.CompletedTasks,
+		&stats.TotalCost,
+		&stats.ActiveBlockers,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project stats: %w", err)
+	}
+	if stats.TotalTasks > 0 {
+		stats.CompletionPercentage = float64(stats.CompletedTasks) / float64(stats.TotalTasks) * 100
+	}
+
+	return stats, nil
+}
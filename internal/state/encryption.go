@@ -0,0 +1,181 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrEncryptionUnsupported is returned when an encrypted store is requested
+// but geoffrussy wasn't built with the sqlcipher tag, or the SQLite library
+// it was linked against is not SQLCipher
+var ErrEncryptionUnsupported = errors.New("database encryption is not supported by this build")
+
+// ErrInvalidPassphrase is returned when an encrypted database can't be read
+// with the passphrase it was opened with
+var ErrInvalidPassphrase = errors.New("invalid database passphrase")
+
+// NewEncryptedStore creates a state store whose database file is encrypted
+// at rest with passphrase. A new database is encrypted on creation; an
+// existing one must have been created with the same passphrase.
+//
+// Encryption needs a binary built with the sqlcipher tag and linked against
+// SQLCipher, e.g. go build -tags "sqlcipher libsqlite3". Any other build, or
+// a tagged build whose SQLite library turns out not to be SQLCipher, fails
+// with ErrEncryptionUnsupported rather than silently writing plain text.
+func NewEncryptedStore(dbPath, passphrase string) (*Store, error) {
+	if !encryptionBuild {
+		return nil, ErrEncryptionUnsupported
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+	if dbPath == ":memory:" || dbPath == SharedMemoryPath {
+		return nil, fmt.Errorf("in-memory databases cannot be encrypted")
+	}
+
+	store := &Store{
+		dbPath:     dbPath,
+		dsn:        dbPath,
+		passphrase: passphrase,
+	}
+	if err := store.open(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// PassphraseEnv names the environment variable holding the database
+// passphrase used by OpenStore
+const PassphraseEnv = "GEOFFRUSSY_DB_PASSPHRASE"
+
+// OpenStore opens the state store at dbPath, encrypted with the passphrase
+// in GEOFFRUSSY_DB_PASSPHRASE when it is set and unencrypted otherwise
+func OpenStore(dbPath string) (*Store, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return NewEncryptedStore(dbPath, passphrase)
+	}
+	return NewStore(dbPath)
+}
+
+// Rekey re-encrypts the database with a new passphrase. The store switches
+// to a handle opened with the new key, so it stays usable afterwards. Only
+// stores opened with NewEncryptedStore can be rekeyed.
+func (s *Store) Rekey(newPassphrase string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+	if !encryptionBuild {
+		return ErrEncryptionUnsupported
+	}
+	if newPassphrase == "" {
+		return fmt.Errorf("passphrase cannot be empty")
+	}
+
+	// Hold the write lock throughout so no query runs on a connection keyed
+	// with the old passphrase once the file is re-encrypted
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+	if s.passphrase == "" {
+		return fmt.Errorf("store is not encrypted")
+	}
+
+	ctx := context.Background()
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	// SQLCipher can't rekey a database in WAL mode; connect switches it back
+	if _, err := conn.ExecContext(ctx, "PRAGMA journal_mode = DELETE"); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to leave WAL mode: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA rekey = "+quotePragmaString(newPassphrase)); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to rekey database: %w", err)
+	}
+	conn.Close()
+
+	// Pooled connections still hold the old key, so swap in a new handle
+	s.passphrase = newPassphrase
+	db, migrationManager, stmts, err := s.connect()
+	if err != nil {
+		return fmt.Errorf("failed to re-open database: %w", err)
+	}
+
+	closeStatements(s.stmts)
+	oldDB := s.db
+	s.db = db
+	s.migrationManager = migrationManager
+	s.stmts = stmts
+	if err := oldDB.Close(); err != nil {
+		return fmt.Errorf("failed to close database connection: %w", err)
+	}
+
+	return nil
+}
+
+// openDB opens the database, keying every connection when the store is
+// encrypted. An encrypted database is checked to be readable straight away,
+// so a wrong passphrase fails here rather than on first use.
+func (s *Store) openDB() (*sql.DB, error) {
+	if s.passphrase == "" {
+		return sql.Open("sqlite3", s.dsn)
+	}
+
+	key := "PRAGMA key = " + quotePragmaString(s.passphrase)
+	db := sql.OpenDB(&keyedConnector{
+		dsn: s.dsn,
+		driver: &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				_, err := conn.Exec(key, nil)
+				return err
+			},
+		},
+	})
+
+	// Plain SQLite ignores PRAGMA key, so make sure SQLCipher is really there
+	var version string
+	if err := db.QueryRow("PRAGMA cipher_version").Scan(&version); err != nil || version == "" {
+		db.Close()
+		return nil, ErrEncryptionUnsupported
+	}
+	if _, err := db.Exec("SELECT count(*) FROM sqlite_master"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPassphrase, err)
+	}
+
+	return db, nil
+}
+
+// keyedConnector opens connections through a driver whose connect hook sets
+// the encryption key
+type keyedConnector struct {
+	dsn    string
+	driver *sqlite3.SQLiteDriver
+}
+
+func (c *keyedConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *keyedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// quotePragmaString quotes a value as an SQL string literal, since PRAGMA
+// statements don't accept bound parameters
+func quotePragmaString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,6 @@
+//go:build !sqlcipher
+
+package state
+
+// encryptionBuild reports whether this binary was built for SQLCipher
+const encryptionBuild = false
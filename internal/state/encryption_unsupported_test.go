@@ -0,0 +1,41 @@
+//go:build !sqlcipher
+
+package state
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewEncryptedStore_Unsupported(t *testing.T) {
+	if _, err := NewEncryptedStore(filepath.Join(t.TempDir(), "encrypted.db"), "secret"); !errors.Is(err, ErrEncryptionUnsupported) {
+		t.Errorf("Expected ErrEncryptionUnsupported without the sqlcipher tag, got %v", err)
+	}
+
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.Rekey("secret"); !errors.Is(err, ErrEncryptionUnsupported) {
+		t.Errorf("Expected ErrEncryptionUnsupported rekeying without the sqlcipher tag, got %v", err)
+	}
+}
+
+func TestOpenStore_Passphrase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "state.db")
+
+	t.Setenv(PassphraseEnv, "")
+	store, err := OpenStore(dbPath)
+	if err != nil {
+		t.Fatalf("Expected an unencrypted store without a passphrase, got %v", err)
+	}
+	store.Close()
+
+	// A passphrase must never silently fall back to plain text
+	t.Setenv(PassphraseEnv, "secret")
+	if _, err := OpenStore(dbPath); !errors.Is(err, ErrEncryptionUnsupported) {
+		t.Errorf("Expected ErrEncryptionUnsupported with a passphrase set, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SaveIntervention records a request for human intervention on a blocker
+func (s *Store) SaveIntervention(intervention *Intervention) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO interventions (id, blocker_id, context, requested_at, resolved_at, response)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			context = excluded.context,
+			resolved_at = excluded.resolved_at,
+			response = excluded.response
+	`
+	_, err := s.handle().Exec(query,
+		intervention.ID,
+		intervention.BlockerID,
+		intervention.Context,
+		intervention.RequestedAt,
+		intervention.ResolvedAt,
+		intervention.Response,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save intervention: %w", err)
+	}
+	return nil
+}
+
+// ListPendingInterventions retrieves unresolved intervention requests for a
+// project, oldest first
+func (s *Store) ListPendingInterventions(projectID string) ([]Intervention, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT i.id, i.blocker_id, i.context, i.requested_at, i.resolved_at, i.response
+		FROM interventions i
+		JOIN blockers b ON i.blocker_id = b.id
+		JOIN tasks t ON b.task_id = t.id
+		JOIN phases p ON t.phase_id = p.id
+		WHERE p.project_id = ? AND i.resolved_at IS NULL
+		ORDER BY i.requested_at ASC
+	`
+	rows, err := s.handle().Query(query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending interventions: %w", err)
+	}
+	defer rows.Close()
+
+	var interventions []Intervention
+	for rows.Next() {
+		var intervention Intervention
+		var context, response sql.NullString
+
+		err := rows.Scan(
+			&intervention.ID,
+			&intervention.BlockerID,
+			&context,
+			&intervention.RequestedAt,
+			&intervention.ResolvedAt,
+			&response,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan intervention: %w", err)
+		}
+
+		intervention.Context = context.String
+		intervention.Response = response.String
+		interventions = append(interventions, intervention)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating interventions: %w", err)
+	}
+
+	return interventions, nil
+}
+
+// ResolveIntervention records the human's response and closes the request
+func (s *Store) ResolveIntervention(id, response string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE interventions
+		SET response = ?, resolved_at = ?
+		WHERE id = ?
+	`
+	result, err := s.handle().Exec(query, response, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to resolve intervention: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("intervention not found: %s", id)
+	}
+
+	return nil
+}
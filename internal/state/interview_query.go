@@ -0,0 +1,115 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// FindProjectsByInterviewField returns the projects whose stored interview
+// data has value at jsonPath, filtered in SQLite with its JSON functions
+// rather than by loading each project's interview. Paths use the stored
+// field names, such as "$.ProblemStatement" or "$.TechnicalStack.Backend.Name".
+// When the path holds an array, as with "$.TargetUsers", a project matches
+// if any element equals value. Projects are ordered by creation time.
+func (s *Store) FindProjectsByInterviewField(jsonPath, value string) ([]*Project, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(jsonPath, "$") {
+		return nil, fmt.Errorf("invalid JSON path %q: must start with $", jsonPath)
+	}
+
+	// json_each yields a single row for a scalar and one per element for an
+	// array, so the same condition covers both
+	rows, err := s.handle().Query(`
+		SELECT p.id, p.name, p.created_at, p.current_stage, p.current_phase_id, p.paused, p.pause_reason, p.archived_at
+		FROM projects p
+		JOIN interview_data i ON i.project_id = p.id
+		WHERE EXISTS (
+			SELECT 1 FROM json_each(i.data, ?) WHERE json_each.value = ?
+		)
+		ORDER BY p.created_at, p.id
+	`, jsonPath, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects by interview field: %w", err)
+	}
+	defer rows.Close()
+
+	return scanProjects(rows)
+}
+
+// ListProjectsMissingInterview returns the projects with no interview yet
+// or an interview that isn't completed, ordered by creation time. Archived
+// projects are left out.
+func (s *Store) ListProjectsMissingInterview() ([]*Project, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.handle().Query(`
+		SELECT p.id, p.name, p.created_at, p.current_stage, p.current_phase_id, p.paused, p.pause_reason, p.archived_at
+		FROM projects p
+		LEFT JOIN interview_data i ON i.project_id = p.id
+		WHERE p.archived_at IS NULL AND (i.project_id IS NULL OR i.completed = 0)
+		ORDER BY p.created_at, p.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects missing an interview: %w", err)
+	}
+	defer rows.Close()
+
+	return scanProjects(rows)
+}
+
+// scanProjects reads project rows selected in GetProject's column order
+func scanProjects(rows *sql.Rows) ([]*Project, error) {
+	var projects []*Project
+	for rows.Next() {
+		var project Project
+		var pauseReason sql.NullString
+		if err := rows.Scan(
+			&project.ID,
+			&project.Name,
+			&project.CreatedAt,
+			&project.CurrentStage,
+			&project.CurrentPhase,
+			&project.Paused,
+			&pauseReason,
+			&project.ArchivedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		project.PauseReason = pauseReason.String
+		projects = append(projects, &project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating projects: %w", err)
+	}
+	return projects, nil
+}
+
+// GetInterviewField returns the value at jsonPath in a project's stored
+// interview data as text, or "" when the path is absent
+func (s *Store) GetInterviewField(projectID, jsonPath string) (string, error) {
+	if err := s.ensureOpen(); err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(jsonPath, "$") {
+		return "", fmt.Errorf("invalid JSON path %q: must start with $", jsonPath)
+	}
+
+	var value sql.NullString
+	err := s.handle().QueryRow(`
+		SELECT json_extract(data, ?)
+		FROM interview_data
+		WHERE project_id = ?
+	`, jsonPath, projectID).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("interview data not found for project: %s", projectID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract interview field: %w", err)
+	}
+	return value.String, nil
+}
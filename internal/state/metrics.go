@@ -0,0 +1,71 @@
+package state
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// StoreMetrics are database-wide counts for monitoring a geoffrussy service
+type StoreMetrics struct {
+	Projects         int // Projects that aren't archived
+	ArchivedProjects int
+	ActiveBlockers   int
+	TotalCost        float64 // LLM spend across all projects, in dollars
+	TokensInput      int64
+	TokensOutput     int64
+}
+
+// metricsQuery gathers every metric in one round trip
+const metricsQuery = `
+	SELECT
+		(SELECT COUNT(*) FROM projects WHERE archived_at IS NULL),
+		(SELECT COUNT(*) FROM projects WHERE archived_at IS NOT NULL),
+		(SELECT COUNT(*) FROM blockers WHERE resolved_at IS NULL),
+		(SELECT COALESCE(SUM(cost), 0) FROM token_usage),
+		(SELECT COALESCE(SUM(tokens_input), 0) FROM token_usage),
+		(SELECT COALESCE(SUM(tokens_output), 0) FROM token_usage)
+`
+
+// Metrics collects the current StoreMetrics
+func (s *Store) Metrics() (*StoreMetrics, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	m := &StoreMetrics{}
+	err := s.handle().QueryRow(metricsQuery).Scan(
+		&m.Projects,
+		&m.ArchivedProjects,
+		&m.ActiveBlockers,
+		&m.TotalCost,
+		&m.TokensInput,
+		&m.TokensOutput,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect store metrics: %w", err)
+	}
+	return m, nil
+}
+
+// WritePrometheus writes m in the Prometheus text exposition format, ready
+// to serve from a /metrics endpoint. Write errors are left to the caller's
+// writer to surface, as an HTTP response writer does.
+func WritePrometheus(m *StoreMetrics, w io.Writer) {
+	writeGauge(w, "geoffrussy_projects", "Number of projects that are not archived.", float64(m.Projects))
+	writeGauge(w, "geoffrussy_archived_projects", "Number of archived projects.", float64(m.ArchivedProjects))
+	writeGauge(w, "geoffrussy_active_blockers", "Number of unresolved task blockers.", float64(m.ActiveBlockers))
+	writeGauge(w, "geoffrussy_llm_cost_dollars", "Total LLM spend across all projects, in dollars.", m.TotalCost)
+
+	fmt.Fprintln(w, "# HELP geoffrussy_llm_tokens Total LLM tokens used across all projects.")
+	fmt.Fprintln(w, "# TYPE geoffrussy_llm_tokens gauge")
+	fmt.Fprintf(w, "geoffrussy_llm_tokens{direction=\"input\"} %d\n", m.TokensInput)
+	fmt.Fprintf(w, "geoffrussy_llm_tokens{direction=\"output\"} %d\n", m.TokensOutput)
+}
+
+// writeGauge writes one unlabelled gauge with its HELP and TYPE lines
+func writeGauge(w io.Writer, name, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(w, "%s %s\n", name, strconv.FormatFloat(value, 'g', -1, 64))
+}
@@ -2,10 +2,15 @@ package state
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 )
 
+// ErrDatabaseNewer is returned when the database was migrated by a newer
+// version of geoffrussy than the one opening it
+var ErrDatabaseNewer = errors.New("database schema is newer than this version of geoffrussy supports")
+
 // Migration represents a database migration
 type Migration struct {
 	Version     int
@@ -35,6 +40,286 @@ var migrations = []Migration{
 			DROP TABLE IF EXISTS projects;
 		`,
 	},
+	{
+		Version:     2,
+		Description: "Phase content revisions",
+		Up: `
+			CREATE TABLE IF NOT EXISTS phase_revisions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				phase_id TEXT NOT NULL,
+				revision INTEGER NOT NULL,
+				title TEXT NOT NULL,
+				content TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				UNIQUE (phase_id, revision),
+				FOREIGN KEY (phase_id) REFERENCES phases(id) ON DELETE CASCADE
+			);
+			CREATE INDEX IF NOT EXISTS idx_phase_revisions_phase_id ON phase_revisions(phase_id);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS phase_revisions;
+		`,
+	},
+	{
+		Version:     3,
+		Description: "Human intervention requests",
+		Up: `
+			CREATE TABLE IF NOT EXISTS interventions (
+				id TEXT PRIMARY KEY,
+				blocker_id TEXT NOT NULL,
+				context TEXT,
+				requested_at TIMESTAMP NOT NULL,
+				resolved_at TIMESTAMP,
+				response TEXT,
+				FOREIGN KEY (blocker_id) REFERENCES blockers(id) ON DELETE CASCADE
+			);
+			CREATE INDEX IF NOT EXISTS idx_interventions_blocker_id ON interventions(blocker_id);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS interventions;
+		`,
+	},
+	{
+		Version:     4,
+		Description: "Created and updated timestamps",
+		Up: `
+			ALTER TABLE tasks ADD COLUMN created_at TIMESTAMP;
+			ALTER TABLE tasks ADD COLUMN updated_at TIMESTAMP;
+			ALTER TABLE blockers ADD COLUMN updated_at TIMESTAMP;
+			ALTER TABLE checkpoints ADD COLUMN updated_at TIMESTAMP;
+			UPDATE tasks SET
+				created_at = COALESCE(started_at, CURRENT_TIMESTAMP),
+				updated_at = COALESCE(completed_at, started_at, CURRENT_TIMESTAMP);
+			UPDATE blockers SET updated_at = COALESCE(resolved_at, created_at);
+			UPDATE checkpoints SET updated_at = created_at;
+		`,
+		Down: `
+			ALTER TABLE checkpoints DROP COLUMN updated_at;
+			ALTER TABLE blockers DROP COLUMN updated_at;
+			ALTER TABLE tasks DROP COLUMN updated_at;
+			ALTER TABLE tasks DROP COLUMN created_at;
+		`,
+	},
+	{
+		Version:     5,
+		Description: "Project pause state",
+		Up: `
+			ALTER TABLE projects ADD COLUMN paused INTEGER NOT NULL DEFAULT 0;
+			ALTER TABLE projects ADD COLUMN pause_reason TEXT;
+		`,
+		Down: `
+			ALTER TABLE projects DROP COLUMN pause_reason;
+			ALTER TABLE projects DROP COLUMN paused;
+		`,
+	},
+	{
+		Version:     6,
+		Description: "Checkpoint cost and progress snapshot",
+		Up: `
+			ALTER TABLE checkpoints ADD COLUMN total_cost REAL NOT NULL DEFAULT 0;
+			ALTER TABLE checkpoints ADD COLUMN completion_percentage REAL NOT NULL DEFAULT 0;
+		`,
+		Down: `
+			ALTER TABLE checkpoints DROP COLUMN completion_percentage;
+			ALTER TABLE checkpoints DROP COLUMN total_cost;
+		`,
+	},
+	{
+		Version:     7,
+		Description: "Token usage stage attribution",
+		Up: `
+			ALTER TABLE token_usage ADD COLUMN stage TEXT NOT NULL DEFAULT '';
+			CREATE INDEX IF NOT EXISTS idx_token_usage_stage ON token_usage(project_id, stage);
+		`,
+		Down: `
+			DROP INDEX IF EXISTS idx_token_usage_stage;
+			ALTER TABLE token_usage DROP COLUMN stage;
+		`,
+	},
+	{
+		Version:     8,
+		Description: "Project archive state",
+		Up: `
+			ALTER TABLE projects ADD COLUMN archived_at TIMESTAMP;
+		`,
+		Down: `
+			ALTER TABLE projects DROP COLUMN archived_at;
+		`,
+	},
+	{
+		Version:     9,
+		Description: "Phase success criteria tracking",
+		Up: `
+			CREATE TABLE IF NOT EXISTS success_criteria (
+				phase_id TEXT NOT NULL,
+				position INTEGER NOT NULL,
+				text TEXT NOT NULL,
+				met INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (phase_id, position),
+				FOREIGN KEY (phase_id) REFERENCES phases(id) ON DELETE CASCADE
+			);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS success_criteria;
+		`,
+	},
+	{
+		Version:     10,
+		Description: "Task commit links",
+		Up: `
+			CREATE TABLE IF NOT EXISTS task_commits (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				task_id TEXT NOT NULL,
+				sha TEXT NOT NULL,
+				message TEXT NOT NULL,
+				linked_at TIMESTAMP NOT NULL,
+				UNIQUE (task_id, sha),
+				FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+			);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS task_commits;
+		`,
+	},
+	{
+		Version:     11,
+		Description: "Saved reports",
+		Up: `
+			CREATE TABLE IF NOT EXISTS saved_reports (
+				name TEXT PRIMARY KEY,
+				sql_text TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				updated_at TIMESTAMP NOT NULL
+			);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS saved_reports;
+		`,
+	},
+	{
+		Version:     12,
+		Description: "Queryable interview columns",
+		Up: `
+			ALTER TABLE interview_data ADD COLUMN problem_statement TEXT NOT NULL DEFAULT '';
+			ALTER TABLE interview_data ADD COLUMN target_users TEXT NOT NULL DEFAULT '[]';
+			ALTER TABLE interview_data ADD COLUMN completed INTEGER NOT NULL DEFAULT 0;
+			UPDATE interview_data SET
+				problem_statement = COALESCE(json_extract(data, '$.ProblemStatement'), ''),
+				target_users = COALESCE(json_extract(data, '$.TargetUsers'), '[]'),
+				completed = CASE
+					WHEN json_valid(json_extract(data, '$.RawSession'))
+					THEN COALESCE(
+						json_extract(json_extract(data, '$.RawSession'), '$.completed'),
+						json_extract(json_extract(data, '$.RawSession'), '$.Completed'),
+						0
+					)
+					ELSE 0
+				END;
+			CREATE INDEX IF NOT EXISTS idx_interview_data_completed ON interview_data(completed);
+		`,
+		Down: `
+			DROP INDEX IF EXISTS idx_interview_data_completed;
+			ALTER TABLE interview_data DROP COLUMN completed;
+			ALTER TABLE interview_data DROP COLUMN target_users;
+			ALTER TABLE interview_data DROP COLUMN problem_statement;
+		`,
+	},
+	{
+		Version:     13,
+		Description: "Project stage transitions",
+		Up: `
+			CREATE TABLE IF NOT EXISTS stage_transitions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				project_id TEXT NOT NULL,
+				from_stage TEXT NOT NULL,
+				to_stage TEXT NOT NULL,
+				transitioned_at TIMESTAMP NOT NULL,
+				FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+			);
+			CREATE INDEX IF NOT EXISTS idx_stage_transitions_project_id ON stage_transitions(project_id);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS stage_transitions;
+		`,
+	},
+	{
+		Version:     14,
+		Description: "Task progress notes",
+		Up: `
+			CREATE TABLE IF NOT EXISTS task_notes (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				task_id TEXT NOT NULL,
+				note TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+			);
+			CREATE INDEX IF NOT EXISTS idx_task_notes_task_id ON task_notes(task_id);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS task_notes;
+		`,
+	},
+	{
+		Version:     15,
+		Description: "Phase template library",
+		Up: `
+			CREATE TABLE IF NOT EXISTS phase_templates (
+				name TEXT PRIMARY KEY,
+				title TEXT NOT NULL,
+				content TEXT NOT NULL,
+				tasks TEXT NOT NULL,
+				success_criteria TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				updated_at TIMESTAMP NOT NULL
+			);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS phase_templates;
+		`,
+	},
+	{
+		Version:     16,
+		Description: "Shell checks for success criteria",
+		Up: `
+			ALTER TABLE success_criteria ADD COLUMN check_command TEXT NOT NULL DEFAULT '';
+		`,
+		Down: `
+			ALTER TABLE success_criteria DROP COLUMN check_command;
+		`,
+	},
+	{
+		Version:     17,
+		Description: "Task dependencies",
+		Up: `
+			ALTER TABLE tasks ADD COLUMN depends_on TEXT NOT NULL DEFAULT '';
+		`,
+		Down: `
+			ALTER TABLE tasks DROP COLUMN depends_on;
+		`,
+	},
+	{
+		Version:     18,
+		Description: "Task effort estimates",
+		Up: `
+			ALTER TABLE tasks ADD COLUMN difficulty TEXT NOT NULL DEFAULT '';
+			ALTER TABLE tasks ADD COLUMN estimated_tokens INTEGER NOT NULL DEFAULT 0;
+		`,
+		Down: `
+			ALTER TABLE tasks DROP COLUMN estimated_tokens;
+			ALTER TABLE tasks DROP COLUMN difficulty;
+		`,
+	},
+}
+
+// LatestVersion returns the newest schema version this binary knows about
+func LatestVersion() int {
+	latest := 0
+	for _, migration := range migrations {
+		if migration.Version > latest {
+			latest = migration.Version
+		}
+	}
+	return latest
 }
 
 // MigrationManager handles database migrations
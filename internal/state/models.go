@@ -43,6 +43,9 @@ type Project struct {
 	CreatedAt    time.Time
 	CurrentStage Stage
 	CurrentPhase string
+	Paused       bool
+	PauseReason  string
+	ArchivedAt   *time.Time // Set while the project is archived
 }
 
 // InterviewData contains all gathered requirements
@@ -59,6 +62,9 @@ type InterviewData struct {
 	Constraints       []string
 	Assumptions       []string
 	Unknowns          []string
+	References        []string          // Files and links cited alongside answers
+	Glossary          map[string]string // Project-specific terms and their definitions, which may be empty
+	ElevatorPitch     string            // 2-3 sentence summary of the project for sharing
 	RefinementHistory []Refinement
 	RawSession        string // Stores the complete session state as JSON
 }
@@ -122,6 +128,29 @@ type Phase struct {
 	CreatedAt       time.Time
 	StartedAt       *time.Time
 	CompletedAt     *time.Time
+	// SuccessCriteria replaces the phase's tracked criteria when saved.
+	// Nil leaves the stored criteria as they are.
+	SuccessCriteria []string
+	// CriteriaChecks are shell commands verifying success criteria, keyed by
+	// criterion text, saved along with SuccessCriteria
+	CriteriaChecks map[string]string
+}
+
+// PhaseRevision is a snapshot of a phase's content before it was edited
+type PhaseRevision struct {
+	PhaseID   string
+	Revision  int
+	Title     string
+	Content   string
+	CreatedAt time.Time
+}
+
+// StageTransition records a project moving from one pipeline stage to another
+type StageTransition struct {
+	ProjectID      string
+	FromStage      Stage
+	ToStage        Stage
+	TransitionedAt time.Time
 }
 
 // Task represents a single development task
@@ -133,6 +162,15 @@ type Task struct {
 	Status      TaskStatus
 	StartedAt   *time.Time
 	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	PhaseNumber int // Only populated by project-wide queries
+	// DependsOn lists the numbers or IDs of tasks in the same phase that
+	// must finish first
+	DependsOn []string
+	// Difficulty and EstimatedTokens carry the plan's effort estimate
+	Difficulty      string
+	EstimatedTokens int
 }
 
 // Checkpoint represents a saved state
@@ -142,7 +180,12 @@ type Checkpoint struct {
 	Name      string
 	GitTag    string
 	CreatedAt time.Time
+	UpdatedAt time.Time
 	Metadata  map[string]string
+
+	// Snapshot of the project when the checkpoint was created
+	TotalCost            float64
+	CompletionPercentage float64
 }
 
 // TokenUsage tracks API usage
@@ -157,6 +200,7 @@ type TokenUsage struct {
 	TokensOutput int
 	Cost         float64
 	Timestamp    time.Time
+	Stage        string // Pipeline stage the call was made for, e.g. "interview"; empty if unknown
 }
 
 // RateLimitInfo contains rate limit information
@@ -168,6 +212,11 @@ type RateLimitInfo struct {
 	CheckedAt         time.Time
 }
 
+// Age returns how long ago the rate limit was checked
+func (r *RateLimitInfo) Age() time.Duration {
+	return time.Since(r.CheckedAt)
+}
+
 // QuotaInfo contains quota information
 type QuotaInfo struct {
 	Provider        string
@@ -179,6 +228,11 @@ type QuotaInfo struct {
 	CheckedAt       time.Time
 }
 
+// Age returns how long ago the quota was checked
+func (q *QuotaInfo) Age() time.Duration {
+	return time.Since(q.CheckedAt)
+}
+
 // TokenStats contains token usage statistics
 type TokenStats struct {
 	TotalInput    int
@@ -203,5 +257,24 @@ type Blocker struct {
 	Description string
 	Resolution  string
 	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ResolvedAt  *time.Time
+}
+
+// FlakyTask is a task that has repeatedly been blocked
+type FlakyTask struct {
+	TaskID       string
+	Number       string
+	Description  string
+	BlockerCount int
+}
+
+// Intervention is a request for a human to help resolve a blocker
+type Intervention struct {
+	ID          string
+	BlockerID   string
+	Context     string
+	RequestedAt time.Time
 	ResolvedAt  *time.Time
+	Response    string
 }
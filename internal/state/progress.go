@@ -49,6 +49,10 @@ type PhaseProgress struct {
 
 // CalculateProgress calculates overall project progress
 func (s *Store) CalculateProgress(projectID string) (*ProgressStats, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	// Get project
 	project, err := s.GetProject(projectID)
 	if err != nil {
@@ -133,6 +137,10 @@ func (s *Store) CalculateProgress(projectID string) (*ProgressStats, error) {
 
 // GetPhaseProgress gets progress for a specific phase
 func (s *Store) GetPhaseProgress(phaseID string) (*PhaseProgress, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	phase, err := s.GetPhase(phaseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get phase: %w", err)
@@ -171,6 +179,10 @@ func (s *Store) GetPhaseProgress(phaseID string) (*PhaseProgress, error) {
 
 // ListAllPhaseProgress gets progress for all phases in a project
 func (s *Store) ListAllPhaseProgress(projectID string) ([]*PhaseProgress, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	phases, err := s.ListPhases(projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list phases: %w", err)
@@ -231,6 +243,10 @@ type ProgressFilter struct {
 
 // GetFilteredProgress gets progress with filters applied
 func (s *Store) GetFilteredProgress(projectID string, filter *ProgressFilter) ([]*PhaseProgress, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	allProgress, err := s.ListAllPhaseProgress(projectID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,75 @@
+package state
+
+import "fmt"
+
+// RepairReport counts the orphaned references fixed by RepairOrphans
+type RepairReport struct {
+	TokenUsagePhaseRefs int // token_usage rows whose phase_id was cleared
+	TokenUsageTaskRefs  int // token_usage rows whose task_id was cleared
+	DanglingBlockers    int // blockers deleted because their task is gone
+}
+
+// Total returns the number of rows repaired
+func (r *RepairReport) Total() int {
+	return r.TokenUsagePhaseRefs + r.TokenUsageTaskRefs + r.DanglingBlockers
+}
+
+// RepairOrphans fixes references left dangling by crashes, manual edits or
+// data written before foreign keys were enforced. Token usage keeps its cost
+// history with the missing phase or task reference cleared, while blockers
+// for tasks that no longer exist are deleted.
+func (s *Store) RepairOrphans() (*RepairReport, error) {
+	return s.repairOrphans(true)
+}
+
+// FindOrphans reports what RepairOrphans would fix without changing anything
+func (s *Store) FindOrphans() (*RepairReport, error) {
+	return s.repairOrphans(false)
+}
+
+// repairOrphans runs the repairs in a transaction, committing them only
+// when commit is set so a dry run counts exactly what a repair would fix
+func (s *Store) repairOrphans(commit bool) (*RepairReport, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.handle().Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	report := &RepairReport{}
+	repairs := []struct {
+		query string
+		count *int
+	}{
+		{`UPDATE token_usage SET phase_id = NULL
+			WHERE phase_id IS NOT NULL AND phase_id NOT IN (SELECT id FROM phases)`, &report.TokenUsagePhaseRefs},
+		{`UPDATE token_usage SET task_id = NULL
+			WHERE task_id IS NOT NULL AND task_id NOT IN (SELECT id FROM tasks)`, &report.TokenUsageTaskRefs},
+		{`DELETE FROM blockers WHERE task_id NOT IN (SELECT id FROM tasks)`, &report.DanglingBlockers},
+	}
+
+	for _, repair := range repairs {
+		result, err := tx.Exec(repair.query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to repair orphaned rows: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		*repair.count = int(affected)
+	}
+
+	if !commit {
+		return report, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit repair: %w", err)
+	}
+
+	return report, nil
+}
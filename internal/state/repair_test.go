@@ -0,0 +1,92 @@
+package state
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_RepairOrphans(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	phase := &Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Setup", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	task := &Task{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Task", Status: TaskInProgress}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	valid := &TokenUsage{ProjectID: project.ID, PhaseID: phase.ID, TaskID: task.ID, Provider: "openai", Model: "gpt-4", Cost: 0.5, Timestamp: time.Now()}
+	if err := store.RecordTokenUsage(valid); err != nil {
+		t.Fatalf("Failed to record token usage: %v", err)
+	}
+
+	// Write orphans the way pre-foreign-key data would have looked
+	ctx := context.Background()
+	conn, err := store.db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	statements := []string{
+		"PRAGMA foreign_keys = OFF",
+		`INSERT INTO token_usage (project_id, phase_id, task_id, provider, model, tokens_input, tokens_output, cost, timestamp)
+			VALUES ('proj-123', 'phase-gone', 'task-gone', 'openai', 'gpt-4', 10, 10, 1.25, CURRENT_TIMESTAMP)`,
+		`INSERT INTO blockers (id, task_id, description, created_at)
+			VALUES ('blocker-gone', 'task-gone', 'Stale blocker', CURRENT_TIMESTAMP)`,
+		"PRAGMA foreign_keys = ON",
+	}
+	for _, stmt := range statements {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("Failed to insert orphans: %v", err)
+		}
+	}
+	conn.Close()
+
+	// A dry run counts the orphans but leaves them in place
+	found, err := store.FindOrphans()
+	if err != nil {
+		t.Fatalf("FindOrphans failed: %v", err)
+	}
+	if found.TokenUsagePhaseRefs != 1 || found.TokenUsageTaskRefs != 1 || found.DanglingBlockers != 1 {
+		t.Errorf("Unexpected dry run report: %+v", found)
+	}
+	if again, err := store.FindOrphans(); err != nil || again.Total() != found.Total() {
+		t.Errorf("Expected a dry run to change nothing, got %+v, %v", again, err)
+	}
+
+	report, err := store.RepairOrphans()
+	if err != nil {
+		t.Fatalf("RepairOrphans failed: %v", err)
+	}
+	if report.TokenUsagePhaseRefs != 1 || report.TokenUsageTaskRefs != 1 || report.DanglingBlockers != 1 {
+		t.Errorf("Unexpected repair report: %+v", report)
+	}
+
+	// Cost history must survive the repair
+	total, err := store.GetTotalCost(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get total cost: %v", err)
+	}
+	if total != 1.75 {
+		t.Errorf("Expected total cost 1.75 after repair, got %f", total)
+	}
+
+	// A second run has nothing left to fix
+	report, err = store.RepairOrphans()
+	if err != nil {
+		t.Fatalf("RepairOrphans failed: %v", err)
+	}
+	if report.Total() != 0 {
+		t.Errorf("Expected nothing to repair on second run, got %+v", report)
+	}
+}
@@ -0,0 +1,120 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// validateReportSQL accepts only a single SELECT statement, so a saved
+// report can read the database but never change it
+func validateReportSQL(sqlText string) (string, error) {
+	query := strings.TrimSpace(sqlText)
+	query = strings.TrimSpace(strings.TrimRight(query, ";"))
+	if query == "" {
+		return "", fmt.Errorf("report query cannot be empty")
+	}
+
+	fields := strings.Fields(query)
+	if !strings.EqualFold(fields[0], "SELECT") {
+		return "", fmt.Errorf("report query must be a SELECT statement")
+	}
+	if strings.Contains(query, ";") {
+		return "", fmt.Errorf("report query must be a single statement")
+	}
+	return query, nil
+}
+
+// SaveReport stores a named read-only SELECT query, replacing any report
+// saved under the same name
+func (s *Store) SaveReport(name, sqlText string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("report name cannot be empty")
+	}
+	query, err := validateReportSQL(sqlText)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = s.handle().Exec(`
+		INSERT INTO saved_reports (name, sql_text, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET sql_text = excluded.sql_text, updated_at = excluded.updated_at
+	`, name, query, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+	return nil
+}
+
+// RunReport executes a saved report and returns its rows keyed by column
+// name. Text columns come back as strings rather than byte slices. The
+// query runs in a transaction that is always rolled back, as a second
+// guard against it changing anything.
+func (s *Store) RunReport(name string) ([]map[string]interface{}, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	var sqlText string
+	if err := s.handle().QueryRow("SELECT sql_text FROM saved_reports WHERE name = ?", name).Scan(&sqlText); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("report not found: %s", name)
+		}
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	query, err := validateReportSQL(sqlText)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.handle().Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run report: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report columns: %w", err)
+	}
+
+	results := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan report row: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating report rows: %w", err)
+	}
+	return results, nil
+}
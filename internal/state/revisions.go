@@ -0,0 +1,125 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// snapshotPhaseRevision records the currently stored title and content of a
+// phase as a new revision if the incoming phase changes either of them.
+// New phases and status-only updates do not produce a revision.
+func snapshotPhaseRevision(tx *sql.Tx, phase *Phase) error {
+	var title, content string
+	err := tx.QueryRow(`
+		SELECT title, content
+		FROM phases
+		WHERE id = ?
+	`, phase.ID).Scan(&title, &content)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load current phase content: %w", err)
+	}
+
+	if title == phase.Title && content == phase.Content {
+		return nil
+	}
+
+	var next int
+	err = tx.QueryRow(`
+		SELECT COALESCE(MAX(revision), 0) + 1
+		FROM phase_revisions
+		WHERE phase_id = ?
+	`, phase.ID).Scan(&next)
+	if err != nil {
+		return fmt.Errorf("failed to get next phase revision: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO phase_revisions (phase_id, revision, title, content, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, phase.ID, next, title, content, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save phase revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetPhaseRevisions retrieves the content history of a phase, oldest first
+func (s *Store) GetPhaseRevisions(phaseID string) ([]PhaseRevision, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT phase_id, revision, title, content, created_at
+		FROM phase_revisions
+		WHERE phase_id = ?
+		ORDER BY revision ASC
+	`
+	rows, err := s.handle().Query(query, phaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list phase revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []PhaseRevision
+	for rows.Next() {
+		var revision PhaseRevision
+		err := rows.Scan(
+			&revision.PhaseID,
+			&revision.Revision,
+			&revision.Title,
+			&revision.Content,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan phase revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating phase revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// RevertPhase restores a phase's title and content from an earlier revision.
+// The content being replaced is itself kept as a new revision, so a revert
+// can be undone. The revision is read and the phase rewritten in a single
+// transaction.
+func (s *Store) RevertPhase(phaseID string, revision int) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	return s.WithTransaction(func(tx *sql.Tx) error {
+		var title, content string
+		err := tx.QueryRow(`
+			SELECT title, content
+			FROM phase_revisions
+			WHERE phase_id = ? AND revision = ?
+		`, phaseID, revision).Scan(&title, &content)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("revision %d not found for phase: %s", revision, phaseID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get phase revision: %w", err)
+		}
+
+		phase, err := getPhase(tx, phaseID)
+		if err != nil {
+			return err
+		}
+
+		phase.Title = title
+		phase.Content = content
+
+		return savePhase(tx, phase)
+	})
+}
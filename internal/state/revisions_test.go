@@ -0,0 +1,112 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore_PhaseRevisions(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StagePlan,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	phase := &Phase{
+		ID:        "phase-1",
+		ProjectID: "proj-123",
+		Number:    1,
+		Title:     "Database",
+		Content:   "Version 1",
+		Status:    PhaseNotStarted,
+		CreatedAt: time.Now(),
+	}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+
+	// Saving without changing content should not create a revision
+	phase.Status = PhaseInProgress
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+
+	revisions, err := store.GetPhaseRevisions(phase.ID)
+	if err != nil {
+		t.Fatalf("Failed to get revisions: %v", err)
+	}
+	if len(revisions) != 0 {
+		t.Fatalf("Expected no revisions before editing, got %d", len(revisions))
+	}
+
+	// Edit the phase twice
+	phase.Content = "Version 2"
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	phase.Content = "Version 3"
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+
+	revisions, err = store.GetPhaseRevisions(phase.ID)
+	if err != nil {
+		t.Fatalf("Failed to get revisions: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("Expected 2 revisions, got %d", len(revisions))
+	}
+	if revisions[0].Revision != 1 || revisions[0].Content != "Version 1" {
+		t.Errorf("Unexpected first revision: %+v", revisions[0])
+	}
+	if revisions[1].Revision != 2 || revisions[1].Content != "Version 2" {
+		t.Errorf("Unexpected second revision: %+v", revisions[1])
+	}
+
+	// Revert to the first revision
+	if err := store.RevertPhase(phase.ID, 1); err != nil {
+		t.Fatalf("Failed to revert phase: %v", err)
+	}
+
+	retrieved, err := store.GetPhase(phase.ID)
+	if err != nil {
+		t.Fatalf("Failed to get phase: %v", err)
+	}
+	if retrieved.Content != "Version 1" {
+		t.Errorf("Content not reverted: got %s, want %s", retrieved.Content, "Version 1")
+	}
+	if retrieved.Status != PhaseInProgress {
+		t.Errorf("Revert should keep status: got %s", retrieved.Status)
+	}
+
+	// The reverted-away content is kept as a new revision
+	revisions, err = store.GetPhaseRevisions(phase.ID)
+	if err != nil {
+		t.Fatalf("Failed to get revisions: %v", err)
+	}
+	if len(revisions) != 3 || revisions[2].Content != "Version 3" {
+		t.Errorf("Expected revert to snapshot replaced content, got %+v", revisions)
+	}
+}
+
+func TestStore_RevertPhase_UnknownRevision(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.RevertPhase("missing-phase", 1); err == nil {
+		t.Error("Expected error reverting to a missing revision")
+	}
+}
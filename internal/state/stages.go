@@ -0,0 +1,74 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// recordStageTransition logs a project's move to stage if it is currently
+// in a different one. Unknown projects are left for the caller's update to
+// report.
+func recordStageTransition(tx *sql.Tx, projectID string, stage Stage) error {
+	var current Stage
+	err := tx.QueryRow("SELECT current_stage FROM projects WHERE id = ?", projectID).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load current project stage: %w", err)
+	}
+
+	if current == stage {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO stage_transitions (project_id, from_stage, to_stage, transitioned_at)
+		VALUES (?, ?, ?, ?)
+	`, projectID, current, stage, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record stage transition: %w", err)
+	}
+
+	return nil
+}
+
+// GetStageHistory retrieves every stage change of a project, oldest first
+func (s *Store) GetStageHistory(projectID string) ([]StageTransition, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT project_id, from_stage, to_stage, transitioned_at
+		FROM stage_transitions
+		WHERE project_id = ?
+		ORDER BY transitioned_at ASC, id ASC
+	`
+	rows, err := s.handle().Query(query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stage transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []StageTransition
+	for rows.Next() {
+		var transition StageTransition
+		err := rows.Scan(
+			&transition.ProjectID,
+			&transition.FromStage,
+			&transition.ToStage,
+			&transition.TransitionedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stage transition: %w", err)
+		}
+		transitions = append(transitions, transition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stage transitions: %w", err)
+	}
+
+	return transitions, nil
+}
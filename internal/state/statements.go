@@ -0,0 +1,72 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Hot-path queries that are prepared once per connection instead of being
+// re-parsed on every call from the executor loop
+const (
+	updateTaskStartedQuery = `
+		UPDATE tasks
+		SET status = ?, started_at = COALESCE(started_at, ?), updated_at = ?
+		WHERE id = ?
+	`
+	updateTaskCompletedQuery = `
+		UPDATE tasks
+		SET status = ?, completed_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+	updateTaskStatusQuery = `
+		UPDATE tasks
+		SET status = ?, updated_at = ?
+		WHERE id = ?
+	`
+	recordTokenUsageQuery = `
+		INSERT INTO token_usage (project_id, phase_id, task_id, provider, model, tokens_input, tokens_output, cost, timestamp, stage)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+)
+
+// preparedQueries lists the queries prepareStatements caches
+var preparedQueries = []string{
+	updateTaskStartedQuery,
+	updateTaskCompletedQuery,
+	updateTaskStatusQuery,
+	recordTokenUsageQuery,
+}
+
+// prepareStatements prepares the hot-path queries on db
+func prepareStatements(db *sql.DB) (map[string]*sql.Stmt, error) {
+	stmts := make(map[string]*sql.Stmt, len(preparedQueries))
+	for _, query := range preparedQueries {
+		stmt, err := db.Prepare(query)
+		if err != nil {
+			closeStatements(stmts)
+			return nil, fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		stmts[query] = stmt
+	}
+	return stmts, nil
+}
+
+// closeStatements closes every prepared statement
+func closeStatements(stmts map[string]*sql.Stmt) {
+	for _, stmt := range stmts {
+		stmt.Close()
+	}
+}
+
+// exec runs query through its prepared statement when one is cached and
+// falls back to an ad-hoc Exec otherwise. The read lock keeps Close from
+// closing the statement mid-call.
+func (s *Store) exec(query string, args ...interface{}) (sql.Result, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if stmt, ok := s.stmts[query]; ok {
+		return stmt.Exec(args...)
+	}
+	return s.db.Exec(query, args...)
+}
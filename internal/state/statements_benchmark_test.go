@@ -0,0 +1,73 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const benchmarkStatusUpdates = 10000
+
+// setupStatusBenchmark creates a store with a single task to update
+func setupStatusBenchmark(b *testing.B) *Store {
+	b.Helper()
+
+	store, err := NewStore(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatalf("Failed to create store: %v", err)
+	}
+
+	project := &Project{
+		ID:           "bench-project",
+		Name:         "Benchmark Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageDevelop,
+	}
+	if err := store.CreateProject(project); err != nil {
+		b.Fatalf("Failed to create project: %v", err)
+	}
+	phase := &Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Phase 1", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		b.Fatalf("Failed to save phase: %v", err)
+	}
+	task := &Task{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Task", Status: TaskNotStarted}
+	if err := store.SaveTask(task); err != nil {
+		b.Fatalf("Failed to save task: %v", err)
+	}
+
+	return store
+}
+
+// BenchmarkUpdateTaskStatus_Prepared runs 10k status updates through the
+// cached prepared statement
+func BenchmarkUpdateTaskStatus_Prepared(b *testing.B) {
+	store := setupStatusBenchmark(b)
+	defer store.Close()
+
+	statuses := []TaskStatus{TaskBlocked, TaskNotStarted}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchmarkStatusUpdates; j++ {
+			if err := store.UpdateTaskStatus("task-1", statuses[j%2]); err != nil {
+				b.Fatalf("Failed to update task status: %v", err)
+			}
+		}
+	}
+}
+
+// BenchmarkUpdateTaskStatus_AdHoc runs the same 10k updates through
+// db.Exec, re-parsing the SQL each time
+func BenchmarkUpdateTaskStatus_AdHoc(b *testing.B) {
+	store := setupStatusBenchmark(b)
+	defer store.Close()
+
+	statuses := []TaskStatus{TaskBlocked, TaskNotStarted}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchmarkStatusUpdates; j++ {
+			if _, err := store.db.Exec(updateTaskStatusQuery, statuses[j%2], time.Now(), "task-1"); err != nil {
+				b.Fatalf("Failed to update task status: %v", err)
+			}
+		}
+	}
+}
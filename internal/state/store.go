@@ -2,27 +2,65 @@ package state
 
 import (
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 )
 
+// ErrStoreClosed is returned by store operations called after Close
+var ErrStoreClosed = errors.New("state store is closed")
+
+// ErrProjectLimit is returned by CreateProject when the database already
+// holds the maximum number of active projects
+var ErrProjectLimit = errors.New("project limit reached")
+
+// ErrStale is returned by the *Fresh lookups when the newest record is older
+// than the requested maximum age
+var ErrStale = errors.New("record is stale")
+
+// SharedMemoryPath opens an in-memory database whose connections all share
+// one cache, so concurrent callers see each other's writes without a temp
+// file. Unlike ":memory:", which gives each pooled connection its own empty
+// database, the data lives until the store is closed.
+const SharedMemoryPath = ":shared-memory:"
+
+// sharedMemoryCounter names each shared in-memory database so separate
+// stores in one process stay isolated
+var sharedMemoryCounter atomic.Int64
+
 // Store represents the state store
 type Store struct {
 	db               *sql.DB
 	migrationManager *MigrationManager
 	dbPath           string
+	dsn              string
+	passphrase       string // SQLCipher key, empty for an unencrypted database
+	stmts            map[string]*sql.Stmt
+
+	mu     sync.RWMutex
+	closed bool
 }
 
 // NewStore creates a new state store
 func NewStore(dbPath string) (*Store, error) {
 	store := &Store{
 		dbPath: dbPath,
+		dsn:    dbPath,
+	}
+	if dbPath == SharedMemoryPath {
+		store.dsn = fmt.Sprintf("file:geoffrussy-shared-%d?mode=memory&cache=shared", sharedMemoryCounter.Add(1))
 	}
 
 	if err := store.open(); err != nil {
@@ -34,54 +72,128 @@ func NewStore(dbPath string) (*Store, error) {
 
 // open opens the database connection and initializes the store
 func (s *Store) open() error {
+	db, migrationManager, stmts, err := s.connect()
+	if err != nil {
+		return err
+	}
+
+	// Only a fully migrated database reopens the store
+	s.mu.Lock()
+	s.db = db
+	s.migrationManager = migrationManager
+	s.stmts = stmts
+	s.closed = false
+	s.mu.Unlock()
+
+	return nil
+}
+
+// connect opens and migrates a new database handle without installing it
+func (s *Store) connect() (*sql.DB, *MigrationManager, map[string]*sql.Stmt, error) {
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(s.dbPath)
 	if dir != "" && dir != "." {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create database directory: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to create database directory: %w", err)
 		}
 	}
 
 	// Open database connection
-	db, err := sql.Open("sqlite3", s.dbPath)
+	db, err := s.openDB()
 	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	// Enable foreign keys
 	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
 		db.Close()
-		return fmt.Errorf("failed to enable foreign keys: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to enable foreign keys: %w", err)
 	}
 
 	// Enable WAL mode for better concurrency
 	if _, err := db.Exec("PRAGMA journal_mode = WAL"); err != nil {
 		db.Close()
-		return fmt.Errorf("failed to enable WAL mode: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to enable WAL mode: %w", err)
 	}
 
-	s.db = db
-	s.migrationManager = NewMigrationManager(db)
+	migrationManager := NewMigrationManager(db)
+
+	// Refuse to touch a database written by a newer binary
+	if err := migrationManager.Initialize(); err != nil {
+		db.Close()
+		return nil, nil, nil, fmt.Errorf("failed to initialize migrations table: %w", err)
+	}
+	currentVersion, err := migrationManager.CurrentVersion()
+	if err != nil {
+		db.Close()
+		return nil, nil, nil, err
+	}
+	if latest := LatestVersion(); currentVersion > latest {
+		db.Close()
+		return nil, nil, nil, fmt.Errorf("%w (database is at version %d, this binary supports up to %d); please upgrade geoffrussy", ErrDatabaseNewer, currentVersion, latest)
+	}
 
 	// Run migrations
-	if err := s.migrationManager.Migrate(); err != nil {
+	if err := migrationManager.Migrate(); err != nil {
 		db.Close()
-		return fmt.Errorf("failed to run migrations: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	return nil
+	stmts, err := prepareStatements(db)
+	if err != nil {
+		db.Close()
+		return nil, nil, nil, err
+	}
+
+	return db, migrationManager, stmts, nil
 }
 
-// Close closes the database connection
+// Close closes the database connection. Calling Close more than once is a
+// no-op.
 func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	closeStatements(s.stmts)
+	s.stmts = nil
+
 	if s.db != nil {
 		return s.db.Close()
 	}
 	return nil
 }
 
+// ensureOpen returns ErrStoreClosed once the store has been closed
+func (s *Store) ensureOpen() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+	return nil
+}
+
+// handle returns the current database handle. Rekey swaps the handle under
+// the write lock, so it is always read under the read lock.
+func (s *Store) handle() *sql.DB {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.db
+}
+
 // Backup creates a backup of the database to the specified path
 func (s *Store) Backup(destPath string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	// Ensure destination directory exists
 	dir := filepath.Dir(destPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -92,22 +204,52 @@ func (s *Store) Backup(destPath string) error {
 	// Note: We need to quote the path to handle spaces and escape single quotes
 	escapedPath := strings.ReplaceAll(destPath, "'", "''")
 	query := fmt.Sprintf("VACUUM INTO '%s'", escapedPath)
-	if _, err := s.db.Exec(query); err != nil {
+	if _, err := s.handle().Exec(query); err != nil {
 		return fmt.Errorf("failed to backup database: %w", err)
 	}
 
 	return nil
 }
 
+// BackupTo writes a consistent point-in-time copy of the live database to
+// path, replacing any file already there. The copy is made next to path and
+// renamed into place, so a failed backup leaves the previous one intact.
+func (s *Store) BackupTo(path string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return fmt.Errorf("backup path is a directory: %s", path)
+	}
+
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath)
+	if err := s.Backup(tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to move backup into place: %w", err)
+	}
+
+	return nil
+}
+
 // GetAllCheckpoints retrieves all checkpoints across all projects
 // This is used primarily for history preservation during rollback
 func (s *Store) GetAllCheckpoints() ([]*Checkpoint, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, project_id, name, git_tag, created_at, metadata
+		SELECT id, project_id, name, git_tag, created_at, updated_at, metadata, total_cost, completion_percentage
 		FROM checkpoints
 		ORDER BY created_at DESC
 	`
-	rows, err := s.db.Query(query)
+	rows, err := s.handle().Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
 	}
@@ -117,6 +259,7 @@ func (s *Store) GetAllCheckpoints() ([]*Checkpoint, error) {
 	for rows.Next() {
 		var checkpoint Checkpoint
 		var metadataJSON sql.NullString
+		var updatedAt sql.NullTime
 
 		err := rows.Scan(
 			&checkpoint.ID,
@@ -124,11 +267,15 @@ func (s *Store) GetAllCheckpoints() ([]*Checkpoint, error) {
 			&checkpoint.Name,
 			&checkpoint.GitTag,
 			&checkpoint.CreatedAt,
+			&updatedAt,
 			&metadataJSON,
+			&checkpoint.TotalCost,
+			&checkpoint.CompletionPercentage,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan checkpoint: %w", err)
 		}
+		checkpoint.UpdatedAt = updatedAt.Time
 
 		// Unmarshal metadata if present
 		if metadataJSON.Valid && metadataJSON.String != "" {
@@ -153,6 +300,13 @@ func (s *Store) GetAllCheckpoints() ([]*Checkpoint, error) {
 // It preserves checkpoint history by saving current checkpoints before restore
 // and re-applying them after restore.
 func (s *Store) Restore(backupPath string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+	if s.dbPath == ":memory:" || s.dbPath == SharedMemoryPath {
+		return fmt.Errorf("cannot restore into an in-memory database")
+	}
+
 	// 1. Preserve history: Get all checkpoints from current state
 	checkpoints, err := s.GetAllCheckpoints()
 	if err != nil {
@@ -199,19 +353,26 @@ func (s *Store) Restore(backupPath string) error {
 
 // DB returns the underlying database connection
 func (s *Store) DB() *sql.DB {
-	return s.db
+	return s.handle()
 }
 
 // MigrationManager returns the migration manager
 func (s *Store) MigrationManager() *MigrationManager {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.migrationManager
 }
 
 // HealthCheck verifies the database is accessible and not corrupted
 func (s *Store) HealthCheck() error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	// Try a simple query
 	var result int
-	err := s.db.QueryRow("SELECT 1").Scan(&result)
+	err := s.handle().QueryRow("SELECT 1").Scan(&result)
 	if err != nil {
 		return fmt.Errorf("database health check failed: %w", err)
 	}
@@ -221,7 +382,7 @@ func (s *Store) HealthCheck() error {
 	}
 	
 	// Check schema version
-	version, err := s.migrationManager.CurrentVersion()
+	version, err := s.MigrationManager().CurrentVersion()
 	if err != nil {
 		return fmt.Errorf("failed to get schema version: %w", err)
 	}
@@ -235,44 +396,165 @@ func (s *Store) HealthCheck() error {
 
 // BeginTx starts a new transaction
 func (s *Store) BeginTx() (*sql.Tx, error) {
-	return s.db.Begin()
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	return s.handle().Begin()
+}
+
+// WithTransaction runs fn in a transaction, committing if it returns nil and
+// rolling back if it returns an error or panics. A panic is re-raised after
+// the rollback.
+func (s *Store) WithTransaction(fn func(tx *sql.Tx) error) (err error) {
+	tx, err := s.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
 // Project operations
 
-// CreateProject creates a new project
+// maxProjectsConfigKey is the config key holding the project limit
+const maxProjectsConfigKey = "max_projects"
+
+// CreateProject creates a new project. If a project limit is set and the
+// database already holds that many unarchived projects, it returns
+// ErrProjectLimit.
 func (s *Store) CreateProject(project *Project) error {
-	query := `
-		INSERT INTO projects (id, name, created_at, current_stage, current_phase_id)
-		VALUES (?, ?, ?, ?, ?)
-	`
-	_, err := s.db.Exec(query,
-		project.ID,
-		project.Name,
-		project.CreatedAt,
-		project.CurrentStage,
-		project.CurrentPhase,
-	)
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	return s.WithTransaction(func(tx *sql.Tx) error {
+		limit, err := queryMaxProjects(tx)
+		if err != nil {
+			return err
+		}
+		if limit > 0 {
+			count, err := countProjects(tx)
+			if err != nil {
+				return err
+			}
+			if count >= limit {
+				return fmt.Errorf("%w: %d of %d projects in use", ErrProjectLimit, count, limit)
+			}
+		}
+
+		query := `
+			INSERT INTO projects (id, name, created_at, current_stage, current_phase_id)
+			VALUES (?, ?, ?, ?, ?)
+		`
+		_, err = tx.Exec(query,
+			project.ID,
+			project.Name,
+			project.CreatedAt,
+			project.CurrentStage,
+			project.CurrentPhase,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create project: %w", err)
+		}
+		return nil
+	})
+}
+
+// CountProjects returns the number of projects that aren't archived
+func (s *Store) CountProjects() (int, error) {
+	if err := s.ensureOpen(); err != nil {
+		return 0, err
+	}
+	return countProjects(s.handle())
+}
+
+// SetMaxProjects limits how many unarchived projects the database may hold.
+// A limit of zero or less removes the cap. Existing projects beyond a new
+// limit are kept; only further creation is refused.
+func (s *Store) SetMaxProjects(limit int) error {
+	if limit < 0 {
+		limit = 0
+	}
+	return s.SetConfig(maxProjectsConfigKey, strconv.Itoa(limit))
+}
+
+// GetMaxProjects returns the project limit, or zero if there is none
+func (s *Store) GetMaxProjects() (int, error) {
+	if err := s.ensureOpen(); err != nil {
+		return 0, err
+	}
+	return queryMaxProjects(s.handle())
+}
+
+// rowQuerier is satisfied by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// countProjects counts unarchived projects
+func countProjects(q rowQuerier) (int, error) {
+	var count int
+	if err := q.QueryRow("SELECT COUNT(*) FROM projects WHERE archived_at IS NULL").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count projects: %w", err)
+	}
+	return count, nil
+}
+
+// queryMaxProjects reads the project limit, treating an unset limit as none
+func queryMaxProjects(q rowQuerier) (int, error) {
+	var value string
+	err := q.QueryRow("SELECT value FROM config WHERE key = ?", maxProjectsConfigKey).Scan(&value)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create project: %w", err)
+		return 0, fmt.Errorf("failed to get project limit: %w", err)
 	}
-	return nil
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid project limit %q: %w", value, err)
+	}
+	return limit, nil
 }
 
 // GetProject retrieves a project by ID
 func (s *Store) GetProject(id string) (*Project, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, name, created_at, current_stage, current_phase_id
+		SELECT id, name, created_at, current_stage, current_phase_id, paused, pause_reason, archived_at
 		FROM projects
 		WHERE id = ?
 	`
 	var project Project
-	err := s.db.QueryRow(query, id).Scan(
+	var pauseReason sql.NullString
+	err := s.handle().QueryRow(query, id).Scan(
 		&project.ID,
 		&project.Name,
 		&project.CreatedAt,
 		&project.CurrentStage,
 		&project.CurrentPhase,
+		&project.Paused,
+		&pauseReason,
+		&project.ArchivedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("project not found: %s", id)
@@ -280,124 +562,300 @@ func (s *Store) GetProject(id string) (*Project, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
+	project.PauseReason = pauseReason.String
 	return &project, nil
 }
 
-// UpdateProject updates an existing project
-func (s *Store) UpdateProject(project *Project) error {
-	query := `
+// ProjectExists reports whether a project exists without loading it
+func (s *Store) ProjectExists(id string) (bool, error) {
+	return s.rowExists("SELECT 1 FROM projects WHERE id = ? LIMIT 1", id)
+}
+
+// rowExists reports whether query, a SELECT 1 ... LIMIT 1, returns a row
+func (s *Store) rowExists(query string, args ...interface{}) (bool, error) {
+	if err := s.ensureOpen(); err != nil {
+		return false, err
+	}
+
+	var one int
+	err := s.handle().QueryRow(query, args...).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check existence: %w", err)
+	}
+	return true, nil
+}
+
+// SetProjectPaused pauses or resumes a project. The reason is recorded
+// when pausing and cleared when resuming.
+func (s *Store) SetProjectPaused(projectID string, paused bool, reason string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	var pauseReason interface{}
+	if paused {
+		pauseReason = reason
+	}
+
+	result, err := s.handle().Exec(`
 		UPDATE projects
-		SET name = ?, current_stage = ?, current_phase_id = ?
+		SET paused = ?, pause_reason = ?
 		WHERE id = ?
-	`
-	result, err := s.db.Exec(query,
-		project.Name,
-		project.CurrentStage,
-		project.CurrentPhase,
-		project.ID,
-	)
+	`, paused, pauseReason, projectID)
 	if err != nil {
-		return fmt.Errorf("failed to update project: %w", err)
+		return fmt.Errorf("failed to set project paused: %w", err)
 	}
-	
+
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("project not found: %s", project.ID)
+		return fmt.Errorf("project not found: %s", projectID)
 	}
-	
+
 	return nil
 }
 
-// UpdateProjectStage updates the current stage of a project
-func (s *Store) UpdateProjectStage(id string, stage Stage) error {
-	query := `
+// SetProjectArchived archives or unarchives a project. Archived projects
+// keep their data but are left out of the dashboard.
+func (s *Store) SetProjectArchived(projectID string, archived bool) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	var archivedAt interface{}
+	if archived {
+		archivedAt = time.Now()
+	}
+
+	result, err := s.handle().Exec(`
 		UPDATE projects
-		SET current_stage = ?
+		SET archived_at = ?
 		WHERE id = ?
-	`
-	result, err := s.db.Exec(query, stage, id)
+	`, archivedAt, projectID)
 	if err != nil {
-		return fmt.Errorf("failed to update project stage: %w", err)
+		return fmt.Errorf("failed to set project archived: %w", err)
 	}
-	
+
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("project not found: %s", id)
+		return fmt.Errorf("project not found: %s", projectID)
 	}
-	
+
 	return nil
 }
 
-// ResetProjectProgress resets all phases and tasks progress for a project
-func (s *Store) ResetProjectProgress(projectID string) error {
-	tx, err := s.BeginTx()
+// IsPaused reports whether a project is paused and why
+func (s *Store) IsPaused(projectID string) (bool, string, error) {
+	if err := s.ensureOpen(); err != nil {
+		return false, "", err
+	}
+
+	var paused bool
+	var reason sql.NullString
+	err := s.handle().QueryRow(`
+		SELECT paused, pause_reason
+		FROM projects
+		WHERE id = ?
+	`, projectID).Scan(&paused, &reason)
+	if err == sql.ErrNoRows {
+		return false, "", fmt.Errorf("project not found: %s", projectID)
+	}
 	if err != nil {
+		return false, "", fmt.Errorf("failed to check project pause state: %w", err)
+	}
+	return paused, reason.String, nil
+}
+
+// UpdateProject updates an existing project
+func (s *Store) UpdateProject(project *Project) error {
+	if err := s.ensureOpen(); err != nil {
 		return err
 	}
-	defer tx.Rollback()
 
-	// Reset tasks
-	_, err = tx.Exec(`
-		UPDATE tasks
-		SET status = ?, started_at = NULL, completed_at = NULL
-		WHERE phase_id IN (SELECT id FROM phases WHERE project_id = ?)
-	`, TaskNotStarted, projectID)
-	if err != nil {
-		return fmt.Errorf("failed to reset tasks: %w", err)
+	return s.WithTransaction(func(tx *sql.Tx) error {
+		if err := recordStageTransition(tx, project.ID, project.CurrentStage); err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE projects
+			SET name = ?, current_stage = ?, current_phase_id = ?
+			WHERE id = ?
+		`
+		result, err := tx.Exec(query,
+			project.Name,
+			project.CurrentStage,
+			project.CurrentPhase,
+			project.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update project: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("project not found: %s", project.ID)
+		}
+
+		return nil
+	})
+}
+
+// UpdateProjectStage updates the current stage of a project
+func (s *Store) UpdateProjectStage(id string, stage Stage) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
 	}
 
-	// Reset phases
-	_, err = tx.Exec(`
-		UPDATE phases
-		SET status = ?, started_at = NULL, completed_at = NULL
-		WHERE project_id = ?
-	`, PhaseNotStarted, projectID)
-	if err != nil {
-		return fmt.Errorf("failed to reset phases: %w", err)
+	return s.WithTransaction(func(tx *sql.Tx) error {
+		if err := recordStageTransition(tx, id, stage); err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE projects
+			SET current_stage = ?
+			WHERE id = ?
+		`
+		result, err := tx.Exec(query, stage, id)
+		if err != nil {
+			return fmt.Errorf("failed to update project stage: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("project not found: %s", id)
+		}
+
+		return nil
+	})
+}
+
+// ResetProjectProgress resets all phases and tasks progress for a project
+func (s *Store) ResetProjectProgress(projectID string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
 	}
 
-	return tx.Commit()
+	return s.WithTransaction(func(tx *sql.Tx) error {
+		// Reset tasks
+		_, err := tx.Exec(`
+			UPDATE tasks
+			SET status = ?, started_at = NULL, completed_at = NULL, updated_at = ?
+			WHERE phase_id IN (SELECT id FROM phases WHERE project_id = ?)
+		`, TaskNotStarted, time.Now(), projectID)
+		if err != nil {
+			return fmt.Errorf("failed to reset tasks: %w", err)
+		}
+
+		// Reset phases
+		_, err = tx.Exec(`
+			UPDATE phases
+			SET status = ?, started_at = NULL, completed_at = NULL
+			WHERE project_id = ?
+		`, PhaseNotStarted, projectID)
+		if err != nil {
+			return fmt.Errorf("failed to reset phases: %w", err)
+		}
+
+		// Reset success criteria
+		_, err = tx.Exec(`
+			UPDATE success_criteria
+			SET met = 0
+			WHERE phase_id IN (SELECT id FROM phases WHERE project_id = ?)
+		`, projectID)
+		if err != nil {
+			return fmt.Errorf("failed to reset success criteria: %w", err)
+		}
+
+		return nil
+	})
 }
 
 // Interview data operations
 
 // SaveInterviewData saves interview data for a project
 func (s *Store) SaveInterviewData(projectID string, data *InterviewData) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	// Convert data to JSON
 	jsonData, err := marshalJSON(data)
 	if err != nil {
 		return fmt.Errorf("failed to marshal interview data: %w", err)
 	}
+	targetUsers := data.TargetUsers
+	if targetUsers == nil {
+		targetUsers = []string{}
+	}
+	targetUsersJSON, err := marshalJSON(targetUsers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal target users: %w", err)
+	}
 	
+	// The blob stays the source of truth; the other columns copy fields
+	// out of it for indexed queries
 	query := `
-		INSERT INTO interview_data (project_id, data, completed_at)
-		VALUES (?, ?, ?)
+		INSERT INTO interview_data (project_id, data, completed_at, problem_statement, target_users, completed)
+		VALUES (?, ?, ?, ?, ?, ?)
 		ON CONFLICT(project_id) DO UPDATE SET
 			data = excluded.data,
-			completed_at = excluded.completed_at
+			completed_at = excluded.completed_at,
+			problem_statement = excluded.problem_statement,
+			target_users = excluded.target_users,
+			completed = excluded.completed
 	`
-	_, err = s.db.Exec(query, projectID, jsonData, data.CreatedAt)
+	_, err = s.handle().Exec(query, projectID, jsonData, data.CreatedAt,
+		data.ProblemStatement, targetUsersJSON, interviewCompleted(data))
 	if err != nil {
 		return fmt.Errorf("failed to save interview data: %w", err)
 	}
 	return nil
 }
 
+// interviewCompleted reports whether the raw session saved with the
+// interview data is marked completed
+func interviewCompleted(data *InterviewData) bool {
+	if data.RawSession == "" {
+		return false
+	}
+	var session struct {
+		Completed bool `json:"completed"`
+	}
+	if err := json.Unmarshal([]byte(data.RawSession), &session); err != nil {
+		return false
+	}
+	return session.Completed
+}
+
 // GetInterviewData retrieves interview data for a project
 func (s *Store) GetInterviewData(projectID string) (*InterviewData, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT data
 		FROM interview_data
 		WHERE project_id = ?
 	`
 	var jsonData string
-	err := s.db.QueryRow(query, projectID).Scan(&jsonData)
+	err := s.handle().QueryRow(query, projectID).Scan(&jsonData)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("interview data not found for project: %s", projectID)
 	}
@@ -417,6 +875,10 @@ func (s *Store) GetInterviewData(projectID string) (*InterviewData, error) {
 
 // SaveArchitecture saves architecture for a project
 func (s *Store) SaveArchitecture(projectID string, arch *Architecture) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO architectures (project_id, content, created_at)
 		VALUES (?, ?, ?)
@@ -424,7 +886,7 @@ func (s *Store) SaveArchitecture(projectID string, arch *Architecture) error {
 			content = excluded.content,
 			created_at = excluded.created_at
 	`
-	_, err := s.db.Exec(query, projectID, arch.Content, arch.CreatedAt)
+	_, err := s.handle().Exec(query, projectID, arch.Content, arch.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to save architecture: %w", err)
 	}
@@ -433,13 +895,17 @@ func (s *Store) SaveArchitecture(projectID string, arch *Architecture) error {
 
 // GetArchitecture retrieves architecture for a project
 func (s *Store) GetArchitecture(projectID string) (*Architecture, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT project_id, content, created_at
 		FROM architectures
 		WHERE project_id = ?
 	`
 	var arch Architecture
-	err := s.db.QueryRow(query, projectID).Scan(
+	err := s.handle().QueryRow(query, projectID).Scan(
 		&arch.ProjectID,
 		&arch.Content,
 		&arch.CreatedAt,
@@ -455,8 +921,36 @@ func (s *Store) GetArchitecture(projectID string) (*Architecture, error) {
 
 // Phase operations
 
-// SavePhase saves a phase
+// SavePhase saves a phase. When an existing phase's title or content
+// changes, the previous version is recorded in phase_revisions first.
 func (s *Store) SavePhase(phase *Phase) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	tx, err := s.handle().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := savePhase(tx, phase); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// savePhase writes a phase and its success criteria within tx, recording the
+// content it replaces as a revision
+func savePhase(tx *sql.Tx, phase *Phase) error {
+	if err := snapshotPhaseRevision(tx, phase); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO phases (id, project_id, number, title, content, status, created_at, started_at, completed_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
@@ -468,7 +962,7 @@ func (s *Store) SavePhase(phase *Phase) error {
 			started_at = excluded.started_at,
 			completed_at = excluded.completed_at
 	`
-	_, err := s.db.Exec(query,
+	_, err := tx.Exec(query,
 		phase.ID,
 		phase.ProjectID,
 		phase.Number,
@@ -482,18 +976,34 @@ func (s *Store) SavePhase(phase *Phase) error {
 	if err != nil {
 		return fmt.Errorf("failed to save phase: %w", err)
 	}
+
+	if phase.SuccessCriteria != nil {
+		if err := saveSuccessCriteria(tx, phase.ID, phase.SuccessCriteria, phase.CriteriaChecks); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // GetPhase retrieves a phase by ID
 func (s *Store) GetPhase(id string) (*Phase, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	return getPhase(s.handle(), id)
+}
+
+// getPhase reads a phase through q, which may be the database or a
+// transaction
+func getPhase(q rowQuerier, id string) (*Phase, error) {
 	query := `
 		SELECT id, project_id, number, title, content, status, created_at, started_at, completed_at
 		FROM phases
 		WHERE id = ?
 	`
 	var phase Phase
-	err := s.db.QueryRow(query, id).Scan(
+	err := q.QueryRow(query, id).Scan(
 		&phase.ID,
 		&phase.ProjectID,
 		&phase.Number,
@@ -513,15 +1023,24 @@ func (s *Store) GetPhase(id string) (*Phase, error) {
 	return &phase, nil
 }
 
+// PhaseExists reports whether a phase exists without loading it
+func (s *Store) PhaseExists(id string) (bool, error) {
+	return s.rowExists("SELECT 1 FROM phases WHERE id = ? LIMIT 1", id)
+}
+
 // ListPhases retrieves all phases for a project
 func (s *Store) ListPhases(projectID string) ([]*Phase, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT id, project_id, number, title, content, status, created_at, started_at, completed_at
 		FROM phases
 		WHERE project_id = ?
 		ORDER BY number ASC
 	`
-	rows, err := s.db.Query(query, projectID)
+	rows, err := s.handle().Query(query, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list phases: %w", err)
 	}
@@ -556,6 +1075,10 @@ func (s *Store) ListPhases(projectID string) ([]*Phase, error) {
 
 // UpdatePhaseStatus updates the status of a phase
 func (s *Store) UpdatePhaseStatus(id string, status PhaseStatus) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	now := time.Now()
 	var query string
 	var args []interface{}
@@ -569,6 +1092,13 @@ func (s *Store) UpdatePhaseStatus(id string, status PhaseStatus) error {
 		`
 		args = []interface{}{status, now, id}
 	case PhaseCompleted:
+		unmet, err := s.countUnmetCriteria(id)
+		if err != nil {
+			return err
+		}
+		if unmet > 0 {
+			return fmt.Errorf("%w: phase %s has %d unmet", ErrUnmetCriteria, id, unmet)
+		}
 		query = `
 			UPDATE phases
 			SET status = ?, completed_at = ?
@@ -584,7 +1114,7 @@ func (s *Store) UpdatePhaseStatus(id string, status PhaseStatus) error {
 		args = []interface{}{status, id}
 	}
 	
-	result, err := s.db.Exec(query, args...)
+	result, err := s.handle().Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update phase status: %w", err)
 	}
@@ -600,57 +1130,155 @@ func (s *Store) UpdatePhaseStatus(id string, status PhaseStatus) error {
 	return nil
 }
 
-// DeletePhase deletes a phase and its tasks
-func (s *Store) DeletePhase(id string) error {
-	// Start transaction
-	tx, err := s.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+// ReorderPhases renumbers a project's phases to follow orderedPhaseIDs,
+// which must list every phase of the project exactly once. Numbering starts
+// from the project's lowest current phase number, and task numbers prefixed
+// with their phase's old number are moved to the new one.
+func (s *Store) ReorderPhases(projectID string, orderedPhaseIDs []string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
 	}
-	defer tx.Rollback()
 
-	// Delete tasks first (manual cascade for safety)
-	_, err = tx.Exec("DELETE FROM tasks WHERE phase_id = ?", id)
-	if err != nil {
-		return fmt.Errorf("failed to delete phase tasks: %w", err)
-	}
+	return s.WithTransaction(func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT id, number FROM phases WHERE project_id = ?", projectID)
+		if err != nil {
+			return fmt.Errorf("failed to list phases: %w", err)
+		}
+		current := make(map[string]int)
+		first := 0
+		for rows.Next() {
+			var id string
+			var number int
+			if err := rows.Scan(&id, &number); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan phase: %w", err)
+			}
+			if len(current) == 0 || number < first {
+				first = number
+			}
+			current[id] = number
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("error iterating phases: %w", err)
+		}
+		rows.Close()
 
-	// Delete phase
-	result, err := tx.Exec("DELETE FROM phases WHERE id = ?", id)
-	if err != nil {
-		return fmt.Errorf("failed to delete phase: %w", err)
-	}
+		if len(orderedPhaseIDs) != len(current) {
+			return fmt.Errorf("expected %d phase IDs for project %s, got %d", len(current), projectID, len(orderedPhaseIDs))
+		}
+		seen := make(map[string]bool, len(orderedPhaseIDs))
+		for _, id := range orderedPhaseIDs {
+			if _, ok := current[id]; !ok {
+				return fmt.Errorf("phase %s does not belong to project %s", id, projectID)
+			}
+			if seen[id] {
+				return fmt.Errorf("phase %s listed more than once", id)
+			}
+			seen[id] = true
+		}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-	if rows == 0 {
-		return fmt.Errorf("phase not found: %s", id)
-	}
+		for i, id := range orderedPhaseIDs {
+			number := first + i
+			oldNumber := current[id]
+			if number == oldNumber {
+				continue
+			}
+			if _, err := tx.Exec("UPDATE phases SET number = ? WHERE id = ?", number, id); err != nil {
+				return fmt.Errorf("failed to renumber phase: %w", err)
+			}
+			oldPrefix := strconv.Itoa(oldNumber)
+			_, err := tx.Exec(`
+				UPDATE tasks
+				SET number = ? || substr(number, ?)
+				WHERE phase_id = ? AND number LIKE ?
+			`, strconv.Itoa(number), len(oldPrefix)+1, id, oldPrefix+".%")
+			if err != nil {
+				return fmt.Errorf("failed to renumber phase tasks: %w", err)
+			}
+		}
+		return nil
+	})
+}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+// DeletePhase deletes a phase with its tasks and success criteria
+func (s *Store) DeletePhase(id string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
 	}
 
-	return nil
+	return s.WithTransaction(func(tx *sql.Tx) error {
+		// Delete tasks first (manual cascade for safety)
+		_, err := tx.Exec("DELETE FROM tasks WHERE phase_id = ?", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete phase tasks: %w", err)
+		}
+
+		_, err = tx.Exec("DELETE FROM success_criteria WHERE phase_id = ?", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete phase success criteria: %w", err)
+		}
+
+		// Delete phase
+		result, err := tx.Exec("DELETE FROM phases WHERE id = ?", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete phase: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("phase not found: %s", id)
+		}
+
+		return nil
+	})
 }
 
 // Task operations
 
 // SaveTask saves a task
 func (s *Store) SaveTask(task *Task) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = now
+	}
+	task.UpdatedAt = now
+
+	var dependsOn string
+	if len(task.DependsOn) > 0 {
+		encoded, err := marshalJSON(task.DependsOn)
+		if err != nil {
+			return fmt.Errorf("failed to marshal task dependencies: %w", err)
+		}
+		dependsOn = encoded
+	}
+
+	// created_at is left untouched on conflict so re-saves keep the original,
+	// and the stored value is read back so the caller's struct matches the row
 	query := `
-		INSERT INTO tasks (id, phase_id, number, description, status, started_at, completed_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO tasks (id, phase_id, number, description, status, started_at, completed_at, created_at, updated_at, depends_on, difficulty, estimated_tokens)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			number = excluded.number,
 			description = excluded.description,
 			status = excluded.status,
 			started_at = excluded.started_at,
-			completed_at = excluded.completed_at
+			completed_at = excluded.completed_at,
+			updated_at = excluded.updated_at,
+			depends_on = excluded.depends_on,
+			difficulty = excluded.difficulty,
+			estimated_tokens = excluded.estimated_tokens
+		RETURNING created_at
 	`
-	_, err := s.db.Exec(query,
+	var createdAt time.Time
+	err := s.handle().QueryRow(query,
 		task.ID,
 		task.PhaseID,
 		task.Number,
@@ -658,22 +1286,34 @@ func (s *Store) SaveTask(task *Task) error {
 		task.Status,
 		task.StartedAt,
 		task.CompletedAt,
-	)
+		task.CreatedAt,
+		task.UpdatedAt,
+		dependsOn,
+		task.Difficulty,
+		task.EstimatedTokens,
+	).Scan(&createdAt)
 	if err != nil {
 		return fmt.Errorf("failed to save task: %w", err)
 	}
+	task.CreatedAt = createdAt
 	return nil
 }
 
 // GetTask retrieves a task by ID
 func (s *Store) GetTask(id string) (*Task, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, phase_id, number, description, status, started_at, completed_at
+		SELECT id, phase_id, number, description, status, started_at, completed_at, created_at, updated_at, depends_on, difficulty, estimated_tokens
 		FROM tasks
 		WHERE id = ?
 	`
 	var task Task
-	err := s.db.QueryRow(query, id).Scan(
+	var createdAt, updatedAt sql.NullTime
+	var dependsOn string
+	err := s.handle().QueryRow(query, id).Scan(
 		&task.ID,
 		&task.PhaseID,
 		&task.Number,
@@ -681,6 +1321,11 @@ func (s *Store) GetTask(id string) (*Task, error) {
 		&task.Status,
 		&task.StartedAt,
 		&task.CompletedAt,
+		&createdAt,
+		&updatedAt,
+		&dependsOn,
+		&task.Difficulty,
+		&task.EstimatedTokens,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("task not found: %s", id)
@@ -688,40 +1333,40 @@ func (s *Store) GetTask(id string) (*Task, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
+	task.CreatedAt = createdAt.Time
+	task.UpdatedAt = updatedAt.Time
+	task.DependsOn = decodeTaskDependencies(dependsOn)
 	return &task, nil
 }
 
+// TaskExists reports whether a task exists without loading it
+func (s *Store) TaskExists(id string) (bool, error) {
+	return s.rowExists("SELECT 1 FROM tasks WHERE id = ? LIMIT 1", id)
+}
+
 // UpdateTaskStatus updates the status of a task
 func (s *Store) UpdateTaskStatus(id string, status TaskStatus) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	now := time.Now()
 	var query string
 	var args []interface{}
 	
 	switch status {
 	case TaskInProgress:
-		query = `
-			UPDATE tasks
-			SET status = ?, started_at = COALESCE(started_at, ?)
-			WHERE id = ?
-		`
-		args = []interface{}{status, now, id}
+		query = updateTaskStartedQuery
+		args = []interface{}{status, now, now, id}
 	case TaskCompleted:
-		query = `
-			UPDATE tasks
-			SET status = ?, completed_at = ?
-			WHERE id = ?
-		`
-		args = []interface{}{status, now, id}
+		query = updateTaskCompletedQuery
+		args = []interface{}{status, now, now, id}
 	default:
-		query = `
-			UPDATE tasks
-			SET status = ?
-			WHERE id = ?
-		`
-		args = []interface{}{status, id}
+		query = updateTaskStatusQuery
+		args = []interface{}{status, now, id}
 	}
 	
-	result, err := s.db.Exec(query, args...)
+	result, err := s.exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update task status: %w", err)
 	}
@@ -739,13 +1384,17 @@ func (s *Store) UpdateTaskStatus(id string, status TaskStatus) error {
 
 // ListTasks retrieves all tasks for a phase
 func (s *Store) ListTasks(phaseID string) ([]Task, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, phase_id, number, description, status, started_at, completed_at
+		SELECT id, phase_id, number, description, status, started_at, completed_at, created_at, updated_at, depends_on, difficulty, estimated_tokens
 		FROM tasks
 		WHERE phase_id = ?
 		ORDER BY number
 	`
-	rows, err := s.db.Query(query, phaseID)
+	rows, err := s.handle().Query(query, phaseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tasks: %w", err)
 	}
@@ -754,6 +1403,8 @@ func (s *Store) ListTasks(phaseID string) ([]Task, error) {
 	var tasks []Task
 	for rows.Next() {
 		var task Task
+		var createdAt, updatedAt sql.NullTime
+		var dependsOn string
 		err := rows.Scan(
 			&task.ID,
 			&task.PhaseID,
@@ -762,10 +1413,18 @@ func (s *Store) ListTasks(phaseID string) ([]Task, error) {
 			&task.Status,
 			&task.StartedAt,
 			&task.CompletedAt,
+			&createdAt,
+			&updatedAt,
+			&dependsOn,
+			&task.Difficulty,
+			&task.EstimatedTokens,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
+		task.CreatedAt = createdAt.Time
+		task.UpdatedAt = updatedAt.Time
+		task.DependsOn = decodeTaskDependencies(dependsOn)
 		tasks = append(tasks, task)
 	}
 
@@ -776,16 +1435,21 @@ func (s *Store) ListTasks(phaseID string) ([]Task, error) {
 	return tasks, nil
 }
 
-// ListTasksByProject retrieves all tasks for a project
+// ListTasksByProject retrieves all tasks for a project with their phase
+// number attached
 func (s *Store) ListTasksByProject(projectID string) ([]Task, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT t.id, t.phase_id, t.number, t.description, t.status, t.started_at, t.completed_at
+		SELECT t.id, t.phase_id, t.number, t.description, t.status, t.started_at, t.completed_at, t.created_at, t.updated_at, t.depends_on, t.difficulty, t.estimated_tokens, p.number
 		FROM tasks t
 		JOIN phases p ON t.phase_id = p.id
 		WHERE p.project_id = ?
 		ORDER BY p.number, t.number
 	`
-	rows, err := s.db.Query(query, projectID)
+	rows, err := s.handle().Query(query, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tasks for project: %w", err)
 	}
@@ -794,6 +1458,8 @@ func (s *Store) ListTasksByProject(projectID string) ([]Task, error) {
 	var tasks []Task
 	for rows.Next() {
 		var task Task
+		var createdAt, updatedAt sql.NullTime
+		var dependsOn string
 		err := rows.Scan(
 			&task.ID,
 			&task.PhaseID,
@@ -802,18 +1468,85 @@ func (s *Store) ListTasksByProject(projectID string) ([]Task, error) {
 			&task.Status,
 			&task.StartedAt,
 			&task.CompletedAt,
+			&createdAt,
+			&updatedAt,
+			&dependsOn,
+			&task.Difficulty,
+			&task.EstimatedTokens,
+			&task.PhaseNumber,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
+		task.CreatedAt = createdAt.Time
+		task.UpdatedAt = updatedAt.Time
+		task.DependsOn = decodeTaskDependencies(dependsOn)
 		tasks = append(tasks, task)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating tasks: %w", err)
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// ListAllTasks retrieves every task in a project with its phase number
+// attached, ordered by phase number and then task number
+func (s *Store) ListAllTasks(projectID string) ([]*Task, error) {
+	listed, err := s.ListTasksByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*Task, len(listed))
+	for i := range listed {
+		tasks[i] = &listed[i]
+	}
+
+	// Task numbers are dotted strings, so "1.10" must sort after "1.2"
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].PhaseNumber != tasks[j].PhaseNumber {
+			return tasks[i].PhaseNumber < tasks[j].PhaseNumber
+		}
+		return compareTaskNumbers(tasks[i].Number, tasks[j].Number) < 0
+	})
+
+	return tasks, nil
+}
+
+// decodeTaskDependencies reads the depends_on column, treating an empty or
+// unreadable value as no dependencies
+func decodeTaskDependencies(raw string) []string {
+	var deps []string
+	if raw == "" || unmarshalJSON(raw, &deps) != nil {
+		return nil
 	}
+	return deps
+}
 
-	return tasks, nil
+// compareTaskNumbers compares dotted task numbers segment by segment,
+// numerically where both segments are integers
+func compareTaskNumbers(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		if aErr == nil && bErr == nil {
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+		if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+	return len(aParts) - len(bParts)
 }
 
 // Helper functions for JSON marshaling
@@ -834,6 +1567,10 @@ func unmarshalJSON(data string, v interface{}) error {
 
 // SaveCheckpoint saves a checkpoint
 func (s *Store) SaveCheckpoint(checkpoint *Checkpoint) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	// Convert metadata to JSON
 	var metadataJSON string
 	if checkpoint.Metadata != nil {
@@ -844,21 +1581,40 @@ func (s *Store) SaveCheckpoint(checkpoint *Checkpoint) error {
 		metadataJSON = jsonData
 	}
 	
+	checkpoint.UpdatedAt = time.Now()
+
+	// Snapshot cost and progress unless the caller is re-saving a checkpoint
+	// that already carries them
+	if checkpoint.TotalCost == 0 && checkpoint.CompletionPercentage == 0 {
+		totalCost, completion, err := s.checkpointSnapshot(checkpoint.ProjectID)
+		if err != nil {
+			return err
+		}
+		checkpoint.TotalCost = totalCost
+		checkpoint.CompletionPercentage = completion
+	}
+	
+	// The snapshot columns are left alone on conflict so that it always
+	// reflects the checkpoint's creation
 	query := `
-		INSERT INTO checkpoints (id, project_id, name, git_tag, created_at, metadata)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO checkpoints (id, project_id, name, git_tag, created_at, updated_at, metadata, total_cost, completion_percentage)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			name = excluded.name,
 			git_tag = excluded.git_tag,
+			updated_at = excluded.updated_at,
 			metadata = excluded.metadata
 	`
-	_, err := s.db.Exec(query,
+	_, err := s.handle().Exec(query,
 		checkpoint.ID,
 		checkpoint.ProjectID,
 		checkpoint.Name,
 		checkpoint.GitTag,
 		checkpoint.CreatedAt,
+		checkpoint.UpdatedAt,
 		metadataJSON,
+		checkpoint.TotalCost,
+		checkpoint.CompletionPercentage,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save checkpoint: %w", err)
@@ -866,23 +1622,57 @@ func (s *Store) SaveCheckpoint(checkpoint *Checkpoint) error {
 	return nil
 }
 
+// checkpointSnapshot returns a project's total spend and the percentage of
+// its tasks that are completed
+func (s *Store) checkpointSnapshot(projectID string) (float64, float64, error) {
+	totalCost, err := s.GetTotalCost(projectID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN t.status = ? THEN 1 ELSE 0 END), 0)
+		FROM tasks t
+		JOIN phases p ON t.phase_id = p.id
+		WHERE p.project_id = ?
+	`
+	var total, completed int
+	if err := s.handle().QueryRow(query, TaskCompleted, projectID).Scan(&total, &completed); err != nil {
+		return 0, 0, fmt.Errorf("failed to get task completion: %w", err)
+	}
+
+	var completion float64
+	if total > 0 {
+		completion = float64(completed) / float64(total) * 100
+	}
+	return totalCost, completion, nil
+}
+
 // GetCheckpoint retrieves a checkpoint by ID
 func (s *Store) GetCheckpoint(id string) (*Checkpoint, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, project_id, name, git_tag, created_at, metadata
+		SELECT id, project_id, name, git_tag, created_at, updated_at, metadata, total_cost, completion_percentage
 		FROM checkpoints
 		WHERE id = ?
 	`
 	var checkpoint Checkpoint
 	var metadataJSON sql.NullString
+	var updatedAt sql.NullTime
 	
-	err := s.db.QueryRow(query, id).Scan(
+	err := s.handle().QueryRow(query, id).Scan(
 		&checkpoint.ID,
 		&checkpoint.ProjectID,
 		&checkpoint.Name,
 		&checkpoint.GitTag,
 		&checkpoint.CreatedAt,
+		&updatedAt,
 		&metadataJSON,
+		&checkpoint.TotalCost,
+		&checkpoint.CompletionPercentage,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("checkpoint not found: %s", id)
@@ -890,6 +1680,7 @@ func (s *Store) GetCheckpoint(id string) (*Checkpoint, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get checkpoint: %w", err)
 	}
+	checkpoint.UpdatedAt = updatedAt.Time
 	
 	// Unmarshal metadata if present
 	if metadataJSON.Valid && metadataJSON.String != "" {
@@ -905,13 +1696,17 @@ func (s *Store) GetCheckpoint(id string) (*Checkpoint, error) {
 
 // ListCheckpoints retrieves all checkpoints for a project
 func (s *Store) ListCheckpoints(projectID string) ([]*Checkpoint, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, project_id, name, git_tag, created_at, metadata
+		SELECT id, project_id, name, git_tag, created_at, updated_at, metadata, total_cost, completion_percentage
 		FROM checkpoints
 		WHERE project_id = ?
 		ORDER BY created_at DESC
 	`
-	rows, err := s.db.Query(query, projectID)
+	rows, err := s.handle().Query(query, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
 	}
@@ -921,6 +1716,7 @@ func (s *Store) ListCheckpoints(projectID string) ([]*Checkpoint, error) {
 	for rows.Next() {
 		var checkpoint Checkpoint
 		var metadataJSON sql.NullString
+		var updatedAt sql.NullTime
 		
 		err := rows.Scan(
 			&checkpoint.ID,
@@ -928,11 +1724,15 @@ func (s *Store) ListCheckpoints(projectID string) ([]*Checkpoint, error) {
 			&checkpoint.Name,
 			&checkpoint.GitTag,
 			&checkpoint.CreatedAt,
+			&updatedAt,
 			&metadataJSON,
+			&checkpoint.TotalCost,
+			&checkpoint.CompletionPercentage,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan checkpoint: %w", err)
 		}
+		checkpoint.UpdatedAt = updatedAt.Time
 		
 		// Unmarshal metadata if present
 		if metadataJSON.Valid && metadataJSON.String != "" {
@@ -957,11 +1757,10 @@ func (s *Store) ListCheckpoints(projectID string) ([]*Checkpoint, error) {
 
 // RecordTokenUsage records token usage
 func (s *Store) RecordTokenUsage(usage *TokenUsage) error {
-	query := `
-		INSERT INTO token_usage (project_id, phase_id, task_id, provider, model, tokens_input, tokens_output, cost, timestamp)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-	
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	// Handle nullable phase_id and task_id
 	var phaseID, taskID interface{}
 	if usage.PhaseID != "" {
@@ -975,7 +1774,7 @@ func (s *Store) RecordTokenUsage(usage *TokenUsage) error {
 		taskID = nil
 	}
 	
-	result, err := s.db.Exec(query,
+	result, err := s.exec(recordTokenUsageQuery,
 		usage.ProjectID,
 		phaseID,
 		taskID,
@@ -985,6 +1784,7 @@ func (s *Store) RecordTokenUsage(usage *TokenUsage) error {
 		usage.TokensOutput,
 		usage.Cost,
 		usage.Timestamp,
+		usage.Stage,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to record token usage: %w", err)
@@ -1002,21 +1802,122 @@ func (s *Store) RecordTokenUsage(usage *TokenUsage) error {
 
 // GetTotalCost retrieves the total cost for a project
 func (s *Store) GetTotalCost(projectID string) (float64, error) {
+	if err := s.ensureOpen(); err != nil {
+		return 0, err
+	}
+
 	query := `
 		SELECT COALESCE(SUM(cost), 0)
 		FROM token_usage
 		WHERE project_id = ?
 	`
 	var totalCost float64
-	err := s.db.QueryRow(query, projectID).Scan(&totalCost)
+	err := s.handle().QueryRow(query, projectID).Scan(&totalCost)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get total cost: %w", err)
 	}
 	return totalCost, nil
 }
 
+// GetCostByStage returns a project's total cost per pipeline stage, so that
+// interview and design spend can be told apart from development. Usage
+// recorded without a stage is reported under the empty string.
+func (s *Store) GetCostByStage(projectID string) (map[string]float64, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT stage, SUM(cost)
+		FROM token_usage
+		WHERE project_id = ?
+		GROUP BY stage
+	`
+	rows, err := s.handle().Query(query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost by stage: %w", err)
+	}
+	defer rows.Close()
+
+	costs := make(map[string]float64)
+	for rows.Next() {
+		var stage string
+		var total float64
+		if err := rows.Scan(&stage, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan stage cost: %w", err)
+		}
+		costs[stage] = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stage costs: %w", err)
+	}
+
+	return costs, nil
+}
+
+// CheckProviderBudget reports whether a project's spend on one provider has
+// reached limit. A limit of zero or less is no cap and never exceeded.
+func (s *Store) CheckProviderBudget(projectID, provider string, limit float64) (bool, error) {
+	if err := s.ensureOpen(); err != nil {
+		return false, err
+	}
+	if limit <= 0 {
+		return false, nil
+	}
+
+	var spent float64
+	err := s.handle().QueryRow(`
+		SELECT COALESCE(SUM(cost), 0)
+		FROM token_usage
+		WHERE project_id = ? AND provider = ?
+	`, projectID, provider).Scan(&spent)
+	if err != nil {
+		return false, fmt.Errorf("failed to get provider cost: %w", err)
+	}
+	return spent >= limit, nil
+}
+
+// GetCostByTask returns a project's total cost per task. Usage recorded
+// without a task is left out.
+func (s *Store) GetCostByTask(projectID string) (map[string]float64, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT task_id, SUM(cost)
+		FROM token_usage
+		WHERE project_id = ? AND task_id IS NOT NULL AND task_id != ''
+		GROUP BY task_id
+	`
+	rows, err := s.handle().Query(query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost by task: %w", err)
+	}
+	defer rows.Close()
+
+	costs := make(map[string]float64)
+	for rows.Next() {
+		var taskID string
+		var total float64
+		if err := rows.Scan(&taskID, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan task cost: %w", err)
+		}
+		costs[taskID] = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task costs: %w", err)
+	}
+
+	return costs, nil
+}
+
 // GetTokenStats retrieves token statistics for a project
 func (s *Store) GetTokenStats(projectID string) (*TokenStats, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	// Get total tokens
 	query := `
 		SELECT 
@@ -1026,7 +1927,7 @@ func (s *Store) GetTokenStats(projectID string) (*TokenStats, error) {
 		WHERE project_id = ?
 	`
 	var stats TokenStats
-	err := s.db.QueryRow(query, projectID).Scan(&stats.TotalInput, &stats.TotalOutput)
+	err := s.handle().QueryRow(query, projectID).Scan(&stats.TotalInput, &stats.TotalOutput)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token stats: %w", err)
 	}
@@ -1039,7 +1940,7 @@ func (s *Store) GetTokenStats(projectID string) (*TokenStats, error) {
 		WHERE project_id = ?
 		GROUP BY provider
 	`
-	rows, err := s.db.Query(providerQuery, projectID)
+	rows, err := s.handle().Query(providerQuery, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get provider stats: %w", err)
 	}
@@ -1062,7 +1963,7 @@ func (s *Store) GetTokenStats(projectID string) (*TokenStats, error) {
 		WHERE project_id = ? AND phase_id IS NOT NULL
 		GROUP BY phase_id
 	`
-	rows, err = s.db.Query(phaseQuery, projectID)
+	rows, err = s.handle().Query(phaseQuery, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get phase stats: %w", err)
 	}
@@ -1083,6 +1984,10 @@ func (s *Store) GetTokenStats(projectID string) (*TokenStats, error) {
 
 // CacheTokenStats caches token statistics for faster retrieval
 func (s *Store) CacheTokenStats(projectID string, stats *TokenStats) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	byProviderJSON, err := marshalJSON(stats.ByProvider)
 	if err != nil {
 		return fmt.Errorf("failed to marshal provider stats: %w", err)
@@ -1097,7 +2002,7 @@ func (s *Store) CacheTokenStats(projectID string, stats *TokenStats) error {
 		INSERT OR REPLACE INTO token_stats_cache (project_id, total_input, total_output, by_provider, by_phase, last_updated)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	_, err = s.db.Exec(query,
+	_, err = s.handle().Exec(query,
 		projectID,
 		stats.TotalInput,
 		stats.TotalOutput,
@@ -1114,6 +2019,10 @@ func (s *Store) CacheTokenStats(projectID string, stats *TokenStats) error {
 
 // GetCachedTokenStats retrieves cached token statistics
 func (s *Store) GetCachedTokenStats(projectID string) (*TokenStats, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT total_input, total_output, by_provider, by_phase, last_updated
 		FROM token_stats_cache
@@ -1122,7 +2031,7 @@ func (s *Store) GetCachedTokenStats(projectID string) (*TokenStats, error) {
 	var stats TokenStats
 	var byProviderJSON, byPhaseJSON string
 	
-	err := s.db.QueryRow(query, projectID).Scan(
+	err := s.handle().QueryRow(query, projectID).Scan(
 		&stats.TotalInput,
 		&stats.TotalOutput,
 		&byProviderJSON,
@@ -1149,8 +2058,12 @@ func (s *Store) GetCachedTokenStats(projectID string) (*TokenStats, error) {
 
 // InvalidateTokenStatsCache removes cached token statistics
 func (s *Store) InvalidateTokenStatsCache(projectID string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	query := `DELETE FROM token_stats_cache WHERE project_id = ?`
-	_, err := s.db.Exec(query, projectID)
+	_, err := s.handle().Exec(query, projectID)
 	if err != nil {
 		return fmt.Errorf("failed to invalidate token stats cache: %w", err)
 	}
@@ -1161,6 +2074,10 @@ func (s *Store) InvalidateTokenStatsCache(projectID string) error {
 
 // GetCostStats retrieves cost statistics for a project
 func (s *Store) GetCostStats(projectID string) (*CostStats, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	// Get total cost
 	query := `
 		SELECT COALESCE(SUM(cost), 0)
@@ -1168,7 +2085,7 @@ func (s *Store) GetCostStats(projectID string) (*CostStats, error) {
 		WHERE project_id = ?
 	`
 	var stats CostStats
-	err := s.db.QueryRow(query, projectID).Scan(&stats.TotalCost)
+	err := s.handle().QueryRow(query, projectID).Scan(&stats.TotalCost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cost stats: %w", err)
 	}
@@ -1181,7 +2098,7 @@ func (s *Store) GetCostStats(projectID string) (*CostStats, error) {
 		WHERE project_id = ?
 		GROUP BY provider
 	`
-	rows, err := s.db.Query(providerQuery, projectID)
+	rows, err := s.handle().Query(providerQuery, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get provider cost stats: %w", err)
 	}
@@ -1204,7 +2121,7 @@ func (s *Store) GetCostStats(projectID string) (*CostStats, error) {
 		WHERE project_id = ? AND phase_id IS NOT NULL
 		GROUP BY phase_id
 	`
-	rows, err = s.db.Query(phaseQuery, projectID)
+	rows, err = s.handle().Query(phaseQuery, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get phase cost stats: %w", err)
 	}
@@ -1225,14 +2142,18 @@ func (s *Store) GetCostStats(projectID string) (*CostStats, error) {
 
 // GetMostExpensiveCalls retrieves the most expensive API calls
 func (s *Store) GetMostExpensiveCalls(projectID string, limit int) ([]*TokenUsage, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, project_id, phase_id, task_id, provider, model, tokens_input, tokens_output, cost, timestamp
+		SELECT id, project_id, phase_id, task_id, provider, model, tokens_input, tokens_output, cost, timestamp, stage
 		FROM token_usage
 		WHERE project_id = ?
 		ORDER BY cost DESC
 		LIMIT ?
 	`
-	rows, err := s.db.Query(query, projectID, limit)
+	rows, err := s.handle().Query(query, projectID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get most expensive calls: %w", err)
 	}
@@ -1254,6 +2175,7 @@ func (s *Store) GetMostExpensiveCalls(projectID string, limit int) ([]*TokenUsag
 			&usage.TokensOutput,
 			&usage.Cost,
 			&usage.Timestamp,
+			&usage.Stage,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan token usage: %w", err)
@@ -1274,13 +2196,17 @@ func (s *Store) GetMostExpensiveCalls(projectID string, limit int) ([]*TokenUsag
 
 // GetTokenUsageByTimeRange retrieves token usage within a time range
 func (s *Store) GetTokenUsageByTimeRange(projectID string, startTime, endTime time.Time) ([]*TokenUsage, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, project_id, phase_id, task_id, provider, model, tokens_input, tokens_output, cost, timestamp
+		SELECT id, project_id, phase_id, task_id, provider, model, tokens_input, tokens_output, cost, timestamp, stage
 		FROM token_usage
 		WHERE project_id = ? AND timestamp BETWEEN ? AND ?
 		ORDER BY timestamp ASC
 	`
-	rows, err := s.db.Query(query, projectID, startTime, endTime)
+	rows, err := s.handle().Query(query, projectID, startTime, endTime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token usage by time range: %w", err)
 	}
@@ -1302,6 +2228,7 @@ func (s *Store) GetTokenUsageByTimeRange(projectID string, startTime, endTime ti
 			&usage.TokensOutput,
 			&usage.Cost,
 			&usage.Timestamp,
+			&usage.Stage,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan token usage: %w", err)
@@ -1320,15 +2247,107 @@ func (s *Store) GetTokenUsageByTimeRange(projectID string, startTime, endTime ti
 	return usages, nil
 }
 
+// GetBurnRate returns the average tokens and cost spent per hour by a project
+// over the trailing window
+func (s *Store) GetBurnRate(projectID string, window time.Duration) (tokensPerHour float64, costPerHour float64, err error) {
+	if err := s.ensureOpen(); err != nil {
+		return 0, 0, err
+	}
+
+	if window <= 0 {
+		return 0, 0, fmt.Errorf("window must be positive")
+	}
+
+	query := `
+		SELECT COALESCE(SUM(tokens_input + tokens_output), 0), COALESCE(SUM(cost), 0)
+		FROM token_usage
+		WHERE project_id = ? AND timestamp >= ?
+	`
+	var tokens int64
+	var cost float64
+	since := time.Now().Add(-window)
+	if err := s.handle().QueryRow(query, projectID, since).Scan(&tokens, &cost); err != nil {
+		return 0, 0, fmt.Errorf("failed to get burn rate: %w", err)
+	}
+
+	hours := window.Hours()
+	return float64(tokens) / hours, cost / hours, nil
+}
+
+// ExportCostCSV writes one row per token usage record for a project as CSV,
+// with a header row, so the cost report can be opened in a spreadsheet
+func (s *Store) ExportCostCSV(projectID string, w io.Writer) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	query := `
+		SELECT timestamp, provider, model, phase_id, task_id, tokens_input, tokens_output, cost
+		FROM token_usage
+		WHERE project_id = ?
+		ORDER BY timestamp ASC, id ASC
+	`
+	rows, err := s.handle().Query(query, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get token usage: %w", err)
+	}
+	defer rows.Close()
+
+	cw := csv.NewWriter(w)
+	header := []string{"timestamp", "provider", "model", "phase", "task", "tokens_in", "tokens_out", "cost"}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	for rows.Next() {
+		var timestamp time.Time
+		var providerName, model string
+		var phaseID, taskID sql.NullString
+		var tokensIn, tokensOut int
+		var cost float64
+
+		if err := rows.Scan(&timestamp, &providerName, &model, &phaseID, &taskID, &tokensIn, &tokensOut, &cost); err != nil {
+			return fmt.Errorf("failed to scan token usage: %w", err)
+		}
+
+		record := []string{
+			timestamp.Format(time.RFC3339),
+			providerName,
+			model,
+			phaseID.String,
+			taskID.String,
+			strconv.Itoa(tokensIn),
+			strconv.Itoa(tokensOut),
+			strconv.FormatFloat(cost, 'f', 6, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read token usage: %w", err)
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to flush csv: %w", err)
+	}
+	return nil
+}
+
 // Rate limit operations
 
 // SaveRateLimit saves rate limit information
 func (s *Store) SaveRateLimit(provider string, info *RateLimitInfo) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO rate_limits (provider, requests_remaining, requests_limit, reset_at, checked_at)
 		VALUES (?, ?, ?, ?, ?)
 	`
-	_, err := s.db.Exec(query,
+	_, err := s.handle().Exec(query,
 		provider,
 		info.RequestsRemaining,
 		info.RequestsLimit,
@@ -1343,6 +2362,10 @@ func (s *Store) SaveRateLimit(provider string, info *RateLimitInfo) error {
 
 // GetRateLimit retrieves the most recent rate limit information for a provider
 func (s *Store) GetRateLimit(provider string) (*RateLimitInfo, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT provider, requests_remaining, requests_limit, reset_at, checked_at
 		FROM rate_limits
@@ -1351,7 +2374,7 @@ func (s *Store) GetRateLimit(provider string) (*RateLimitInfo, error) {
 		LIMIT 1
 	`
 	var info RateLimitInfo
-	err := s.db.QueryRow(query, provider).Scan(
+	err := s.handle().QueryRow(query, provider).Scan(
 		&info.Provider,
 		&info.RequestsRemaining,
 		&info.RequestsLimit,
@@ -1367,15 +2390,33 @@ func (s *Store) GetRateLimit(provider string) (*RateLimitInfo, error) {
 	return &info, nil
 }
 
+// GetRateLimitFresh is GetRateLimit for callers that must not act on old
+// limits. When the newest record is older than maxAge it is returned along
+// with an error wrapping ErrStale. A maxAge of zero or less disables the check.
+func (s *Store) GetRateLimitFresh(provider string, maxAge time.Duration) (*RateLimitInfo, error) {
+	info, err := s.GetRateLimit(provider)
+	if err != nil {
+		return nil, err
+	}
+	if age := info.Age(); maxAge > 0 && age > maxAge {
+		return info, fmt.Errorf("%w: rate limit for %s was checked %s ago", ErrStale, provider, age.Round(time.Second))
+	}
+	return info, nil
+}
+
 // Quota operations
 
 // SaveQuota saves quota information
 func (s *Store) SaveQuota(provider string, info *QuotaInfo) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO quotas (provider, tokens_remaining, tokens_limit, cost_remaining, cost_limit, reset_at, checked_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := s.db.Exec(query,
+	_, err := s.handle().Exec(query,
 		provider,
 		info.TokensRemaining,
 		info.TokensLimit,
@@ -1392,6 +2433,10 @@ func (s *Store) SaveQuota(provider string, info *QuotaInfo) error {
 
 // GetQuota retrieves the most recent quota information for a provider
 func (s *Store) GetQuota(provider string) (*QuotaInfo, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT provider, tokens_remaining, tokens_limit, cost_remaining, cost_limit, reset_at, checked_at
 		FROM quotas
@@ -1403,7 +2448,7 @@ func (s *Store) GetQuota(provider string) (*QuotaInfo, error) {
 	var tokensRemaining, tokensLimit sql.NullInt64
 	var costRemaining, costLimit sql.NullFloat64
 	
-	err := s.db.QueryRow(query, provider).Scan(
+	err := s.handle().QueryRow(query, provider).Scan(
 		&info.Provider,
 		&tokensRemaining,
 		&tokensLimit,
@@ -1438,24 +2483,86 @@ func (s *Store) GetQuota(provider string) (*QuotaInfo, error) {
 	return &info, nil
 }
 
+// GetQuotaFresh is GetQuota with the staleness check of GetRateLimitFresh
+func (s *Store) GetQuotaFresh(provider string, maxAge time.Duration) (*QuotaInfo, error) {
+	info, err := s.GetQuota(provider)
+	if err != nil {
+		return nil, err
+	}
+	if age := info.Age(); maxAge > 0 && age > maxAge {
+		return info, fmt.Errorf("%w: quota for %s was checked %s ago", ErrStale, provider, age.Round(time.Second))
+	}
+	return info, nil
+}
+
+// PruneRateLimitHistory deletes all but the keepLatest most recently
+// checked rate limit rows of each provider. Only the newest row is ever
+// read, so older ones are history that would otherwise grow forever.
+func (s *Store) PruneRateLimitHistory(keepLatest int) error {
+	return s.pruneProviderHistory("rate_limits", keepLatest)
+}
+
+// PruneQuotaHistory deletes all but the keepLatest most recently checked
+// quota rows of each provider
+func (s *Store) PruneQuotaHistory(keepLatest int) error {
+	return s.pruneProviderHistory("quotas", keepLatest)
+}
+
+// pruneProviderHistory keeps the keepLatest newest rows per provider of a
+// rate_limits or quotas table
+func (s *Store) pruneProviderHistory(table string, keepLatest int) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+	if keepLatest < 1 {
+		return fmt.Errorf("failed to prune %s: must keep at least 1 row per provider, got %d", table, keepLatest)
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (
+					PARTITION BY provider
+					ORDER BY checked_at DESC, id DESC
+				) AS position
+				FROM %[1]s
+			)
+			WHERE position > ?
+		)
+	`, table)
+	if _, err := s.handle().Exec(query, keepLatest); err != nil {
+		return fmt.Errorf("failed to prune %s: %w", table, err)
+	}
+	return nil
+}
+
 // Blocker operations
 
 // SaveBlocker saves a blocker
 func (s *Store) SaveBlocker(blocker *Blocker) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	blocker.UpdatedAt = time.Now()
+
 	query := `
-		INSERT INTO blockers (id, task_id, description, resolution, created_at, resolved_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO blockers (id, task_id, description, resolution, created_at, updated_at, resolved_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			description = excluded.description,
 			resolution = excluded.resolution,
+			updated_at = excluded.updated_at,
 			resolved_at = excluded.resolved_at
 	`
-	_, err := s.db.Exec(query,
+	_, err := s.handle().Exec(query,
 		blocker.ID,
 		blocker.TaskID,
 		blocker.Description,
 		blocker.Resolution,
 		blocker.CreatedAt,
+		blocker.UpdatedAt,
 		blocker.ResolvedAt,
 	)
 	if err != nil {
@@ -1466,13 +2573,17 @@ func (s *Store) SaveBlocker(blocker *Blocker) error {
 
 // ResolveBlocker marks a blocker as resolved
 func (s *Store) ResolveBlocker(id string, resolution string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	now := time.Now()
 	query := `
 		UPDATE blockers
-		SET resolution = ?, resolved_at = ?
+		SET resolution = ?, resolved_at = ?, updated_at = ?
 		WHERE id = ?
 	`
-	result, err := s.db.Exec(query, resolution, now, id)
+	result, err := s.handle().Exec(query, resolution, now, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to resolve blocker: %w", err)
 	}
@@ -1490,15 +2601,19 @@ func (s *Store) ResolveBlocker(id string, resolution string) error {
 
 // ListActiveBlockers retrieves all active (unresolved) blockers for a project
 func (s *Store) ListActiveBlockers(projectID string) ([]*Blocker, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT b.id, b.task_id, b.description, b.resolution, b.created_at, b.resolved_at
+		SELECT b.id, b.task_id, b.description, b.resolution, b.created_at, b.updated_at, b.resolved_at
 		FROM blockers b
 		JOIN tasks t ON b.task_id = t.id
 		JOIN phases p ON t.phase_id = p.id
 		WHERE p.project_id = ? AND b.resolved_at IS NULL
 		ORDER BY b.created_at DESC
 	`
-	rows, err := s.db.Query(query, projectID)
+	rows, err := s.handle().Query(query, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list active blockers: %w", err)
 	}
@@ -1508,6 +2623,7 @@ func (s *Store) ListActiveBlockers(projectID string) ([]*Blocker, error) {
 	for rows.Next() {
 		var blocker Blocker
 		var resolution sql.NullString
+		var updatedAt sql.NullTime
 		
 		err := rows.Scan(
 			&blocker.ID,
@@ -1515,6 +2631,7 @@ func (s *Store) ListActiveBlockers(projectID string) ([]*Blocker, error) {
 			&blocker.Description,
 			&resolution,
 			&blocker.CreatedAt,
+			&updatedAt,
 			&blocker.ResolvedAt,
 		)
 		if err != nil {
@@ -1524,6 +2641,7 @@ func (s *Store) ListActiveBlockers(projectID string) ([]*Blocker, error) {
 		if resolution.Valid {
 			blocker.Resolution = resolution.String
 		}
+		blocker.UpdatedAt = updatedAt.Time
 		
 		blockers = append(blockers, &blocker)
 	}
@@ -1535,10 +2653,95 @@ func (s *Store) ListActiveBlockers(projectID string) ([]*Blocker, error) {
 	return blockers, nil
 }
 
+// GetTaskBlockerCounts counts every blocker ever raised per task in a
+// project, resolved or not
+func (s *Store) GetTaskBlockerCounts(projectID string) (map[string]int, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT b.task_id, COUNT(*)
+		FROM blockers b
+		JOIN tasks t ON b.task_id = t.id
+		JOIN phases p ON t.phase_id = p.id
+		WHERE p.project_id = ?
+		GROUP BY b.task_id
+	`
+	rows, err := s.handle().Query(query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task blocker counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var taskID string
+		var count int
+		if err := rows.Scan(&taskID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan blocker count: %w", err)
+		}
+		counts[taskID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating blocker counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// FlakiestTasks returns the tasks with the most blockers in a project, worst
+// first, up to top entries
+func (s *Store) FlakiestTasks(projectID string, top int) ([]FlakyTask, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	if top <= 0 {
+		return nil, fmt.Errorf("top must be positive")
+	}
+
+	query := `
+		SELECT t.id, t.number, t.description, COUNT(b.id) AS blocker_count
+		FROM blockers b
+		JOIN tasks t ON b.task_id = t.id
+		JOIN phases p ON t.phase_id = p.id
+		WHERE p.project_id = ?
+		GROUP BY t.id, t.number, t.description
+		ORDER BY blocker_count DESC, t.id ASC
+		LIMIT ?
+	`
+	rows, err := s.handle().Query(query, projectID, top)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flakiest tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []FlakyTask
+	for rows.Next() {
+		var task FlakyTask
+		if err := rows.Scan(&task.TaskID, &task.Number, &task.Description, &task.BlockerCount); err != nil {
+			return nil, fmt.Errorf("failed to scan flaky task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating flaky tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
 // Configuration operations
 
 // SetConfig sets a configuration value
 func (s *Store) SetConfig(key string, value string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO config (key, value, updated_at)
 		VALUES (?, ?, ?)
@@ -1546,7 +2749,7 @@ func (s *Store) SetConfig(key string, value string) error {
 			value = excluded.value,
 			updated_at = excluded.updated_at
 	`
-	_, err := s.db.Exec(query, key, value, time.Now())
+	_, err := s.handle().Exec(query, key, value, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to set config: %w", err)
 	}
@@ -1555,13 +2758,17 @@ func (s *Store) SetConfig(key string, value string) error {
 
 // GetConfig retrieves a configuration value
 func (s *Store) GetConfig(key string) (string, error) {
+	if err := s.ensureOpen(); err != nil {
+		return "", err
+	}
+
 	query := `
 		SELECT value
 		FROM config
 		WHERE key = ?
 	`
 	var value string
-	err := s.db.QueryRow(query, key).Scan(&value)
+	err := s.handle().QueryRow(query, key).Scan(&value)
 	if err == sql.ErrNoRows {
 		return "", fmt.Errorf("config key not found: %s", key)
 	}
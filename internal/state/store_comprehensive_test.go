@@ -1,6 +1,7 @@
 package state
 
 import (
+	"context"
 	"path/filepath"
 	"sync"
 	"testing"
@@ -487,6 +488,63 @@ func TestStore_ConcurrentReads(t *testing.T) {
 	wg.Wait()
 }
 
+// TestStore_SharedMemory tests that connections to a shared in-memory store
+// see each other's writes
+func TestStore_SharedMemory(t *testing.T) {
+	store, err := NewStore(SharedMemoryPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	// Pin one connection so the write below has to use another
+	conn, err := store.db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageDevelop,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	var name string
+	if err := conn.QueryRowContext(context.Background(), "SELECT name FROM projects WHERE id = ?", project.ID).Scan(&name); err != nil {
+		t.Fatalf("Pinned connection did not see the project: %v", err)
+	}
+	if name != project.Name {
+		t.Errorf("Expected name %s, got %s", project.Name, name)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			if _, err := store.GetProject(project.ID); err != nil {
+				t.Errorf("Failed to get project in goroutine %d: %v", index, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Each shared in-memory store gets its own database
+	other, err := NewStore(SharedMemoryPath)
+	if err != nil {
+		t.Fatalf("Failed to create second store: %v", err)
+	}
+	defer other.Close()
+	if _, err := other.GetProject(project.ID); err == nil {
+		t.Error("Expected a separate shared in-memory store not to see the project")
+	}
+}
+
 // TestStore_UpdatePhaseStatus_Idempotent tests that updating phase status is idempotent
 func TestStore_UpdatePhaseStatus_Idempotent(t *testing.T) {
 	store, err := NewStore(":memory:")
@@ -1,10 +1,21 @@
 package state
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/mattn/go-sqlite3"
 )
 
 func TestNewStore(t *testing.T) {
@@ -54,6 +65,32 @@ func TestNewStore_CreatesDirectory(t *testing.T) {
 	}
 }
 
+func TestNewStore_DatabaseNewer(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	// Stamp a schema version from the future, as a newer binary would
+	_, err = store.db.Exec(`
+		INSERT INTO schema_migrations (version, description, applied_at)
+		VALUES (?, ?, ?)
+	`, LatestVersion()+1, "Future migration", time.Now())
+	if err != nil {
+		t.Fatalf("Failed to stamp version: %v", err)
+	}
+	store.Close()
+
+	_, err = NewStore(dbPath)
+	if !errors.Is(err, ErrDatabaseNewer) {
+		t.Fatalf("Expected ErrDatabaseNewer, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "upgrade") {
+		t.Errorf("Expected error to advise upgrading, got %q", err.Error())
+	}
+}
+
 func TestStore_HealthCheck(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -90,6 +127,49 @@ func TestStore_Close(t *testing.T) {
 	}
 }
 
+func TestStore_UseAfterClose(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if _, err := store.GetProject("proj-123"); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("Expected ErrStoreClosed from GetProject, got %v", err)
+	}
+	if err := store.HealthCheck(); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("Expected ErrStoreClosed from HealthCheck, got %v", err)
+	}
+}
+
+func TestStore_FailedReopenStaysClosed(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	_, err = store.db.Exec(`
+		INSERT INTO schema_migrations (version, description, applied_at)
+		VALUES (?, ?, ?)
+	`, LatestVersion()+1, "Future migration", time.Now())
+	if err != nil {
+		t.Fatalf("Failed to stamp version: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if err := store.open(); !errors.Is(err, ErrDatabaseNewer) {
+		t.Fatalf("Expected ErrDatabaseNewer, got %v", err)
+	}
+	if _, err := store.GetProject("proj-123"); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("Expected the store to stay closed after a failed reopen, got %v", err)
+	}
+}
+
 func TestStore_InMemory(t *testing.T) {
 	// Test with in-memory database
 	store, err := NewStore(":memory:")
@@ -164,6 +244,75 @@ func TestStore_BeginTx(t *testing.T) {
 	}
 }
 
+func TestStore_WithTransaction(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	insert := func(tx *sql.Tx, key string) error {
+		_, err := tx.Exec("INSERT INTO config (key, value, updated_at) VALUES (?, ?, ?)", key, "value", time.Now())
+		return err
+	}
+	exists := func(key string) bool {
+		_, err := store.GetConfig(key)
+		return err == nil
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		err := store.WithTransaction(func(tx *sql.Tx) error {
+			return insert(tx, "committed")
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !exists("committed") {
+			t.Error("Expected row to be committed")
+		}
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		err := store.WithTransaction(func(tx *sql.Tx) error {
+			if err := insert(tx, "errored"); err != nil {
+				return err
+			}
+			return errBoom
+		})
+		if !errors.Is(err, errBoom) {
+			t.Fatalf("Expected the callback's error, got %v", err)
+		}
+		if exists("errored") {
+			t.Error("Expected row to be rolled back")
+		}
+	})
+
+	t.Run("RollbackOnPanic", func(t *testing.T) {
+		func() {
+			defer func() {
+				if p := recover(); p != "boom" {
+					t.Errorf("Expected panic to propagate, got %v", p)
+				}
+			}()
+			store.WithTransaction(func(tx *sql.Tx) error {
+				if err := insert(tx, "panicked"); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		}()
+		if exists("panicked") {
+			t.Error("Expected row to be rolled back")
+		}
+
+		// The connection isn't left in a transaction
+		if err := store.SetConfig("after-panic", "value"); err != nil {
+			t.Errorf("Expected store to be usable after a panic: %v", err)
+		}
+	})
+}
+
 // Project operations tests
 
 func TestStore_CreateProject(t *testing.T) {
@@ -263,6 +412,46 @@ func TestStore_UpdateProject(t *testing.T) {
 	}
 }
 
+func TestStore_SetProjectPaused(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	if err := store.SetProjectPaused("proj-123", true, "budget limit exceeded"); err != nil {
+		t.Fatalf("Failed to pause project: %v", err)
+	}
+
+	retrieved, err := store.GetProject("proj-123")
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if !retrieved.Paused || retrieved.PauseReason != "budget limit exceeded" {
+		t.Errorf("Expected paused project with reason, got paused=%v reason=%q", retrieved.Paused, retrieved.PauseReason)
+	}
+
+	if err := store.SetProjectPaused("proj-123", false, ""); err != nil {
+		t.Fatalf("Failed to resume project: %v", err)
+	}
+	paused, reason, err := store.IsPaused("proj-123")
+	if err != nil {
+		t.Fatalf("Failed to check pause state: %v", err)
+	}
+	if paused || reason != "" {
+		t.Errorf("Expected resumed project with no reason, got paused=%v reason=%q", paused, reason)
+	}
+
+	if err := store.SetProjectPaused("missing", true, "x"); err == nil {
+		t.Error("Expected error for missing project")
+	}
+}
+
 // Interview data operations tests
 
 func TestStore_SaveAndGetInterviewData(t *testing.T) {
@@ -368,6 +557,65 @@ func TestStore_SaveInterviewData_Update(t *testing.T) {
 	}
 }
 
+func TestStore_FindProjectsByInterviewField(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	interviews := map[string]*InterviewData{
+		"proj-1": {ProblemStatement: "Slow deploys", TargetUsers: []string{"developers", "ops teams"}},
+		"proj-2": {ProblemStatement: "Manual invoicing", TargetUsers: []string{"accountants"}},
+	}
+	for i, id := range []string{"proj-1", "proj-2"} {
+		project := &Project{ID: id, Name: id, CreatedAt: time.Now().Add(time.Duration(i) * time.Second), CurrentStage: StageInterview}
+		if err := store.CreateProject(project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+		data := interviews[id]
+		data.ProjectID = id
+		data.CreatedAt = time.Now()
+		if err := store.SaveInterviewData(id, data); err != nil {
+			t.Fatalf("Failed to save interview data: %v", err)
+		}
+	}
+
+	problem, err := store.GetInterviewField("proj-2", "$.ProblemStatement")
+	if err != nil {
+		t.Fatalf("Failed to extract field: %v", err)
+	}
+	if problem != "Manual invoicing" {
+		t.Errorf("Expected extracted problem statement, got %q", problem)
+	}
+
+	projects, err := store.FindProjectsByInterviewField("$.ProblemStatement", "Slow deploys")
+	if err != nil {
+		t.Fatalf("Failed to find projects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].ID != "proj-1" {
+		t.Errorf("Expected only proj-1 to match, got %v", projects)
+	}
+
+	// Array fields match on any element
+	projects, err = store.FindProjectsByInterviewField("$.TargetUsers", "accountants")
+	if err != nil {
+		t.Fatalf("Failed to find projects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].ID != "proj-2" {
+		t.Errorf("Expected only proj-2 to match, got %v", projects)
+	}
+
+	projects, err = store.FindProjectsByInterviewField("$.ProblemStatement", "Nothing")
+	if err != nil || len(projects) != 0 {
+		t.Errorf("Expected no matches, got %v (err %v)", projects, err)
+	}
+
+	if _, err := store.FindProjectsByInterviewField("ProblemStatement", "x"); err == nil {
+		t.Error("Expected error for a path without $")
+	}
+}
+
 // Architecture operations tests
 
 func TestStore_SaveAndGetArchitecture(t *testing.T) {
@@ -542,6 +790,67 @@ func TestStore_ListPhases(t *testing.T) {
 	}
 }
 
+func TestStore_ReorderPhases(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, id := range []string{"proj-123", "proj-other"} {
+		project := &Project{ID: id, Name: id, CreatedAt: time.Now(), CurrentStage: StagePlan}
+		if err := store.CreateProject(project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+	for i, id := range []string{"phase-a", "phase-b", "phase-c"} {
+		phase := &Phase{ID: id, ProjectID: "proj-123", Number: i + 1, Title: id, Content: "Content", Status: PhaseNotStarted, CreatedAt: time.Now()}
+		if err := store.SavePhase(phase); err != nil {
+			t.Fatalf("Failed to save phase: %v", err)
+		}
+	}
+	other := &Phase{ID: "phase-x", ProjectID: "proj-other", Number: 1, Title: "Other", Content: "Content", Status: PhaseNotStarted, CreatedAt: time.Now()}
+	if err := store.SavePhase(other); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	task := &Task{ID: "task-c1", PhaseID: "phase-c", Number: "3.1", Description: "Task", Status: TaskNotStarted}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	if err := store.ReorderPhases("proj-123", []string{"phase-c", "phase-a", "phase-b"}); err != nil {
+		t.Fatalf("Failed to reorder phases: %v", err)
+	}
+
+	phases, err := store.ListPhases("proj-123")
+	if err != nil {
+		t.Fatalf("Failed to list phases: %v", err)
+	}
+	for i, id := range []string{"phase-c", "phase-a", "phase-b"} {
+		if phases[i].ID != id || phases[i].Number != i+1 {
+			t.Errorf("Position %d: expected %s numbered %d, got %s numbered %d", i, id, i+1, phases[i].ID, phases[i].Number)
+		}
+	}
+	if renumbered, err := store.GetTask("task-c1"); err != nil || renumbered.Number != "1.1" {
+		t.Errorf("Expected task to follow its phase to 1.1, got %+v (err %v)", renumbered, err)
+	}
+
+	invalid := [][]string{
+		{"phase-a", "phase-b"},
+		{"phase-a", "phase-b", "phase-x"},
+		{"phase-a", "phase-a", "phase-b"},
+	}
+	for _, order := range invalid {
+		if err := store.ReorderPhases("proj-123", order); err == nil {
+			t.Errorf("Expected error reordering with %v", order)
+		}
+	}
+	phases, _ = store.ListPhases("proj-123")
+	if phases[0].ID != "phase-c" {
+		t.Error("Expected a rejected reorder to leave the order unchanged")
+	}
+}
+
 func TestStore_UpdatePhaseStatus(t *testing.T) {
 	store, err := NewStore(":memory:")
 	if err != nil {
@@ -615,8 +924,160 @@ func TestStore_UpdatePhaseStatus(t *testing.T) {
 	}
 }
 
+func TestStore_SuccessCriteria(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	phase := &Phase{
+		ID:              "phase-1",
+		ProjectID:       "proj-123",
+		Number:          1,
+		Title:           "Phase 1",
+		Content:         "Content",
+		Status:          PhaseInProgress,
+		CreatedAt:       time.Now(),
+		SuccessCriteria: []string{"Schema created", "Migrations run"},
+		CriteriaChecks:  map[string]string{"Migrations run": "make migrate"},
+	}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+
+	tracked, err := store.ListSuccessCriteria(phase.ID)
+	if err != nil {
+		t.Fatalf("Failed to list criteria: %v", err)
+	}
+	if len(tracked) != 2 || tracked[0].Check != "" || tracked[1].Check != "make migrate" {
+		t.Fatalf("Expected the check saved with its criterion, got %+v", tracked)
+	}
+
+	if err := store.MarkCriterionMet(phase.ID, 0, true); err != nil {
+		t.Fatalf("Failed to mark criterion: %v", err)
+	}
+	if err := store.MarkCriterionMet(phase.ID, 5, true); err == nil {
+		t.Error("Expected error for unknown criterion index")
+	}
+
+	outstanding, err := store.ListOutstandingCriteria(phase.ID)
+	if err != nil {
+		t.Fatalf("Failed to list outstanding criteria: %v", err)
+	}
+	if len(outstanding) != 1 || outstanding[0].Text != "Migrations run" || outstanding[0].Index != 1 {
+		t.Fatalf("Expected only \"Migrations run\" outstanding, got %+v", outstanding)
+	}
+
+	// Completing with an unmet criterion is rejected
+	err = store.UpdatePhaseStatus(phase.ID, PhaseCompleted)
+	if !errors.Is(err, ErrUnmetCriteria) {
+		t.Fatalf("Expected ErrUnmetCriteria, got %v", err)
+	}
+	retrieved, err := store.GetPhase(phase.ID)
+	if err != nil {
+		t.Fatalf("Failed to get phase: %v", err)
+	}
+	if retrieved.Status != PhaseInProgress {
+		t.Errorf("Expected phase to stay in progress, got %s", retrieved.Status)
+	}
+
+	// Re-saving keeps met state for unchanged criteria
+	phase.SuccessCriteria = []string{"Schema created", "Migrations run", "Seed data loaded"}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to re-save phase: %v", err)
+	}
+	criteria, err := store.ListSuccessCriteria(phase.ID)
+	if err != nil {
+		t.Fatalf("Failed to list criteria: %v", err)
+	}
+	if len(criteria) != 3 || !criteria[0].Met || criteria[1].Met || criteria[2].Met {
+		t.Fatalf("Expected first criterion to stay met, got %+v", criteria)
+	}
+
+	// Saving without criteria leaves them untouched
+	phase.SuccessCriteria = nil
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to re-save phase: %v", err)
+	}
+	if criteria, _ := store.ListSuccessCriteria(phase.ID); len(criteria) != 3 {
+		t.Errorf("Expected criteria to be kept, got %d", len(criteria))
+	}
+
+	for i := 1; i < 3; i++ {
+		if err := store.MarkCriterionMet(phase.ID, i, true); err != nil {
+			t.Fatalf("Failed to mark criterion: %v", err)
+		}
+	}
+	if err := store.UpdatePhaseStatus(phase.ID, PhaseCompleted); err != nil {
+		t.Fatalf("Expected completion once all criteria are met: %v", err)
+	}
+}
+
 // Task operations tests
 
+func TestStore_TaskCommits(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	phase := &Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Phase 1", Content: "Content", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	task := &Task{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Add login", Status: TaskInProgress}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	links := []struct{ sha, message string }{
+		{"a1b2c3d", "Add login form"},
+		{"e4f5a6b", "Validate credentials"},
+		{"a1b2c3d", "Add login form"}, // Linking again is ignored
+		{"c7d8e9f", "Add login tests"},
+	}
+	for _, link := range links {
+		if err := store.LinkTaskCommit(task.ID, link.sha, link.message); err != nil {
+			t.Fatalf("Failed to link commit: %v", err)
+		}
+	}
+	if err := store.LinkTaskCommit(task.ID, " ", "empty"); err == nil {
+		t.Error("Expected error for an empty SHA")
+	}
+
+	commits, err := store.GetTaskCommits(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task commits: %v", err)
+	}
+	expected := []string{"a1b2c3d", "e4f5a6b", "c7d8e9f"}
+	if len(commits) != len(expected) {
+		t.Fatalf("Expected %d commits, got %d", len(expected), len(commits))
+	}
+	for i, sha := range expected {
+		if commits[i].SHA != sha {
+			t.Errorf("Commit %d: expected %s, got %s", i, sha, commits[i].SHA)
+		}
+	}
+	if commits[1].Message != "Validate credentials" {
+		t.Errorf("Expected message to be kept, got %q", commits[1].Message)
+	}
+
+	if err := store.LinkTaskCommit("missing-task", "abc1234", "orphan"); err == nil {
+		t.Error("Expected error linking a commit to an unknown task")
+	}
+}
+
 func TestStore_SaveAndGetTask(t *testing.T) {
 	store, err := NewStore(":memory:")
 	if err != nil {
@@ -680,52 +1141,205 @@ func TestStore_SaveAndGetTask(t *testing.T) {
 	}
 }
 
-func TestStore_UpdateTaskStatus(t *testing.T) {
+func TestStore_TaskDependencies(t *testing.T) {
 	store, err := NewStore(":memory:")
 	if err != nil {
 		t.Fatalf("Failed to create store: %v", err)
 	}
 	defer store.Close()
-	
-	// Create project and phase
-	project := &Project{
-		ID:           "proj-123",
-		Name:         "Test Project",
-		CreatedAt:    time.Now(),
-		CurrentStage: StageDevelop,
-	}
-	err = store.CreateProject(project)
-	if err != nil {
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
 		t.Fatalf("Failed to create project: %v", err)
 	}
-	
-	phase := &Phase{
-		ID:        "phase-1",
-		ProjectID: "proj-123",
-		Number:    1,
-		Title:     "Phase 1",
-		Content:   "Content",
-		Status:    PhaseInProgress,
-		CreatedAt: time.Now(),
+	phase := &Phase{ID: "phase-1", ProjectID: "proj-123", Number: 1, Title: "Phase 1", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
 	}
-	err = store.SavePhase(phase)
+
+	for _, task := range []*Task{
+		{ID: "task-1", PhaseID: "phase-1", Number: "1.1", Description: "Schema", Status: TaskNotStarted},
+		{ID: "task-2", PhaseID: "phase-1", Number: "1.2", Description: "Models", Status: TaskNotStarted, DependsOn: []string{"1.1"}},
+	} {
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+
+	retrieved, err := store.GetTask("task-2")
 	if err != nil {
-		t.Fatalf("Failed to save phase: %v", err)
+		t.Fatalf("Failed to get task: %v", err)
 	}
-	
-	// Save task
-	task := &Task{
-		ID:          "task-1",
-		PhaseID:     "phase-1",
-		Number:      "1.1",
-		Description: "Implement feature X",
-		Status:      TaskNotStarted,
+	if len(retrieved.DependsOn) != 1 || retrieved.DependsOn[0] != "1.1" {
+		t.Errorf("Expected dependency on 1.1, got %v", retrieved.DependsOn)
 	}
-	err = store.SaveTask(task)
+
+	tasks, err := store.ListTasks("phase-1")
 	if err != nil {
-		t.Fatalf("Failed to save task: %v", err)
+		t.Fatalf("Failed to list tasks: %v", err)
 	}
-	
+	if len(tasks) != 2 || tasks[0].DependsOn != nil || len(tasks[1].DependsOn) != 1 {
+		t.Errorf("Expected dependencies listed with tasks, got %+v", tasks)
+	}
+
+	// Re-saving without dependencies clears them
+	retrieved.DependsOn = nil
+	if err := store.SaveTask(retrieved); err != nil {
+		t.Fatalf("Failed to re-save task: %v", err)
+	}
+	if again, _ := store.GetTask("task-2"); again.DependsOn != nil {
+		t.Errorf("Expected dependencies cleared, got %v", again.DependsOn)
+	}
+}
+
+func TestStore_TaskEstimates(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	phase := &Phase{ID: "phase-1", ProjectID: "proj-123", Number: 1, Title: "Phase 1", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	task := &Task{ID: "task-1", PhaseID: "phase-1", Number: "1.1", Description: "Schema", Status: TaskNotStarted, Difficulty: "complex", EstimatedTokens: 4200}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	retrieved, err := store.GetTask("task-1")
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if retrieved.Difficulty != "complex" || retrieved.EstimatedTokens != 4200 {
+		t.Errorf("Expected estimates to round-trip, got %q/%d", retrieved.Difficulty, retrieved.EstimatedTokens)
+	}
+
+	tasks, err := store.ListAllTasks("proj-123")
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Difficulty != "complex" || tasks[0].EstimatedTokens != 4200 {
+		t.Errorf("Expected estimates listed with project tasks, got %+v", tasks)
+	}
+}
+
+func TestStore_ListAllTasks(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageDevelop,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// Save the later phase first so ordering can't come from insertion order
+	for _, phase := range []*Phase{
+		{ID: "phase-2", ProjectID: "proj-123", Number: 2, Title: "Phase 2", Status: PhaseNotStarted, CreatedAt: time.Now()},
+		{ID: "phase-1", ProjectID: "proj-123", Number: 1, Title: "Phase 1", Status: PhaseNotStarted, CreatedAt: time.Now()},
+	} {
+		if err := store.SavePhase(phase); err != nil {
+			t.Fatalf("Failed to save phase: %v", err)
+		}
+	}
+
+	for _, task := range []*Task{
+		{ID: "task-2-1", PhaseID: "phase-2", Number: "2.1", Description: "Second phase task", Status: TaskNotStarted},
+		{ID: "task-1-10", PhaseID: "phase-1", Number: "1.10", Description: "Tenth task", Status: TaskNotStarted},
+		{ID: "task-1-2", PhaseID: "phase-1", Number: "1.2", Description: "Second task", Status: TaskNotStarted},
+		{ID: "task-1-1", PhaseID: "phase-1", Number: "1.1", Description: "First task", Status: TaskCompleted},
+	} {
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+
+	tasks, err := store.ListAllTasks(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to list all tasks: %v", err)
+	}
+
+	expected := []struct {
+		id          string
+		phaseNumber int
+	}{
+		{"task-1-1", 1},
+		{"task-1-2", 1},
+		{"task-1-10", 1},
+		{"task-2-1", 2},
+	}
+	if len(tasks) != len(expected) {
+		t.Fatalf("Expected %d tasks, got %d", len(expected), len(tasks))
+	}
+	for i, want := range expected {
+		if tasks[i].ID != want.id {
+			t.Errorf("Task %d: got %s, want %s", i, tasks[i].ID, want.id)
+		}
+		if tasks[i].PhaseNumber != want.phaseNumber {
+			t.Errorf("Task %s: got phase number %d, want %d", tasks[i].ID, tasks[i].PhaseNumber, want.phaseNumber)
+		}
+	}
+}
+
+func TestStore_UpdateTaskStatus(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	
+	// Create project and phase
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageDevelop,
+	}
+	err = store.CreateProject(project)
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	
+	phase := &Phase{
+		ID:        "phase-1",
+		ProjectID: "proj-123",
+		Number:    1,
+		Title:     "Phase 1",
+		Content:   "Content",
+		Status:    PhaseInProgress,
+		CreatedAt: time.Now(),
+	}
+	err = store.SavePhase(phase)
+	if err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	
+	// Save task
+	task := &Task{
+		ID:          "task-1",
+		PhaseID:     "phase-1",
+		Number:      "1.1",
+		Description: "Implement feature X",
+		Status:      TaskNotStarted,
+	}
+	err = store.SaveTask(task)
+	if err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	
 	// Update to in progress
 	err = store.UpdateTaskStatus(task.ID, TaskInProgress)
 	if err != nil {
@@ -765,6 +1379,71 @@ func TestStore_UpdateTaskStatus(t *testing.T) {
 	}
 }
 
+func TestStore_TaskTimestamps(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	phase := &Phase{ID: "phase-1", ProjectID: "proj-123", Number: 1, Title: "Phase 1", Status: PhaseNotStarted, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+
+	task := &Task{ID: "task-1", PhaseID: "phase-1", Number: "1.1", Description: "Test task", Status: TaskNotStarted}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	saved, err := store.GetTask("task-1")
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if saved.CreatedAt.IsZero() || saved.UpdatedAt.IsZero() {
+		t.Fatalf("Expected timestamps to be set, got created=%v updated=%v", saved.CreatedAt, saved.UpdatedAt)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	// Re-save from a fresh struct so created_at can only survive via the store
+	updated := &Task{ID: "task-1", PhaseID: "phase-1", Number: "1.1", Description: "Updated task", Status: TaskNotStarted}
+	if err := store.SaveTask(updated); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	reloaded, err := store.GetTask("task-1")
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if !reloaded.CreatedAt.Equal(saved.CreatedAt) {
+		t.Errorf("Expected created_at %v to be preserved, got %v", saved.CreatedAt, reloaded.CreatedAt)
+	}
+	if !updated.CreatedAt.Equal(saved.CreatedAt) {
+		t.Errorf("Expected the re-saved task to carry the stored created_at %v, got %v", saved.CreatedAt, updated.CreatedAt)
+	}
+	if !reloaded.UpdatedAt.After(saved.UpdatedAt) {
+		t.Errorf("Expected updated_at to advance past %v, got %v", saved.UpdatedAt, reloaded.UpdatedAt)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if err := store.UpdateTaskStatus("task-1", TaskInProgress); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+	started, err := store.GetTask("task-1")
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if !started.UpdatedAt.After(reloaded.UpdatedAt) {
+		t.Errorf("Expected status change to advance updated_at, got %v", started.UpdatedAt)
+	}
+}
+
 // Additional CRUD tests for comprehensive coverage
 
 // Checkpoint operations tests
@@ -875,6 +1554,73 @@ func TestStore_ListCheckpoints(t *testing.T) {
 	}
 }
 
+func TestStore_CheckpointSnapshot(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageDevelop,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	phase := &Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Setup", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	statuses := []TaskStatus{TaskCompleted, TaskCompleted, TaskInProgress, TaskNotStarted, TaskNotStarted}
+	for i, status := range statuses {
+		task := &Task{ID: fmt.Sprintf("task-%d", i), PhaseID: phase.ID, Number: fmt.Sprintf("1.%d", i), Description: "Task", Status: status}
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+	if err := store.RecordTokenUsage(&TokenUsage{ProjectID: project.ID, Provider: "openai", Model: "gpt-4", Cost: 4.10, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to record token usage: %v", err)
+	}
+
+	checkpoint := &Checkpoint{ID: "cp-1", ProjectID: project.ID, Name: "v0.2.0", GitTag: "v0.2.0", CreatedAt: time.Now()}
+	if err := store.SaveCheckpoint(checkpoint); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+
+	// Later spend must not change the snapshot, even when the checkpoint is re-saved
+	if err := store.RecordTokenUsage(&TokenUsage{ProjectID: project.ID, Provider: "openai", Model: "gpt-4", Cost: 1.00, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to record token usage: %v", err)
+	}
+	if err := store.SaveCheckpoint(checkpoint); err != nil {
+		t.Fatalf("Failed to re-save checkpoint: %v", err)
+	}
+
+	// Reopen to make sure the snapshot was persisted
+	store.Close()
+	store, err = NewStore(store.dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer store.Close()
+
+	checkpoints, err := store.ListCheckpoints(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to list checkpoints: %v", err)
+	}
+	if len(checkpoints) != 1 {
+		t.Fatalf("Expected 1 checkpoint, got %d", len(checkpoints))
+	}
+	if got := checkpoints[0].TotalCost; got < 4.099 || got > 4.101 {
+		t.Errorf("Expected snapshot cost 4.10, got %f", got)
+	}
+	if got := checkpoints[0].CompletionPercentage; got != 40 {
+		t.Errorf("Expected snapshot completion 40%%, got %f", got)
+	}
+}
+
 // Token usage operations tests
 
 func TestStore_RecordTokenUsage(t *testing.T) {
@@ -979,73 +1725,323 @@ func TestStore_GetTotalCost(t *testing.T) {
 	}
 }
 
-// Rate limit operations tests
-
-func TestStore_SaveAndGetRateLimit(t *testing.T) {
+func TestStore_GetBurnRate(t *testing.T) {
 	store, err := NewStore(":memory:")
 	if err != nil {
 		t.Fatalf("Failed to create store: %v", err)
 	}
 	defer store.Close()
-	
-	// Save rate limit
-	rateLimit := &RateLimitInfo{
-		Provider:          "openai",
-		RequestsRemaining: 100,
-		RequestsLimit:     200,
-		ResetAt:           time.Now().Add(1 * time.Hour),
-		CheckedAt:         time.Now(),
+
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageDevelop,
 	}
-	
-	err = store.SaveRateLimit(rateLimit.Provider, rateLimit)
-	if err != nil {
-		t.Fatalf("Failed to save rate limit: %v", err)
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
 	}
-	
-	// Retrieve rate limit
-	retrieved, err := store.GetRateLimit(rateLimit.Provider)
+
+	now := time.Now()
+	usages := []*TokenUsage{
+		{ProjectID: "proj-123", Provider: "openai", Model: "gpt-4", TokensInput: 1000, TokensOutput: 1000, Cost: 1.00, Timestamp: now.Add(-30 * time.Minute)},
+		{ProjectID: "proj-123", Provider: "openai", Model: "gpt-4", TokensInput: 500, TokensOutput: 500, Cost: 0.50, Timestamp: now.Add(-90 * time.Minute)},
+		// Outside the window, must be ignored
+		{ProjectID: "proj-123", Provider: "openai", Model: "gpt-4", TokensInput: 9000, TokensOutput: 9000, Cost: 9.00, Timestamp: now.Add(-5 * time.Hour)},
+	}
+	for _, usage := range usages {
+		if err := store.RecordTokenUsage(usage); err != nil {
+			t.Fatalf("Failed to record token usage: %v", err)
+		}
+	}
+
+	tokensPerHour, costPerHour, err := store.GetBurnRate(project.ID, 2*time.Hour)
 	if err != nil {
-		t.Fatalf("Failed to get rate limit: %v", err)
+		t.Fatalf("Failed to get burn rate: %v", err)
 	}
-	
-	if retrieved.Provider != rateLimit.Provider {
-		t.Errorf("Provider mismatch: got %s, want %s", retrieved.Provider, rateLimit.Provider)
+	if tokensPerHour != 1500 {
+		t.Errorf("Expected 1500 tokens/hour, got %f", tokensPerHour)
 	}
-	if retrieved.RequestsRemaining != rateLimit.RequestsRemaining {
-		t.Errorf("RequestsRemaining mismatch: got %d, want %d", retrieved.RequestsRemaining, rateLimit.RequestsRemaining)
+	if costPerHour != 0.75 {
+		t.Errorf("Expected $0.75/hour, got %f", costPerHour)
 	}
-}
 
-// Quota operations tests
+	if _, _, err := store.GetBurnRate(project.ID, 0); err == nil {
+		t.Error("Expected error for non-positive window")
+	}
+}
 
-func TestStore_SaveAndGetQuota(t *testing.T) {
+func TestStore_ExportCostCSV(t *testing.T) {
 	store, err := NewStore(":memory:")
 	if err != nil {
 		t.Fatalf("Failed to create store: %v", err)
 	}
 	defer store.Close()
-	
-	// Save quota
-	tokensRemaining := 10000
-	tokensLimit := 20000
-	costRemaining := 50.0
-	costLimit := 100.0
-	
-	quota := &QuotaInfo{
-		Provider:        "openai",
-		TokensRemaining: &tokensRemaining,
-		TokensLimit:     &tokensLimit,
-		CostRemaining:   &costRemaining,
-		CostLimit:       &costLimit,
-		ResetAt:         time.Now().Add(24 * time.Hour),
-		CheckedAt:       time.Now(),
+
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageDevelop,
 	}
-	
-	err = store.SaveQuota(quota.Provider, quota)
-	if err != nil {
-		t.Fatalf("Failed to save quota: %v", err)
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
 	}
-	
+
+	now := time.Now()
+	usages := []*TokenUsage{
+		{ProjectID: "proj-123", Provider: "openai", Model: "gpt-4", TokensInput: 1000, TokensOutput: 500, Cost: 0.25, Timestamp: now.Add(-time.Hour)},
+		{ProjectID: "proj-123", Provider: "anthropic", Model: "claude, latest", TokensInput: 200, TokensOutput: 100, Cost: 0.05, Timestamp: now},
+	}
+	for _, usage := range usages {
+		if err := store.RecordTokenUsage(usage); err != nil {
+			t.Fatalf("Failed to record token usage: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := store.ExportCostCSV(project.ID, &buf); err != nil {
+		t.Fatalf("Failed to export cost CSV: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse exported CSV: %v", err)
+	}
+
+	expectedHeader := "timestamp,provider,model,phase,task,tokens_in,tokens_out,cost"
+	if got := strings.Join(records[0], ","); got != expectedHeader {
+		t.Errorf("Expected header %q, got %q", expectedHeader, got)
+	}
+	if len(records)-1 != len(usages) {
+		t.Fatalf("Expected %d rows, got %d", len(usages), len(records)-1)
+	}
+	if records[1][1] != "openai" || records[1][5] != "1000" || records[1][7] != "0.250000" {
+		t.Errorf("Unexpected first row: %v", records[1])
+	}
+	if records[2][2] != "claude, latest" {
+		t.Errorf("Expected model with comma to round-trip, got %q", records[2][2])
+	}
+}
+
+func TestStore_GetCostByStage(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageDevelop,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	now := time.Now()
+	usages := []*TokenUsage{
+		{ProjectID: "proj-123", Provider: "openai", Model: "gpt-4", Cost: 0.10, Timestamp: now, Stage: string(StageInterview)},
+		{ProjectID: "proj-123", Provider: "openai", Model: "gpt-4", Cost: 0.15, Timestamp: now, Stage: string(StageInterview)},
+		{ProjectID: "proj-123", Provider: "anthropic", Model: "claude", Cost: 1.00, Timestamp: now, Stage: string(StageDevelop)},
+		{ProjectID: "proj-123", Provider: "anthropic", Model: "claude", Cost: 0.50, Timestamp: now},
+	}
+	for _, usage := range usages {
+		if err := store.RecordTokenUsage(usage); err != nil {
+			t.Fatalf("Failed to record token usage: %v", err)
+		}
+	}
+
+	costs, err := store.GetCostByStage(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get cost by stage: %v", err)
+	}
+	if got := costs[string(StageInterview)]; got < 0.2499 || got > 0.2501 {
+		t.Errorf("Expected interview cost 0.25, got %f", got)
+	}
+	if got := costs[string(StageDevelop)]; got != 1.00 {
+		t.Errorf("Expected develop cost 1.00, got %f", got)
+	}
+	if got := costs[""]; got != 0.50 {
+		t.Errorf("Expected unattributed cost 0.50, got %f", got)
+	}
+
+	calls, err := store.GetMostExpensiveCalls(project.ID, 1)
+	if err != nil {
+		t.Fatalf("Failed to get most expensive calls: %v", err)
+	}
+	if len(calls) != 1 || calls[0].Stage != string(StageDevelop) {
+		t.Errorf("Expected stage to be read back, got %+v", calls)
+	}
+}
+
+func TestStore_CheckProviderBudget(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	record := func(provider string, cost float64) {
+		t.Helper()
+		usage := &TokenUsage{ProjectID: project.ID, Provider: provider, Model: "model", Cost: cost, Timestamp: time.Now()}
+		if err := store.RecordTokenUsage(usage); err != nil {
+			t.Fatalf("Failed to record usage: %v", err)
+		}
+	}
+	check := func(provider string, limit float64) bool {
+		t.Helper()
+		exceeded, err := store.CheckProviderBudget(project.ID, provider, limit)
+		if err != nil {
+			t.Fatalf("Failed to check provider budget: %v", err)
+		}
+		return exceeded
+	}
+
+	record("anthropic", 15)
+	record("openai", 30)
+	if check("anthropic", 20) {
+		t.Error("Expected anthropic to be under its cap")
+	}
+
+	// Only the capped provider's usage counts
+	record("anthropic", 6)
+	if !check("anthropic", 20) {
+		t.Error("Expected anthropic to be over its cap")
+	}
+	if check("openai", 50) {
+		t.Error("Expected openai to be under its cap")
+	}
+	if check("anthropic", 0) {
+		t.Error("Expected no cap with a zero limit")
+	}
+}
+
+// Rate limit operations tests
+
+func TestStore_SaveAndGetRateLimit(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	
+	// Save rate limit
+	rateLimit := &RateLimitInfo{
+		Provider:          "openai",
+		RequestsRemaining: 100,
+		RequestsLimit:     200,
+		ResetAt:           time.Now().Add(1 * time.Hour),
+		CheckedAt:         time.Now(),
+	}
+	
+	err = store.SaveRateLimit(rateLimit.Provider, rateLimit)
+	if err != nil {
+		t.Fatalf("Failed to save rate limit: %v", err)
+	}
+	
+	// Retrieve rate limit
+	retrieved, err := store.GetRateLimit(rateLimit.Provider)
+	if err != nil {
+		t.Fatalf("Failed to get rate limit: %v", err)
+	}
+	
+	if retrieved.Provider != rateLimit.Provider {
+		t.Errorf("Provider mismatch: got %s, want %s", retrieved.Provider, rateLimit.Provider)
+	}
+	if retrieved.RequestsRemaining != rateLimit.RequestsRemaining {
+		t.Errorf("RequestsRemaining mismatch: got %d, want %d", retrieved.RequestsRemaining, rateLimit.RequestsRemaining)
+	}
+}
+
+func TestStore_GetRateLimitFresh(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	stale := &RateLimitInfo{
+		Provider:          "openai",
+		RequestsRemaining: 5,
+		RequestsLimit:     200,
+		ResetAt:           time.Now().Add(-2 * time.Hour),
+		CheckedAt:         time.Now().Add(-3 * time.Hour),
+	}
+	if err := store.SaveRateLimit(stale.Provider, stale); err != nil {
+		t.Fatalf("Failed to save rate limit: %v", err)
+	}
+
+	info, err := store.GetRateLimitFresh("openai", time.Hour)
+	if !errors.Is(err, ErrStale) {
+		t.Fatalf("Expected ErrStale, got %v", err)
+	}
+	if info == nil || info.Age() < 3*time.Hour {
+		t.Errorf("Expected the stale record to be returned with its age, got %+v", info)
+	}
+	if _, err := store.GetRateLimitFresh("openai", 0); err != nil {
+		t.Errorf("Expected no staleness check with zero max age, got %v", err)
+	}
+
+	fresh := *stale
+	fresh.RequestsRemaining = 150
+	fresh.CheckedAt = time.Now()
+	if err := store.SaveRateLimit(fresh.Provider, &fresh); err != nil {
+		t.Fatalf("Failed to save rate limit: %v", err)
+	}
+	info, err = store.GetRateLimitFresh("openai", time.Hour)
+	if err != nil {
+		t.Fatalf("Expected fresh rate limit, got %v", err)
+	}
+	if info.RequestsRemaining != 150 {
+		t.Errorf("Expected newest record, got %d remaining", info.RequestsRemaining)
+	}
+
+	if err := store.SaveQuota("openai", &QuotaInfo{CheckedAt: time.Now().Add(-2 * time.Hour)}); err != nil {
+		t.Fatalf("Failed to save quota: %v", err)
+	}
+	if _, err := store.GetQuotaFresh("openai", time.Hour); !errors.Is(err, ErrStale) {
+		t.Errorf("Expected stale quota, got %v", err)
+	}
+}
+
+// Quota operations tests
+
+func TestStore_SaveAndGetQuota(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	
+	// Save quota
+	tokensRemaining := 10000
+	tokensLimit := 20000
+	costRemaining := 50.0
+	costLimit := 100.0
+	
+	quota := &QuotaInfo{
+		Provider:        "openai",
+		TokensRemaining: &tokensRemaining,
+		TokensLimit:     &tokensLimit,
+		CostRemaining:   &costRemaining,
+		CostLimit:       &costLimit,
+		ResetAt:         time.Now().Add(24 * time.Hour),
+		CheckedAt:       time.Now(),
+	}
+	
+	err = store.SaveQuota(quota.Provider, quota)
+	if err != nil {
+		t.Fatalf("Failed to save quota: %v", err)
+	}
+	
 	// Retrieve quota
 	retrieved, err := store.GetQuota(quota.Provider)
 	if err != nil {
@@ -1060,6 +2056,68 @@ func TestStore_SaveAndGetQuota(t *testing.T) {
 	}
 }
 
+func TestStore_PruneProviderHistory(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 10; i++ {
+		checkedAt := base.Add(time.Duration(i) * time.Minute)
+		for _, provider := range []string{"openai", "anthropic"} {
+			rateLimit := &RateLimitInfo{RequestsRemaining: i, RequestsLimit: 100, ResetAt: checkedAt.Add(time.Hour), CheckedAt: checkedAt}
+			if err := store.SaveRateLimit(provider, rateLimit); err != nil {
+				t.Fatalf("Failed to save rate limit: %v", err)
+			}
+			remaining := i
+			quota := &QuotaInfo{TokensRemaining: &remaining, ResetAt: checkedAt.Add(time.Hour), CheckedAt: checkedAt}
+			if err := store.SaveQuota(provider, quota); err != nil {
+				t.Fatalf("Failed to save quota: %v", err)
+			}
+		}
+	}
+
+	if err := store.PruneRateLimitHistory(2); err != nil {
+		t.Fatalf("Failed to prune rate limits: %v", err)
+	}
+	if err := store.PruneQuotaHistory(2); err != nil {
+		t.Fatalf("Failed to prune quotas: %v", err)
+	}
+
+	for _, table := range []string{"rate_limits", "quotas"} {
+		for _, provider := range []string{"openai", "anthropic"} {
+			var count int
+			if err := store.DB().QueryRow("SELECT COUNT(*) FROM "+table+" WHERE provider = ?", provider).Scan(&count); err != nil {
+				t.Fatalf("Failed to count %s: %v", table, err)
+			}
+			if count != 2 {
+				t.Errorf("Expected 2 %s rows for %s, got %d", table, provider, count)
+			}
+		}
+	}
+
+	rateLimit, err := store.GetRateLimit("openai")
+	if err != nil {
+		t.Fatalf("Failed to get rate limit: %v", err)
+	}
+	if rateLimit.RequestsRemaining != 9 {
+		t.Errorf("Expected newest rate limit to remain, got %d requests remaining", rateLimit.RequestsRemaining)
+	}
+	quota, err := store.GetQuota("anthropic")
+	if err != nil {
+		t.Fatalf("Failed to get quota: %v", err)
+	}
+	if quota.TokensRemaining == nil || *quota.TokensRemaining != 9 {
+		t.Errorf("Expected newest quota to remain, got %v", quota.TokensRemaining)
+	}
+
+	if err := store.PruneRateLimitHistory(0); err == nil {
+		t.Error("Expected error when keeping no rows")
+	}
+}
+
 // Blocker operations tests
 
 func TestStore_SaveAndGetBlocker(t *testing.T) {
@@ -1210,52 +2268,319 @@ func TestStore_ResolveBlocker(t *testing.T) {
 	}
 }
 
-// Configuration operations tests
-
-func TestStore_SetAndGetConfig(t *testing.T) {
+func TestStore_GetTaskBlockerCounts(t *testing.T) {
 	store, err := NewStore(":memory:")
 	if err != nil {
 		t.Fatalf("Failed to create store: %v", err)
 	}
 	defer store.Close()
-	
-	// Set config
-	key := "default_model"
-	value := "gpt-4"
-	
-	err = store.SetConfig(key, value)
-	if err != nil {
-		t.Fatalf("Failed to set config: %v", err)
+
+	project := &Project{
+		ID:           "proj-123",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: StageDevelop,
 	}
-	
-	// Get config
-	retrieved, err := store.GetConfig(key)
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	phase := &Phase{
+		ID:        "phase-1",
+		ProjectID: "proj-123",
+		Number:    1,
+		Title:     "Phase 1",
+		Status:    PhaseInProgress,
+		CreatedAt: time.Now(),
+	}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+
+	for _, task := range []*Task{
+		{ID: "task-1", PhaseID: "phase-1", Number: "1.1", Description: "Stable task", Status: TaskCompleted},
+		{ID: "task-2", PhaseID: "phase-1", Number: "1.2", Description: "Flaky task", Status: TaskBlocked},
+		{ID: "task-3", PhaseID: "phase-1", Number: "1.3", Description: "Occasionally blocked", Status: TaskInProgress},
+	} {
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+
+	// task-2 has three blockers (two resolved), task-3 has one, task-1 none
+	blockers := []struct {
+		id, taskID string
+		resolved   bool
+	}{
+		{"blocker-1", "task-2", true},
+		{"blocker-2", "task-2", true},
+		{"blocker-3", "task-2", false},
+		{"blocker-4", "task-3", true},
+	}
+	for _, b := range blockers {
+		blocker := &Blocker{ID: b.id, TaskID: b.taskID, Description: "Failed", CreatedAt: time.Now()}
+		if err := store.SaveBlocker(blocker); err != nil {
+			t.Fatalf("Failed to save blocker: %v", err)
+		}
+		if b.resolved {
+			if err := store.ResolveBlocker(b.id, "Fixed"); err != nil {
+				t.Fatalf("Failed to resolve blocker: %v", err)
+			}
+		}
+	}
+
+	counts, err := store.GetTaskBlockerCounts(project.ID)
 	if err != nil {
-		t.Fatalf("Failed to get config: %v", err)
+		t.Fatalf("Failed to get blocker counts: %v", err)
 	}
-	
-	if retrieved != value {
-		t.Errorf("Config value mismatch: got %s, want %s", retrieved, value)
+	if counts["task-2"] != 3 {
+		t.Errorf("Expected 3 blockers for task-2, got %d", counts["task-2"])
+	}
+	if counts["task-3"] != 1 {
+		t.Errorf("Expected 1 blocker for task-3, got %d", counts["task-3"])
+	}
+	if _, ok := counts["task-1"]; ok {
+		t.Error("Expected no entry for a task without blockers")
 	}
-}
 
-func TestStore_GetConfig_NotFound(t *testing.T) {
-	store, err := NewStore(":memory:")
+	flakiest, err := store.FlakiestTasks(project.ID, 1)
 	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
+		t.Fatalf("Failed to get flakiest tasks: %v", err)
 	}
-	defer store.Close()
-	
-	_, err = store.GetConfig("nonexistent_key")
-	if err == nil {
-		t.Error("Expected error for nonexistent config key, got nil")
+	if len(flakiest) != 1 {
+		t.Fatalf("Expected 1 flaky task, got %d", len(flakiest))
+	}
+	if flakiest[0].TaskID != "task-2" || flakiest[0].Description != "Flaky task" || flakiest[0].BlockerCount != 3 {
+		t.Errorf("Unexpected flakiest task: %+v", flakiest[0])
 	}
 }
 
-// Error handling tests
+// countingConnector opens SQLite connections that count the statements
+// prepared on them. The connections don't implement the driver's direct
+// query interfaces, so database/sql prepares every query it runs.
+type countingConnector struct {
+	dsn     string
+	queries *atomic.Int64
+}
 
-func TestStore_UpdateProject_NotFound(t *testing.T) {
-	store, err := NewStore(":memory:")
+func (c *countingConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.Driver().Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn, queries: c.queries}, nil
+}
+
+func (c *countingConnector) Driver() driver.Driver {
+	return &sqlite3.SQLiteDriver{}
+}
+
+type countingConn struct {
+	driver.Conn
+	queries *atomic.Int64
+}
+
+func (c *countingConn) Prepare(query string) (driver.Stmt, error) {
+	c.queries.Add(1)
+	return c.Conn.Prepare(query)
+}
+
+// countQueries points store at a counting connection to the same shared
+// database until the test ends, and returns the count
+func countQueries(t *testing.T, store *Store) *atomic.Int64 {
+	t.Helper()
+	queries := &atomic.Int64{}
+	original := store.db
+	counted := sql.OpenDB(&countingConnector{dsn: store.dsn, queries: queries})
+	store.db = counted
+	t.Cleanup(func() {
+		store.db = original
+		counted.Close()
+	})
+	return queries
+}
+
+func TestStore_GetDashboard(t *testing.T) {
+	store, err := NewStore(SharedMemoryPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	base := time.Now().Add(-time.Hour)
+	projects := []*Project{
+		{ID: "proj-new", Name: "New", CreatedAt: base, CurrentStage: StageInterview},
+		{ID: "proj-dev", Name: "Developing", CreatedAt: base.Add(time.Minute), CurrentStage: StageDevelop},
+		{ID: "proj-done", Name: "Done", CreatedAt: base.Add(2 * time.Minute), CurrentStage: StageComplete},
+		{ID: "proj-old", Name: "Archived", CreatedAt: base.Add(3 * time.Minute), CurrentStage: StageDevelop},
+	}
+	for _, project := range projects {
+		if err := store.CreateProject(project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+	if err := store.SetProjectPaused("proj-dev", true, "waiting"); err != nil {
+		t.Fatalf("Failed to pause project: %v", err)
+	}
+
+	for _, phase := range []*Phase{
+		{ID: "dev-phase", ProjectID: "proj-dev", Number: 1, Title: "Phase 1", Status: PhaseInProgress, CreatedAt: time.Now()},
+		{ID: "done-phase", ProjectID: "proj-done", Number: 1, Title: "Phase 1", Status: PhaseCompleted, CreatedAt: time.Now()},
+		{ID: "old-phase", ProjectID: "proj-old", Number: 1, Title: "Phase 1", Status: PhaseInProgress, CreatedAt: time.Now()},
+	} {
+		if err := store.SavePhase(phase); err != nil {
+			t.Fatalf("Failed to save phase: %v", err)
+		}
+	}
+	for _, task := range []*Task{
+		{ID: "dev-1", PhaseID: "dev-phase", Number: "1.1", Description: "Done", Status: TaskCompleted},
+		{ID: "dev-2", PhaseID: "dev-phase", Number: "1.2", Description: "Blocked", Status: TaskBlocked},
+		{ID: "dev-3", PhaseID: "dev-phase", Number: "1.3", Description: "Blocked too", Status: TaskBlocked},
+		{ID: "dev-4", PhaseID: "dev-phase", Number: "1.4", Description: "Pending", Status: TaskNotStarted},
+		{ID: "done-1", PhaseID: "done-phase", Number: "1.1", Description: "Done", Status: TaskCompleted},
+		{ID: "done-2", PhaseID: "done-phase", Number: "1.2", Description: "Done", Status: TaskCompleted},
+		{ID: "old-1", PhaseID: "old-phase", Number: "1.1", Description: "Blocked", Status: TaskBlocked},
+	} {
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+
+	// Two active blockers and one resolved in proj-dev, one in the archived project
+	for _, blocker := range []*Blocker{
+		{ID: "blocker-1", TaskID: "dev-2", Description: "Failed", CreatedAt: time.Now()},
+		{ID: "blocker-2", TaskID: "dev-3", Description: "Failed", CreatedAt: time.Now()},
+		{ID: "blocker-3", TaskID: "dev-3", Description: "Failed", CreatedAt: time.Now()},
+		{ID: "blocker-4", TaskID: "old-1", Description: "Failed", CreatedAt: time.Now()},
+	} {
+		if err := store.SaveBlocker(blocker); err != nil {
+			t.Fatalf("Failed to save blocker: %v", err)
+		}
+	}
+	if err := store.ResolveBlocker("blocker-3", "Fixed"); err != nil {
+		t.Fatalf("Failed to resolve blocker: %v", err)
+	}
+
+	for _, usage := range []*TokenUsage{
+		{ProjectID: "proj-new", Provider: "openai", Model: "gpt-4", Cost: 0.25, Timestamp: time.Now()},
+		{ProjectID: "proj-dev", Provider: "openai", Model: "gpt-4", Cost: 1.00, Timestamp: time.Now()},
+		{ProjectID: "proj-dev", Provider: "openai", Model: "gpt-4", Cost: 0.50, Timestamp: time.Now()},
+		{ProjectID: "proj-old", Provider: "openai", Model: "gpt-4", Cost: 9.00, Timestamp: time.Now()},
+	} {
+		if err := store.RecordTokenUsage(usage); err != nil {
+			t.Fatalf("Failed to record token usage: %v", err)
+		}
+	}
+
+	if err := store.SetProjectArchived("proj-old", true); err != nil {
+		t.Fatalf("Failed to archive project: %v", err)
+	}
+	archived, err := store.GetProject("proj-old")
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if archived.ArchivedAt == nil {
+		t.Error("Expected archived project to have ArchivedAt set")
+	}
+
+	queries := countQueries(t, store)
+	dashboard, err := store.GetDashboard()
+	if err != nil {
+		t.Fatalf("Failed to get dashboard: %v", err)
+	}
+	if n := queries.Load(); n != 1 {
+		t.Errorf("Expected the dashboard to take a single query, got %d", n)
+	}
+	if len(dashboard) != 3 {
+		t.Fatalf("Expected 3 unarchived projects, got %d", len(dashboard))
+	}
+
+	expected := []ProjectSummary{
+		{ProjectID: "proj-new", CurrentStage: StageInterview, TotalCost: 0.25},
+		{ProjectID: "proj-dev", CurrentStage: StageDevelop, Paused: true, TotalTasks: 4, CompletedTasks: 1, CompletionPercentage: 25, TotalCost: 1.50, ActiveBlockers: 2},
+		{ProjectID: "proj-done", CurrentStage: StageComplete, TotalTasks: 2, CompletedTasks: 2, CompletionPercentage: 100},
+	}
+	for i, want := range expected {
+		got := dashboard[i]
+		if got.ProjectID != want.ProjectID || got.CurrentStage != want.CurrentStage || got.Paused != want.Paused {
+			t.Errorf("Summary %d: expected %s in %s (paused %v), got %s in %s (paused %v)",
+				i, want.ProjectID, want.CurrentStage, want.Paused, got.ProjectID, got.CurrentStage, got.Paused)
+		}
+		if got.TotalTasks != want.TotalTasks || got.CompletedTasks != want.CompletedTasks || got.CompletionPercentage != want.CompletionPercentage {
+			t.Errorf("%s: expected %d/%d tasks (%.0f%%), got %d/%d (%.0f%%)", want.ProjectID,
+				want.CompletedTasks, want.TotalTasks, want.CompletionPercentage, got.CompletedTasks, got.TotalTasks, got.CompletionPercentage)
+		}
+		if got.TotalCost < want.TotalCost-0.0001 || got.TotalCost > want.TotalCost+0.0001 {
+			t.Errorf("%s: expected cost %.2f, got %.2f", want.ProjectID, want.TotalCost, got.TotalCost)
+		}
+		if got.ActiveBlockers != want.ActiveBlockers {
+			t.Errorf("%s: expected %d active blockers, got %d", want.ProjectID, want.ActiveBlockers, got.ActiveBlockers)
+		}
+	}
+
+	// Unarchiving brings the project back
+	if err := store.SetProjectArchived("proj-old", false); err != nil {
+		t.Fatalf("Failed to unarchive project: %v", err)
+	}
+	dashboard, err = store.GetDashboard()
+	if err != nil {
+		t.Fatalf("Failed to get dashboard: %v", err)
+	}
+	if len(dashboard) != 4 || dashboard[3].ActiveBlockers != 1 {
+		t.Errorf("Expected unarchived project back with 1 active blocker, got %+v", dashboard)
+	}
+
+	if err := store.SetProjectArchived("missing", true); err == nil {
+		t.Error("Expected error archiving a missing project")
+	}
+}
+
+// Configuration operations tests
+
+func TestStore_SetAndGetConfig(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	
+	// Set config
+	key := "default_model"
+	value := "gpt-4"
+	
+	err = store.SetConfig(key, value)
+	if err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	
+	// Get config
+	retrieved, err := store.GetConfig(key)
+	if err != nil {
+		t.Fatalf("Failed to get config: %v", err)
+	}
+	
+	if retrieved != value {
+		t.Errorf("Config value mismatch: got %s, want %s", retrieved, value)
+	}
+}
+
+func TestStore_GetConfig_NotFound(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	
+	_, err = store.GetConfig("nonexistent_key")
+	if err == nil {
+		t.Error("Expected error for nonexistent config key, got nil")
+	}
+}
+
+// Error handling tests
+
+func TestStore_UpdateProject_NotFound(t *testing.T) {
+	store, err := NewStore(":memory:")
 	if err != nil {
 		t.Fatalf("Failed to create store: %v", err)
 	}
@@ -1643,3 +2968,603 @@ func TestStore_HealthCheck_AfterClose(t *testing.T) {
 		t.Error("Expected health check to fail after close, got nil")
 	}
 }
+
+func TestStore_SavedReports(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	phase := &Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Phase 1", Content: "Content", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	statuses := []TaskStatus{TaskCompleted, TaskCompleted, TaskInProgress, TaskNotStarted, TaskCompleted}
+	for i, status := range statuses {
+		task := &Task{ID: fmt.Sprintf("task-%d", i), PhaseID: phase.ID, Number: fmt.Sprintf("1.%d", i+1), Description: "Task", Status: status}
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+
+	if err := store.SaveReport("tasks-by-status", "SELECT status, COUNT(*) AS total FROM tasks GROUP BY status ORDER BY status;"); err != nil {
+		t.Fatalf("Failed to save report: %v", err)
+	}
+
+	rows, err := store.RunReport("tasks-by-status")
+	if err != nil {
+		t.Fatalf("Failed to run report: %v", err)
+	}
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		status, _ := row["status"].(string)
+		total, _ := row["total"].(int64)
+		counts[status] = total
+	}
+	expected := map[string]int64{"completed": 3, "in_progress": 1, "not_started": 1}
+	if len(counts) != len(expected) {
+		t.Fatalf("Expected %d rows, got %v", len(expected), rows)
+	}
+	for status, total := range expected {
+		if counts[status] != total {
+			t.Errorf("Expected %d %s tasks, got %d", total, status, counts[status])
+		}
+	}
+
+	rejected := []string{
+		"DELETE FROM tasks",
+		"UPDATE tasks SET status = 'completed'",
+		"SELECT * FROM tasks; DROP TABLE tasks",
+		"  ",
+	}
+	for _, query := range rejected {
+		if err := store.SaveReport("bad", query); err == nil {
+			t.Errorf("Expected %q to be rejected", query)
+		}
+	}
+
+	if _, err := store.RunReport("missing"); err == nil {
+		t.Error("Expected error for an unknown report")
+	}
+}
+
+func TestStore_ProjectLimit(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	newProject := func(id string) *Project {
+		return &Project{ID: id, Name: id, CreatedAt: time.Now(), CurrentStage: StageInit}
+	}
+
+	if limit, err := store.GetMaxProjects(); err != nil || limit != 0 {
+		t.Fatalf("Expected no limit by default, got %d (%v)", limit, err)
+	}
+	if err := store.SetMaxProjects(2); err != nil {
+		t.Fatalf("Failed to set project limit: %v", err)
+	}
+
+	for _, id := range []string{"proj-1", "proj-2"} {
+		if err := store.CreateProject(newProject(id)); err != nil {
+			t.Fatalf("Failed to create project %s: %v", id, err)
+		}
+	}
+	if err := store.CreateProject(newProject("proj-3")); !errors.Is(err, ErrProjectLimit) {
+		t.Fatalf("Expected ErrProjectLimit, got %v", err)
+	}
+	if _, err := store.GetProject("proj-3"); err == nil {
+		t.Error("Expected the rejected project not to be created")
+	}
+
+	// Archived projects don't count against the limit
+	if err := store.SetProjectArchived("proj-1", true); err != nil {
+		t.Fatalf("Failed to archive project: %v", err)
+	}
+	count, err := store.CountProjects()
+	if err != nil {
+		t.Fatalf("Failed to count projects: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 unarchived project, got %d", count)
+	}
+	if err := store.CreateProject(newProject("proj-3")); err != nil {
+		t.Errorf("Expected archiving to free a slot, got %v", err)
+	}
+
+	if err := store.SetMaxProjects(0); err != nil {
+		t.Fatalf("Failed to remove project limit: %v", err)
+	}
+	if err := store.CreateProject(newProject("proj-4")); err != nil {
+		t.Errorf("Expected no limit after removing it, got %v", err)
+	}
+}
+
+func TestStore_Exists(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	phase := &Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Phase 1", Content: "Content", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	task := &Task{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Task", Status: TaskNotStarted}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	checks := []struct {
+		name   string
+		exists func(string) (bool, error)
+		id     string
+		want   bool
+	}{
+		{"project", store.ProjectExists, "proj-123", true},
+		{"missing project", store.ProjectExists, "proj-missing", false},
+		{"phase", store.PhaseExists, "phase-1", true},
+		{"missing phase", store.PhaseExists, "phase-missing", false},
+		{"task", store.TaskExists, "task-1", true},
+		{"missing task", store.TaskExists, "task-missing", false},
+	}
+	for _, check := range checks {
+		got, err := check.exists(check.id)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", check.name, err)
+			continue
+		}
+		if got != check.want {
+			t.Errorf("%s: expected exists = %v, got %v", check.name, check.want, got)
+		}
+	}
+
+	store.Close()
+	if _, err := store.TaskExists("task-1"); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("Expected ErrStoreClosed after close, got %v", err)
+	}
+}
+
+func TestStore_InterviewColumnsInSync(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, id := range []string{"proj-a", "proj-b", "proj-c"} {
+		if err := store.CreateProject(&Project{ID: id, Name: id, CreatedAt: time.Now(), CurrentStage: StageInterview}); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+
+	data := &InterviewData{
+		ProjectID:        "proj-a",
+		ProblemStatement: "Chores get forgotten",
+		TargetUsers:      []string{"Roommates"},
+		RawSession:       `{"completed": false}`,
+		CreatedAt:        time.Now(),
+	}
+	if err := store.SaveInterviewData("proj-a", data); err != nil {
+		t.Fatalf("Failed to save interview data: %v", err)
+	}
+	if err := store.SaveInterviewData("proj-b", &InterviewData{ProjectID: "proj-b", RawSession: `{"completed": true}`, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to save interview data: %v", err)
+	}
+
+	readColumns := func() (string, string, bool) {
+		t.Helper()
+		var problem, users string
+		var completed bool
+		err := store.db.QueryRow(
+			"SELECT problem_statement, target_users, completed FROM interview_data WHERE project_id = ?", "proj-a",
+		).Scan(&problem, &users, &completed)
+		if err != nil {
+			t.Fatalf("Failed to read interview columns: %v", err)
+		}
+		return problem, users, completed
+	}
+
+	if problem, users, completed := readColumns(); problem != "Chores get forgotten" || users != `["Roommates"]` || completed {
+		t.Errorf("Unexpected columns after first save: %q %q %v", problem, users, completed)
+	}
+
+	missing, err := store.ListProjectsMissingInterview()
+	if err != nil {
+		t.Fatalf("Failed to list projects missing an interview: %v", err)
+	}
+	if len(missing) != 2 || missing[0].ID != "proj-a" || missing[1].ID != "proj-c" {
+		t.Errorf("Expected proj-a and proj-c to be missing an interview, got %v", missing)
+	}
+
+	data.ProblemStatement = "Shared chores get forgotten"
+	data.TargetUsers = []string{"Roommates", "Families"}
+	data.RawSession = `{"completed": true}`
+	if err := store.SaveInterviewData("proj-a", data); err != nil {
+		t.Fatalf("Failed to update interview data: %v", err)
+	}
+
+	if problem, users, completed := readColumns(); problem != "Shared chores get forgotten" || users != `["Roommates","Families"]` || !completed {
+		t.Errorf("Expected columns to follow the update, got %q %q %v", problem, users, completed)
+	}
+	loaded, err := store.GetInterviewData("proj-a")
+	if err != nil {
+		t.Fatalf("Failed to get interview data: %v", err)
+	}
+	if loaded.ProblemStatement != "Shared chores get forgotten" || len(loaded.TargetUsers) != 2 {
+		t.Errorf("Expected the blob to hold the update, got %+v", loaded)
+	}
+
+	missing, err = store.ListProjectsMissingInterview()
+	if err != nil {
+		t.Fatalf("Failed to list projects missing an interview: %v", err)
+	}
+	if len(missing) != 1 || missing[0].ID != "proj-c" {
+		t.Errorf("Expected only proj-c to be missing an interview, got %v", missing)
+	}
+}
+
+func TestStore_GetStageHistory(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageInterview}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	if err := store.UpdateProjectStage(project.ID, StageDesign); err != nil {
+		t.Fatalf("Failed to update stage: %v", err)
+	}
+	// Saving the same stage again is not a transition
+	if err := store.UpdateProjectStage(project.ID, StageDesign); err != nil {
+		t.Fatalf("Failed to update stage: %v", err)
+	}
+	project.CurrentStage = StagePlan
+	if err := store.UpdateProject(project); err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+
+	history, err := store.GetStageHistory(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get stage history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 transitions, got %d: %+v", len(history), history)
+	}
+	if history[0].FromStage != StageInterview || history[0].ToStage != StageDesign {
+		t.Errorf("Expected interview -> design first, got %s -> %s", history[0].FromStage, history[0].ToStage)
+	}
+	if history[1].FromStage != StageDesign || history[1].ToStage != StagePlan {
+		t.Errorf("Expected design -> plan second, got %s -> %s", history[1].FromStage, history[1].ToStage)
+	}
+	if history[1].TransitionedAt.Before(history[0].TransitionedAt) {
+		t.Errorf("Expected transitions in time order, got %v then %v", history[0].TransitionedAt, history[1].TransitionedAt)
+	}
+
+	if err := store.UpdateProjectStage("missing", StagePlan); err == nil {
+		t.Error("Expected an error updating the stage of a missing project")
+	}
+}
+
+func TestStore_TaskNotes(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &Project{ID: "proj-123", Name: "Test Project", CreatedAt: time.Now(), CurrentStage: StageDevelop}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	phase := &Phase{ID: "phase-1", ProjectID: project.ID, Number: 1, Title: "Phase 1", Content: "Content", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	task := &Task{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Task", Status: TaskNotStarted}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	entries := []string{"Tried the ORM, too slow", "Switched to raw SQL", "Tests pass"}
+	for i, entry := range entries {
+		if err := store.AppendTaskNote(task.ID, entry); err != nil {
+			t.Fatalf("Failed to append note: %v", err)
+		}
+		if i == 0 {
+			if err := store.UpdateTaskStatus(task.ID, TaskInProgress); err != nil {
+				t.Fatalf("Failed to update task status: %v", err)
+			}
+		}
+	}
+	task.Status = TaskCompleted
+	if err := store.SaveTask(task); err != nil {
+		t.Fatalf("Failed to re-save task: %v", err)
+	}
+
+	notes, err := store.GetTaskNotes(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get notes: %v", err)
+	}
+	if len(notes) != len(entries) {
+		t.Fatalf("Expected %d notes after status updates, got %d", len(entries), len(notes))
+	}
+	for i, note := range notes {
+		if note.Note != entries[i] || note.TaskID != task.ID {
+			t.Errorf("Note %d: expected %q, got %+v", i, entries[i], note)
+		}
+		if i > 0 && note.CreatedAt.Before(notes[i-1].CreatedAt) {
+			t.Errorf("Expected notes in chronological order, got %v before %v", notes[i-1].CreatedAt, note.CreatedAt)
+		}
+	}
+
+	if err := store.AppendTaskNote(task.ID, "   "); err == nil {
+		t.Error("Expected an error appending an empty note")
+	}
+	if err := store.AppendTaskNote("missing-task", "note"); err == nil {
+		t.Error("Expected an error appending a note to a missing task")
+	}
+}
+
+func TestStore_MetricsPrometheus(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, id := range []string{"proj-1", "proj-2", "proj-3"} {
+		if err := store.CreateProject(&Project{ID: id, Name: id, CreatedAt: time.Now(), CurrentStage: StageDevelop}); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+	if err := store.SetProjectArchived("proj-3", true); err != nil {
+		t.Fatalf("Failed to archive project: %v", err)
+	}
+
+	phase := &Phase{ID: "phase-1", ProjectID: "proj-1", Number: 1, Title: "Phase 1", Content: "Content", Status: PhaseInProgress, CreatedAt: time.Now()}
+	if err := store.SavePhase(phase); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	if err := store.SaveTask(&Task{ID: "task-1", PhaseID: phase.ID, Number: "1.1", Description: "Task", Status: TaskBlocked}); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	resolved := time.Now()
+	for _, blocker := range []*Blocker{
+		{ID: "blocker-1", TaskID: "task-1", Description: "Missing key", CreatedAt: time.Now()},
+		{ID: "blocker-2", TaskID: "task-1", Description: "Flaky test", CreatedAt: time.Now(), ResolvedAt: &resolved},
+	} {
+		if err := store.SaveBlocker(blocker); err != nil {
+			t.Fatalf("Failed to save blocker: %v", err)
+		}
+	}
+
+	for _, usage := range []*TokenUsage{
+		{ProjectID: "proj-1", Provider: "openai", Model: "gpt-4", TokensInput: 1000, TokensOutput: 200, Cost: 1.25, Timestamp: time.Now()},
+		{ProjectID: "proj-2", Provider: "openai", Model: "gpt-4", TokensInput: 500, TokensOutput: 100, Cost: 0.5, Timestamp: time.Now()},
+	} {
+		if err := store.RecordTokenUsage(usage); err != nil {
+			t.Fatalf("Failed to record usage: %v", err)
+		}
+	}
+
+	metrics, err := store.Metrics()
+	if err != nil {
+		t.Fatalf("Failed to collect metrics: %v", err)
+	}
+
+	var out strings.Builder
+	WritePrometheus(metrics, &out)
+	for _, line := range []string{
+		"# TYPE geoffrussy_projects gauge",
+		"geoffrussy_projects 2",
+		"geoffrussy_archived_projects 1",
+		"geoffrussy_active_blockers 1",
+		"geoffrussy_llm_cost_dollars 1.75",
+		`geoffrussy_llm_tokens{direction="input"} 1500`,
+		`geoffrussy_llm_tokens{direction="output"} 300`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestStore_PhaseTemplates(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, id := range []string{"proj-src", "proj-a", "proj-b"} {
+		project := &Project{ID: id, Name: id, CreatedAt: time.Now(), CurrentStage: StagePlan}
+		if err := store.CreateProject(project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+	existing := &Phase{ID: "phase-b-1", ProjectID: "proj-b", Number: 1, Title: "Setup", Content: "Setup", Status: PhaseCompleted, CreatedAt: time.Now()}
+	if err := store.SavePhase(existing); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+
+	source := &Phase{ID: "phase-src-1", ProjectID: "proj-src", Number: 1, Title: "Auth", Content: "Add login",
+		Status: PhaseCompleted, CreatedAt: time.Now(), SuccessCriteria: []string{"Users can log in"}}
+	if err := store.SavePhase(source); err != nil {
+		t.Fatalf("Failed to save phase: %v", err)
+	}
+	for i, description := range []string{"Create users table", "Add login endpoint"} {
+		task := &Task{ID: fmt.Sprintf("src-task-%d", i+1), PhaseID: source.ID, Number: fmt.Sprintf("1.%d", i+1), Description: description, Status: TaskCompleted}
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+	source.SuccessCriteria = nil
+	if err := store.SavePhaseTemplate("auth", source); err != nil {
+		t.Fatalf("Failed to save template: %v", err)
+	}
+
+	phaseA, err := store.ApplyPhaseTemplate("auth", "proj-a")
+	if err != nil {
+		t.Fatalf("Failed to apply template: %v", err)
+	}
+	phaseB, err := store.ApplyPhaseTemplate("auth", "proj-b")
+	if err != nil {
+		t.Fatalf("Failed to apply template: %v", err)
+	}
+
+	if phaseA.ID == phaseB.ID || phaseA.ID == source.ID {
+		t.Errorf("Expected fresh phase IDs, got %s and %s", phaseA.ID, phaseB.ID)
+	}
+	if phaseA.Number != 1 || phaseB.Number != 2 {
+		t.Errorf("Expected phases appended as 1 and 2, got %d and %d", phaseA.Number, phaseB.Number)
+	}
+
+	for _, phase := range []*Phase{phaseA, phaseB} {
+		stored, err := store.GetPhase(phase.ID)
+		if err != nil {
+			t.Fatalf("Failed to get phase: %v", err)
+		}
+		if stored.Title != "Auth" || stored.Content != "Add login" || stored.Status != PhaseNotStarted {
+			t.Errorf("Unexpected instantiated phase: %+v", stored)
+		}
+
+		tasks, err := store.ListTasks(phase.ID)
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if len(tasks) != 2 || tasks[1].Description != "Add login endpoint" {
+			t.Fatalf("Expected template tasks copied, got %+v", tasks)
+		}
+		for _, task := range tasks {
+			if task.Status != TaskNotStarted || strings.HasPrefix(task.ID, "src-") {
+				t.Errorf("Expected a fresh not-started task, got %+v", task)
+			}
+		}
+		if tasks[0].Number != fmt.Sprintf("%d.1", phase.Number) {
+			t.Errorf("Expected task numbered under phase %d, got %s", phase.Number, tasks[0].Number)
+		}
+
+		criteria, err := store.ListSuccessCriteria(phase.ID)
+		if err != nil {
+			t.Fatalf("Failed to list criteria: %v", err)
+		}
+		if len(criteria) != 1 || criteria[0].Text != "Users can log in" || criteria[0].Met {
+			t.Errorf("Expected unmet template criteria, got %+v", criteria)
+		}
+	}
+
+	if _, err := store.ApplyPhaseTemplate("missing", "proj-a"); err == nil {
+		t.Error("Expected an error for an unknown template")
+	}
+	if _, err := store.ApplyPhaseTemplate("auth", "proj-missing"); err == nil {
+		t.Error("Expected an error for an unknown project")
+	}
+}
+
+func TestStore_GetProjectWithStats(t *testing.T) {
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, project := range []*Project{
+		{ID: "proj-1", Name: "Seeded", CreatedAt: time.Now(), CurrentStage: StageDevelop},
+		{ID: "proj-2", Name: "Other", CreatedAt: time.Now(), CurrentStage: StageDevelop},
+	} {
+		if err := store.CreateProject(project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+	for _, phase := range []*Phase{
+		{ID: "p1-phase-1", ProjectID: "proj-1", Number: 1, Title: "Phase 1", Status: PhaseCompleted, CreatedAt: time.Now()},
+		{ID: "p1-phase-2", ProjectID: "proj-1", Number: 2, Title: "Phase 2", Status: PhaseInProgress, CreatedAt: time.Now()},
+		{ID: "p1-phase-3", ProjectID: "proj-1", Number: 3, Title: "Phase 3", Status: PhaseNotStarted, CreatedAt: time.Now()},
+		{ID: "p2-phase-1", ProjectID: "proj-2", Number: 1, Title: "Phase 1", Status: PhaseCompleted, CreatedAt: time.Now()},
+	} {
+		if err := store.SavePhase(phase); err != nil {
+			t.Fatalf("Failed to save phase: %v", err)
+		}
+	}
+	for _, task := range []*Task{
+		{ID: "t1", PhaseID: "p1-phase-1", Number: "1.1", Description: "Done", Status: TaskCompleted},
+		{ID: "t2", PhaseID: "p1-phase-1", Number: "1.2", Description: "Done", Status: TaskCompleted},
+		{ID: "t3", PhaseID: "p1-phase-2", Number: "2.1", Description: "Done", Status: TaskCompleted},
+		{ID: "t4", PhaseID: "p1-phase-2", Number: "2.2", Description: "Blocked", Status: TaskBlocked},
+		{ID: "t5", PhaseID: "p1-phase-3", Number: "3.1", Description: "Pending", Status: TaskNotStarted},
+		{ID: "t6", PhaseID: "p2-phase-1", Number: "1.1", Description: "Blocked", Status: TaskBlocked},
+	} {
+		if err := store.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+	for _, blocker := range []*Blocker{
+		{ID: "blocker-1", TaskID: "t4", Description: "Failed", CreatedAt: time.Now()},
+		{ID: "blocker-2", TaskID: "t4", Description: "Failed again", CreatedAt: time.Now()},
+		{ID: "blocker-3", TaskID: "t6", Description: "Failed", CreatedAt: time.Now()},
+	} {
+		if err := store.SaveBlocker(blocker); err != nil {
+			t.Fatalf("Failed to save blocker: %v", err)
+		}
+	}
+	if err := store.ResolveBlocker("blocker-1", "Fixed"); err != nil {
+		t.Fatalf("Failed to resolve blocker: %v", err)
+	}
+	for _, usage := range []*TokenUsage{
+		{ProjectID: "proj-1", Provider: "openai", Model: "gpt-4", Cost: 1.25, Timestamp: time.Now()},
+		{ProjectID: "proj-1", Provider: "openai", Model: "gpt-4", Cost: 0.75, Timestamp: time.Now()},
+		{ProjectID: "proj-2", Provider: "openai", Model: "gpt-4", Cost: 5.00, Timestamp: time.Now()},
+	} {
+		if err := store.RecordTokenUsage(usage); err != nil {
+			t.Fatalf("Failed to record token usage: %v", err)
+		}
+	}
+
+	stats, err := store.GetProjectWithStats("proj-1")
+	if err != nil {
+		t.Fatalf("Failed to get project stats: %v", err)
+	}
+	if stats.ID != "proj-1" || stats.Name != "Seeded" || stats.CurrentStage != StageDevelop {
+		t.Errorf("Expected the project row embedded, got %+v", stats.Project)
+	}
+	if stats.TotalPhases != 3 || stats.CompletedPhases != 1 {
+		t.Errorf("Expected 1/3 phases completed, got %d/%d", stats.CompletedPhases, stats.TotalPhases)
+	}
+	if stats.TotalTasks != 5 || stats.CompletedTasks != 3 || stats.CompletionPercentage != 60 {
+		t.Errorf("Expected 3/5 tasks (60%%), got %d/%d (%.0f%%)", stats.CompletedTasks, stats.TotalTasks, stats.CompletionPercentage)
+	}
+	if stats.TotalCost != 2.00 {
+		t.Errorf("Expected total cost 2.00, got %.2f", stats.TotalCost)
+	}
+	if stats.ActiveBlockers != 1 {
+		t.Errorf("Expected 1 active blocker, got %d", stats.ActiveBlockers)
+	}
+
+	empty, err := store.GetProjectWithStats("proj-2")
+	if err != nil {
+		t.Fatalf("Failed to get project stats: %v", err)
+	}
+	if empty.TotalPhases != 1 || empty.TotalTasks != 1 || empty.CompletionPercentage != 0 || empty.ActiveBlockers != 1 {
+		t.Errorf("Unexpected stats for proj-2: %+v", empty)
+	}
+
+	if _, err := store.GetProjectWithStats("missing"); err == nil {
+		t.Error("Expected an error for an unknown project")
+	}
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaskNote is an entry in a task's progress log: an attempt, a decision or
+// anything else worth remembering about how the task went
+type TaskNote struct {
+	TaskID    string
+	Note      string
+	CreatedAt time.Time
+}
+
+// AppendTaskNote adds a note to the end of a task's progress log
+func (s *Store) AppendTaskNote(taskID, note string) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return fmt.Errorf("task note cannot be empty")
+	}
+
+	_, err := s.handle().Exec(`
+		INSERT INTO task_notes (task_id, note, created_at)
+		VALUES (?, ?, ?)
+	`, taskID, note, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to append task note: %w", err)
+	}
+	return nil
+}
+
+// GetTaskNotes returns a task's progress log in the order the notes were
+// added
+func (s *Store) GetTaskNotes(taskID string) ([]*TaskNote, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.handle().Query(`
+		SELECT task_id, note, created_at
+		FROM task_notes
+		WHERE task_id = ?
+		ORDER BY id
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []*TaskNote
+	for rows.Next() {
+		note := &TaskNote{}
+		if err := rows.Scan(&note.TaskID, &note.Note, &note.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task notes: %w", err)
+	}
+	return notes, nil
+}
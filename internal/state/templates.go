@@ -0,0 +1,153 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SavePhaseTemplate stores a phase's title, content, task descriptions and
+// success criteria under name so other projects can reuse it. Tasks and
+// criteria are read from the store; a phase with SuccessCriteria set uses
+// those instead. Saving under an existing name replaces that template.
+func (s *Store) SavePhaseTemplate(name string, phase *Phase) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("template name cannot be empty")
+	}
+	if phase == nil {
+		return fmt.Errorf("phase cannot be nil")
+	}
+
+	tasks, err := s.ListTasks(phase.ID)
+	if err != nil {
+		return err
+	}
+	descriptions := make([]string, len(tasks))
+	for i, task := range tasks {
+		descriptions[i] = task.Description
+	}
+
+	criteria := phase.SuccessCriteria
+	if criteria == nil {
+		tracked, err := s.ListSuccessCriteria(phase.ID)
+		if err != nil {
+			return err
+		}
+		criteria = make([]string, len(tracked))
+		for i, criterion := range tracked {
+			criteria[i] = criterion.Text
+		}
+	}
+
+	tasksJSON, err := marshalJSON(descriptions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template tasks: %w", err)
+	}
+	criteriaJSON, err := marshalJSON(criteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template criteria: %w", err)
+	}
+
+	now := time.Now()
+	_, err = s.handle().Exec(`
+		INSERT INTO phase_templates (name, title, content, tasks, success_criteria, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			title = excluded.title,
+			content = excluded.content,
+			tasks = excluded.tasks,
+			success_criteria = excluded.success_criteria,
+			updated_at = excluded.updated_at
+	`, name, phase.Title, phase.Content, tasksJSON, criteriaJSON, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to save phase template: %w", err)
+	}
+	return nil
+}
+
+// ApplyPhaseTemplate adds a new phase built from the named template to the
+// end of a project, with fresh IDs and its phase and tasks not started
+func (s *Store) ApplyPhaseTemplate(name, projectID string) (*Phase, error) {
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.ProjectExists(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+
+	var title, content, tasksJSON, criteriaJSON string
+	err = s.handle().QueryRow(`
+		SELECT title, content, tasks, success_criteria
+		FROM phase_templates
+		WHERE name = ?
+	`, name).Scan(&title, &content, &tasksJSON, &criteriaJSON)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("phase template not found: %s", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get phase template: %w", err)
+	}
+
+	var descriptions, criteria []string
+	if err := unmarshalJSON(tasksJSON, &descriptions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal template tasks: %w", err)
+	}
+	if err := unmarshalJSON(criteriaJSON, &criteria); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal template criteria: %w", err)
+	}
+
+	now := time.Now()
+	phase := &Phase{
+		ID:              fmt.Sprintf("phase-%s-%d", projectID, now.UnixNano()),
+		ProjectID:       projectID,
+		Title:           title,
+		Content:         content,
+		Status:          PhaseNotStarted,
+		CreatedAt:       now,
+		SuccessCriteria: criteria,
+	}
+
+	err = s.WithTransaction(func(tx *sql.Tx) error {
+		var last sql.NullInt64
+		if err := tx.QueryRow("SELECT MAX(number) FROM phases WHERE project_id = ?", projectID).Scan(&last); err != nil {
+			return fmt.Errorf("failed to get last phase number: %w", err)
+		}
+		phase.Number = int(last.Int64) + 1
+
+		_, err := tx.Exec(`
+			INSERT INTO phases (id, project_id, number, title, content, status, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, phase.ID, phase.ProjectID, phase.Number, phase.Title, phase.Content, phase.Status, phase.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save phase: %w", err)
+		}
+
+		for i, description := range descriptions {
+			_, err := tx.Exec(`
+				INSERT INTO tasks (id, phase_id, number, description, status, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+			`, fmt.Sprintf("%s-task-%d", phase.ID, i+1), phase.ID, fmt.Sprintf("%d.%d", phase.Number, i+1),
+				description, TaskNotStarted, now, now)
+			if err != nil {
+				return fmt.Errorf("failed to save task: %w", err)
+			}
+		}
+
+		return saveSuccessCriteria(tx, phase.ID, criteria, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return phase, nil
+}
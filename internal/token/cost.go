@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mojomast/geoffrussy/internal/config"
 	"github.com/mojomast/geoffrussy/internal/state"
 )
 
@@ -133,6 +134,68 @@ func (c *CostEstimator) CheckBudget(projectID string) (warning string, err error
 	return "", nil
 }
 
+// EnforceBudget checks the budget and applies the on_budget_exceeded policy,
+// one of the config.BudgetPolicy values, when it has been exceeded. The
+// warn policy reports the overrun as a warning, pause additionally marks the
+// project paused with the overrun as the reason and returns an error, and
+// stop (the default) returns an error.
+func (c *CostEstimator) EnforceBudget(projectID string, policy string) (warning string, err error) {
+	warning, err = c.CheckBudget(projectID)
+	if err == nil || c.budgetLimit <= 0 {
+		return warning, err
+	}
+
+	totalCost, costErr := c.GetTotalCost(projectID)
+	if costErr != nil || totalCost < c.budgetLimit {
+		// The error came from reading costs, not from an overrun
+		return "", err
+	}
+
+	switch policy {
+	case config.BudgetPolicyWarn:
+		return err.Error(), nil
+	case config.BudgetPolicyPause:
+		if pauseErr := c.store.SetProjectPaused(projectID, true, err.Error()); pauseErr != nil {
+			return "", fmt.Errorf("failed to pause project: %w", pauseErr)
+		}
+		return "", fmt.Errorf("project paused: %w", err)
+	default:
+		return "", err
+	}
+}
+
+// EstimateTimeToExhaustion estimates how long the remaining budget will last
+// at the burn rate observed over the trailing window. ok is false when no
+// budget limit is set or nothing was spent within the window.
+func (c *CostEstimator) EstimateTimeToExhaustion(projectID string, window time.Duration) (remaining time.Duration, ok bool, err error) {
+	if projectID == "" {
+		return 0, false, fmt.Errorf("project ID cannot be empty")
+	}
+	if c.budgetLimit <= 0 {
+		return 0, false, nil
+	}
+
+	_, costPerHour, err := c.store.GetBurnRate(projectID, window)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to estimate time to exhaustion: %w", err)
+	}
+	if costPerHour <= 0 {
+		return 0, false, nil
+	}
+
+	totalCost, err := c.GetTotalCost(projectID)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to estimate time to exhaustion: %w", err)
+	}
+
+	left := c.budgetLimit - totalCost
+	if left <= 0 {
+		return 0, true, nil
+	}
+
+	return time.Duration(left / costPerHour * float64(time.Hour)), true, nil
+}
+
 // EstimateDevPlanCost estimates the total cost for a DevPlan
 func (c *CostEstimator) EstimateDevPlanCost(phases []PhaseEstimate) float64 {
 	totalCost := 0.0
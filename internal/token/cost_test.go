@@ -2,9 +2,11 @@ package token
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mojomast/geoffrussy/internal/config"
 	"github.com/mojomast/geoffrussy/internal/state"
 )
 
@@ -292,3 +294,113 @@ func TestCostEstimator_BudgetWarningLevels(t *testing.T) {
 		}
 	})
 }
+
+func TestCostEstimator_EstimateTimeToExhaustion(t *testing.T) {
+	store, err := state.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{
+		ID:           "test-project",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: state.StageInit,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// $2.00 spent over the last hour
+	usage := &state.TokenUsage{
+		ProjectID:    project.ID,
+		Provider:     "openai",
+		Model:        "gpt-4",
+		TokensInput:  1000,
+		TokensOutput: 500,
+		Cost:         2.00,
+		Timestamp:    time.Now().Add(-30 * time.Minute),
+	}
+	if err := store.RecordTokenUsage(usage); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+
+	estimator := NewCostEstimator(store)
+
+	if _, ok, err := estimator.EstimateTimeToExhaustion(project.ID, time.Hour); err != nil || ok {
+		t.Errorf("Expected no estimate without a budget limit, got ok=%v err=%v", ok, err)
+	}
+
+	estimator.SetBudgetLimit(10.00)
+	remaining, ok, err := estimator.EstimateTimeToExhaustion(project.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("Expected an estimate with a budget limit and recent spend")
+	}
+	if remaining != 4*time.Hour {
+		t.Errorf("Expected 4h remaining, got %v", remaining)
+	}
+}
+
+func TestCostEstimator_EnforceBudget(t *testing.T) {
+	store, err := state.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	project := &state.Project{
+		ID:           "test-project",
+		Name:         "Test Project",
+		CreatedAt:    time.Now(),
+		CurrentStage: state.StageDevelop,
+	}
+	if err := store.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	usage := &state.TokenUsage{
+		ProjectID:    project.ID,
+		Provider:     "openai",
+		Model:        "gpt-4",
+		TokensInput:  1000,
+		TokensOutput: 500,
+		Cost:         12.00,
+		Timestamp:    time.Now(),
+	}
+	if err := store.RecordTokenUsage(usage); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+
+	estimator := NewCostEstimator(store)
+	estimator.SetBudgetLimit(10.00)
+
+	warning, err := estimator.EnforceBudget(project.ID, config.BudgetPolicyWarn)
+	if err != nil || warning == "" {
+		t.Errorf("Expected warn policy to report a warning, got warning=%q err=%v", warning, err)
+	}
+	if paused, _, _ := store.IsPaused(project.ID); paused {
+		t.Error("Warn policy should not pause the project")
+	}
+
+	if _, err := estimator.EnforceBudget(project.ID, config.BudgetPolicyPause); err == nil {
+		t.Error("Expected pause policy to return an error")
+	}
+	paused, reason, err := store.IsPaused(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to check pause state: %v", err)
+	}
+	if !paused {
+		t.Fatal("Expected pause policy to pause the project")
+	}
+	if !strings.Contains(reason, "budget limit exceeded") {
+		t.Errorf("Expected budget overrun as pause reason, got %q", reason)
+	}
+
+	if _, err := estimator.EnforceBudget(project.ID, config.BudgetPolicyStop); err == nil {
+		t.Error("Expected stop policy to return an error")
+	}
+}